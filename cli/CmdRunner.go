@@ -30,12 +30,20 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
+	"net"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
 
+	"github.com/openthread/ot-ns/dissectpkt/mle"
+	"github.com/openthread/ot-ns/nodelog"
+	"github.com/openthread/ot-ns/otoutfilter"
 	"github.com/openthread/ot-ns/visualize"
 
 	"github.com/openthread/ot-ns/web"
@@ -45,6 +53,7 @@ import (
 	"github.com/openthread/ot-ns/dispatcher"
 
 	"github.com/openthread/ot-ns/simulation"
+	"github.com/openthread/ot-ns/traffic"
 	. "github.com/openthread/ot-ns/types"
 	"github.com/pkg/errors"
 	"github.com/simonlingoogle/go-simplelogger"
@@ -52,6 +61,9 @@ import (
 
 const (
 	Prompt = "> "
+
+	// defaultGoUntilTimeout is the timeout (in seconds of virtual time) of go until without a timeout.
+	defaultGoUntilTimeout = 3600
 )
 
 type CommandContext struct {
@@ -99,24 +111,65 @@ type CmdRunner struct {
 	sim           *simulation.Simulation
 	ctx           *progctx.ProgCtx
 	contextNodeId NodeId
+	// exitOnExpectFailure makes the CLI exit when an expect command fails
+	exitOnExpectFailure bool
+	vars                map[string]string
+	block               *blockReader
+	sourceDepth         int
+	// session is true for a remote CLI session, which ends by the exit command
+	session       bool
+	sessionExited bool
+	// nodeHelp caches the OpenThread CLI commands of the nodes for completion
+	nodeHelp map[NodeId]nodeHelpEntry
+}
+
+// ExpectError is the error of a failed expect command.
+type ExpectError struct {
+	reason string
+}
+
+func (e *ExpectError) Error() string {
+	return "expect failed: " + e.reason
 }
 
 func (rt *CmdRunner) RunCommand(cmdline string, output io.Writer) error {
-	// run the OTNS-CLI command without node contexts
+	_, err := rt.runCommand(cmdline, output)
+	return err
+}
+
+// runCommand runs the OTNS-CLI command without node contexts. It returns the error of the command as cmdErr, and the
+// error of writing the output as err.
+func (rt *CmdRunner) runCommand(cmdline string, output io.Writer) (cmdErr error, err error) {
 	cmd := Command{}
 
 	if err := ParseBytes([]byte(cmdline), &cmd); err != nil {
-		if _, err := fmt.Fprintf(output, "Error: %v\n", err); err != nil {
-			return err
+		if err := rt.outputError(output, err); err != nil {
+			return nil, err
 		}
-	} else {
-		rt.execute(&cmd, output)
+		return err, nil
 	}
 
-	return nil
+	return rt.execute(&cmd, output), nil
 }
 
 func (rt *CmdRunner) HandleCommand(cmdline string, output io.Writer) error {
+	cmdErr, err := rt.runScriptLine(cmdline, output, &rt.block)
+	if err != nil {
+		return err
+	}
+
+	if rt.sessionExited {
+		return errSessionExit
+	}
+
+	if _, ok := errors.Cause(cmdErr).(*ExpectError); ok && rt.exitOnExpectFailure {
+		return cmdErr
+	}
+	return nil
+}
+
+// runCommandLine runs the command line in the node context, or runs the OTNS-CLI command.
+func (rt *CmdRunner) runCommandLine(cmdline string, output io.Writer) (cmdErr error, err error) {
 	if rt.contextNodeId != InvalidNodeId && !isContextlessCommand(cmdline) {
 		// run the command in node context
 		cmd := Command{
@@ -125,22 +178,23 @@ func (rt *CmdRunner) HandleCommand(cmdline string, output io.Writer) error {
 				Command: &cmdline,
 			},
 		}
-		rt.execute(&cmd, output)
-		return nil
-	} else {
-		return rt.RunCommand(cmdline, output)
+		return rt.execute(&cmd, output), nil
 	}
+
+	return rt.runCommand(cmdline, output)
 }
 
 func (rt *CmdRunner) GetPrompt() string {
-	if rt.contextNodeId == InvalidNodeId {
+	if rt.block != nil {
+		return BlockPrompt
+	} else if rt.contextNodeId == InvalidNodeId {
 		return Prompt
 	} else {
 		return fmt.Sprintf("node %d%s", rt.contextNodeId, Prompt)
 	}
 }
 
-func (rt *CmdRunner) execute(cmd *Command, output io.Writer) {
+func (rt *CmdRunner) execute(cmd *Command, output io.Writer) (err error) {
 	cc := &CommandContext{
 		Command: cmd,
 		rt:      rt,
@@ -149,10 +203,11 @@ func (rt *CmdRunner) execute(cmd *Command, output io.Writer) {
 
 	defer func() {
 		if cc.Err() != nil {
-			cc.outputf("Error: %v\n", cc.Err())
+			_ = rt.outputError(output, cc.Err())
 		} else {
 			cc.outputf("Done\n")
 		}
+		err = cc.Err()
 	}()
 
 	defer func() {
@@ -181,6 +236,12 @@ func (rt *CmdRunner) execute(cmd *Command, output io.Writer) {
 		rt.executeAddNode(cc, cmd.Add)
 	} else if cmd.Del != nil {
 		rt.executeDelNode(cc, cmd.Del)
+	} else if cmd.Reboot != nil {
+		rt.executeRebootNode(cc, cmd.Reboot)
+	} else if cmd.PowerCycle != nil {
+		rt.executePowerCycleNode(cc, cmd.PowerCycle)
+	} else if cmd.FactoryReset != nil {
+		rt.executeFactoryResetNode(cc, cmd.FactoryReset)
 	} else if cmd.Ping != nil {
 		rt.executePing(cc, cmd.Ping)
 	} else if cmd.Node != nil {
@@ -195,10 +256,24 @@ func (rt *CmdRunner) execute(cmd *Command, output io.Writer) {
 		rt.executeCollectPings(cc, cc.Pings)
 	} else if cmd.Counters != nil {
 		rt.executeCounters(cc, cc.Counters)
+	} else if cmd.Crashes != nil {
+		rt.executeCrashes(cc, cc.Crashes)
+	} else if cmd.Unquarantine != nil {
+		rt.executeUnquarantine(cc, cc.Unquarantine)
+	} else if cmd.Logs != nil {
+		rt.executeLogs(cc, cc.Logs)
+	} else if cmd.NetState != nil {
+		rt.executeNetState(cc, cc.NetState)
+	} else if cmd.LogLevel != nil {
+		rt.executeLogLevel(cc, cc.LogLevel)
+	} else if cmd.LogTrigger != nil {
+		rt.executeLogTrigger(cc, cc.LogTrigger)
 	} else if cmd.Joins != nil {
 		rt.executeCollectJoins(cc, cc.Joins)
 	} else if cmd.Coaps != nil {
 		rt.executeCoaps(cc, cc.Coaps)
+	} else if cmd.Mcasts != nil {
+		rt.executeMcasts(cc, cc.Mcasts)
 	} else if cmd.Scan != nil {
 		rt.executeScan(cc, cc.Scan)
 	} else if cmd.ConfigVisualization != nil {
@@ -207,10 +282,18 @@ func (rt *CmdRunner) execute(cmd *Command, output io.Writer) {
 		rt.executeDebug(cc, cmd.Debug)
 	} else if cmd.Title != nil {
 		rt.executeTitle(cc, cmd.Title)
+	} else if cmd.Traffic != nil {
+		rt.executeTraffic(cc, cmd.Traffic)
 	} else if cmd.DemoLegend != nil {
 		rt.executeDemoLegend(cc, cmd.DemoLegend)
 	} else if cmd.Exit != nil {
 		rt.executeExit(cc, cmd.Exit)
+	} else if cmd.Expect != nil {
+		rt.executeExpect(cc, cmd.Expect)
+	} else if cmd.Source != nil {
+		rt.executeSource(cc, cmd.Source)
+	} else if cmd.Help != nil {
+		rt.executeHelp(cc, cmd.Help)
 	} else if cmd.Web != nil {
 		rt.executeWeb(cc, cc.Web)
 	} else if cmd.NetInfo != nil {
@@ -218,6 +301,7 @@ func (rt *CmdRunner) execute(cmd *Command, output io.Writer) {
 	} else {
 		simplelogger.Panicf("unimplemented command: %#v", cmd)
 	}
+	return
 }
 
 func (rt *CmdRunner) executeGo(cc *CommandContext, cmd *GoCmd) {
@@ -226,6 +310,11 @@ func (rt *CmdRunner) executeGo(cc *CommandContext, cmd *GoCmd) {
 			sim.SetSpeed(*cmd.Speed)
 		})
 	}
+	if cmd.Until != nil {
+		rt.executeGoUntil(cc, cmd.Until)
+		return
+	}
+
 	var done <-chan struct{}
 
 	if cmd.Ever == nil {
@@ -248,6 +337,92 @@ func (rt *CmdRunner) executeGo(cc *CommandContext, cmd *GoCmd) {
 	}
 }
 
+func (rt *CmdRunner) executeGoUntil(cc *CommandContext, cmd *GoUntilArg) {
+	var cond dispatcher.GoCondition
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		defer func() {
+			err := recover()
+			if err != nil {
+				cond = nil
+				cc.errorf("%+v", err)
+			}
+		}()
+
+		cond = rt.goUntilCondition(cc, sim, cmd)
+	})
+
+	if cond == nil {
+		return
+	}
+
+	timeout := float64(defaultGoUntilTimeout)
+	if cmd.Timeout != nil {
+		timeout = *cmd.Timeout
+	}
+
+	var met <-chan bool
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		met = sim.GoUntil(cond, time.Duration(float64(time.Second)*timeout))
+	})
+
+	if !<-met {
+		if rt.ctx.Err() != nil {
+			cc.errorf("go until interrupted")
+		} else {
+			cc.errorf("go until timeout after %vs", timeout)
+		}
+		return
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		curTime := sim.Dispatcher().CurTime
+		cc.outputf("%d.%06d\n", curTime/1000000, curTime%1000000)
+	})
+}
+
+func (rt *CmdRunner) goUntilCondition(cc *CommandContext, sim *simulation.Simulation, cmd *GoUntilArg) dispatcher.GoCondition {
+	if cmd.Partitions != nil {
+		return dispatcher.UntilPartitions(cmd.Partitions.Val)
+	} else if cmd.Role != nil {
+		role, err := ParseOtDeviceRole(cmd.Role.Role)
+		if err != nil {
+			cc.error(err)
+			return nil
+		}
+		return dispatcher.UntilRole(cmd.Role.Node.Id, role)
+	} else if cmd.Attached != nil {
+		var nodeids []NodeId
+		for _, sel := range cmd.Attached.Nodes {
+			if node, _ := rt.getNode(sim, sel); node == nil {
+				cc.errorf("node %v not found", sel)
+				return nil
+			}
+			nodeids = append(nodeids, sel.Id)
+		}
+		return dispatcher.UntilAttached(nodeids...)
+	} else {
+		if src, _ := rt.getNode(sim, cmd.Ping.Src); src == nil {
+			cc.errorf("src node not found")
+			return nil
+		}
+
+		var addrs []string
+		if cmd.Ping.Dst != nil {
+			dst, _ := rt.getNode(sim, *cmd.Ping.Dst)
+			if dst == nil {
+				cc.errorf("dst node not found")
+				return nil
+			}
+			if err := dst.CheckRunning(); err != nil {
+				cc.error(err)
+				return nil
+			}
+			addrs = dst.GetIpAddr()
+		}
+		return dispatcher.UntilPingReplied(cmd.Ping.Src.Id, addrs, sim.Dispatcher().CurTime)
+	}
+}
+
 func (rt *CmdRunner) executeSpeed(cc *CommandContext, cmd *SpeedCmd) {
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		if cmd.Speed == nil && cmd.Max == nil {
@@ -260,13 +435,24 @@ func (rt *CmdRunner) executeSpeed(cc *CommandContext, cmd *SpeedCmd) {
 	})
 }
 
+// postAsyncWait runs the function in the dispatcher goroutine and waits for it to finish.
+// A panic in the function is passed back to the caller.
 func (rt *CmdRunner) postAsyncWait(f func(sim *simulation.Simulation)) {
 	done := make(chan struct{})
+	var panicked interface{}
 	rt.sim.PostAsync(false, func() {
+		defer close(done)
+		defer func() {
+			panicked = recover()
+		}()
+
 		f(rt.sim)
-		close(done)
 	})
 	<-done
+
+	if panicked != nil {
+		panic(panicked)
+	}
 }
 
 func (rt *CmdRunner) executeAddNode(cc *CommandContext, cmd *AddCmd) {
@@ -313,6 +499,13 @@ func (rt *CmdRunner) executeAddNode(cc *CommandContext, cmd *AddCmd) {
 
 	cfg.Restore = cmd.Restore != nil
 
+	if cmd.Restart != nil {
+		cfg.RestartPolicy = simulation.RestartOnCrash
+		if cmd.Restart.MaxRestarts != nil {
+			cfg.MaxRestarts = *cmd.Restart.MaxRestarts
+		}
+	}
+
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		node, err := sim.AddNode(cfg)
 		if err != nil {
@@ -343,12 +536,163 @@ func (rt *CmdRunner) executeExit(cc *CommandContext, cmd *ExitCmd) {
 		return
 	}
 
+	if rt.session {
+		// exit the remote CLI session only
+		rt.sessionExited = true
+		return
+	}
+
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		sim.Stop()
 	})
 	rt.ctx.Cancel("exit")
 }
 
+// executeExpect checks the expectation by evaluating the go until condition once. Any failure, including unknown
+// nodes and invalid arguments, is reported as an ExpectError.
+func (rt *CmdRunner) executeExpect(cc *CommandContext, cmd *ExpectCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		var reason string
+		defer func() {
+			if err := recover(); err != nil {
+				reason = fmt.Sprintf("%+v", err)
+			}
+			if reason != "" {
+				cc.error(&ExpectError{reason: reason})
+			}
+		}()
+
+		if cmd.Role != nil {
+			reason = rt.expectRole(sim, cmd.Role)
+		} else if cmd.Partitions != nil {
+			reason = rt.expectPartitions(sim, cmd.Partitions)
+		} else if cmd.PingLoss != nil {
+			reason = rt.expectPingLoss(sim, cmd.PingLoss)
+		} else {
+			reason = rt.expectNodesAttached(sim, cmd.NodesAttached)
+		}
+	})
+}
+
+func (rt *CmdRunner) expectRole(sim *simulation.Simulation, cmd *RoleCondition) string {
+	role, err := ParseOtDeviceRole(cmd.Role)
+	if err != nil {
+		return err.Error()
+	}
+
+	_, dnode := rt.getNode(sim, cmd.Node)
+	if dnode == nil {
+		return fmt.Sprintf("node %v not found", &cmd.Node)
+	}
+
+	if !dispatcher.UntilRole(dnode.Id, role)(sim.Dispatcher()) {
+		return fmt.Sprintf("node %d is %s, expected %s", dnode.Id, dnode.Role, role)
+	}
+	return ""
+}
+
+func (rt *CmdRunner) expectPartitions(sim *simulation.Simulation, cmd *PartitionsCondition) string {
+	d := sim.Dispatcher()
+	if !dispatcher.UntilPartitions(cmd.Val)(d) {
+		return fmt.Sprintf("%d partitions, expected %d", dispatcher.CountPartitions(d), cmd.Val)
+	}
+	return ""
+}
+
+func (rt *CmdRunner) expectPingLoss(sim *simulation.Simulation, cmd *PingLossExpect) string {
+	src, dnode := rt.getNode(sim, cmd.Src)
+	if src == nil {
+		return fmt.Sprintf("src node %v not found", &cmd.Src)
+	}
+
+	dst, _ := rt.getNode(sim, cmd.Dst)
+	if dst == nil {
+		return fmt.Sprintf("dst node %v not found", &cmd.Dst)
+	}
+
+	if err := dst.CheckRunning(); err != nil {
+		return err.Error()
+	}
+
+	var dstips []net.IP
+	for _, addr := range dst.GetIpAddr() {
+		if ip := net.ParseIP(addr); ip != nil {
+			dstips = append(dstips, ip)
+		}
+	}
+
+	sent, lost := 0, 0
+	for _, stats := range dispatcher.ComputePingStats(dnode.Pings()) {
+		dstip := net.ParseIP(stats.Dst)
+		for _, ip := range dstips {
+			if ip.Equal(dstip) {
+				sent += stats.Sent
+				lost += stats.Lost
+				break
+			}
+		}
+	}
+
+	if sent == 0 {
+		return fmt.Sprintf("no ping from node %d to node %d", cmd.Src.Id, cmd.Dst.Id)
+	}
+
+	loss := float64(lost) / float64(sent)
+	var ok bool
+	switch cmd.Op {
+	case "<":
+		ok = loss < cmd.Val
+	case "<=":
+		ok = loss <= cmd.Val
+	case ">":
+		ok = loss > cmd.Val
+	case ">=":
+		ok = loss >= cmd.Val
+	case "=", "==":
+		ok = loss == cmd.Val
+	case "!=":
+		ok = loss != cmd.Val
+	default:
+		return fmt.Sprintf("invalid operator: %s", cmd.Op)
+	}
+
+	if !ok {
+		return fmt.Sprintf("ping loss from node %d to node %d is %.3f (%d/%d), expected %s %v",
+			cmd.Src.Id, cmd.Dst.Id, loss, lost, sent, cmd.Op, cmd.Val)
+	}
+	return ""
+}
+
+func (rt *CmdRunner) expectNodesAttached(sim *simulation.Simulation, cmd *NodesAttachedExpect) string {
+	d := sim.Dispatcher()
+	var nodeids []NodeId
+	if cmd.All != nil {
+		for nodeid := range d.Nodes() {
+			nodeids = append(nodeids, nodeid)
+		}
+		sort.Ints(nodeids)
+	} else {
+		for _, sel := range cmd.Nodes {
+			if d.GetNode(sel.Id) == nil {
+				return fmt.Sprintf("node %d not found", sel.Id)
+			}
+			nodeids = append(nodeids, sel.Id)
+		}
+	}
+
+	if len(nodeids) == 0 || dispatcher.UntilAttached(nodeids...)(d) {
+		return ""
+	}
+
+	var detached []string
+	for _, nodeid := range nodeids {
+		if dnode := d.GetNode(nodeid); dnode.Role < OtDeviceRoleChild {
+			detached = append(detached, fmt.Sprintf("%d(%s)", nodeid, dnode.Role))
+		}
+	}
+	return fmt.Sprintf("nodes not attached: %s", strings.Join(detached, ","))
+}
+
 func (rt *CmdRunner) executePing(cc *CommandContext, cmd *PingCmd) {
 	simplelogger.Debugf("ping %#v", cmd)
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
@@ -358,6 +702,11 @@ func (rt *CmdRunner) executePing(cc *CommandContext, cmd *PingCmd) {
 			return
 		}
 
+		if err := src.CheckRunning(); err != nil {
+			cc.error(err)
+			return
+		}
+
 		var dstaddr string
 		if cmd.Dst != nil {
 			dst, _ := rt.getNode(sim, *cmd.Dst)
@@ -366,7 +715,11 @@ func (rt *CmdRunner) executePing(cc *CommandContext, cmd *PingCmd) {
 				cc.errorf("dst node not found")
 				return
 			}
-			dstaddrs := rt.getAddrs(dst, cmd.AddrType)
+			dstaddrs, err := rt.getAddrs(dst, cmd.AddrType)
+			if err != nil {
+				cc.error(err)
+				return
+			}
 			if len(dstaddrs) <= 0 {
 				cc.errorf("dst addr not found")
 				return
@@ -409,33 +762,40 @@ func (rt *CmdRunner) getNode(sim *simulation.Simulation, sel NodeSelector) (*sim
 	panic("node selector not implemented")
 }
 
-func (rt *CmdRunner) getAddrs(node *simulation.Node, addrType *AddrTypeFlag) []string {
+func (rt *CmdRunner) getAddrs(node *simulation.Node, addrType *AddrTypeFlag) ([]string, error) {
 	if node == nil {
-		return nil
+		return nil, nil
 	}
 
-	var addrs []string
-	if (addrType == nil || addrType.Type == AddrTypeAny) || addrType.Type == AddrTypeMleid {
-		addrs = append(addrs, node.GetIpAddrMleid()...)
+	if err := node.CheckRunning(); err != nil {
+		return nil, err
 	}
 
-	if len(addrs) > 0 {
-		return addrs
+	// `any` prefers the ML-EID, then the RLOC, then the link-local address
+	types := []AddrType{AddrTypeMleid, AddrTypeRloc, AddrTypeLinkLocal}
+	if addrType != nil && addrType.Type != AddrTypeAny {
+		types = []AddrType{addrType.Type}
 	}
 
-	if (addrType == nil || addrType.Type == AddrTypeAny) || addrType.Type == AddrTypeRloc {
-		addrs = append(addrs, node.GetIpAddrRloc()...)
+	ipaddrs, err := node.GetIpAddrs()
+	if err != nil {
+		return nil, err
 	}
 
-	if len(addrs) > 0 {
-		return addrs
-	}
+	var addrs []string
+	for _, t := range types {
+		for _, ipaddr := range ipaddrs {
+			if ipaddr.Type == t {
+				addrs = append(addrs, ipaddr.String())
+			}
+		}
 
-	if (addrType == nil || addrType.Type == AddrTypeAny) || addrType.Type == AddrTypeLinkLocal {
-		addrs = append(addrs, node.GetIpAddrLinkLocal()...)
+		if len(addrs) > 0 {
+			break
+		}
 	}
 
-	return addrs
+	return addrs, nil
 }
 
 func (rt *CmdRunner) executeDebug(cc *CommandContext, cmd *DebugCmd) {
@@ -459,6 +819,11 @@ func (rt *CmdRunner) executeNode(cc *CommandContext, cmd *NodeCmd) {
 			return
 		}
 
+		if err := node.CheckRunning(); err != nil {
+			cc.error(err)
+			return
+		}
+
 		defer func() {
 			err := recover()
 			if err != nil {
@@ -482,6 +847,36 @@ func (rt *CmdRunner) executeNode(cc *CommandContext, cmd *NodeCmd) {
 	}
 }
 
+func (rt *CmdRunner) executeRebootNode(cc *CommandContext, cmd *RebootCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for _, sel := range cmd.Nodes {
+			if err := sim.RebootNode(sel.Id); err != nil {
+				cc.error(err)
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executePowerCycleNode(cc *CommandContext, cmd *PowerCycleCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for _, sel := range cmd.Nodes {
+			if err := sim.PowerCycleNode(sel.Id, time.Duration(cmd.OffTime*float64(time.Second))); err != nil {
+				cc.error(err)
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executeFactoryResetNode(cc *CommandContext, cmd *FactoryResetCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for _, sel := range cmd.Nodes {
+			if err := sim.FactoryResetNode(sel.Id); err != nil {
+				cc.error(err)
+			}
+		}
+	})
+}
+
 func (rt *CmdRunner) executeDemoLegend(cc *CommandContext, cmd *DemoLegendCmd) {
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		sim.ShowDemoLegend(cmd.X, cmd.Y, cmd.Title)
@@ -536,8 +931,8 @@ func (rt *CmdRunner) executeLsNodes(cc *CommandContext, cmd *NodesCmd) {
 		for nodeid := range sim.Nodes() {
 			dnode := sim.Dispatcher().GetNode(nodeid)
 			var line strings.Builder
-			line.WriteString(fmt.Sprintf("id=%d\textaddr=%016x\trloc16=%04x\tx=%d\ty=%d\tstate=%s\tfailed=%v", nodeid, dnode.ExtAddr, dnode.Rloc16,
-				dnode.X, dnode.Y, dnode.Role, dnode.IsFailed()))
+			line.WriteString(fmt.Sprintf("id=%d\textaddr=%016x\trloc16=%04x\tx=%d\ty=%d\tstate=%s\tfailed=%v\tcrashed=%v", nodeid, dnode.ExtAddr, dnode.Rloc16,
+				dnode.X, dnode.Y, dnode.Role, dnode.IsFailed(), dnode.Crashed))
 			cc.outputf("%s\n", line.String())
 		}
 	})
@@ -566,24 +961,105 @@ func (rt *CmdRunner) executeLsPartitions(cc *CommandContext) {
 }
 
 func (rt *CmdRunner) executeCollectPings(cc *CommandContext, pings *PingsCmd) {
-	allPings := make(map[NodeId][]*dispatcher.PingResult)
+	var allPings []*dispatcher.PingResult
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		d := sim.Dispatcher()
-		for nodeid, node := range d.Nodes() {
-			pings := node.CollectPings()
-			if len(pings) > 0 {
-				allPings[nodeid] = pings
+		var nodeids []NodeId
+		for nodeid := range d.Nodes() {
+			nodeids = append(nodeids, nodeid)
+		}
+		sort.Ints(nodeids)
+
+		for _, nodeid := range nodeids {
+			node := d.GetNode(nodeid)
+			if pings.Stats != nil {
+				// statistics do not collect the ping results
+				allPings = append(allPings, node.Pings()...)
+			} else {
+				allPings = append(allPings, node.CollectPings()...)
 			}
 		}
 	})
 
-	for nodeid, pings := range allPings {
-		for _, ping := range pings {
-			cc.outputf("node=%-4d dst=%-40s datasize=%-3d delay=%.3fms\n", nodeid, ping.Dst, ping.DataSize, float64(ping.Delay)/1000)
+	if pings.Stats != nil {
+		output := []pingStatsOutput{}
+		for _, stats := range dispatcher.ComputePingStats(allPings) {
+			output = append(output, newPingStatsOutput(stats))
 		}
+		cc.outputItemsAsYaml(output)
+		return
+	}
+
+	for _, ping := range allPings {
+		cc.outputf("node=%-4d dst=%-40s datasize=%-3d delay=%.3fms hoplimit=%-3d time=%d.%06d", ping.Src, ping.Dst,
+			ping.DataSize, float64(ping.Delay)/1000, ping.HopLimit, ping.Timestamp/1000000, ping.Timestamp%1000000)
+		if ping.Timeout {
+			cc.outputf(" timeout")
+		} else if ping.ReplyAddr != ping.Dst {
+			cc.outputf(" reply=%s", ping.ReplyAddr)
+		}
+		cc.outputf("\n")
 	}
 }
 
+type pingStatsOutput struct {
+	Src      NodeId   `yaml:"node"`
+	Dst      string   `yaml:"dst"`
+	Sent     int      `yaml:"sent"`
+	Lost     int      `yaml:"lost"`
+	LossRate float64  `yaml:"loss"`
+	Min      *float64 `yaml:"min,omitempty"`
+	Avg      *float64 `yaml:"avg,omitempty"`
+	Max      *float64 `yaml:"max,omitempty"`
+	P50      *float64 `yaml:"p50,omitempty"`
+	P95      *float64 `yaml:"p95,omitempty"`
+	P99      *float64 `yaml:"p99,omitempty"`
+
+	Repliers []pingReplierStatsOutput `yaml:"repliers,omitempty"`
+}
+
+type pingReplierStatsOutput struct {
+	Addr    string  `yaml:"addr"`
+	Replies int     `yaml:"replies"`
+	Min     float64 `yaml:"min"`
+	Avg     float64 `yaml:"avg"`
+	Max     float64 `yaml:"max"`
+}
+
+func newPingStatsOutput(stats *dispatcher.PingStats) pingStatsOutput {
+	output := pingStatsOutput{
+		Src:      stats.Src,
+		Dst:      stats.Dst,
+		Sent:     stats.Sent,
+		Lost:     stats.Lost,
+		LossRate: math.Round(stats.LossRate()*100) / 100,
+	}
+	// delays are displayed in milliseconds
+	delayMs := func(delay uint64) *float64 {
+		ms := float64(delay) / 1000
+		return &ms
+	}
+	if stats.Lost < stats.Sent {
+		// delays are only available if any ping is replied
+		output.Min = delayMs(stats.MinDelay)
+		output.Avg = delayMs(stats.AvgDelay)
+		output.Max = delayMs(stats.MaxDelay)
+		output.P50 = delayMs(stats.P50Delay)
+		output.P95 = delayMs(stats.P95Delay)
+		output.P99 = delayMs(stats.P99Delay)
+	}
+	for _, replier := range stats.Repliers {
+		output.Repliers = append(output.Repliers, pingReplierStatsOutput{
+			Addr:    replier.Addr,
+			Replies: replier.Replies,
+			Min:     *delayMs(replier.MinDelay),
+			Avg:     *delayMs(replier.AvgDelay),
+			Max:     *delayMs(replier.MaxDelay),
+		})
+	}
+	return output
+}
+
 func (rt *CmdRunner) executeCollectJoins(cc *CommandContext, joins *JoinsCmd) {
 	allJoins := make(map[NodeId][]*dispatcher.JoinResult)
 
@@ -604,7 +1080,420 @@ func (rt *CmdRunner) executeCollectJoins(cc *CommandContext, joins *JoinsCmd) {
 	}
 }
 
+func (rt *CmdRunner) executeCrashes(cc *CommandContext, crashes *CrashesCmd) {
+	var records []simulation.CrashRecord
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		records = sim.Crashes()
+	})
+
+	for _, record := range records {
+		cc.outputf("node=%-4d time=%.3fs restarted=%-5v status=%q stderr=%q\n", record.NodeId, float64(record.Time)/1000000,
+			record.Restarted, record.ExitStatus, strings.TrimSpace(record.Stderr))
+	}
+}
+
+func (rt *CmdRunner) executeLogs(cc *CommandContext, cmd *LogsCmd) {
+	var pattern *regexp.Regexp
+	if cmd.Grep != nil {
+		var err error
+		if pattern, err = regexp.Compile(cmd.Grep.Pattern); err != nil {
+			cc.error(err)
+			return
+		}
+	}
+
+	last := 0
+	if cmd.Last != nil {
+		last = cmd.Last.Val
+	}
+
+	var logs *nodelog.Capture
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		logs = sim.NodeLogs()
+	})
+
+	for _, entry := range logs.Query(cmd.Node.Id, pattern, last) {
+		cc.outputf("%s\n", entry.String())
+	}
+}
+
+func (rt *CmdRunner) executeLogLevel(cc *CommandContext, cmd *LogLevelCmd) {
+	nodeid, region := InvalidNodeId, ""
+	if cmd.Node != nil {
+		nodeid = cmd.Node.Node.Id
+	}
+	if cmd.Region != nil {
+		region = strings.ToUpper(cmd.Region.Region)
+	}
+
+	var levels *nodelog.Levels
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		levels = sim.LogLevels()
+	})
+
+	if cmd.Level == nil {
+		for _, setting := range levels.List() {
+			cc.outputf("node=%-4s region=%-8s level=%s\n", formatLogNode(setting.NodeId), formatLogRegion(setting.Region), setting.Level)
+		}
+	} else if *cmd.Level == "default" {
+		levels.Reset(nodeid, region)
+	} else {
+		level, err := otoutfilter.ParseLogLevel(*cmd.Level)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		levels.Set(nodeid, region, level)
+	}
+}
+
+func (rt *CmdRunner) executeLogTrigger(cc *CommandContext, cmd *LogTriggerCmd) {
+	var triggers *nodelog.Triggers
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		triggers = sim.LogTriggers()
+	})
+
+	if cmd.Add != nil {
+		trigger, err := newLogTrigger(cmd.Add)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		cc.outputf("%d\n", triggers.Add(trigger))
+	} else if cmd.Del != nil {
+		if err := triggers.Remove(cmd.Del.Id); err != nil {
+			cc.error(err)
+		}
+	} else {
+		for _, trigger := range triggers.List() {
+			pattern := ""
+			if trigger.Pattern != nil {
+				pattern = trigger.Pattern.String()
+			}
+			cc.outputf("id=%-3d node=%-4s region=%-8s level=%s action=%-5s count=%-6d grep=%q\n", trigger.Id,
+				formatLogNode(trigger.NodeId), formatLogRegion(trigger.Region), trigger.Level, trigger.Action, trigger.Count, pattern)
+		}
+	}
+}
+
+func newLogTrigger(arg *LogTriggerAddArg) (trigger nodelog.Trigger, err error) {
+	trigger.Level = otoutfilter.LogLevelDebg
+	if arg.Node != nil {
+		trigger.NodeId = arg.Node.Node.Id
+	}
+	if arg.Region != nil {
+		trigger.Region = strings.ToUpper(arg.Region.Region)
+	}
+	if arg.Level != nil {
+		if trigger.Level, err = otoutfilter.ParseLogLevel(arg.Level.Level); err != nil {
+			return
+		}
+	}
+	if arg.Grep != nil {
+		if trigger.Pattern, err = regexp.Compile(arg.Grep.Pattern); err != nil {
+			return
+		}
+	}
+	trigger.Action, err = nodelog.ParseTriggerAction(arg.Action)
+	return
+}
+
+func formatLogNode(nodeid NodeId) string {
+	if nodeid == InvalidNodeId {
+		return "any"
+	}
+	return strconv.Itoa(nodeid)
+}
+
+func formatLogRegion(region string) string {
+	if region == "" {
+		return "any"
+	}
+	return region
+}
+
+func (rt *CmdRunner) executeTraffic(cc *CommandContext, cmd *TrafficCmd) {
+	if cmd.Add != nil {
+		rt.executeTrafficAdd(cc, cmd.Add)
+		return
+	}
+
+	var output []trafficFlowOutput
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		var err error
+		if cmd.Stop != nil {
+			err = sim.Traffic().Stop(cmd.Stop.Id, sim.Dispatcher().CurTime)
+		} else if cmd.Del != nil {
+			err = sim.Traffic().Remove(cmd.Del.Id)
+		} else {
+			output = []trafficFlowOutput{}
+			for _, stats := range sim.Traffic().Stats(sim.Dispatcher().CurTime) {
+				output = append(output, newTrafficFlowOutput(stats))
+			}
+		}
+
+		if err != nil {
+			cc.error(err)
+		}
+	})
+
+	if output != nil {
+		cc.outputItemsAsYaml(output)
+	}
+}
+
+func (rt *CmdRunner) executeMcasts(cc *CommandContext, cmd *McastsCmd) {
+	var deliveries []*traffic.Delivery
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		deliveries = sim.Traffic().CollectDeliveries()
+	})
+
+	output := []mcastOutput{}
+	for _, delivery := range deliveries {
+		mo := mcastOutput{
+			Timestamp: delivery.Timestamp,
+			FlowId:    delivery.FlowId,
+			Seq:       delivery.Seq,
+			SrcNode:   delivery.Src,
+			DstAddr:   delivery.Dst,
+			Delivery:  math.Round(delivery.Ratio()*10000) / 10000,
+			Receivers: []mcastRecvOutput{},
+		}
+		for _, r := range delivery.Receivers {
+			mo.Receivers = append(mo.Receivers, mcastRecvOutput{Timestamp: r.Timestamp, DstNode: r.NodeId, Latency: r.Latency})
+		}
+		output = append(output, mo)
+	}
+	cc.outputItemsAsYaml(output)
+}
+
+type mcastOutput struct {
+	Timestamp uint64            `yaml:"time"`
+	FlowId    int               `yaml:"flow"`
+	Seq       int               `yaml:"seq"`
+	SrcNode   NodeId            `yaml:"src"`
+	DstAddr   string            `yaml:"dst_addr"`
+	Delivery  float64           `yaml:"delivery"`
+	Receivers []mcastRecvOutput `yaml:"receivers"`
+}
+
+type mcastRecvOutput struct {
+	Timestamp uint64 `yaml:"time"`
+	DstNode   NodeId `yaml:"dst"`
+	Latency   uint64 `yaml:"latency"`
+}
+
+func (rt *CmdRunner) executeTrafficAdd(cc *CommandContext, arg *TrafficAddArg) {
+	cfg := traffic.FlowConfig{
+		Src:   arg.Src.Id,
+		Rate:  1,
+		Size:  20,
+		Burst: 5,
+	}
+
+	var err error
+	if cfg.Pattern, err = traffic.ParsePattern(arg.Pattern); err != nil {
+		cc.error(err)
+		return
+	}
+	if arg.Rate != nil {
+		cfg.Rate = arg.Rate.Val
+	}
+	if arg.Size != nil {
+		cfg.Size = arg.Size.Val
+	}
+	if arg.Burst != nil {
+		cfg.Burst = arg.Burst.Val
+	}
+	if arg.Count != nil {
+		cfg.Count = arg.Count.Val
+	}
+
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		if arg.Dst != nil {
+			dst, _ := rt.getNode(sim, *arg.Dst)
+			if dst == nil {
+				cc.errorf("dst node not found")
+				return
+			}
+			dstaddrs, err := rt.getAddrs(dst, arg.AddrType)
+			if err != nil {
+				cc.error(err)
+				return
+			}
+			if len(dstaddrs) <= 0 {
+				cc.errorf("dst addr not found")
+				return
+			}
+			cfg.Dst = dstaddrs[0]
+			cfg.Receivers = []NodeId{dst.Id}
+		} else {
+			ip := net.ParseIP(arg.DstAddr.Addr)
+			if ip == nil {
+				cc.errorf("invalid dst addr: %s", arg.DstAddr.Addr)
+				return
+			}
+			cfg.Dst = arg.DstAddr.Addr
+			if ip.IsMulticast() {
+				// other nodes subscribed to the multicast address are expected to receive the datagrams
+				for _, nodeid := range sim.McastSubscribers(cfg.Dst) {
+					if nodeid != cfg.Src {
+						cfg.Receivers = append(cfg.Receivers, nodeid)
+					}
+				}
+			} else if owner := sim.UnicastOwner(cfg.Dst); owner != InvalidNodeId && owner != cfg.Src {
+				// the node that has the unicast address is expected to receive the datagrams
+				cfg.Receivers = []NodeId{owner}
+			}
+		}
+
+		id, err := sim.AddTrafficFlow(cfg)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		cc.outputf("%d\n", id)
+	})
+}
+
+type trafficFlowOutput struct {
+	Id        int                     `yaml:"id"`
+	Pattern   string                  `yaml:"pattern"`
+	Src       NodeId                  `yaml:"src"`
+	Dst       string                  `yaml:"dst"`
+	Running   bool                    `yaml:"running"`
+	Duration  float64                 `yaml:"duration"`
+	Sent      int                     `yaml:"sent"`
+	SentBytes int                     `yaml:"sent_bytes"`
+	Receivers []trafficReceiverOutput `yaml:"receivers"`
+}
+
+type trafficReceiverOutput struct {
+	Node          NodeId   `yaml:"node"`
+	Received      int      `yaml:"received"`
+	ReceivedBytes int      `yaml:"received_bytes"`
+	Loss          float64  `yaml:"loss"`
+	Throughput    float64  `yaml:"throughput"`
+	Min           *float64 `yaml:"min,omitempty"`
+	Avg           *float64 `yaml:"avg,omitempty"`
+	Max           *float64 `yaml:"max,omitempty"`
+}
+
+func newTrafficFlowOutput(stats traffic.FlowStats) trafficFlowOutput {
+	output := trafficFlowOutput{
+		Id:        stats.Id,
+		Pattern:   stats.Pattern.String(),
+		Src:       stats.Src,
+		Dst:       stats.Dst,
+		Running:   stats.Running,
+		Duration:  float64(stats.Duration) / 1000000,
+		Sent:      stats.Sent,
+		SentBytes: stats.SentBytes,
+		Receivers: []trafficReceiverOutput{},
+	}
+
+	for _, r := range stats.Receivers {
+		ro := trafficReceiverOutput{
+			Node:          r.NodeId,
+			Received:      r.Received,
+			ReceivedBytes: r.ReceivedBytes,
+			Loss:          math.Round(r.LossRate*100) / 100,
+			Throughput:    math.Round(r.Throughput*100) / 100,
+		}
+		if r.Received > 0 {
+			// delays are displayed in milliseconds
+			delayMs := func(delay uint64) *float64 {
+				ms := float64(delay) / 1000
+				return &ms
+			}
+			ro.Min = delayMs(r.MinDelay)
+			ro.Avg = delayMs(r.AvgDelay)
+			ro.Max = delayMs(r.MaxDelay)
+		}
+		output.Receivers = append(output.Receivers, ro)
+	}
+	return output
+}
+
+type netStateOutput struct {
+	Leader      int                `yaml:"leader"`
+	KeySequence uint32             `yaml:"key_seq"`
+	Channel     int                `yaml:"channel"`
+	Prefixes    []string           `yaml:"prefixes"`
+	Routes      []string           `yaml:"routes"`
+	Services    []string           `yaml:"services"`
+	SrpHosts    []srpHostOutput    `yaml:"srp_hosts"`
+	SrpServices []srpServiceOutput `yaml:"srp_services"`
+}
+
+type srpHostOutput struct {
+	Name      string   `yaml:"name"`
+	Addresses []string `yaml:"addresses"`
+}
+
+type srpServiceOutput struct {
+	Name string `yaml:"name"`
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+func (rt *CmdRunner) executeNetState(cc *CommandContext, cmd *NetStateCmd) {
+	var output *netStateOutput
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		dnode := sim.Dispatcher().GetNode(cmd.Node.Id)
+		if dnode == nil {
+			cc.errorf("node %v not found", cmd.Node)
+			return
+		}
+
+		if err := sim.RefreshNetworkState(dnode.Id); err != nil {
+			cc.error(err)
+			return
+		}
+
+		state := &dnode.NetworkState
+		output = &netStateOutput{
+			Leader:      state.LeaderRouterId,
+			KeySequence: state.KeySequence,
+			Channel:     state.Channel,
+			Prefixes:    []string{},
+			Routes:      []string{},
+			Services:    []string{},
+			SrpHosts:    []srpHostOutput{},
+			SrpServices: []srpServiceOutput{},
+		}
+		for _, prefix := range state.NetData.Prefixes {
+			output.Prefixes = append(output.Prefixes, prefix.String())
+		}
+		for _, route := range state.NetData.Routes {
+			output.Routes = append(output.Routes, route.String())
+		}
+		for _, service := range state.NetData.Services {
+			output.Services = append(output.Services, service.String())
+		}
+		for _, host := range state.SrpHosts {
+			output.SrpHosts = append(output.SrpHosts, srpHostOutput{Name: host.Name, Addresses: append([]string{}, host.Addresses...)})
+		}
+		for _, service := range state.SrpServices {
+			output.SrpServices = append(output.SrpServices, srpServiceOutput{Name: service.Name, Host: service.Host, Port: service.Port})
+		}
+	})
+
+	if output != nil {
+		cc.outputItemsAsYaml(output)
+	}
+}
+
 func (rt *CmdRunner) executeCounters(cc *CommandContext, counters *CountersCmd) {
+	if counters.Mle != nil {
+		rt.executeMleCounters(cc, counters.Mle)
+		return
+	}
+	if counters.Malformed != nil {
+		rt.executeMalformedCounters(cc, counters.Malformed)
+		return
+	}
+
 	rt.postAsyncWait(func(sim *simulation.Simulation) {
 		d := sim.Dispatcher()
 		countersVal := reflect.ValueOf(d.Counters)
@@ -617,6 +1506,80 @@ func (rt *CmdRunner) executeCounters(cc *CommandContext, counters *CountersCmd)
 	})
 }
 
+func (rt *CmdRunner) executeMleCounters(cc *CommandContext, arg *MleCountersArg) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		var nodeids []NodeId
+		if len(arg.Nodes) > 0 {
+			for _, sel := range arg.Nodes {
+				nodeids = append(nodeids, sel.Id)
+			}
+		} else {
+			for nodeid := range sim.Dispatcher().Nodes() {
+				nodeids = append(nodeids, nodeid)
+			}
+			sort.Ints(nodeids)
+		}
+
+		for _, nodeid := range nodeids {
+			dnode := sim.Dispatcher().GetNode(nodeid)
+			if dnode == nil {
+				cc.errorf("node %d not found", nodeid)
+				continue
+			}
+
+			var cmdTypes []int
+			for cmdType := range dnode.MleTxCounters {
+				cmdTypes = append(cmdTypes, int(cmdType))
+			}
+			sort.Ints(cmdTypes)
+
+			for _, cmdType := range cmdTypes {
+				cc.outputf("node=%-4d %-40s %d\n", nodeid, mle.CommandType(cmdType), dnode.MleTxCounters[mle.CommandType(cmdType)])
+			}
+		}
+	})
+}
+
+func (rt *CmdRunner) executeMalformedCounters(cc *CommandContext, arg *MalformedCountersArg) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		var nodeids []NodeId
+		if len(arg.Nodes) > 0 {
+			for _, sel := range arg.Nodes {
+				nodeids = append(nodeids, sel.Id)
+			}
+		} else {
+			for nodeid := range sim.Dispatcher().Nodes() {
+				nodeids = append(nodeids, nodeid)
+			}
+			sort.Ints(nodeids)
+		}
+
+		for _, nodeid := range nodeids {
+			dnode := sim.Dispatcher().GetNode(nodeid)
+			if dnode == nil {
+				cc.errorf("node %d not found", nodeid)
+				continue
+			}
+
+			cc.outputf("node=%-4d malformed=%-8d quarantined=%v\n", nodeid, dnode.MalformedCount, dnode.Quarantined)
+		}
+	})
+}
+
+func (rt *CmdRunner) executeUnquarantine(cc *CommandContext, cmd *UnquarantineCmd) {
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for _, sel := range cmd.Nodes {
+			node, _ := rt.getNode(sim, sel)
+			if node == nil {
+				cc.errorf("node %d not found", sel.Id)
+				continue
+			}
+
+			sim.Dispatcher().LiftQuarantine(node.Id)
+		}
+	})
+}
+
 func (rt *CmdRunner) executeWeb(cc *CommandContext, webcmd *WebCmd) {
 	if err := web.OpenWeb(rt.ctx); err != nil {
 		cc.error(err)
@@ -651,6 +1614,11 @@ func (rt *CmdRunner) executeScan(cc *CommandContext, cmd *ScanCmd) {
 			return
 		}
 
+		if err := node.CheckRunning(); err != nil {
+			cc.error(err)
+			return
+		}
+
 		node.CommandExpectNone("scan", simulation.DefaultCommandTimeout)
 	})
 
@@ -659,7 +1627,7 @@ func (rt *CmdRunner) executeScan(cc *CommandContext, cmd *ScanCmd) {
 	for time.Now().Before(deadline) {
 		rt.postAsyncWait(func(sim *simulation.Simulation) {
 			node, _ := rt.getNode(sim, cmd.Node)
-			if node == nil {
+			if node == nil || node.CheckRunning() != nil {
 				return
 			}
 			node.AssurePrompt()
@@ -774,6 +1742,7 @@ func NewCmdRunner(ctx *progctx.ProgCtx, sim *simulation.Simulation) *CmdRunner {
 		ctx:           ctx,
 		sim:           sim,
 		contextNodeId: InvalidNodeId,
+		vars:          map[string]string{},
 	}
 	sim.SetCmdRunner(cr)
 	return cr
@@ -36,30 +36,44 @@ import (
 
 //noinspection GoStructTag
 type Command struct {
-	Add                 *AddCmd                 `  @@` //nolint
-	Coaps               *CoapsCmd               `| @@` //nolint
-	ConfigVisualization *ConfigVisualizationCmd `| @@` //nolint
-	CountDown           *CountDownCmd           `| @@` //nolint
-	Counters            *CountersCmd            `| @@` //nolint
-	Debug               *DebugCmd               `| @@` //nolint
-	Del                 *DelCmd                 `| @@` //nolint
-	DemoLegend          *DemoLegendCmd          `| @@` //nolint
-	Exit                *ExitCmd                `| @@` //nolint
-	Go                  *GoCmd                  `| @@` //nolint
-	Joins               *JoinsCmd               `| @@` //nolint
-	Move                *Move                   `| @@` //nolint
-	NetInfo             *NetInfoCmd             `| @@` //nolint
-	Node                *NodeCmd                `| @@` //nolint
-	Nodes               *NodesCmd               `| @@` //nolint
-	Partitions          *PartitionsCmd          `| @@` //nolint
-	Ping                *PingCmd                `| @@` //nolint
-	Pings               *PingsCmd               `| @@` //nolint
-	Plr                 *PlrCmd                 `| @@` //nolint
-	Radio               *RadioCmd               `| @@` //nolint
-	Scan                *ScanCmd                `| @@` //nolint
-	Speed               *SpeedCmd               `| @@` //nolint
-	Title               *TitleCmd               `| @@` //nolint
-	Web                 *WebCmd                 `| @@` //nolint
+	Add                 *AddCmd                 `parser:"  @@" help:"Add a node"`                                                           //nolint
+	Coaps               *CoapsCmd               `parser:"| @@" help:"Enable collecting CoAP messages, or show the collected CoAP messages"` //nolint
+	ConfigVisualization *ConfigVisualizationCmd `parser:"| @@" help:"Configure visualization options"`                                      //nolint
+	CountDown           *CountDownCmd           `parser:"| @@" help:"Display a countdown in the simulation title"`                          //nolint
+	Counters            *CountersCmd            `parser:"| @@" help:"Display runtime counters"`                                             //nolint
+	Crashes             *CrashesCmd             `parser:"| @@" help:"Display the node processes that exited unexpectedly"`                  //nolint
+	Debug               *DebugCmd               `parser:"| @@" help:"Debug the CLI"`                                                        //nolint
+	Del                 *DelCmd                 `parser:"| @@" help:"Delete nodes"`                                                         //nolint
+	DemoLegend          *DemoLegendCmd          `parser:"| @@" help:"Display a legend for demos"`                                           //nolint
+	Exit                *ExitCmd                `parser:"| @@" help:"Exit the node context, or exit OTNS"`                                  //nolint
+	Expect              *ExpectCmd              `parser:"| @@" help:"Assert on the simulation state"`                                       //nolint
+	FactoryReset        *FactoryResetCmd        `parser:"| @@" help:"Factory reset nodes"`                                                  //nolint
+	Go                  *GoCmd                  `parser:"| @@" help:"Simulate for a time, for ever, or until a condition is met"`           //nolint
+	Help                *HelpCmd                `parser:"| @@" help:"Display the commands, or the syntax of a command"`                     //nolint
+	Joins               *JoinsCmd               `parser:"| @@" help:"Display finished joiner sessions"`                                     //nolint
+	LogLevel            *LogLevelCmd            `parser:"| @@" help:"Get or set the log levels of node logs"`                               //nolint
+	LogTrigger          *LogTriggerCmd          `parser:"| @@" help:"Manage log triggers"`                                                  //nolint
+	Logs                *LogsCmd                `parser:"| @@" help:"Display the logs of a node"`                                           //nolint
+	Mcasts              *McastsCmd              `parser:"| @@" help:"Display the delivery of multicast traffic"`                            //nolint
+	Move                *Move                   `parser:"| @@" help:"Move a node"`                                                          //nolint
+	NetInfo             *NetInfoCmd             `parser:"| @@" help:"Set network info"`                                                     //nolint
+	NetState            *NetStateCmd            `parser:"| @@" help:"Display the network state of a node"`                                  //nolint
+	Node                *NodeCmd                `parser:"| @@" help:"Run an OpenThread CLI command on a node, or enter the node context"`   //nolint
+	Nodes               *NodesCmd               `parser:"| @@" help:"List the nodes"`                                                       //nolint
+	Partitions          *PartitionsCmd          `parser:"| @@" help:"List the partitions"`                                                  //nolint
+	Ping                *PingCmd                `parser:"| @@" help:"Ping from a node"`                                                     //nolint
+	Pings               *PingsCmd               `parser:"| @@" help:"Display finished ping sessions, or their statistics"`                  //nolint
+	Plr                 *PlrCmd                 `parser:"| @@" help:"Get or set the global packet loss ratio"`                              //nolint
+	PowerCycle          *PowerCycleCmd          `parser:"| @@" help:"Power off nodes and power them on after a time"`                       //nolint
+	Radio               *RadioCmd               `parser:"| @@" help:"Turn on or off the radios of nodes, or set their fail time"`           //nolint
+	Reboot              *RebootCmd              `parser:"| @@" help:"Reboot nodes"`                                                         //nolint
+	Scan                *ScanCmd                `parser:"| @@" help:"Perform a network scan"`                                               //nolint
+	Source              *SourceCmd              `parser:"| @@" help:"Run a script file"`                                                    //nolint
+	Speed               *SpeedCmd               `parser:"| @@" help:"Get or set the simulating speed"`                                      //nolint
+	Title               *TitleCmd               `parser:"| @@" help:"Set simulation title"`                                                 //nolint
+	Traffic             *TrafficCmd             `parser:"| @@" help:"Manage UDP traffic flows"`                                             //nolint
+	Unquarantine        *UnquarantineCmd        `parser:"| @@" help:"Lift the quarantine of nodes"`                                         //nolint
+	Web                 *WebCmd                 `parser:"| @@" help:"Open a web browser for visualization"`                                 //nolint
 }
 
 //noinspection GoStructTag
@@ -98,10 +112,50 @@ type DebugCmd struct {
 
 //noinspection GoStructTag
 type GoCmd struct {
-	Cmd     struct{}  `"go"`                      //nolint
-	Seconds float64   `( (@Int|@Float)`           //nolint
-	Ever    *EverFlag `| @@ )`                    //nolint
-	Speed   *float64  `[ "speed" (@Int|@Float) ]` //nolint
+	Cmd     struct{}    `"go"`                      //nolint
+	Seconds float64     `( (@Int|@Float)`           //nolint
+	Ever    *EverFlag   `| @@`                      //nolint
+	Until   *GoUntilArg `| @@ )`                    //nolint
+	Speed   *float64    `[ "speed" (@Int|@Float) ]` //nolint
+}
+
+//noinspection GoStructTag
+type GoUntilArg struct {
+	Cmd        struct{}             `"until"`                     //nolint
+	Partitions *PartitionsCondition `( @@`                        //nolint
+	Role       *RoleCondition       `| @@`                        //nolint
+	Attached   *AttachedCondition   `| @@`                        //nolint
+	Ping       *PingCondition       `| @@ )`                      //nolint
+	Timeout    *float64             `[ "timeout" (@Int|@Float) ]` //nolint
+}
+
+//noinspection GoStructTag
+type PartitionsCondition struct {
+	Val int `"partitions" @Int` //nolint
+}
+
+//noinspection GoStructTag
+type RoleCondition struct {
+	Node NodeSelector `"role" @@`                                                    //nolint
+	Role string       `@( "leader" | "router" | "child" | "detached" | "disabled" )` //nolint
+}
+
+//noinspection GoStructTag
+type AttachedCondition struct {
+	Dummy struct{}       `"attached"` //nolint
+	Nodes []NodeSelector `( @@ )*`    //nolint
+}
+
+//noinspection GoStructTag
+type PingCondition struct {
+	Src NodeSelector  `"ping" @@` //nolint
+	Dst *NodeSelector `[ @@ ]`    //nolint
+}
+
+//noinspection GoStructTag
+type HelpCmd struct {
+	Cmd     struct{} `"help"`     //nolint
+	Command *string  `[ @Ident ]` //nolint
 }
 
 //noinspection GoStructTag
@@ -232,6 +286,12 @@ type ScanCmd struct {
 	Node NodeSelector `@@`     // nolint
 }
 
+//noinspection GoStructTag
+type SourceCmd struct {
+	Cmd  struct{} `"source"` //nolint
+	File string   `@String`  //nolint
+}
+
 //noinspection GoStructTag
 type SpeedCmd struct {
 	Cmd   struct{}      `"speed"`               //nolint
@@ -257,6 +317,7 @@ type AddCmd struct {
 	Id         *AddNodeId      `| @@`                 //nolint
 	RadioRange *RadioRangeFlag `| @@`                 //nolint
 	Restore    *RestoreFlag    `| @@`                 //nolint
+	Restart    *RestartFlag    `| @@`                 //nolint
 	Executable *ExecutableFlag `| @@ )*`              //nolint
 }
 
@@ -270,6 +331,12 @@ type RestoreFlag struct {
 	Dummy struct{} `"restore"` //nolint
 }
 
+//noinspection GoStructTag
+type RestartFlag struct {
+	Dummy       struct{} `"restart"` //nolint
+	MaxRestarts *int     `[ @Int ]`  //nolint
+}
+
 //noinspection GoStructTag
 type ExecutableFlag struct {
 	Dummy struct{} `"exe"`   //nolint
@@ -307,6 +374,25 @@ type DelCmd struct {
 	Nodes []NodeSelector `( @@ )+` //nolint
 }
 
+//noinspection GoStructTag
+type RebootCmd struct {
+	Cmd   struct{}       `"reboot"` //nolint
+	Nodes []NodeSelector `( @@ )+`  //nolint
+}
+
+//noinspection GoStructTag
+type PowerCycleCmd struct {
+	Cmd     struct{}       `"powercycle"`        //nolint
+	Nodes   []NodeSelector `( @@ )+`             //nolint
+	OffTime float64        `"off" (@Int|@Float)` //nolint
+}
+
+//noinspection GoStructTag
+type FactoryResetCmd struct {
+	Cmd   struct{}       `"factoryreset"` //nolint
+	Nodes []NodeSelector `( @@ )+`        //nolint
+}
+
 //noinspection GoStructTag
 type EverFlag struct {
 	Dummy struct{} `"ever"` //nolint
@@ -322,6 +408,29 @@ type ExitCmd struct {
 	Cmd struct{} `"exit"` //nolint
 }
 
+//noinspection GoStructTag
+type ExpectCmd struct {
+	Cmd           struct{}             `"expect"` //nolint
+	Role          *RoleCondition       `( @@`     //nolint
+	Partitions    *PartitionsCondition `| @@`     //nolint
+	PingLoss      *PingLossExpect      `| @@`     //nolint
+	NodesAttached *NodesAttachedExpect `| @@ )`   //nolint
+}
+
+//noinspection GoStructTag
+type PingLossExpect struct {
+	Src NodeSelector `"ping" "-" "loss" @@`                //nolint
+	Dst NodeSelector `@@`                                  //nolint
+	Op  string       `@( "<" | ">" | "=" | "!" ) [ @"=" ]` //nolint
+	Val float64      `(@Int|@Float)`                       //nolint
+}
+
+//noinspection GoStructTag
+type NodesAttachedExpect struct {
+	All   *string        `"nodes" "-" "attached" ( @"all"` //nolint
+	Nodes []NodeSelector `| ( @@ )+ )`                     //nolint
+}
+
 //noinspection GoStructTag
 type WebCmd struct {
 	Cmd struct{} `"web"` //nolint
@@ -388,7 +497,18 @@ type PartitionsCmd struct {
 
 //noinspection GoStructTag
 type PingsCmd struct {
-	Cmd struct{} `"pings"` //nolint
+	Cmd   struct{}   `"pings"` //nolint
+	Stats *StatsFlag `[ @@ ]`  //nolint
+}
+
+//noinspection GoStructTag
+type StatsFlag struct {
+	Dummy struct{} `"stats"` //nolint
+}
+
+//noinspection GoStructTag
+type McastsCmd struct {
+	Cmd struct{} `"mcasts"` //nolint
 }
 
 //noinspection GoStructTag
@@ -396,9 +516,153 @@ type JoinsCmd struct {
 	Cmd struct{} `"joins"` //nolint
 }
 
+//noinspection GoStructTag
+type UnquarantineCmd struct {
+	Cmd   struct{}       `"unquarantine"` //nolint
+	Nodes []NodeSelector `( @@ )+`        //nolint
+}
+
+//noinspection GoStructTag
+type CrashesCmd struct {
+	Cmd struct{} `"crashes"` //nolint
+}
+
+//noinspection GoStructTag
+type LogsCmd struct {
+	Cmd  struct{}     `"logs"`  //nolint
+	Node NodeSelector `@@`      //nolint
+	Grep *GrepFlag    `( @@`    //nolint
+	Last *LastFlag    `| @@ )*` //nolint
+}
+
+//noinspection GoStructTag
+type GrepFlag struct {
+	Pattern string `"grep" @String` //nolint
+}
+
+//noinspection GoStructTag
+type LastFlag struct {
+	Val int `"last" @Int` //nolint
+}
+
+//noinspection GoStructTag
+type LogLevelCmd struct {
+	Cmd    struct{}       `"loglevel"`                                                         //nolint
+	Node   *LogNodeFlag   `( @@`                                                               //nolint
+	Region *LogRegionFlag `| @@ )*`                                                            //nolint
+	Level  *string        `[ @("none"|"crit"|"warn"|"note"|"info"|"debg"|"debug"|"default") ]` //nolint
+}
+
+//noinspection GoStructTag
+type LogNodeFlag struct {
+	Node NodeSelector `"node" @@` //nolint
+}
+
+//noinspection GoStructTag
+type LogRegionFlag struct {
+	Region string `"region" (@Ident|@String)` //nolint
+}
+
+//noinspection GoStructTag
+type LogLevelFlag struct {
+	Level string `"level" @("none"|"crit"|"warn"|"note"|"info"|"debg"|"debug")` //nolint
+}
+
+//noinspection GoStructTag
+type LogTriggerCmd struct {
+	Cmd struct{}          `"logtrigger"` //nolint
+	Add *LogTriggerAddArg `[ @@`         //nolint
+	Del *LogTriggerDelArg `| @@ ]`       //nolint
+}
+
+//noinspection GoStructTag
+type LogTriggerAddArg struct {
+	Cmd    struct{}       `"add"`              //nolint
+	Node   *LogNodeFlag   `( @@`               //nolint
+	Region *LogRegionFlag `| @@`               //nolint
+	Level  *LogLevelFlag  `| @@`               //nolint
+	Grep   *GrepFlag      `| @@ )*`            //nolint
+	Action string         `@("count"|"pause")` //nolint
+}
+
+//noinspection GoStructTag
+type LogTriggerDelArg struct {
+	Cmd struct{} `"del"` //nolint
+	Id  int      `@Int`  //nolint
+}
+
+//noinspection GoStructTag
+type TrafficCmd struct {
+	Cmd  struct{}        `"traffic"` //nolint
+	Add  *TrafficAddArg  `[ @@`      //nolint
+	Stop *TrafficStopArg `| @@`      //nolint
+	Del  *TrafficDelArg  `| @@ ]`    //nolint
+}
+
+//noinspection GoStructTag
+type TrafficAddArg struct {
+	Cmd      struct{}          `"add"`                      //nolint
+	Pattern  string            `@("cbr"|"poisson"|"burst")` //nolint
+	Src      NodeSelector      `@@`                         //nolint
+	Dst      *NodeSelector     `( @@`                       //nolint
+	AddrType *AddrTypeFlag     `  [ @@ ]`                   //nolint
+	DstAddr  *Ipv6Address      `| @@)`                      //nolint
+	Rate     *TrafficRateFlag  `( @@`                       //nolint
+	Size     *TrafficSizeFlag  `| @@`                       //nolint
+	Burst    *TrafficBurstFlag `| @@`                       //nolint
+	Count    *CountFlag        `| @@ )*`                    //nolint
+}
+
+//noinspection GoStructTag
+type TrafficRateFlag struct {
+	Val float64 `"rate" (@Int|@Float)` //nolint
+}
+
+//noinspection GoStructTag
+type TrafficSizeFlag struct {
+	Val int `"size" @Int` //nolint
+}
+
+//noinspection GoStructTag
+type TrafficBurstFlag struct {
+	Val int `"burst" @Int` //nolint
+}
+
+//noinspection GoStructTag
+type TrafficStopArg struct {
+	Cmd struct{} `"stop"` //nolint
+	Id  int      `@Int`   //nolint
+}
+
+//noinspection GoStructTag
+type TrafficDelArg struct {
+	Cmd struct{} `"del"` //nolint
+	Id  int      `@Int`  //nolint
+}
+
+//noinspection GoStructTag
+type NetStateCmd struct {
+	Cmd  struct{}     `"netstate"` //nolint
+	Node NodeSelector `@@`         //nolint
+}
+
 //noinspection GoStructTag
 type CountersCmd struct {
-	Cmd struct{} `"counters"` //nolint
+	Cmd       struct{}              `"counters"` //nolint
+	Mle       *MleCountersArg       `[ @@`       //nolint
+	Malformed *MalformedCountersArg `| @@ ]`     //nolint
+}
+
+//noinspection GoStructTag
+type MleCountersArg struct {
+	Flag  struct{}       `"mle"`    //nolint
+	Nodes []NodeSelector `( @@ )*` //nolint
+}
+
+//noinspection GoStructTag
+type MalformedCountersArg struct {
+	Flag  struct{}       `"malformed"` //nolint
+	Nodes []NodeSelector `( @@ )*`     //nolint
 }
 
 //noinspection GoStructTag
@@ -53,11 +53,21 @@ func TestParseBytes(t *testing.T) {
 	assert.True(t, cmd.Add.RadioRange.Val == 1234)
 	assert.Nil(t, ParseBytes([]byte("add router x 1 y 2 id 3 rr 1234"), &cmd))
 	assert.Nil(t, ParseBytes([]byte("add router rr 1234 id 3 y 2 x 1"), &cmd))
+	assert.Nil(t, ParseBytes([]byte("add router restart"), &cmd))
+	assert.True(t, cmd.Add.Restart != nil && cmd.Add.Restart.MaxRestarts == nil)
+	assert.Nil(t, ParseBytes([]byte("add router restore restart 3 x 1"), &cmd))
+	assert.True(t, cmd.Add.Restore != nil && *cmd.Add.Restart.MaxRestarts == 3 && *cmd.Add.X == 1)
 
 	assert.True(t, ParseBytes([]byte("countdown 3"), &cmd) == nil && cmd.CountDown != nil)
 	assert.True(t, ParseBytes([]byte("countdown 3 \"abc\""), &cmd) == nil && cmd.CountDown != nil)
 
-	assert.True(t, ParseBytes([]byte("counters"), &cmd) == nil && cmd.Counters != nil)
+	assert.True(t, ParseBytes([]byte("counters"), &cmd) == nil && cmd.Counters != nil && cmd.Counters.Mle == nil)
+	assert.True(t, ParseBytes([]byte("counters mle"), &cmd) == nil && cmd.Counters.Mle != nil && len(cmd.Counters.Mle.Nodes) == 0)
+	assert.True(t, ParseBytes([]byte("counters mle 1 2"), &cmd) == nil && len(cmd.Counters.Mle.Nodes) == 2)
+	assert.True(t, ParseBytes([]byte("counters malformed"), &cmd) == nil && cmd.Counters.Malformed != nil && len(cmd.Counters.Malformed.Nodes) == 0)
+	assert.True(t, ParseBytes([]byte("counters malformed 3"), &cmd) == nil && cmd.Counters.Malformed.Nodes[0].Id == 3)
+	assert.True(t, ParseBytes([]byte("unquarantine 1 2"), &cmd) == nil && len(cmd.Unquarantine.Nodes) == 2)
+	assert.True(t, ParseBytes([]byte("unquarantine"), &cmd) != nil)
 
 	assert.True(t, ParseBytes([]byte("del 1"), &cmd) == nil && cmd.Del != nil)
 	assert.True(t, ParseBytes([]byte("del 1 2"), &cmd) == nil && cmd.Del != nil)
@@ -68,6 +78,15 @@ func TestParseBytes(t *testing.T) {
 
 	assert.True(t, ParseBytes([]byte("exit"), &cmd) == nil && cmd.Exit != nil)
 
+	assert.True(t, ParseBytes([]byte("expect role 1 leader"), &cmd) == nil && cmd.Expect.Role.Role == "leader")
+	assert.True(t, ParseBytes([]byte("expect partitions 1"), &cmd) == nil && cmd.Expect.Partitions.Val == 1)
+	assert.True(t, ParseBytes([]byte("expect ping-loss 2 5 < 0.1"), &cmd) == nil && cmd.Expect.PingLoss.Op == "<" && cmd.Expect.PingLoss.Val == 0.1)
+	assert.True(t, ParseBytes([]byte("expect ping-loss 2 5 >= 1"), &cmd) == nil && cmd.Expect.PingLoss.Op == ">=" && cmd.Expect.PingLoss.Dst.Id == 5)
+	assert.True(t, ParseBytes([]byte("expect nodes-attached all"), &cmd) == nil && cmd.Expect.NodesAttached.All != nil)
+	assert.True(t, ParseBytes([]byte("expect nodes-attached 1 2"), &cmd) == nil && len(cmd.Expect.NodesAttached.Nodes) == 2)
+	assert.True(t, ParseBytes([]byte("expect nodes-attached"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("expect ping-loss 2 5 0.1"), &cmd) != nil)
+
 	assert.Nil(t, ParseBytes([]byte("go 1"), &cmd))
 	assert.NotNil(t, cmd.Go)
 	assert.Nil(t, ParseBytes([]byte("go 1.1"), &cmd))
@@ -78,9 +97,48 @@ func TestParseBytes(t *testing.T) {
 	assert.NotNil(t, cmd.Go)
 	assert.Nil(t, ParseBytes([]byte("go 100 speed 2"), &cmd))
 	assert.NotNil(t, cmd.Go)
+	assert.True(t, ParseBytes([]byte("go until partitions 1"), &cmd) == nil && cmd.Go.Until.Partitions.Val == 1)
+	assert.True(t, ParseBytes([]byte("go until role 1 leader timeout 100"), &cmd) == nil && cmd.Go.Until.Role.Role == "leader" && *cmd.Go.Until.Timeout == 100)
+	assert.True(t, ParseBytes([]byte("go until attached"), &cmd) == nil && len(cmd.Go.Until.Attached.Nodes) == 0)
+	assert.True(t, ParseBytes([]byte("go until attached 2 3 timeout 10.5"), &cmd) == nil && len(cmd.Go.Until.Attached.Nodes) == 2)
+	assert.True(t, ParseBytes([]byte("go until ping 1 2 speed 10"), &cmd) == nil && cmd.Go.Until.Ping.Dst.Id == 2 && *cmd.Go.Speed == 10)
+	assert.True(t, ParseBytes([]byte("go until ping 1"), &cmd) == nil && cmd.Go.Until.Ping.Dst == nil)
+	assert.True(t, ParseBytes([]byte("go until role 1 boss"), &cmd) != nil)
+	assert.True(t, ParseBytes([]byte("go until"), &cmd) != nil)
+
+	assert.True(t, ParseBytes([]byte("crashes"), &cmd) == nil && cmd.Crashes != nil)
+
+	assert.True(t, ParseBytes([]byte("factoryreset 1"), &cmd) == nil && cmd.FactoryReset != nil && len(cmd.FactoryReset.Nodes) == 1)
 
 	assert.True(t, ParseBytes([]byte("joins"), &cmd) == nil && cmd.Joins != nil)
 
+	assert.True(t, ParseBytes([]byte("loglevel"), &cmd) == nil && cmd.LogLevel != nil && cmd.LogLevel.Level == nil)
+	assert.True(t, ParseBytes([]byte("loglevel warn"), &cmd) == nil && cmd.LogLevel != nil && *cmd.LogLevel.Level == "warn")
+	assert.True(t, ParseBytes([]byte("loglevel node 1 region mle debg"), &cmd) == nil && cmd.LogLevel != nil &&
+		cmd.LogLevel.Node.Node.Id == 1 && cmd.LogLevel.Region.Region == "mle" && *cmd.LogLevel.Level == "debg")
+	assert.True(t, ParseBytes([]byte("loglevel region \"MESH-CP\" default"), &cmd) == nil && cmd.LogLevel != nil &&
+		cmd.LogLevel.Node == nil && cmd.LogLevel.Region.Region == "MESH-CP" && *cmd.LogLevel.Level == "default")
+	assert.NotNil(t, ParseBytes([]byte("loglevel verbose"), &cmd))
+
+	assert.True(t, ParseBytes([]byte("logtrigger"), &cmd) == nil && cmd.LogTrigger != nil && cmd.LogTrigger.Add == nil && cmd.LogTrigger.Del == nil)
+	assert.True(t, ParseBytes([]byte("logtrigger add grep \"Failed to send\" count"), &cmd) == nil && cmd.LogTrigger != nil &&
+		cmd.LogTrigger.Add.Grep.Pattern == "Failed to send" && cmd.LogTrigger.Add.Action == "count")
+	assert.True(t, ParseBytes([]byte("logtrigger add node 2 region MAC level crit pause"), &cmd) == nil && cmd.LogTrigger != nil &&
+		cmd.LogTrigger.Add.Node.Node.Id == 2 && cmd.LogTrigger.Add.Region.Region == "MAC" && cmd.LogTrigger.Add.Level.Level == "crit" &&
+		cmd.LogTrigger.Add.Action == "pause")
+	assert.NotNil(t, ParseBytes([]byte("logtrigger add level crit"), &cmd))
+	assert.True(t, ParseBytes([]byte("logtrigger del 1"), &cmd) == nil && cmd.LogTrigger != nil && cmd.LogTrigger.Del.Id == 1)
+	assert.True(t, ParseBytes([]byte("mcasts"), &cmd) == nil && cmd.Mcasts != nil)
+
+	assert.True(t, ParseBytes([]byte("logs 1"), &cmd) == nil && cmd.Logs != nil && cmd.Logs.Grep == nil && cmd.Logs.Last == nil)
+	assert.True(t, ParseBytes([]byte("logs 1 grep \"MLE\" last 10"), &cmd) == nil && cmd.Logs != nil &&
+		cmd.Logs.Grep.Pattern == "MLE" && cmd.Logs.Last.Val == 10)
+	assert.True(t, ParseBytes([]byte("logs 1 last 10 grep \"MLE\""), &cmd) == nil && cmd.Logs != nil &&
+		cmd.Logs.Grep.Pattern == "MLE" && cmd.Logs.Last.Val == 10)
+
+	assert.True(t, ParseBytes([]byte("netstate 1"), &cmd) == nil && cmd.NetState != nil && cmd.NetState.Node.Id == 1)
+	assert.NotNil(t, ParseBytes([]byte("netstate"), &cmd))
+
 	assert.True(t, ParseBytes([]byte("move 1 200 300"), &cmd) == nil && cmd.Move != nil)
 
 	assert.True(t, ParseBytes([]byte("node 1 \"cmd\""), &cmd) == nil && cmd.Node != nil, cmd.Node.Command != nil)
@@ -103,18 +161,35 @@ func TestParseBytes(t *testing.T) {
 	assert.True(t, ParseBytes([]byte("ping 1 2 hoplimit 3"), &cmd) == nil && cmd.Ping != nil && cmd.Ping.HopLimit.Val == 3)
 	assert.True(t, ParseBytes([]byte("ping 1 2 datasize 20 interval 3 hoplimit 60"), &cmd) == nil && cmd.Ping != nil)
 	assert.True(t, ParseBytes([]byte("ping 1 2 datasize 20 hoplimit 60 interval 3"), &cmd) == nil && cmd.Ping != nil)
-	assert.True(t, ParseBytes([]byte("pings"), &cmd) == nil && cmd.Pings != nil)
+	assert.True(t, ParseBytes([]byte("pings"), &cmd) == nil && cmd.Pings != nil && cmd.Pings.Stats == nil)
+	assert.True(t, ParseBytes([]byte("pings stats"), &cmd) == nil && cmd.Pings != nil && cmd.Pings.Stats != nil)
 
 	assert.True(t, ParseBytes([]byte("plr"), &cmd) == nil && cmd.Plr != nil && cmd.Plr.Val == nil)
 	assert.True(t, ParseBytes([]byte("plr 1"), &cmd) == nil && cmd.Plr != nil && *cmd.Plr.Val == 1)
+	assert.True(t, ParseBytes([]byte("powercycle 1 off 10"), &cmd) == nil && cmd.PowerCycle != nil && cmd.PowerCycle.OffTime == 10)
+	assert.True(t, ParseBytes([]byte("powercycle 1 2 3 off 0.5"), &cmd) == nil && len(cmd.PowerCycle.Nodes) == 3 && cmd.PowerCycle.OffTime == 0.5)
+	assert.NotNil(t, ParseBytes([]byte("powercycle 1 10"), &cmd))
+
 	assert.True(t, ParseBytes([]byte("radio 1 on"), &cmd) == nil && cmd.Radio != nil)
 	assert.True(t, ParseBytes([]byte("radio 1 off"), &cmd) == nil && cmd.Radio != nil)
 	assert.True(t, ParseBytes([]byte("radio 1 2 3 on"), &cmd) == nil && cmd.Radio != nil)
 	assert.True(t, ParseBytes([]byte("radio 4 5 6 off"), &cmd) == nil && cmd.Radio != nil)
 	assert.True(t, ParseBytes([]byte("radio 4 5 6 ft 10 60"), &cmd) == nil && cmd.Radio != nil)
+	assert.True(t, ParseBytes([]byte("reboot 1"), &cmd) == nil && cmd.Reboot != nil && len(cmd.Reboot.Nodes) == 1)
+	assert.True(t, ParseBytes([]byte("reboot 1 2 3"), &cmd) == nil && cmd.Reboot != nil && len(cmd.Reboot.Nodes) == 3)
 	assert.True(t, ParseBytes([]byte("scan 1"), &cmd) == nil && cmd.Scan != nil)
 	assert.True(t, ParseBytes([]byte("speed"), &cmd) == nil && cmd.Speed != nil && cmd.Speed.Speed == nil)
 	assert.True(t, ParseBytes([]byte("speed 1"), &cmd) == nil && cmd.Speed != nil && *cmd.Speed.Speed == 1)
+	assert.True(t, ParseBytes([]byte("traffic"), &cmd) == nil && cmd.Traffic != nil && cmd.Traffic.Add == nil)
+	assert.True(t, ParseBytes([]byte("traffic add cbr 1 2"), &cmd) == nil && cmd.Traffic != nil && cmd.Traffic.Add.Pattern == "cbr" &&
+		cmd.Traffic.Add.Dst.Id == 2)
+	assert.True(t, ParseBytes([]byte("traffic add poisson 1 2 rloc rate 0.5 size 40 count 100"), &cmd) == nil && cmd.Traffic != nil &&
+		cmd.Traffic.Add.Rate.Val == 0.5 && cmd.Traffic.Add.Size.Val == 40 && cmd.Traffic.Add.Count.Val == 100)
+	assert.True(t, ParseBytes([]byte("traffic add burst 1 \"ff03::1\" burst 10 rate 2"), &cmd) == nil && cmd.Traffic != nil &&
+		cmd.Traffic.Add.Pattern == "burst" && cmd.Traffic.Add.DstAddr.Addr == "ff03::1" && cmd.Traffic.Add.Burst.Val == 10)
+	assert.NotNil(t, ParseBytes([]byte("traffic add random 1 2"), &cmd))
+	assert.True(t, ParseBytes([]byte("traffic stop 1"), &cmd) == nil && cmd.Traffic != nil && cmd.Traffic.Stop.Id == 1)
+	assert.True(t, ParseBytes([]byte("traffic del 2"), &cmd) == nil && cmd.Traffic != nil && cmd.Traffic.Del.Id == 2)
 	assert.True(t, ParseBytes([]byte("web"), &cmd) == nil && cmd.Web != nil)
 }
 
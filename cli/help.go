@@ -0,0 +1,366 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openthread/ot-ns/simulation"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// commandInfo describes an OTNS-CLI command. It is derived from the Command grammar, so that the help and the
+// completion always match the commands.
+type commandInfo struct {
+	Name    string
+	Aliases []string
+	Syntax  string
+	Help    string
+	// Keywords are the keywords and flags of the command.
+	Keywords []string
+	// NodeArg is true if the command takes node IDs.
+	NodeArg bool
+}
+
+var (
+	grammarIntFloatPat = regexp.MustCompile(`\(\s*@Int\s*\|\s*@Float\s*\)`)
+	grammarTokenPat    = regexp.MustCompile(`@(Int|Float|Ident)\b`)
+	grammarStringPat   = regexp.MustCompile(`@String\b`)
+	grammarCapturePat  = regexp.MustCompile(`@([("])`)
+	grammarLiteralPat  = regexp.MustCompile(`"([^"]*)"`)
+	grammarOptionalPat = regexp.MustCompile(`(\S+)\s*\?`)
+	placeholderPat     = regexp.MustCompile(`^<[\w-]+>$`)
+	grammarHyphenPat   = regexp.MustCompile(`"(\w+)"\s*"-"\s*"(\w+)"`)
+	grammarKeywordPat  = regexp.MustCompile(`"([A-Za-z_][\w-]*)"`)
+	camelCasePat       = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+	nodeSelectorType = reflect.TypeOf(NodeSelector{})
+	commandInfos     = loadCommandInfos()
+
+	// scriptKeywords are the keywords of the script statements.
+	scriptKeywords = []string{"end", "for", "repeat", "set", "sleep"}
+)
+
+func loadCommandInfos() []*commandInfo {
+	var infos []*commandInfo
+
+	ct := reflect.TypeOf(Command{})
+	for i := 0; i < ct.NumField(); i++ {
+		field := ct.Field(i)
+		st := field.Type.Elem()
+
+		names := grammarKeywords(grammarTag(st.Field(0)))
+		simplelogger.AssertTrue(len(names) > 0)
+
+		info := &commandInfo{
+			Name:    names[0],
+			Aliases: names[1:],
+			Syntax:  grammarSyntax(st),
+			Help:    field.Tag.Get("help"),
+		}
+
+		keywords := map[string]struct{}{}
+		info.NodeArg = collectKeywords(st, keywords, map[reflect.Type]struct{}{})
+		for _, name := range names {
+			delete(keywords, name)
+		}
+		for keyword := range keywords {
+			info.Keywords = append(info.Keywords, keyword)
+		}
+		sort.Strings(info.Keywords)
+
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+func findCommandInfo(name string) *commandInfo {
+	for _, info := range commandInfos {
+		if info.Name == name {
+			return info
+		}
+		for _, alias := range info.Aliases {
+			if alias == name {
+				return info
+			}
+		}
+	}
+	return nil
+}
+
+// grammarTag returns the grammar of the field, in the same way as participle.
+func grammarTag(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("parser"); ok {
+		return tag
+	}
+	return string(field.Tag)
+}
+
+func grammarKeywords(tag string) []string {
+	var keywords []string
+	for _, subs := range grammarKeywordPat.FindAllStringSubmatch(grammarHyphenPat.ReplaceAllString(tag, `"$1-$2"`), -1) {
+		keywords = append(keywords, subs[1])
+	}
+	return keywords
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t
+}
+
+// collectKeywords collects the keywords of the grammar of the struct type, and returns true if it takes node IDs.
+func collectKeywords(st reflect.Type, keywords map[string]struct{}, visited map[reflect.Type]struct{}) bool {
+	if st == nodeSelectorType {
+		return true
+	}
+	if _, ok := visited[st]; ok {
+		return false
+	}
+	visited[st] = struct{}{}
+
+	nodeArg := false
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		tag := grammarTag(field)
+		for _, keyword := range grammarKeywords(tag) {
+			keywords[keyword] = struct{}{}
+		}
+
+		if ft := elemType(field.Type); strings.Contains(tag, "@@") && ft.Kind() == reflect.Struct {
+			nodeArg = collectKeywords(ft, keywords, visited) || nodeArg
+		}
+	}
+	return nodeArg
+}
+
+// grammarSyntax returns the human readable syntax of the grammar of the struct type.
+func grammarSyntax(st reflect.Type) string {
+	var parts []string
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		tag := grammarTag(field)
+		if tag == "" {
+			continue
+		}
+
+		tag = grammarHyphenPat.ReplaceAllString(tag, `"$1-$2"`)
+		placeholder := grammarPlaceholder(st, field, tag)
+
+		tag = grammarIntFloatPat.ReplaceAllString(tag, placeholder)
+		tag = grammarTokenPat.ReplaceAllString(tag, placeholder)
+		tag = grammarStringPat.ReplaceAllString(tag, `"`+placeholder+`"`)
+		tag = grammarCapturePat.ReplaceAllString(tag, "$1")
+		tag = grammarLiteralPat.ReplaceAllStringFunc(tag, func(literal string) string {
+			if placeholderPat.MatchString(literal[1 : len(literal)-1]) {
+				return literal
+			}
+			return literal[1 : len(literal)-1]
+		})
+		tag = grammarOptionalPat.ReplaceAllString(tag, "[$1]")
+
+		if ft := elemType(field.Type); strings.Contains(tag, "@@") {
+			sub := "<node-id>"
+			if ft != nodeSelectorType {
+				sub = grammarSyntax(ft)
+			} else if field.Name != "Node" && field.Name != "Nodes" {
+				sub = strings.TrimSuffix(placeholder, ">") + "-id>"
+			}
+			tag = strings.Replace(tag, "@@", sub, -1)
+		}
+
+		parts = append(parts, tag)
+	}
+
+	syntax := strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+	for _, r := range [][2]string{{"( ", "("}, {" )", ")"}, {"[ ", "["}, {" ]", "]"}} {
+		syntax = strings.Replace(syntax, r[0], r[1], -1)
+	}
+	return syntax
+}
+
+// grammarPlaceholder returns the placeholder of the value captured by the field. A value named Val is named by its
+// keyword, or by its command or flag.
+func grammarPlaceholder(st reflect.Type, field reflect.StructField, tag string) string {
+	name := field.Name
+	if name == "Val" {
+		if keywords := grammarKeywords(tag); len(keywords) > 0 {
+			name = keywords[0]
+		} else {
+			name = st.Name()
+			for _, suffix := range []string{"Cmd", "Flag", "Arg", "Expect"} {
+				name = strings.TrimSuffix(name, suffix)
+			}
+		}
+	}
+
+	return "<" + strings.ToLower(camelCasePat.ReplaceAllString(name, "$1-$2")) + ">"
+}
+
+func (rt *CmdRunner) executeHelp(cc *CommandContext, cmd *HelpCmd) {
+	if cmd.Command == nil {
+		for _, info := range commandInfos {
+			cc.outputf("%-14s %s\n", info.Name, info.Help)
+		}
+		return
+	}
+
+	info := findCommandInfo(*cmd.Command)
+	if info == nil {
+		cc.errorf("unknown command: %s", *cmd.Command)
+		return
+	}
+
+	cc.outputf("%s\n", info.Syntax)
+	cc.outputf("    %s\n", info.Help)
+	if len(info.Aliases) > 0 {
+		cc.outputf("    alias: %s\n", strings.Join(info.Aliases, ", "))
+	}
+}
+
+// Complete completes the word at the cursor of the command line, with the commands, keywords and node IDs of OTNS-CLI
+// commands, or with the OpenThread CLI commands of the node in node context.
+func (rt *CmdRunner) Complete(line []rune, pos int) ([][]rune, int) {
+	text := string(line[:pos])
+	words := strings.Fields(text)
+	prefix := ""
+	if len(words) > 0 && !strings.HasSuffix(text, " ") {
+		prefix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var candidates []string
+	if rt.contextNodeId != InvalidNodeId && (len(words) == 0 || !isContextlessCommand(words[0])) {
+		if len(words) == 0 {
+			candidates = append(rt.nodeCommands(rt.contextNodeId), "exit")
+		}
+	} else if len(words) == 0 {
+		for _, info := range commandInfos {
+			candidates = append(candidates, info.Name)
+			candidates = append(candidates, info.Aliases...)
+		}
+		candidates = append(candidates, scriptKeywords...)
+	} else if words[0] == "help" {
+		if len(words) == 1 {
+			for _, info := range commandInfos {
+				candidates = append(candidates, info.Name)
+			}
+		}
+	} else if info := findCommandInfo(words[0]); info != nil {
+		candidates = append(candidates, info.Keywords...)
+		if info.NodeArg {
+			candidates = append(candidates, rt.nodeIds()...)
+		}
+	}
+
+	sort.Strings(candidates)
+
+	var completions [][]rune
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			completions = append(completions, []rune(candidate[len(prefix):]+" "))
+		}
+	}
+	return completions, len([]rune(prefix))
+}
+
+func (rt *CmdRunner) nodeIds() []string {
+	if rt.sim == nil {
+		return nil
+	}
+
+	var nodeids []string
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		for nodeid := range sim.Nodes() {
+			nodeids = append(nodeids, strconv.Itoa(nodeid))
+		}
+	})
+	return nodeids
+}
+
+// nodeHelpEntry is the OpenThread CLI commands of a node process.
+type nodeHelpEntry struct {
+	node     *simulation.Node
+	commands []string
+}
+
+// nodeCommands returns the OpenThread CLI commands of the node, from the output of its `help` command.
+// The commands are cached until the node is deleted or its process is restarted, which might change its type.
+func (rt *CmdRunner) nodeCommands(nodeid NodeId) []string {
+	if rt.sim == nil {
+		return rt.nodeHelp[nodeid].commands
+	}
+
+	var commands []string
+	rt.postAsyncWait(func(sim *simulation.Simulation) {
+		node := sim.Nodes()[nodeid]
+		if entry, ok := rt.nodeHelp[nodeid]; ok {
+			if entry.node == node {
+				commands = entry.commands
+				return
+			}
+			delete(rt.nodeHelp, nodeid)
+		}
+
+		if node == nil || node.CheckRunning() != nil {
+			return
+		}
+
+		defer func() {
+			if err := recover(); err != nil {
+				simplelogger.Warnf("node %d help failed: %v", nodeid, err)
+				commands = nil
+			}
+		}()
+
+		for _, line := range node.Command("help", simulation.DefaultCommandTimeout) {
+			if fields := strings.Fields(line); len(fields) > 0 {
+				commands = append(commands, fields[0])
+			}
+		}
+
+		// failed lookups are not cached, so that they are retried
+		if len(commands) > 0 {
+			if rt.nodeHelp == nil {
+				rt.nodeHelp = map[NodeId]nodeHelpEntry{}
+			}
+			rt.nodeHelp[nodeid] = nodeHelpEntry{node: node, commands: commands}
+		}
+	})
+	return commands
+}
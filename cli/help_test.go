@@ -0,0 +1,109 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandInfos(t *testing.T) {
+	assert.Equal(t, reflect.TypeOf(Command{}).NumField(), len(commandInfos))
+	for _, info := range commandInfos {
+		assert.NotEmpty(t, info.Help, info.Name)
+		assert.Contains(t, info.Syntax, info.Name)
+	}
+
+	assert.Equal(t, "partitions", findCommandInfo("pts").Name)
+	assert.Nil(t, findCommandInfo("foo"))
+
+	info := findCommandInfo("go")
+	assert.Equal(t, "go (<seconds> | ever | until (partitions <partitions> | role <node-id> (leader | router | child | detached | disabled) | "+
+		"attached (<node-id>)* | ping <src-id> [<dst-id>]) [timeout <timeout>]) [speed <speed>]", info.Syntax)
+	assert.True(t, info.NodeArg)
+	assert.Contains(t, info.Keywords, "until")
+	assert.Contains(t, info.Keywords, "leader")
+	assert.NotContains(t, info.Keywords, "go")
+
+	info = findCommandInfo("expect")
+	assert.Contains(t, info.Syntax, "ping-loss <src-id> <dst-id> (< | > | = | !) [=] <ping-loss>")
+	assert.Contains(t, info.Keywords, "nodes-attached")
+	assert.NotContains(t, info.Keywords, "-")
+
+	assert.Equal(t, "coaps [enable]", findCommandInfo("coaps").Syntax)
+	assert.Equal(t, "plr [<plr>]", findCommandInfo("plr").Syntax)
+	assert.Equal(t, "powercycle (<node-id>)+ off <off-time>", findCommandInfo("powercycle").Syntax)
+	assert.False(t, findCommandInfo("plr").NodeArg)
+}
+
+func TestHelp(t *testing.T) {
+	rt := newScriptTestRunner()
+
+	output := runScriptTestLines(rt, "help")
+	assert.Equal(t, len(commandInfos)+1, strings.Count(output, "\n"))
+	assert.Contains(t, output, "go             Simulate for a time, for ever, or until a condition is met\n")
+
+	assert.Equal(t, "source \"<file>\"\n    Run a script file\nDone\n", runScriptTestLines(rt, "help source"))
+	assert.Contains(t, runScriptTestLines(rt, "help pts"), "    alias: pts\n")
+	assert.Equal(t, "Error: unknown command: foo\n", runScriptTestLines(rt, "help foo"))
+}
+
+func completeTest(rt *CmdRunner, line string) []string {
+	completions, length := rt.Complete([]rune(line), len([]rune(line)))
+
+	prefix := line[len(line)-length:]
+	var words []string
+	for _, completion := range completions {
+		words = append(words, prefix+string(completion))
+	}
+	return words
+}
+
+func TestComplete(t *testing.T) {
+	rt := newScriptTestRunner()
+
+	assert.Equal(t, []string{"go "}, completeTest(rt, "g"))
+	assert.Equal(t, []string{"partitions ", "ping ", "pings ", "plr ", "powercycle ", "pts "}, completeTest(rt, "p"))
+	assert.Equal(t, []string{"scan ", "set ", "sleep ", "source ", "speed "}, completeTest(rt, "s"))
+	assert.Equal(t, []string{"until "}, completeTest(rt, "go un"))
+	assert.Equal(t, []string{"ping-loss "}, completeTest(rt, "expect ping"))
+	assert.Equal(t, []string{"title ", "traffic "}, completeTest(rt, "help t"))
+	assert.Nil(t, completeTest(rt, "help go "))
+	assert.Nil(t, completeTest(rt, "foo "))
+	assert.Contains(t, completeTest(rt, "add "), "router ")
+
+	// OpenThread CLI commands in node context
+	rt.contextNodeId = 1
+	rt.nodeHelp = map[NodeId]nodeHelpEntry{1: {commands: []string{"ifconfig", "ipaddr", "state"}}}
+	assert.Equal(t, []string{"ifconfig ", "ipaddr "}, completeTest(rt, "i"))
+	assert.Equal(t, []string{"exit "}, completeTest(rt, "e"))
+	assert.Nil(t, completeTest(rt, "ipaddr "))
+}
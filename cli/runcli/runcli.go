@@ -2,6 +2,7 @@ package runcli
 
 import (
 	"io"
+	"net"
 	"os"
 	"strings"
 
@@ -13,6 +14,18 @@ type CliHandler interface {
 	GetPrompt() string
 }
 
+// CliCompleter is implemented by a CliHandler that completes command lines.
+type CliCompleter interface {
+	// Complete returns the completions of the word at the cursor, and the length of the word.
+	Complete(line []rune, pos int) ([][]rune, int)
+}
+
+type completerFunc func(line []rune, pos int) ([][]rune, int)
+
+func (f completerFunc) Do(line []rune, pos int) ([][]rune, int) {
+	return f(line, pos)
+}
+
 type CliOptions struct {
 	EchoInput bool
 	Stdin     *os.File
@@ -65,21 +78,7 @@ func RunCli(handler CliHandler, options *CliOptions) error {
 		}()
 	}
 
-	readlineConfig := &readline.Config{
-		Prompt:          handler.GetPrompt(),
-		InterruptPrompt: "^C",
-		EOFPrompt:       "exit",
-
-		HistorySearchFold: true,
-		FuncFilterInputRune: func(r rune) (rune, bool) {
-			switch r {
-			// block CtrlZ feature
-			case readline.CharCtrlZ:
-				return r, false
-			}
-			return r, true
-		},
-	}
+	readlineConfig := newReadlineConfig(handler)
 
 	if options.Stdin != nil {
 		readlineConfig.Stdin = options.Stdin
@@ -98,6 +97,55 @@ func RunCli(handler CliHandler, options *CliOptions) error {
 		_ = l.Close()
 	}()
 
+	var echo io.Writer
+	if options.EchoInput {
+		echo = stdout
+	}
+
+	return readCommands(handler, l, echo, func() {
+		_ = stdout.Sync()
+	})
+}
+
+// RunRemoteCli runs the CLI for a client connected with the readline remote protocol, until the client disconnects.
+func RunRemoteCli(handler CliHandler, conn net.Conn) error {
+	l, err := readline.HandleConn(*newReadlineConfig(handler), conn)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = l.Close()
+	}()
+
+	return readCommands(handler, l, nil, func() {})
+}
+
+func newReadlineConfig(handler CliHandler) *readline.Config {
+	config := &readline.Config{
+		Prompt:          handler.GetPrompt(),
+		InterruptPrompt: "^C",
+		EOFPrompt:       "exit",
+
+		HistorySearchFold: true,
+		FuncFilterInputRune: func(r rune) (rune, bool) {
+			switch r {
+			// block CtrlZ feature
+			case readline.CharCtrlZ:
+				return r, false
+			}
+			return r, true
+		},
+	}
+
+	if completer, ok := handler.(CliCompleter); ok {
+		config.AutoComplete = completerFunc(completer.Complete)
+	}
+	return config
+}
+
+// readCommands reads command lines and passes them to the handler, until EOF or interrupt.
+func readCommands(handler CliHandler, l *readline.Instance, echo io.Writer, sync func()) error {
 	for {
 		// update the prompt
 		l.SetPrompt(handler.GetPrompt())
@@ -116,8 +164,8 @@ func RunCli(handler CliHandler, options *CliOptions) error {
 			return err
 		}
 
-		if options.EchoInput {
-			if _, err := stdout.WriteString(line + "\n"); err != nil {
+		if echo != nil {
+			if _, err := io.WriteString(echo, line+"\n"); err != nil {
 				return err
 			}
 		}
@@ -131,6 +179,14 @@ func RunCli(handler CliHandler, options *CliOptions) error {
 			return err
 		}
 
-		_ = stdout.Sync()
+		sync()
+	}
+}
+
+// ParseAddr parses a remote CLI address, which is `unix:<path>` for a Unix socket, or `<host>:<port>` for TCP.
+func ParseAddr(addr string) (network string, address string) {
+	if strings.HasPrefix(addr, "unix:") {
+		return "unix", addr[len("unix:"):]
 	}
+	return "tcp", addr
 }
@@ -27,8 +27,11 @@
 package cli
 
 import (
+	"fmt"
+	"os"
 	"regexp"
 
+	"github.com/chzyer/readline"
 	"github.com/openthread/ot-ns/cli/runcli"
 	"github.com/simonlingoogle/go-simplelogger"
 )
@@ -37,9 +40,42 @@ var (
 	contextLessCommandsPat = regexp.MustCompile(`(exit|node)\b`)
 )
 
-func Run(cr *CmdRunner, cliOptions *runcli.CliOptions) error {
+// ScriptError is the error of the script run before the CLI starts.
+type ScriptError struct {
+	err error
+}
+
+func (e *ScriptError) Error() string {
+	return "script failed: " + e.err.Error()
+}
+
+// Run runs the CLI, after sourcing the script if it is not empty. A failure of the script ends the CLI with a
+// ScriptError.
+func Run(cr *CmdRunner, cliOptions *runcli.CliOptions, script string) error {
 	defer simplelogger.Debugf("CLI exit")
 
+	stdin := os.Stdin
+	if cliOptions != nil && cliOptions.Stdin != nil {
+		stdin = cliOptions.Stdin
+	}
+	// a script piped to the CLI ends at the first failed expect command
+	cr.exitOnExpectFailure = !readline.IsTerminal(int(stdin.Fd()))
+
+	if script != "" {
+		stdout := os.Stdout
+		if cliOptions != nil && cliOptions.Stdout != nil {
+			stdout = cliOptions.Stdout
+		}
+
+		cmdErr, err := cr.runCommand(fmt.Sprintf("source %q", script), stdout)
+		if err != nil {
+			return err
+		}
+		if cmdErr != nil {
+			return &ScriptError{err: cmdErr}
+		}
+	}
+
 	return runcli.RunCli(cr, cliOptions)
 }
 
@@ -0,0 +1,309 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// BlockPrompt is the prompt for the lines of a for or repeat block.
+	BlockPrompt = "... "
+
+	maxSourceDepth = 16
+)
+
+var (
+	scriptVarPat   = regexp.MustCompile(`\$(\w+)|\$\{(\w+)\}`)
+	scriptNamePat  = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+	scriptRangePat = regexp.MustCompile(`^(-?\d+)\.\.(-?\d+)$`)
+)
+
+// blockReader collects the lines of a for or repeat block until its end.
+type blockReader struct {
+	header string
+	lines  []string
+	depth  int
+}
+
+// readLine reads a line of the block, and returns true if the line ends the block.
+func (br *blockReader) readLine(line string) bool {
+	if isBlockHeader(line) {
+		br.depth++
+	} else if line == "end" {
+		br.depth--
+		if br.depth == 0 {
+			return true
+		}
+	}
+
+	br.lines = append(br.lines, line)
+	return false
+}
+
+func isBlockHeader(line string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && (fields[0] == "for" || fields[0] == "repeat")
+}
+
+// runScriptLine runs a line of a script. The lines of a for or repeat block are collected into the block reader,
+// and the block is run when it ends. It returns the error of the line as cmdErr, and the error of writing the output
+// as err.
+func (rt *CmdRunner) runScriptLine(line string, output io.Writer, block **blockReader) (cmdErr error, err error) {
+	line = strings.TrimSpace(line)
+
+	if *block != nil {
+		if !(*block).readLine(line) {
+			return nil, nil
+		}
+
+		br := *block
+		*block = nil
+		return rt.runBlock(br, output)
+	}
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, nil
+	}
+
+	if isBlockHeader(line) {
+		*block = &blockReader{header: line, depth: 1}
+		return nil, nil
+	}
+
+	keyword := strings.Fields(line)[0]
+	if keyword == "end" {
+		return rt.scriptError(output, errors.Errorf("end without for or repeat"))
+	} else if keyword == "set" {
+		return rt.runSet(strings.TrimSpace(line[len(keyword):]), output)
+	}
+
+	line, err = rt.expandVars(line)
+	if err != nil {
+		return rt.scriptError(output, err)
+	}
+
+	if keyword == "sleep" {
+		// sleep in virtual time, which is a simulator command even in a node context
+		return rt.runCommand("go"+line[len(keyword):], output)
+	}
+
+	return rt.runCommandLine(line, output)
+}
+
+// runScriptLines runs the lines of a script, and stops at the first failed line.
+func (rt *CmdRunner) runScriptLines(lines []string, output io.Writer) (cmdErr error, err error) {
+	var block *blockReader
+
+	for _, line := range lines {
+		if rt.ctx.Err() != nil {
+			return nil, nil
+		}
+
+		cmdErr, err := rt.runScriptLine(line, output, &block)
+		if err != nil || cmdErr != nil {
+			return cmdErr, err
+		}
+	}
+
+	if block != nil {
+		return rt.scriptError(output, errors.Errorf("%s: missing end", block.header))
+	}
+	return nil, nil
+}
+
+func (rt *CmdRunner) runBlock(br *blockReader, output io.Writer) (cmdErr error, err error) {
+	header, err := rt.expandVars(br.header)
+	if err != nil {
+		return rt.scriptError(output, err)
+	}
+
+	fields := strings.Fields(header)
+	var name string
+	var values []string
+
+	if fields[0] == "repeat" {
+		var n int
+		if len(fields) == 2 {
+			n, err = strconv.Atoi(fields[1])
+		}
+		if len(fields) != 2 || err != nil || n < 0 {
+			return rt.scriptError(output, errors.Errorf("invalid repeat: %s", header))
+		}
+		values = make([]string, n)
+	} else {
+		if len(fields) < 4 || fields[2] != "in" || !scriptNamePat.MatchString(fields[1]) {
+			return rt.scriptError(output, errors.Errorf("invalid for: %s", header))
+		}
+
+		name = fields[1]
+		values = fields[3:]
+		if subs := scriptRangePat.FindStringSubmatch(values[0]); len(values) == 1 && subs != nil {
+			from, _ := strconv.Atoi(subs[1])
+			to, _ := strconv.Atoi(subs[2])
+
+			values = nil
+			for i := from; i <= to; i++ {
+				values = append(values, strconv.Itoa(i))
+			}
+		}
+	}
+
+	for _, value := range values {
+		if name != "" {
+			rt.vars[name] = value
+		}
+
+		cmdErr, err := rt.runScriptLines(br.lines, output)
+		if err != nil || cmdErr != nil {
+			return cmdErr, err
+		}
+	}
+	return nil, nil
+}
+
+// runSet sets a variable to the value, or to the output of a command if the value is `$(<command>)`.
+func (rt *CmdRunner) runSet(arg string, output io.Writer) (cmdErr error, err error) {
+	name, value := arg, ""
+	if i := strings.IndexAny(arg, " \t"); i >= 0 {
+		name, value = arg[:i], strings.TrimSpace(arg[i:])
+	}
+
+	if !scriptNamePat.MatchString(name) {
+		return rt.scriptError(output, errors.Errorf("invalid variable name: %s", name))
+	}
+
+	if strings.HasPrefix(value, "$(") && strings.HasSuffix(value, ")") {
+		cmdline, err := rt.expandVars(value[2 : len(value)-1])
+		if err != nil {
+			return rt.scriptError(output, err)
+		}
+
+		var captured strings.Builder
+		cmdErr, err := rt.runCommandLine(cmdline, &captured)
+		if err != nil {
+			return nil, err
+		}
+		if cmdErr != nil {
+			_, err = io.WriteString(output, captured.String())
+			return cmdErr, err
+		}
+
+		lines := strings.Split(strings.TrimSuffix(captured.String(), "\n"), "\n")
+		value = strings.Join(lines[:len(lines)-1], " ") // without the trailing Done
+	} else {
+		var err error
+		if value, err = rt.expandVars(value); err != nil {
+			return rt.scriptError(output, err)
+		}
+	}
+
+	rt.vars[name] = value
+	_, err = io.WriteString(output, "Done\n")
+	return nil, err
+}
+
+// expandVars replaces the variables `$<name>` and `${<name>}` in the line with their values.
+func (rt *CmdRunner) expandVars(line string) (string, error) {
+	var err error
+
+	line = scriptVarPat.ReplaceAllStringFunc(line, func(s string) string {
+		subs := scriptVarPat.FindStringSubmatch(s)
+		name := subs[1] + subs[2]
+
+		value, ok := rt.vars[name]
+		if !ok && err == nil {
+			err = errors.Errorf("undefined variable: %s", name)
+		}
+		return value
+	})
+
+	return line, err
+}
+
+func (rt *CmdRunner) scriptError(output io.Writer, lineErr error) (cmdErr error, err error) {
+	return lineErr, rt.outputError(output, lineErr)
+}
+
+// outputError outputs the error of a command. Errors of the lines of a sourced file are not output, because the
+// source command outputs them with the file and line.
+func (rt *CmdRunner) outputError(output io.Writer, cmdErr error) error {
+	if rt.sourceDepth > 0 {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(output, "Error: %v\n", cmdErr)
+	return err
+}
+
+func (rt *CmdRunner) executeSource(cc *CommandContext, cmd *SourceCmd) {
+	if rt.sourceDepth >= maxSourceDepth {
+		cc.errorf("source nested too deeply")
+		return
+	}
+
+	data, err := ioutil.ReadFile(cmd.File)
+	if err != nil {
+		cc.error(err)
+		return
+	}
+
+	rt.sourceDepth++
+	defer func() {
+		rt.sourceDepth--
+	}()
+
+	var block *blockReader
+	lines := strings.Split(string(data), "\n")
+
+	for i, line := range lines {
+		if rt.ctx.Err() != nil {
+			return
+		}
+
+		cmdErr, err := rt.runScriptLine(line, cc.output, &block)
+		if err != nil {
+			cc.error(err)
+			return
+		}
+		if cmdErr != nil {
+			cc.error(errors.Wrapf(cmdErr, "%s:%d", cmd.File, i+1))
+			return
+		}
+	}
+
+	if block != nil {
+		cc.errorf("%s: %s: missing end", cmd.File, block.header)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openthread/ot-ns/cli/runcli"
+	"github.com/openthread/ot-ns/progctx"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newScriptTestRunner() *CmdRunner {
+	return &CmdRunner{
+		ctx:           progctx.New(context.Background()),
+		contextNodeId: InvalidNodeId,
+		vars:          map[string]string{},
+	}
+}
+
+func runScriptTestLines(rt *CmdRunner, lines ...string) string {
+	var output strings.Builder
+	for _, line := range lines {
+		_ = rt.HandleCommand(line, &output)
+	}
+	return output.String()
+}
+
+func TestScriptVars(t *testing.T) {
+	rt := newScriptTestRunner()
+
+	assert.Equal(t, "Done\nDone\nhello 3\nDone\n", runScriptTestLines(rt,
+		"set n 3", "set s hello", `debug echo "$s ${n}"`))
+	assert.Equal(t, "Done\n", runScriptTestLines(rt, `set x $(debug echo "captured $n")`))
+	assert.Equal(t, "captured 3", rt.vars["x"])
+
+	assert.Equal(t, "Error: undefined variable: y\n", runScriptTestLines(rt, `debug echo "$y"`))
+	assert.Equal(t, "Error: invalid variable name: 1x\n", runScriptTestLines(rt, "set 1x 1"))
+	assert.Equal(t, "Error: debug failed\n", runScriptTestLines(rt, "set x $(debug fail)"))
+	assert.Equal(t, "captured 3", rt.vars["x"])
+}
+
+func TestScriptBlocks(t *testing.T) {
+	rt := newScriptTestRunner()
+
+	assert.Equal(t, "", runScriptTestLines(rt, "for i in 1..3", `debug echo "i=$i"`))
+	assert.Equal(t, BlockPrompt, rt.GetPrompt())
+	assert.Equal(t, "i=1\nDone\ni=2\nDone\ni=3\nDone\n", runScriptTestLines(rt, "end"))
+	assert.Equal(t, Prompt, rt.GetPrompt())
+
+	assert.Equal(t, "a1\nDone\na2\nDone\na1\nDone\na2\nDone\n", runScriptTestLines(rt,
+		"repeat 2", "for x in a1 a2", `debug echo "$x"`, "end", "end"))
+	assert.Equal(t, "", runScriptTestLines(rt, "repeat 0", `debug echo "never"`, "end"))
+
+	// a failed line stops the block
+	assert.Equal(t, "1\nDone\nError: debug failed\n", runScriptTestLines(rt,
+		"for i in 1..3", `debug echo "$i"`, "debug fail", "end"))
+
+	assert.Equal(t, "Error: end without for or repeat\n", runScriptTestLines(rt, "end"))
+	assert.Equal(t, "Error: invalid repeat: repeat x\n", runScriptTestLines(rt, "repeat x", "end"))
+	assert.Equal(t, "Error: invalid for: for i 1..3\n", runScriptTestLines(rt, "for i 1..3", "end"))
+}
+
+func TestScriptSleep(t *testing.T) {
+	rt := newScriptTestRunner()
+	rt.contextNodeId = 1
+
+	// sleep is a simulator command even in a node context
+	assert.Contains(t, runScriptTestLines(rt, "sleep x"), `unexpected token "x"`)
+}
+
+func TestSource(t *testing.T) {
+	rt := newScriptTestRunner()
+
+	dir, err := ioutil.TempDir("", "otns-script")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	script := filepath.Join(dir, "test.otns")
+	assert.Nil(t, ioutil.WriteFile(script, []byte("# comment\nset n 2\n\nrepeat $n\ndebug echo \"x\"\nend\n"), 0644))
+	assert.Equal(t, "Done\nx\nDone\nx\nDone\nDone\n", runScriptTestLines(rt, fmt.Sprintf("source %q", script)))
+
+	assert.Nil(t, ioutil.WriteFile(script, []byte("debug echo \"x\"\ndebug fail\ndebug echo \"y\"\n"), 0644))
+	assert.Equal(t, fmt.Sprintf("x\nDone\nError: %s:2: debug failed\n", script),
+		runScriptTestLines(rt, fmt.Sprintf("source %q", script)))
+
+	assert.Nil(t, ioutil.WriteFile(script, []byte("repeat 1\n"), 0644))
+	assert.Equal(t, fmt.Sprintf("Error: %s: repeat 1: missing end\n", script),
+		runScriptTestLines(rt, fmt.Sprintf("source %q", script)))
+
+	// a script sourcing itself
+	assert.Nil(t, ioutil.WriteFile(script, []byte(fmt.Sprintf("source %q\n", script)), 0644))
+	assert.Contains(t, runScriptTestLines(rt, fmt.Sprintf("source %q", script)), "source nested too deeply")
+	assert.Equal(t, 0, rt.sourceDepth)
+}
+
+func TestRunScriptFailure(t *testing.T) {
+	rt := newScriptTestRunner()
+
+	dir, err := ioutil.TempDir("", "otns-script")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	script := filepath.Join(dir, "test.otns")
+	assert.Nil(t, ioutil.WriteFile(script, []byte("debug fail\n"), 0644))
+
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	assert.Nil(t, err)
+	defer stdout.Close()
+
+	err = Run(rt, &runcli.CliOptions{Stdin: stdout, Stdout: stdout}, script)
+	assert.IsType(t, &ScriptError{}, err)
+	assert.EqualError(t, err, fmt.Sprintf("script failed: %s:1: debug failed", script))
+}
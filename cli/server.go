@@ -0,0 +1,163 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"net"
+	"os"
+	"sync"
+
+	"github.com/openthread/ot-ns/cli/runcli"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+var errSessionExit = errors.Errorf("session exit")
+
+// Server serves remote CLI sessions over TCP or a Unix socket. Each session has its own node context and variables,
+// and exiting a session does not stop the simulation.
+type Server struct {
+	rt       *CmdRunner
+	listener net.Listener
+
+	lock   sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// NewServer creates a server listening on the address, which is `unix:<path>` or `<host>:<port>`.
+func NewServer(rt *CmdRunner, addr string) (*Server, error) {
+	network, address := runcli.ParseAddr(addr)
+	if network == "unix" {
+		// remove the socket file left by a previous OTNS
+		if fi, err := os.Stat(address); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(address)
+		}
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listen CLI server on %s", addr)
+	}
+
+	return &Server{
+		rt:       rt,
+		listener: listener,
+		conns:    map[net.Conn]struct{}{},
+	}, nil
+}
+
+// Addr returns the listening address of the server.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Serve accepts the clients and runs their sessions, until the server is closed.
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return nil
+			}
+			return err
+		}
+
+		if !s.addConn(conn) {
+			_ = conn.Close()
+			return nil
+		}
+
+		go s.serveConn(conn)
+	}
+}
+
+// Close stops accepting clients and disconnects all sessions.
+func (s *Server) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	return s.listener.Close()
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.removeConn(conn)
+
+	simplelogger.Infof("CLI session from %s started", conn.RemoteAddr())
+	err := runcli.RunRemoteCli(s.rt.newSession(), conn)
+	if err != nil && err != errSessionExit && !s.isClosed() {
+		simplelogger.Warnf("CLI session from %s failed: %v", conn.RemoteAddr(), err)
+	}
+	simplelogger.Infof("CLI session from %s ended", conn.RemoteAddr())
+}
+
+func (s *Server) addConn(conn net.Conn) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+func (s *Server) removeConn(conn net.Conn) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.conns, conn)
+	_ = conn.Close()
+}
+
+func (s *Server) isClosed() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.closed
+}
+
+// newSession returns a CmdRunner for a remote CLI session, which shares the simulation but has its own node context
+// and variables.
+func (rt *CmdRunner) newSession() *CmdRunner {
+	return &CmdRunner{
+		sim:           rt.sim,
+		ctx:           rt.ctx,
+		contextNodeId: InvalidNodeId,
+		vars:          map[string]string{},
+		session:       true,
+	}
+}
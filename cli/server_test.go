@@ -0,0 +1,134 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package cli
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRemoteClient struct {
+	t      *testing.T
+	conn   net.Conn
+	output strings.Builder
+}
+
+func newTestRemoteClient(t *testing.T, addr net.Addr) *testRemoteClient {
+	conn, err := net.Dial(addr.Network(), addr.String())
+	assert.Nil(t, err)
+
+	c := &testRemoteClient{t: t, conn: conn}
+	// report a non-terminal client of 80 columns
+	c.send(readline.T_ISTTY_REPORT, []byte{0, 0})
+	width := make([]byte, 2)
+	binary.BigEndian.PutUint16(width, 80)
+	c.send(readline.T_WIDTH_REPORT, width)
+	return c
+}
+
+func (c *testRemoteClient) send(t readline.MsgType, data []byte) {
+	_, err := readline.NewMessage(t, data).WriteTo(c.conn)
+	assert.Nil(c.t, err)
+}
+
+// run sends the command line, and returns the output until the line ending with suffix.
+func (c *testRemoteClient) run(line string, suffix string) string {
+	c.send(readline.T_DATA, []byte(line+"\n"))
+
+	_ = c.conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	for !strings.Contains(c.output.String(), suffix) {
+		msg, err := readline.ReadMessage(c.conn)
+		if !assert.Nil(c.t, err) {
+			break
+		}
+		if msg.Type == readline.T_DATA {
+			c.output.Write(msg.Data)
+		}
+	}
+
+	output := c.output.String()
+	c.output.Reset()
+	return output
+}
+
+// waitClosed reads the remaining messages, and returns true if the server closes the connection.
+func (c *testRemoteClient) waitClosed() bool {
+	_ = c.conn.SetReadDeadline(time.Now().Add(time.Second * 5))
+	for {
+		if _, err := readline.ReadMessage(c.conn); err != nil {
+			netErr, ok := err.(net.Error)
+			return !ok || !netErr.Timeout()
+		}
+	}
+}
+
+func TestServerSessions(t *testing.T) {
+	server, err := NewServer(newScriptTestRunner(), "localhost:0")
+	assert.Nil(t, err)
+	served := make(chan error)
+	go func() {
+		served <- server.Serve()
+	}()
+
+	c1 := newTestRemoteClient(t, server.Addr())
+	c2 := newTestRemoteClient(t, server.Addr())
+
+	assert.Contains(t, c1.run("set x 1", "Done\n"), "Done\n")
+	assert.Contains(t, c1.run(`debug echo "x=$x"`, "Done\n"), "x=1\n")
+	// the variables are per session
+	assert.Contains(t, c2.run(`debug echo "x=$x"`, "Error"), "Error: undefined variable: x")
+	assert.Contains(t, c2.run("set x 2", "Done\n"), "Done\n")
+	assert.Contains(t, c1.run(`debug echo "x=$x"`, "Done\n"), "x=1\n")
+
+	// exit ends the session only
+	assert.Contains(t, c1.run("exit", "Done\n"), "Done\n")
+	assert.True(t, c1.waitClosed())
+	assert.Contains(t, c2.run(`debug echo "x=$x"`, "Done\n"), "x=2\n")
+
+	assert.Nil(t, server.Close())
+	assert.Nil(t, <-served)
+	assert.True(t, c2.waitClosed())
+}
+
+func TestServerUnixSocket(t *testing.T) {
+	server, err := NewServer(newScriptTestRunner(), "unix:otns_cli_test.sock")
+	assert.Nil(t, err)
+	assert.Equal(t, "unix", server.Addr().Network())
+	go func() {
+		_ = server.Serve()
+	}()
+
+	c := newTestRemoteClient(t, server.Addr())
+	assert.Contains(t, c.run(`debug echo "hello"`, "Done\n"), "hello\n")
+	assert.Nil(t, server.Close())
+}
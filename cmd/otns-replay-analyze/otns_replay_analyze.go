@@ -0,0 +1,165 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/openthread/ot-ns/visualize/grpc/replay"
+	"github.com/openthread/ot-ns/visualize/grpc/replay/analysis"
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+var queries = []struct {
+	name  string
+	usage string
+}{
+	{"roles", "node role timeline"},
+	{"partitions", "node partition ID timeline"},
+	{"converge", "periods when the network was in a single partition"},
+	{"failures", "node failures and recoveries"},
+	{"frames", "frame counts per node"},
+	{"topology", "state of all nodes at the time set by -at"},
+}
+
+var args struct {
+	Query      string
+	ReplayFile string
+	Format     string
+	Timeline   string
+	At         time.Duration
+	Output     string
+}
+
+func parseArgs() {
+	flag.StringVar(&args.Format, "format", "csv", "set the output format (csv|json)")
+	flag.StringVar(&args.Timeline, "timeline", "wall", "set the time of the events to the wall-clock time or the virtual time of the simulation (wall|virtual)")
+	flag.DurationVar(&args.At, "at", 0, "set the time of the topology query (e.g. 90s), default to the end of the replay")
+	flag.StringVar(&args.Output, "o", "", "write the output to the file instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <query> <replay>\n\nQueries:\n", os.Args[0])
+		for _, q := range queries {
+			fmt.Fprintf(flag.CommandLine.Output(), "  %-12s%s\n", q.name, q.usage)
+		}
+		fmt.Fprintf(flag.CommandLine.Output(), "\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if len(flag.Args()) != 2 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	args.Query = flag.Arg(0)
+	args.ReplayFile = flag.Arg(1)
+
+	for _, q := range queries {
+		if q.name == args.Query {
+			return
+		}
+	}
+
+	fmt.Fprintf(flag.CommandLine.Output(), "unknown query: %s\n", args.Query)
+	flag.Usage()
+	os.Exit(1)
+}
+
+func main() {
+	parseArgs()
+	simplelogger.SetLevel(simplelogger.InfoLevel)
+
+	format, err := analysis.ParseOutputFormat(args.Format)
+	simplelogger.FatalIfError(err)
+
+	timeline, err := replay.ParseTimeline(args.Timeline)
+	simplelogger.FatalIfError(err)
+
+	reader, err := replay.OpenReader(args.ReplayFile)
+	simplelogger.FatalIfError(err)
+	defer reader.Close()
+
+	err = reader.SetTimeline(timeline, 0)
+	simplelogger.FatalIfError(err)
+
+	result, err := runQuery(reader, args.Query)
+	simplelogger.FatalIfError(err)
+
+	var w io.Writer = os.Stdout
+	if args.Output != "" {
+		f, err := os.Create(args.Output)
+		simplelogger.FatalIfError(err)
+		defer f.Close()
+		w = f
+	}
+
+	err = analysis.Write(w, result, format)
+	simplelogger.FatalIfError(err)
+}
+
+func runQuery(reader *replay.Reader, query string) (analysis.Result, error) {
+	if query == "topology" && args.At > 0 {
+		a, err := analysis.AnalyzeUntil(reader, uint64(args.At/time.Microsecond))
+		if err != nil {
+			return nil, err
+		}
+		return a.Topology(), nil
+	}
+
+	a, err := analysis.Analyze(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	switch query {
+	case "roles":
+		return a.Roles(), nil
+	case "partitions":
+		return a.Partitions(), nil
+	case "converge":
+		if converged := a.Converged(); converged != nil {
+			simplelogger.Infof("network converged to a single partition at %v (virtual time %v)",
+				time.Duration(converged.Time)*time.Microsecond, time.Duration(converged.VirtualTime)*time.Microsecond)
+		} else {
+			simplelogger.Infof("network is not in a single partition at the end of the replay")
+		}
+		return a.Convergences(), nil
+	case "failures":
+		return a.Failures(), nil
+	case "frames":
+		return a.Frames(), nil
+	case "topology":
+		return a.Topology(), nil
+	default:
+		return nil, errors.Errorf("unknown query: %s", query)
+	}
+}
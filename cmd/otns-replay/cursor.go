@@ -0,0 +1,491 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/openthread/ot-ns/visualize/grpc/replay"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxReplaySpeed plays the replay as fast as possible (same as the max speed of the web UI).
+	maxReplaySpeed = 1000000
+)
+
+var (
+	heartbeatEvent = &pb.VisualizeEvent{
+		Type: &pb.VisualizeEvent_Heartbeat{Heartbeat: &pb.HeartbeatEvent{}},
+	}
+)
+
+// timelineOptions selects the timeline of the replay played by the cursors.
+type timelineOptions struct {
+	timeline replay.Timeline
+	// maxGap compresses wall-clock gaps longer than maxGap (in us), 0 to keep all gaps
+	maxGap uint64
+}
+
+type cursorCommandResult struct {
+	output []string
+	err    error
+}
+
+type cursorCommand struct {
+	cmd  string
+	done chan cursorCommandResult
+}
+
+// replayCursor plays a replay file to one visualization client.
+// Each client has its own cursor, so that it can be paused, sped up or seeked independently.
+type replayCursor struct {
+	id      int
+	stream  pb.VisualizeGrpcService_VisualizeServer
+	cmdChan chan *cursorCommand
+	done    chan struct{}
+
+	reader *replay.Reader
+	next   *pb.ReplayEntry
+	eof    bool
+	speed  float64
+	paused bool
+	// the replay time `pos` (in us) is played at real time `anchor`
+	pos    uint64
+	anchor time.Time
+	// the timestamp and virtual time of the last played entry
+	played        uint64
+	playedVirtual uint64
+	// nodes added by the played events, which are deleted when jumping to a keyframe or to the beginning
+	nodes map[int32]struct{}
+	// the last advance time event skipped during seeking
+	skippedAdvanceTime *pb.VisualizeEvent
+}
+
+func newReplayCursor(id int, replayFile string, timeline timelineOptions, stream pb.VisualizeGrpcService_VisualizeServer) (*replayCursor, error) {
+	reader, err := replay.OpenReader(replayFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = reader.SetTimeline(timeline.timeline, timeline.maxGap); err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+
+	return &replayCursor{
+		id:      id,
+		stream:  stream,
+		cmdChan: make(chan *cursorCommand),
+		done:    make(chan struct{}),
+		reader:  reader,
+		speed:   1,
+		nodes:   map[int32]struct{}{},
+	}, nil
+}
+
+// run plays the replay until the client disconnects.
+func (c *replayCursor) run(ctx context.Context) error {
+	defer close(c.done)
+	defer c.reader.Close()
+
+	heartbeatTicker := time.NewTicker(time.Second)
+	defer heartbeatTicker.Stop()
+
+	c.anchor = time.Now()
+	if err := c.sendSpeed(); err != nil {
+		return err
+	}
+
+	for {
+		var playTimer <-chan time.Time
+
+		if !c.paused {
+			next, err := c.peek()
+			if err != nil {
+				return err
+			}
+
+			if next != nil {
+				playTimer = time.After(c.realDelay(next.Timestamp))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeatTicker.C:
+			if err := c.stream.Send(heartbeatEvent); err != nil {
+				return err
+			}
+		case cmd := <-c.cmdChan:
+			output, err := c.execute(cmd.cmd)
+			cmd.done <- cursorCommandResult{output: output, err: err}
+		case <-playTimer:
+			if err := c.play(c.next, false); err != nil {
+				return err
+			}
+			c.next = nil
+		}
+	}
+}
+
+// command runs the command on the cursor and returns the output.
+func (c *replayCursor) command(ctx context.Context, cmd string) ([]string, error) {
+	req := &cursorCommand{cmd: cmd, done: make(chan cursorCommandResult, 1)}
+
+	select {
+	case c.cmdChan <- req:
+	case <-c.done:
+		return nil, errors.Errorf("replay client %d disconnected", c.id)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	res := <-req.done
+	return res.output, res.err
+}
+
+// execute runs a replay command. `speed [<speed>]` shows or sets the speed (speed 0 pauses, a positive speed resumes),
+// `pause` and `resume` pause or resume the playback, `seek <time>` seeks to the time (e.g. `90s`, `1h20m`, or relatively
+// if prefixed by `+` or `-`), `step` pauses and plays until the next event, and `restart` seeks to the beginning.
+func (c *replayCursor) execute(cmd string) ([]string, error) {
+	var err error
+
+	args := strings.Fields(cmd)
+	if len(args) == 0 {
+		return nil, errors.Errorf("empty command")
+	}
+
+	switch args[0] {
+	case "speed":
+		if len(args) > 1 {
+			err = c.setSpeed(args[1])
+		}
+	case "pause":
+		c.setPaused(true)
+		err = c.sendSpeed()
+	case "resume":
+		c.setPaused(false)
+		err = c.sendSpeed()
+	case "seek":
+		if len(args) < 2 {
+			return nil, errors.Errorf("seek: missing time")
+		}
+
+		var target uint64
+		if target, err = parseSeekTime(args[1], c.clock()); err == nil {
+			err = c.seek(target)
+		}
+	case "step":
+		err = c.step()
+	case "restart":
+		err = c.seek(0)
+	default:
+		err = errors.Errorf("unknown replay command: %s", args[0])
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{c.String()}, nil
+}
+
+func (c *replayCursor) String() string {
+	state := "playing"
+	if c.paused {
+		state = "paused"
+	} else if c.eof && c.next == nil {
+		state = "finished"
+	}
+
+	return fmt.Sprintf("replay %d: time %v, virtual time %v, speed %v, %s", c.id, time.Duration(c.clock())*time.Microsecond,
+		time.Duration(c.playedVirtual)*time.Microsecond, c.speed, state)
+}
+
+// clock returns the current replay time.
+func (c *replayCursor) clock() uint64 {
+	if c.paused {
+		return c.pos
+	}
+
+	return c.pos + uint64(float64(time.Since(c.anchor)/time.Microsecond)*c.speed)
+}
+
+// rebase restarts the clock from the current replay time, which is required before changing the speed.
+func (c *replayCursor) rebase() {
+	c.pos = c.clock()
+	c.anchor = time.Now()
+}
+
+func (c *replayCursor) realDelay(timestamp uint64) time.Duration {
+	now := c.clock()
+	if timestamp <= now || c.speed >= maxReplaySpeed {
+		return 0
+	}
+
+	return time.Duration(float64(timestamp-now)/c.speed) * time.Microsecond
+}
+
+func (c *replayCursor) setSpeed(s string) error {
+	speed, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+
+	// the web UI pauses by setting the speed to 0 and resumes by setting it back
+	if speed <= 0 {
+		c.setPaused(true)
+	} else {
+		if speed > maxReplaySpeed {
+			speed = maxReplaySpeed
+		}
+
+		c.rebase()
+		c.speed = speed
+		c.setPaused(false)
+	}
+
+	return c.sendSpeed()
+}
+
+func (c *replayCursor) setPaused(paused bool) {
+	c.rebase()
+	c.paused = paused
+}
+
+func (c *replayCursor) sendSpeed() error {
+	speed := c.speed
+	if c.paused {
+		speed = 0
+	}
+
+	return c.stream.Send(&pb.VisualizeEvent{
+		Type: &pb.VisualizeEvent_SetSpeed{SetSpeed: &pb.SetSpeedEvent{Speed: speed}},
+	})
+}
+
+// peek returns the next entry to play without consuming it, or nil at the end of the replay.
+func (c *replayCursor) peek() (*pb.ReplayEntry, error) {
+	if c.next == nil && !c.eof {
+		entry, err := c.reader.Next()
+		if err == io.EOF {
+			c.eof = true
+		} else if err != nil {
+			return nil, err
+		} else {
+			c.next = entry
+		}
+	}
+
+	return c.next, nil
+}
+
+// play sends the event of the entry to the client.
+// Animations are skipped if the cursor is seeking, and only the last advance time event is sent.
+func (c *replayCursor) play(entry *pb.ReplayEntry, seeking bool) error {
+	c.played = entry.Timestamp
+	c.playedVirtual = entry.VirtualTime
+
+	if entry.Keyframe != nil {
+		// the state in the keyframe is already built by the played events
+		return nil
+	}
+
+	switch event := entry.Event.Type.(type) {
+	case *pb.VisualizeEvent_SetSpeed, *pb.VisualizeEvent_Heartbeat:
+		// the speed of the replay is controlled by the cursor
+		return nil
+	case *pb.VisualizeEvent_AddNode:
+		c.nodes[event.AddNode.NodeId] = struct{}{}
+	case *pb.VisualizeEvent_DeleteNode:
+		delete(c.nodes, event.DeleteNode.NodeId)
+	case *pb.VisualizeEvent_AdvanceTime:
+		if seeking {
+			c.skippedAdvanceTime = entry.Event
+			return nil
+		}
+	case *pb.VisualizeEvent_Send, *pb.VisualizeEvent_CountDown:
+		if seeking {
+			return nil
+		}
+	}
+
+	return c.stream.Send(entry.Event)
+}
+
+// seek moves the cursor to the replay time `target`.
+// The cursor jumps to the nearest keyframe before the target if it seeks backward or if it can skip events,
+// and then plays the events after the keyframe until the target.
+func (c *replayCursor) seek(target uint64) error {
+	keyframe, err := c.reader.FindKeyframe(target)
+	if err != nil {
+		return err
+	}
+
+	if target < c.played || (keyframe != nil && keyframe.Timestamp > c.played) {
+		if err = c.clear(); err != nil {
+			return err
+		}
+
+		if keyframe != nil {
+			if err = c.playKeyframe(keyframe); err != nil {
+				return err
+			}
+		} else if err = c.reader.Rewind(); err != nil {
+			return err
+		}
+	}
+
+	for {
+		next, err := c.peek()
+		if err != nil {
+			return err
+		}
+
+		if next == nil || next.Timestamp > target {
+			break
+		}
+
+		if err = c.play(next, true); err != nil {
+			return err
+		}
+		c.next = nil
+	}
+
+	if c.skippedAdvanceTime != nil {
+		if err := c.stream.Send(c.skippedAdvanceTime); err != nil {
+			return err
+		}
+		c.skippedAdvanceTime = nil
+	}
+
+	c.pos = target
+	c.anchor = time.Now()
+	return nil
+}
+
+// playKeyframe moves the reader to the keyframe and draws the state in the keyframe.
+func (c *replayCursor) playKeyframe(keyframe *pb.ReplayIndexEntry) error {
+	entry, err := c.reader.SeekKeyframe(keyframe)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range entry.Keyframe.Events {
+		if err = c.play(&pb.ReplayEntry{Timestamp: entry.Timestamp, VirtualTime: entry.VirtualTime, Event: event}, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// clear deletes all nodes from the client and forgets the entries read ahead.
+func (c *replayCursor) clear() error {
+	for nodeid := range c.nodes {
+		if err := c.stream.Send(&pb.VisualizeEvent{
+			Type: &pb.VisualizeEvent_DeleteNode{DeleteNode: &pb.DeleteNodeEvent{NodeId: nodeid}},
+		}); err != nil {
+			return err
+		}
+	}
+
+	c.next = nil
+	c.eof = false
+	c.played = 0
+	c.playedVirtual = 0
+	c.nodes = map[int32]struct{}{}
+	return nil
+}
+
+// step pauses the cursor and plays until the next event (keyframes, advance time, speed and heartbeat events are not counted).
+func (c *replayCursor) step() error {
+	c.setPaused(true)
+
+	for {
+		next, err := c.peek()
+		if err != nil {
+			return err
+		}
+
+		if next == nil {
+			break
+		}
+
+		if err = c.play(next, false); err != nil {
+			return err
+		}
+		c.next = nil
+		c.pos = next.Timestamp
+
+		if next.Keyframe != nil {
+			continue
+		}
+
+		switch next.Event.Type.(type) {
+		case *pb.VisualizeEvent_AdvanceTime, *pb.VisualizeEvent_SetSpeed, *pb.VisualizeEvent_Heartbeat:
+			continue
+		}
+		break
+	}
+
+	return c.sendSpeed()
+}
+
+// parseSeekTime parses the seek time as a duration (e.g. `90s`, `1h20m`) or as seconds.
+// The time is relative to the current replay time `cur` if it starts with `+` or `-`.
+func parseSeekTime(s string, cur uint64) (uint64, error) {
+	relative := strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-")
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		seconds, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil {
+			return 0, errors.Errorf("invalid seek time: %s", s)
+		}
+		d = time.Duration(seconds * float64(time.Second))
+	}
+
+	us := int64(d / time.Microsecond)
+	if relative {
+		us += int64(cur)
+	}
+
+	if us < 0 {
+		us = 0
+	}
+
+	return uint64(us), nil
+}
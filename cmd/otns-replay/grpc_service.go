@@ -27,89 +27,112 @@
 package main
 
 import (
-	"bufio"
 	"context"
-	"os"
-	"time"
+	"strconv"
+	"sync"
 
 	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
 	"github.com/pkg/errors"
 	"github.com/simonlingoogle/go-simplelogger"
-	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/grpc/metadata"
 )
 
-var (
-	unmarshalOptions = prototext.UnmarshalOptions{}
+const (
+	// cursorMetadataKey is the metadata key carrying the replay cursor ID of a visualization client.
+	// It is sent to the client in the header of the Visualize stream, and must be set in Command requests
+	// to select the cursor, so that clients never control each other's playback.
+	cursorMetadataKey = "otns-replay-cursor"
 )
 
 type grpcService struct {
 	replayFile string
+	timeline   timelineOptions
+
+	cursorsLock  sync.Mutex
+	cursors      map[int]*replayCursor
+	nextCursorId int
+}
+
+func newGrpcService(replayFile string, timeline timelineOptions) *grpcService {
+	return &grpcService{
+		replayFile:   replayFile,
+		timeline:     timeline,
+		cursors:      map[int]*replayCursor{},
+		nextCursorId: 1,
+	}
 }
 
 func (gs *grpcService) Visualize(req *pb.VisualizeRequest, stream pb.VisualizeGrpcService_VisualizeServer) error {
 	defer simplelogger.Infof("Visualize finished.")
 
-	heartbeatEvent := &pb.VisualizeEvent{
-		Type: &pb.VisualizeEvent_Heartbeat{Heartbeat: &pb.HeartbeatEvent{}},
-	}
+	gs.cursorsLock.Lock()
+	id := gs.nextCursorId
+	gs.nextCursorId += 1
+	gs.cursorsLock.Unlock()
 
-	visualizeDone := make(chan struct{})
-	go gs.visualizeStream(stream, visualizeDone)
-
-	heartbeatTicker := time.NewTicker(time.Second)
-	defer heartbeatTicker.Stop()
-
-waitloop:
-	for {
-		select {
-		case <-heartbeatTicker.C:
-			_ = stream.Send(heartbeatEvent)
-		case <-visualizeDone:
-			break waitloop
-		case <-stream.Context().Done():
-			break waitloop
-		}
+	cursor, err := newReplayCursor(id, gs.replayFile, gs.timeline, stream)
+	if err != nil {
+		return err
 	}
 
-	return nil
-}
-
-func (gs *grpcService) Command(context.Context, *pb.CommandRequest) (*pb.CommandResponse, error) {
-	// TODO: implement some commands for replay (e.g. speed)
-	return nil, errors.Errorf("can not run command on replay")
-}
+	gs.cursorsLock.Lock()
+	gs.cursors[id] = cursor
+	gs.cursorsLock.Unlock()
 
-func (gs *grpcService) visualizeStream(stream pb.VisualizeGrpcService_VisualizeServer, visualizeDone chan struct{}) {
 	defer func() {
-		close(visualizeDone)
-
-		err := recover()
-		if err != nil && stream.Context().Err() == nil {
-			simplelogger.Errorf("visualization error: %v", err)
-		}
+		gs.cursorsLock.Lock()
+		delete(gs.cursors, id)
+		gs.cursorsLock.Unlock()
 	}()
 
-	replay, err := os.Open(gs.replayFile)
-	simplelogger.PanicIfError(err)
+	_ = stream.SendHeader(metadata.Pairs(cursorMetadataKey, strconv.Itoa(id)))
 
-	scanner := bufio.NewScanner(bufio.NewReader(replay))
-	scanner.Split(bufio.ScanLines)
+	err = cursor.run(stream.Context())
+	if err != nil && stream.Context().Err() == nil {
+		simplelogger.Errorf("visualization error: %v", err)
+	}
 
-	startTime := time.Now()
+	return nil
+}
+
+func (gs *grpcService) Command(ctx context.Context, req *pb.CommandRequest) (*pb.CommandResponse, error) {
+	cursor, err := gs.findCursor(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
+	output, err := cursor.command(ctx, req.Command)
+	if err != nil {
+		return nil, err
+	}
 
-		simplelogger.Infof("visualize: %#v", line)
+	return &pb.CommandResponse{
+		Output: output,
+	}, nil
+}
 
-		var entry pb.ReplayEntry
-		err = unmarshalOptions.Unmarshal([]byte(line), &entry)
-		simplelogger.PanicIfError(err)
+func (gs *grpcService) NodeLogs(req *pb.NodeLogsRequest, stream pb.VisualizeGrpcService_NodeLogsServer) error {
+	return errors.Errorf("node logs are not recorded in replays")
+}
 
-		playTime := startTime.Add(time.Duration(entry.Timestamp) * time.Microsecond)
-		time.Sleep(time.Until(playTime))
+func (gs *grpcService) findCursor(ctx context.Context) (*replayCursor, error) {
+	gs.cursorsLock.Lock()
+	defer gs.cursorsLock.Unlock()
 
-		err = stream.Send(entry.Event)
-		simplelogger.PanicIfError(err)
+	md, _ := metadata.FromIncomingContext(ctx)
+	if len(md.Get(cursorMetadataKey)) == 0 {
+		return nil, errors.Errorf("missing %s metadata", cursorMetadataKey)
 	}
+
+	id, err := strconv.Atoi(md.Get(cursorMetadataKey)[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", cursorMetadataKey)
+	}
+
+	cursor := gs.cursors[id]
+	if cursor == nil {
+		return nil, errors.Errorf("replay client %d not found", id)
+	}
+
+	return cursor, nil
 }
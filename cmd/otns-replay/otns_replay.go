@@ -31,9 +31,11 @@ import (
 	"flag"
 	"net"
 	"os"
+	"time"
 
 	"github.com/openthread/ot-ns/progctx"
 	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/openthread/ot-ns/visualize/grpc/replay"
 	"github.com/openthread/ot-ns/web"
 	webSite "github.com/openthread/ot-ns/web/site"
 	"github.com/simonlingoogle/go-simplelogger"
@@ -42,9 +44,19 @@ import (
 
 var args struct {
 	ReplayFile string
+	Convert    string
+	Format     string
+	Compress   string
+	Timeline   string
+	MaxGap     time.Duration
 }
 
 func parseArgs() {
+	flag.StringVar(&args.Convert, "convert", "", "convert the replay to the specified file instead of playing it")
+	flag.StringVar(&args.Format, "format", "binary", "set the format of the converted replay (text|binary)")
+	flag.StringVar(&args.Compress, "compress", "none", "set the compression of the converted replay (none|gzip|zstd)")
+	flag.StringVar(&args.Timeline, "timeline", "wall", "play the replay in wall-clock time or in the virtual time of the simulation (wall|virtual)")
+	flag.DurationVar(&args.MaxGap, "max-gap", 0, "compress wall-clock gaps between events longer than the duration (e.g. 1s), 0 to keep all gaps")
 	flag.Parse()
 
 	if len(flag.Args()) != 1 {
@@ -57,13 +69,18 @@ func parseArgs() {
 
 func main() {
 	parseArgs()
-	checkReplayFile(args.ReplayFile)
 	simplelogger.SetLevel(simplelogger.InfoLevel)
+	checkReplayFile(args.ReplayFile)
+
+	if args.Convert != "" {
+		convertReplay(args.ReplayFile, args.Convert)
+		return
+	}
 
 	ctx := progctx.New(context.Background())
 
 	server := grpc.NewServer(grpc.ReadBufferSize(1024*8), grpc.WriteBufferSize(1024*1024*1))
-	gs := &grpcService{replayFile: args.ReplayFile}
+	gs := newGrpcService(args.ReplayFile, parseTimelineOptions())
 	pb.RegisterVisualizeGrpcServiceServer(server, gs)
 
 	lis, err := net.Listen("tcp", ":8999")
@@ -84,6 +101,38 @@ func main() {
 	simplelogger.Errorf("server quit: %v", err)
 }
 
+func parseTimelineOptions() timelineOptions {
+	timeline, err := replay.ParseTimeline(args.Timeline)
+	simplelogger.FatalIfError(err)
+
+	if args.MaxGap < 0 {
+		simplelogger.Fatalf("invalid max gap: %v", args.MaxGap)
+	}
+
+	if timeline != replay.TimelineWall && args.MaxGap > 0 {
+		simplelogger.Warnf("max gap is ignored on the %v timeline", timeline)
+	}
+
+	return timelineOptions{
+		timeline: timeline,
+		maxGap:   uint64(args.MaxGap / time.Microsecond),
+	}
+}
+
+func convertReplay(src string, dst string) {
+	var err error
+
+	options := replay.DefaultOptions()
+	options.Format, err = replay.ParseFormat(args.Format)
+	simplelogger.FatalIfError(err)
+	options.Compression, err = replay.ParseCompression(args.Compress)
+	simplelogger.FatalIfError(err)
+
+	err = replay.Convert(src, dst, options)
+	simplelogger.FatalIfError(err)
+	simplelogger.Infof("converted %s to %s (format %v, compression %v)", src, dst, options.Format, options.Compression)
+}
+
 func checkReplayFile(filename string) {
 	f, err := os.Open(filename)
 	simplelogger.PanicIfError(err)
@@ -95,4 +144,13 @@ func checkReplayFile(filename string) {
 	if fs.IsDir() {
 		simplelogger.Panicf("%s is not a valid replay", filename)
 	}
+
+	reader, err := replay.OpenReader(filename)
+	simplelogger.PanicIfError(err)
+	defer reader.Close()
+
+	simplelogger.Infof("replay %s: format %v, compression %v", filename, reader.Format(), reader.Compression())
+	if header := reader.Header(); header != nil {
+		simplelogger.Infof("replay %s: OTNS version %s, seed %d, network info %v", filename, header.OtnsVersion, header.Seed, header.NetworkInfo)
+	}
 }
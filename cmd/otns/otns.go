@@ -37,8 +37,8 @@ import (
 
 func main() {
 	ctx := progctx.New(context.Background())
-	otns_main.Main(ctx, func(ctx *progctx.ProgCtx, args *otns_main.MainArgs) visualize.Visualizer {
+	exitCode := otns_main.Main(ctx, func(ctx *progctx.ProgCtx, args *otns_main.MainArgs) visualize.Visualizer {
 		return nil
 	}, nil)
-	os.Exit(0)
+	os.Exit(exitCode)
 }
@@ -32,27 +32,17 @@ import (
 	"math"
 	"net"
 
+	"github.com/openthread/ot-ns/dissectpkt/mle"
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
 	"github.com/openthread/ot-ns/threadconst"
 	. "github.com/openthread/ot-ns/types"
 	"github.com/simonlingoogle/go-simplelogger"
 )
 
 const (
-	maxPingResultCount = 1000
 	maxJoinResultCount = 1000
 )
 
-type pingRequest struct {
-	Timestamp uint64
-	Dst       string
-	DataSize  int
-}
-type PingResult struct {
-	Dst      string
-	DataSize int
-	Delay    uint64
-}
-
 type joinerSession struct {
 	StartTime  uint64
 	JoinedTime uint64
@@ -74,6 +64,22 @@ type Node struct {
 	CreateTime  uint64
 	CurTime     uint64
 	Role        OtDeviceRole
+	// MleTxCounters counts the MLE messages sent by the node for each command type.
+	MleTxCounters map[mle.CommandType]uint64
+	// MalformedCount counts the malformed events, status pushes and frames sent by the node.
+	MalformedCount uint64
+	// Quarantined is set if the node keeps sending malformed messages, so that its radio and status push events are dropped.
+	Quarantined bool
+	// Crashed is set if the node process has exited unexpectedly.
+	Crashed bool
+	// PoweredOff is set if the node process is shut down until the node is powered on again.
+	PoweredOff bool
+	// Process counts the restarts of the node process, so that crash notifications of earlier processes are ignored.
+	Process int
+	// NetworkState is the network data, leader, key sequence, channel and SRP registrations reported by the node.
+	NetworkState NodeNetworkState
+	// NetworkStatePushed is set if the node pushes its network state, otherwise it is read by the CLI commands of the node.
+	NetworkStatePushed bool
 
 	peerAddr      *net.UDPAddr
 	failureCtrl   *FailureCtrl
@@ -84,6 +90,9 @@ type Node struct {
 	joinerState   OtJoinerState
 	joinerSession *joinerSession
 	joinResults   []*JoinResult
+	malformed     malformedTracker
+	// lastTxSeq is the MAC sequence number of the last non-ack frame sent by the node, or -1 if none.
+	lastTxSeq int
 }
 
 func newNode(d *Dispatcher, nodeid NodeId, x, y int, radioRange int) *Node {
@@ -102,6 +111,10 @@ func newNode(d *Dispatcher, nodeid NodeId, x, y int, radioRange int) *Node {
 		peerAddr:    nil, // peer address will be set when the first event is received
 		radioRange:  radioRange,
 		joinerState: OtJoinerStateIdle,
+
+		MleTxCounters: map[mle.CommandType]uint64{},
+		NetworkState:  NewNodeNetworkState(),
+		lastTxSeq:     -1,
 	}
 
 	nc.failureCtrl = newFailureCtrl(nc, NonFailTime)
@@ -139,6 +152,27 @@ func (node *Node) GetDistanceTo(other *Node) (dist int) {
 	return
 }
 
+// isDown returns if the node has no running process.
+func (node *Node) isDown() bool {
+	return node.Crashed || node.PoweredOff
+}
+
+// countTxFrame counts the MLE message carried by a frame sent by the node.
+// MAC retransmissions reuse the sequence number of the previous frame, so they are not counted again.
+// Fragmented MLE messages are only dissected once reassembled, so they are counted once as well.
+func (node *Node) countTxFrame(frame *wpan.MacFrame, msg *mle.Message) {
+	if frame.FrameControl.FrameType() == wpan.FrameTypeAck {
+		return
+	}
+
+	retry := int(frame.Seq) == node.lastTxSeq
+	node.lastTxSeq = int(frame.Seq)
+
+	if msg != nil && !retry {
+		node.MleTxCounters[msg.Command]++
+	}
+}
+
 func (node *Node) IsFailed() bool {
 	return node.isFailed
 }
@@ -169,59 +203,6 @@ func (node *Node) SetFailTime(failTime FailTime) {
 	node.failureCtrl.SetFailTime(failTime)
 }
 
-func (node *Node) onPingRequest(timestamp uint64, dstaddr string, datasize int) {
-	if datasize < 4 {
-		// if datasize < 4, timestamp is 0, these ping requests are ignored
-		return
-	}
-
-	node.pendingPings = append(node.pendingPings, &pingRequest{
-		Timestamp: timestamp,
-		Dst:       dstaddr,
-		DataSize:  datasize,
-	})
-}
-
-func (node *Node) onPingReply(timestamp uint64, dstaddr string, datasize int, hoplimit int) {
-	if datasize < 4 {
-		// if datasize < 4, timestamp is 0, these ping replies are ignored
-		return
-	}
-	const maxPingDelayUs uint64 = 10 * 1000000
-	var leftPingRequests []*pingRequest
-	for _, req := range node.pendingPings {
-		if req.Timestamp == timestamp && req.Dst == dstaddr {
-			// ping replied
-			node.addPingResult(req.Dst, req.DataSize, node.D.CurTime-req.Timestamp)
-		} else if req.Timestamp+maxPingDelayUs < node.D.CurTime {
-			// ping timeout
-			node.addPingResult(req.Dst, req.DataSize, maxPingDelayUs)
-		} else {
-			leftPingRequests = append(leftPingRequests, req)
-		}
-	}
-
-	node.pendingPings = leftPingRequests
-}
-
-func (node *Node) addPingResult(dst string, datasize int, delay uint64) {
-	node.pingResults = append(node.pingResults, &PingResult{
-		Dst:      dst,
-		DataSize: datasize,
-		Delay:    delay,
-	})
-
-	if len(node.pingResults) > maxPingResultCount {
-		node.pingResults = node.pingResults[1:]
-	}
-}
-
-func (node *Node) CollectPings() []*PingResult {
-	ret := node.pingResults
-	node.pingResults = nil
-	return ret
-}
-
 func (node *Node) CollectJoins() []*JoinResult {
 	ret := node.joinResults
 	node.joinResults = nil
@@ -0,0 +1,61 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/openthread/ot-ns/dissectpkt/mle"
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNode_CountTxFrame(t *testing.T) {
+	_, node := newTestDispatcher()
+	parentRequest := &mle.Message{Command: mle.CommandParentRequest}
+	advertisement := &mle.Message{Command: mle.CommandAdvertisement}
+	dataFrame := wpan.FrameControl(wpan.FrameTypeData)
+	ackFrame := wpan.FrameControl(wpan.FrameTypeAck)
+
+	node.countTxFrame(&wpan.MacFrame{FrameControl: dataFrame, Seq: 10}, parentRequest)
+	// MAC retransmissions of the same frame
+	node.countTxFrame(&wpan.MacFrame{FrameControl: dataFrame, Seq: 10}, parentRequest)
+	node.countTxFrame(&wpan.MacFrame{FrameControl: dataFrame, Seq: 10}, parentRequest)
+	assert.Equal(t, uint64(1), node.MleTxCounters[mle.CommandParentRequest])
+
+	// acks do not reset the retry detection
+	node.countTxFrame(&wpan.MacFrame{FrameControl: ackFrame, Seq: 7}, nil)
+	node.countTxFrame(&wpan.MacFrame{FrameControl: dataFrame, Seq: 10}, parentRequest)
+	assert.Equal(t, uint64(1), node.MleTxCounters[mle.CommandParentRequest])
+
+	// non-MLE frames and new MLE messages
+	node.countTxFrame(&wpan.MacFrame{FrameControl: dataFrame, Seq: 11}, nil)
+	node.countTxFrame(&wpan.MacFrame{FrameControl: dataFrame, Seq: 12}, parentRequest)
+	node.countTxFrame(&wpan.MacFrame{FrameControl: dataFrame, Seq: 13}, advertisement)
+	assert.Equal(t, uint64(2), node.MleTxCounters[mle.CommandParentRequest])
+	assert.Equal(t, uint64(1), node.MleTxCounters[mle.CommandAdvertisement])
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+type crashNotification struct {
+	nodeid  NodeId
+	process int
+	reason  string
+}
+
+// NotifyCrash notifies the dispatcher that the node process has exited unexpectedly, where `process` is the Process
+// of the node when the process was started.
+// It can be called from any goroutine, the crash is handled in the dispatcher routine.
+func (d *Dispatcher) NotifyCrash(nodeid NodeId, process int, reason string) {
+	d.crashChan <- crashNotification{nodeid: nodeid, process: process, reason: reason}
+}
+
+// handleCrash marks the node as crashed, so that the dispatcher stops waiting for its alarms.
+func (d *Dispatcher) handleCrash(crash crashNotification) {
+	node := d.nodes[crash.nodeid]
+	if node == nil || node.Crashed || node.Process != crash.process {
+		// the node was deleted or restarted before the crash is handled
+		return
+	}
+
+	d.Counters.NodeCrashes += 1
+	node.Crashed = true
+	if !d.cfg.Real {
+		d.setSleeping(node.Id)
+	}
+	d.alarmMgr.SetTimestamp(node.Id, Ever)
+	d.setNodeRole(node.Id, OtDeviceRoleDisabled)
+
+	simplelogger.Errorf("node %d crashed: %s", node.Id, crash.reason)
+	d.vis.OnNodeCrash(node.Id, crash.reason)
+	d.cbHandler.OnNodeCrash(node.Id)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleCrash(t *testing.T) {
+	d, node := newTestDispatcher()
+	cb := d.cbHandler.(*testCallbackHandler)
+	d.alarmMgr.SetTimestamp(node.Id, 2000)
+	node.Role = OtDeviceRoleLeader
+
+	d.NotifyCrash(node.Id, 0, "exit status 1")
+	d.NotifyCrash(2, 0, "exit status 1")
+	d.RecvEvents()
+
+	assert.True(t, node.Crashed)
+	assert.Equal(t, []NodeId{1}, cb.crashed)
+	assert.Equal(t, uint64(1), d.Counters.NodeCrashes)
+	assert.Equal(t, 0, d.GetAliveCount())
+	assert.Equal(t, Ever, d.alarmMgr.GetTimestamp(node.Id))
+	assert.Equal(t, OtDeviceRoleDisabled, node.Role)
+
+	// late events of the crashed process are dropped
+	d.handleRecvEvent(&event{NodeId: node.Id, Type: eventTypeAlarmFired, Delay: 10})
+	assert.Equal(t, uint64(0), d.Counters.AlarmEvents)
+
+	// the crashed node does not receive alarms
+	d.CurTime = 3000
+	d.advanceNodeTime(node.Id, d.CurTime, true)
+	assert.Equal(t, 0, d.GetAliveCount())
+
+	// the new process goes to sleep after its first event
+	d.eventChan <- &event{NodeId: node.Id, Type: eventTypeAlarmFired, Delay: 10, SrcAddr: &net.UDPAddr{Port: 9001}}
+	d.RestartNode(node.Id)
+	assert.False(t, node.Crashed)
+	assert.Equal(t, uint64(3000), node.CurTime)
+	assert.NotNil(t, node.peerAddr)
+	assert.Equal(t, uint64(3010), d.alarmMgr.GetTimestamp(node.Id))
+}
+
+func TestHandleCrash_StaleProcess(t *testing.T) {
+	d, node := newTestDispatcher()
+	cb := d.cbHandler.(*testCallbackHandler)
+
+	// the first process crashes, but the node is restarted before the crash is handled
+	d.NotifyCrash(node.Id, 0, "exit status 1")
+	d.PowerOffNode(node.Id, Ever)
+	d.eventChan <- &event{NodeId: node.Id, Type: eventTypeAlarmFired, Delay: 10, SrcAddr: &net.UDPAddr{Port: 9001}}
+	d.RestartNode(node.Id)
+	assert.Equal(t, 1, node.Process)
+	d.RecvEvents()
+	assert.False(t, node.Crashed)
+	assert.Empty(t, cb.crashed)
+	assert.Equal(t, uint64(0), d.Counters.NodeCrashes)
+
+	d.NotifyCrash(node.Id, 1, "exit status 1")
+	d.RecvEvents()
+	assert.True(t, node.Crashed)
+	assert.Equal(t, []NodeId{1}, cb.crashed)
+}
@@ -34,14 +34,17 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/openthread/ot-ns/progctx"
 
 	"github.com/openthread/ot-ns/dissectpkt"
+	"github.com/openthread/ot-ns/dissectpkt/security"
 	"github.com/openthread/ot-ns/dissectpkt/wpan"
 	"github.com/openthread/ot-ns/pcap"
 	"github.com/openthread/ot-ns/threadconst"
 	"github.com/openthread/ot-ns/visualize"
+	"github.com/pkg/errors"
 	"github.com/simonlingoogle/go-simplelogger"
 
 	"math"
@@ -61,8 +64,9 @@ const (
 )
 
 type pcapFrameItem struct {
-	Ustime uint64
-	Data   []byte
+	Ustime    uint64
+	Data      []byte
+	Decrypted bool
 }
 
 type Config struct {
@@ -72,6 +76,7 @@ type Config struct {
 	Port        int
 	DumpPackets bool
 	NoPcap      bool
+	DecryptPcap bool
 }
 
 func DefaultConfig() *Config {
@@ -87,6 +92,10 @@ func DefaultConfig() *Config {
 type CallbackHandler interface {
 	OnNodeFail(nodeid NodeId)
 	OnNodeRecover(nodeid NodeId)
+	// Notifies that the node process has exited unexpectedly.
+	OnNodeCrash(nodeid NodeId)
+	// Notifies that the power-off duration of the node has elapsed.
+	OnNodePowerOn(nodeid NodeId)
 
 	// Notifies that the node's UART was written with data.
 	OnUartWrite(nodeid NodeId, data []byte)
@@ -94,7 +103,11 @@ type CallbackHandler interface {
 
 type goDuration struct {
 	duration time.Duration
-	done     chan struct{}
+	// until is the condition that ends the Go early once it is met, or nil
+	until GoCondition
+	done  chan struct{}
+	// met receives whether until is met when the Go ends
+	met chan bool
 }
 
 type Dispatcher struct {
@@ -108,22 +121,29 @@ type Dispatcher struct {
 	pauseTime             uint64
 	alarmMgr              *alarmMgr
 	sendQueue             *sendQueue
+	scheduledTasks        *taskQueue
 	nodes                 map[NodeId]*Node
 	deletedNodes          map[NodeId]struct{}
 	aliveNodes            map[NodeId]struct{}
 	pcap                  *pcap.File
+	decryptedPcap         *pcap.File
 	pcapFrameChan         chan pcapFrameItem
 	vis                   visualize.Visualizer
 	taskChan              chan func()
+	crashChan             chan crashNotification
 	speed                 float64
 	speedStartRealTime    time.Time
 	speedStartTime        uint64
 	extaddrMap            map[uint64]*Node
 	rloc16Map             rloc16Map
 	goDurationChan        chan goDuration
+	pauseRequested        bool
+	goUntil               GoCondition
+	pingExpiryTime        uint64
 	globalPacketLossRatio float64
 	visOptions            VisualizationOptions
 	coaps                 *coapsHandler
+	dissector             *dissectpkt.Dissector
 
 	Counters struct {
 		// Event counters
@@ -137,6 +157,25 @@ type Dispatcher struct {
 		DispatchByShortAddrSucc uint64
 		DispatchByShortAddrFail uint64
 		DispatchAllInRange      uint64
+		// Frame type counters
+		AckFrames        uint64
+		BeaconFrames     uint64
+		MacCommandFrames uint64
+		MleFrames        uint64
+		TmfFrames        uint64
+		Icmp6Frames      uint64
+		DataFrames       uint64
+		FragmentFrames   uint64
+		EncryptedFrames  uint64
+		UnknownFrames    uint64
+		// Malformed message counters
+		MalformedEvents         uint64
+		MalformedStatusPushes   uint64
+		MalformedFrames         uint64
+		QuarantinedNodes        uint64
+		QuarantineDroppedEvents uint64
+		// Node process counters
+		NodeCrashes uint64
 	}
 	watchingNodes map[NodeId]struct{}
 	stopped       bool
@@ -165,6 +204,7 @@ func NewDispatcher(ctx *progctx.ProgCtx, cfg *Config, cbHandler CallbackHandler)
 		eventChan:          make(chan *event, 10000),
 		alarmMgr:           newAlarmMgr(),
 		sendQueue:          newSendQueue(),
+		scheduledTasks:     newTaskQueue(),
 		nodes:              make(map[NodeId]*Node),
 		deletedNodes:       map[NodeId]struct{}{},
 		aliveNodes:         make(map[NodeId]struct{}),
@@ -175,14 +215,22 @@ func NewDispatcher(ctx *progctx.ProgCtx, cfg *Config, cbHandler CallbackHandler)
 		speedStartRealTime: time.Now(),
 		vis:                vis,
 		taskChan:           make(chan func(), 100),
+		crashChan:          make(chan crashNotification, 100),
 		watchingNodes:      map[NodeId]struct{}{},
 		goDurationChan:     make(chan goDuration, 10),
 		visOptions:         defaultVisualizationOptions(),
+		dissector:          dissectpkt.NewDissector(),
 	}
 	d.speed = d.normalizeSpeed(d.speed)
 	if !d.cfg.NoPcap {
 		d.pcap, err = pcap.NewFile("current.pcap")
 		simplelogger.PanicIfError(err)
+	}
+	if d.cfg.DecryptPcap {
+		d.decryptedPcap, err = pcap.NewFile("current_decrypted.pcap")
+		simplelogger.PanicIfError(err)
+	}
+	if d.pcap != nil || d.decryptedPcap != nil {
 		go d.pcapFrameWriter()
 	}
 
@@ -217,6 +265,20 @@ func (d *Dispatcher) Go(duration time.Duration) <-chan struct{} {
 	return done
 }
 
+// GoUntil runs the simulation for the duration, or until the condition is met. The condition is evaluated in
+// the dispatcher routine before the Go starts and after each event. The returned channel receives whether the
+// condition is met when the Go ends.
+func (d *Dispatcher) GoUntil(cond GoCondition, duration time.Duration) <-chan bool {
+	met := make(chan bool, 1)
+	d.goDurationChan <- goDuration{
+		duration: duration,
+		until:    cond,
+		done:     make(chan struct{}),
+		met:      met,
+	}
+	return met
+}
+
 func (d *Dispatcher) Run() {
 	d.ctx.WaitAdd("dispatcher", 1)
 	defer d.ctx.WaitDone("dispatcher")
@@ -231,13 +293,22 @@ loop:
 		case f := <-d.taskChan:
 			f()
 			break
+		case crash := <-d.crashChan:
+			d.handleCrash(crash)
+			break
 		case duration := <-d.goDurationChan:
+			if duration.until != nil && duration.until(d) {
+				// the condition is met before the Go starts
+				d.finishGo(duration)
+				break
+			}
+
 			// sync the speed start time with the current time
 			if len(d.nodes) == 0 {
 				// no nodes, sleep for a small duration to avoid high cpu
 				d.RecvEvents()
 				time.Sleep(time.Millisecond * 10)
-				close(duration.done)
+				d.finishGo(duration)
 				break
 			}
 
@@ -252,10 +323,12 @@ loop:
 			}
 
 			simplelogger.AssertTrue(d.CurTime <= d.pauseTime)
+			d.goUntil = duration.until
 			d.goUntilPauseTime()
+			d.goUntil = nil
 
 			if d.ctx.Err() != nil {
-				close(duration.done)
+				d.finishGo(duration)
 				break loop
 			}
 
@@ -264,7 +337,10 @@ loop:
 			if d.pcap != nil {
 				_ = d.pcap.Sync()
 			}
-			close(duration.done)
+			if d.decryptedPcap != nil {
+				_ = d.decryptedPcap.Sync()
+			}
+			d.finishGo(duration)
 			break
 		case <-done:
 			break loop
@@ -272,9 +348,18 @@ loop:
 	}
 }
 
+// finishGo notifies that the Go has ended, and whether its condition is met at the current time.
+func (d *Dispatcher) finishGo(duration goDuration) {
+	if duration.met != nil {
+		duration.met <- duration.until(d)
+	}
+	close(duration.done)
+}
+
 func (d *Dispatcher) goUntilPauseTime() {
 	for d.CurTime < d.pauseTime {
 		d.handleTasks()
+		d.handleScheduledTasks()
 
 		if d.ctx.Err() != nil {
 			break
@@ -283,6 +368,19 @@ func (d *Dispatcher) goUntilPauseTime() {
 		d.RecvEvents()
 		d.syncAliveNodes()
 
+		if d.pauseRequested {
+			// end the current Go at the current time
+			d.pauseRequested = false
+			d.pauseTime = d.CurTime
+			break
+		}
+
+		if d.goUntil != nil && d.goUntil(d) {
+			// end the current Go at the time when the condition is met
+			d.pauseTime = d.CurTime
+			break
+		}
+
 		// process the next event
 		goon := d.processNextEvent()
 		simplelogger.AssertTrue(d.CurTime <= d.pauseTime)
@@ -314,6 +412,11 @@ func (d *Dispatcher) handleRecvEvent(evt *event) {
 			d.CurTime, int64(d.nodes[nodeid].CurTime)-int64(d.CurTime), evt.Delay)
 	}
 
+	if evt.err != nil {
+		d.onMalformed(node, malformedEvent, evt.err)
+		return
+	}
+
 	delay := evt.Delay
 	var evtTime uint64
 	if delay >= 2147483647 {
@@ -328,13 +431,27 @@ func (d *Dispatcher) handleRecvEvent(evt *event) {
 		return
 	}
 
+	if node.isDown() {
+		// late events of the exited process are dropped
+		return
+	}
+
+	if node.Quarantined && (evt.Type == eventTypeRadioReceived || evt.Type == eventTypeStatusPush) {
+		// keep processing alarm and UART events of quarantined nodes so that the simulation can go on
+		d.Counters.QuarantineDroppedEvents += 1
+		return
+	}
+
 	switch evt.Type {
 	case eventTypeAlarmFired:
 		d.Counters.AlarmEvents += 1
 		d.setSleeping(nodeid)
 		d.alarmMgr.SetTimestamp(nodeid, evtTime)
 	case eventTypeRadioReceived:
-		simplelogger.AssertTrue(evt.Delay == 1)
+		if evt.Delay != 1 || len(evt.Data) == 0 {
+			d.onMalformed(node, malformedEvent, errors.Errorf("radio event with delay %d and %d bytes of data", evt.Delay, len(evt.Data)))
+			return
+		}
 		d.Counters.RadioEvents += 1
 		d.sendQueue.Add(evtTime, nodeid, evt.Data)
 	case eventTypeStatusPush:
@@ -344,7 +461,7 @@ func (d *Dispatcher) handleRecvEvent(evt *event) {
 		d.Counters.UartWriteEvents += 1
 		d.handleUartWrite(evt.NodeId, evt.Data)
 	default:
-		simplelogger.Panicf("event type not implemented: %v", evt.Type)
+		d.onMalformed(node, malformedEvent, errors.Errorf("event type not implemented: %v", evt.Type))
 	}
 }
 
@@ -362,6 +479,8 @@ loop:
 			case evt := <-d.eventChan:
 				count += 1
 				d.handleRecvEvent(evt)
+			case crash := <-d.crashChan:
+				d.handleCrash(crash)
 			case <-blockTimeout:
 				// timeout
 				break loop
@@ -371,6 +490,8 @@ loop:
 			case evt := <-d.eventChan:
 				count += 1
 				d.handleRecvEvent(evt)
+			case crash := <-d.crashChan:
+				d.handleCrash(crash)
 			default:
 				break loop
 			}
@@ -386,11 +507,15 @@ func (d *Dispatcher) processNextEvent() bool {
 	// we need to wait until all nodes are sleep
 	nextAlarmTime := d.alarmMgr.NextTimestamp()
 	nextSendtime := d.sendQueue.NextTimestamp()
+	nextTaskTime := d.scheduledTasks.NextTimestamp()
 
 	nextEventTime := nextAlarmTime
 	if nextEventTime > nextSendtime {
 		nextEventTime = nextSendtime
 	}
+	if nextEventTime > nextTaskTime {
+		nextEventTime = nextTaskTime
+	}
 
 	// nextEventTime <= d.pauseTime
 	// convert nextEventTime to real time
@@ -434,6 +559,12 @@ func (d *Dispatcher) processNextEvent() bool {
 		return false
 	}
 
+	if nextTaskTime == nextEventTime {
+		// scheduled tasks run in goUntilPauseTime, along with other tasks
+		d.advanceTime(nextTaskTime)
+		return true
+	}
+
 	simplelogger.AssertTrue(nextAlarmTime >= d.CurTime && nextSendtime >= d.CurTime)
 	var procUntilTime uint64
 	if nextAlarmTime <= nextSendtime {
@@ -445,6 +576,9 @@ func (d *Dispatcher) processNextEvent() bool {
 	if procUntilTime > d.pauseTime {
 		procUntilTime = d.pauseTime
 	}
+	if procUntilTime > nextTaskTime {
+		procUntilTime = nextTaskTime
+	}
 
 	for {
 		if nextAlarmTime > procUntilTime && nextSendtime > procUntilTime {
@@ -456,7 +590,11 @@ func (d *Dispatcher) processNextEvent() bool {
 			d.advanceTime(nextAlarmTime)
 			nextAlarm := d.alarmMgr.NextAlarm()
 			simplelogger.AssertNotNil(nextAlarm)
-			d.advanceNodeTime(nextAlarm.NodeId, nextAlarm.Timestamp, false)
+			if d.nodes[nextAlarm.NodeId].PoweredOff {
+				d.onPowerOnTime(nextAlarm.NodeId)
+			} else {
+				d.advanceNodeTime(nextAlarm.NodeId, nextAlarm.Timestamp, false)
+			}
 			// mark the node as alive in the alarm
 		} else {
 			// process the send event
@@ -465,7 +603,7 @@ func (d *Dispatcher) processNextEvent() bool {
 			d.advanceTime(nextSendtime)
 			// construct the message
 			if !d.cfg.NoPcap {
-				d.pcapFrameChan <- pcapFrameItem{nextSendtime, s.Data[1:]}
+				d.pcapFrameChan <- pcapFrameItem{nextSendtime, s.Data[1:], false}
 			}
 			if d.cfg.DumpPackets {
 				d.dumpPacket(s)
@@ -497,26 +635,28 @@ func (d *Dispatcher) eventsReader() {
 			break
 		}
 
-		if n < 11 {
-			simplelogger.Panicf("message length too sort: %d", n)
-		}
-
-		delay := binary.LittleEndian.Uint64(readbuf[:8])
-		typ := readbuf[8]
-		datalen := binary.LittleEndian.Uint16(readbuf[9:11])
 		nodeid := srcaddr.Port - d.cfg.Port
-
-		data := make([]byte, n-11)
-		copy(data, readbuf[11:n])
 		evt := &event{
 			NodeId:  nodeid,
-			Delay:   delay,
-			Type:    typ,
-			DataLen: datalen,
-			Data:    data,
 			SrcAddr: srcaddr,
 		}
 
+		if n < eventHeaderSize {
+			evt.err = errors.Errorf("event length too short: %d", n)
+			d.eventChan <- evt
+			continue
+		}
+
+		evt.Delay = binary.LittleEndian.Uint64(readbuf[:8])
+		evt.Type = readbuf[8]
+		evt.DataLen = binary.LittleEndian.Uint16(readbuf[9:eventHeaderSize])
+		evt.Data = make([]byte, n-eventHeaderSize)
+		copy(evt.Data, readbuf[eventHeaderSize:n])
+
+		if int(evt.DataLen) != len(evt.Data) {
+			evt.err = errors.Errorf("event data length %d mismatches the received %d bytes", evt.DataLen, len(evt.Data))
+		}
+
 		d.eventChan <- evt
 	}
 }
@@ -528,6 +668,11 @@ func (d *Dispatcher) advanceNodeTime(id NodeId, timestamp uint64, force bool) {
 		return
 	}
 
+	if node.isDown() {
+		// there is no process to receive the alarm
+		return
+	}
+
 	oldTime := node.CurTime
 	elapsed := timestamp - oldTime
 	if timestamp <= oldTime {
@@ -559,6 +704,9 @@ func (d *Dispatcher) advanceNodeTime(id NodeId, timestamp uint64, force bool) {
 // SendToUART sends data to virtual time UART of the target node.
 func (d *Dispatcher) SendToUART(id NodeId, data []byte) {
 	node := d.nodes[id]
+	if node.isDown() {
+		return
+	}
 
 	oldTime := node.CurTime
 	timestamp := d.CurTime
@@ -600,8 +748,30 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 		return
 	}
 
-	pktinfo := dissectpkt.Dissect(sit.Data)
+	srcExtAddr := srcnode.ExtAddr
+	if srcExtAddr == InvalidExtAddr {
+		srcExtAddr = 0
+	}
+
+	pktinfo, err := d.dissector.Dissect(d.CurTime, srcExtAddr, sit.Data)
+	if d.decryptedPcap != nil {
+		d.writeDecryptedPcap(sit, pktinfo)
+	}
+
+	if err != nil {
+		// the frame can not be dissected, so just dispatch it to all nodes in range
+		d.onMalformed(srcnode, malformedFrame, err)
+		for _, dstnode := range d.nodes {
+			if d.checkRadioReachable(srcnode, dstnode) {
+				d.sendOneMessage(sit, srcnode, dstnode)
+			}
+		}
+		return
+	}
+
+	d.countFrameType(pktinfo.Type)
 	pktframe := pktinfo.MacFrame
+	srcnode.countTxFrame(pktframe, pktinfo.Mle)
 
 	// try to dispatch the message by extaddr directly
 	dispatchedByDstAddr := false
@@ -613,15 +783,15 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 		if dstnode != srcnode && dstnode != nil {
 			if d.checkRadioReachable(srcnode, dstnode) {
 				d.sendOneMessage(sit, srcnode, dstnode)
-				d.visSendFrame(srcnodeid, dstnode.Id, pktframe)
+				d.visSendFrame(srcnodeid, dstnode.Id, pktinfo)
 			} else {
-				d.visSendFrame(srcnodeid, InvalidNodeId, pktframe)
+				d.visSendFrame(srcnodeid, InvalidNodeId, pktinfo)
 			}
 
 			d.Counters.DispatchByExtAddrSucc++
 		} else {
 			d.Counters.DispatchByExtAddrFail++
-			d.visSendFrame(srcnodeid, InvalidNodeId, pktframe)
+			d.visSendFrame(srcnodeid, InvalidNodeId, pktinfo)
 		}
 
 		dispatchedByDstAddr = true
@@ -635,7 +805,7 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 				for _, dstnode := range dstnodes {
 					if d.checkRadioReachable(srcnode, dstnode) {
 						d.sendOneMessage(sit, srcnode, dstnode)
-						d.visSendFrame(srcnodeid, dstnode.Id, pktframe)
+						d.visSendFrame(srcnodeid, dstnode.Id, pktinfo)
 						dispatchCnt++
 					}
 				}
@@ -645,7 +815,7 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 			}
 
 			if dispatchCnt == 0 {
-				d.visSendFrame(srcnodeid, InvalidNodeId, pktframe)
+				d.visSendFrame(srcnodeid, InvalidNodeId, pktinfo)
 			}
 
 			dispatchedByDstAddr = true
@@ -660,10 +830,40 @@ func (d *Dispatcher) sendNodeMessage(sit *sendItem) {
 			}
 		}
 
-		d.visSendFrame(srcnodeid, BroadcastNodeId, pktframe)
+		d.visSendFrame(srcnodeid, BroadcastNodeId, pktinfo)
+	}
+}
+
+func (d *Dispatcher) countFrameType(pktType dissectpkt.PktType) {
+	switch pktType {
+	case dissectpkt.PktTypeAck:
+		d.Counters.AckFrames++
+	case dissectpkt.PktTypeBeacon:
+		d.Counters.BeaconFrames++
+	case dissectpkt.PktTypeMacCommand:
+		d.Counters.MacCommandFrames++
+	case dissectpkt.PktTypeMle:
+		d.Counters.MleFrames++
+	case dissectpkt.PktTypeTmf:
+		d.Counters.TmfFrames++
+	case dissectpkt.PktTypeIcmp6:
+		d.Counters.Icmp6Frames++
+	case dissectpkt.PktTypeData:
+		d.Counters.DataFrames++
+	case dissectpkt.PktTypeFragment:
+		d.Counters.FragmentFrames++
+	case dissectpkt.PktTypeEncrypted:
+		d.Counters.EncryptedFrames++
+	default:
+		d.Counters.UnknownFrames++
 	}
 }
 
+// SetMeshLocalPrefix sets the mesh-local prefix used for dissecting 6LoWPAN frames.
+func (d *Dispatcher) SetMeshLocalPrefix(prefix net.IPNet) {
+	d.dissector.SetMeshLocalPrefix(prefix)
+}
+
 func (d *Dispatcher) checkRadioReachable(src *Node, dst *Node) bool {
 	return dst != src && src.GetDistanceTo(dst) <= src.radioRange
 }
@@ -671,6 +871,10 @@ func (d *Dispatcher) checkRadioReachable(src *Node, dst *Node) bool {
 func (d *Dispatcher) sendOneMessage(sit *sendItem, srcnode *Node, dstnode *Node) {
 	simplelogger.AssertFalse(d.cfg.Real)
 
+	if dstnode.isDown() {
+		return
+	}
+
 	if srcnode != dstnode {
 		// we should always send the message when srcnode == dstnode, because it is the TX done notify
 		if dstnode.isFailed {
@@ -761,19 +965,51 @@ func (d *Dispatcher) pcapFrameWriter() {
 	defer d.waitGroup.Done()
 
 	defer func() {
-		err := d.pcap.Close()
-		if err != nil {
-			simplelogger.Errorf("failed to close pcap: %v", err)
+		for _, pf := range []*pcap.File{d.pcap, d.decryptedPcap} {
+			if pf == nil {
+				continue
+			}
+			err := pf.Close()
+			if err != nil {
+				simplelogger.Errorf("failed to close pcap: %v", err)
+			}
 		}
 	}()
 	for item := range d.pcapFrameChan {
-		err := d.pcap.AppendFrame(item.Ustime, item.Data)
+		pf := d.pcap
+		if item.Decrypted {
+			pf = d.decryptedPcap
+		}
+		if pf == nil {
+			continue
+		}
+		err := pf.AppendFrame(item.Ustime, item.Data)
 		if err != nil {
 			simplelogger.Errorf("write pcap failed:%+v", err)
 		}
 	}
 }
 
+// writeDecryptedPcap writes the frame to the decrypted pcap, or the original frame if it can not be decrypted.
+func (d *Dispatcher) writeDecryptedPcap(sit *sendItem, pktinfo *dissectpkt.PktInfo) {
+	data := sit.Data[1:]
+	if pktinfo != nil && pktinfo.Decrypted {
+		data = pktinfo.PlainPsdu()
+	}
+	d.pcapFrameChan <- pcapFrameItem{sit.Timestamp, data, true}
+}
+
+// SetMasterKey sets the master key (in hex format) used for decrypting frames.
+func (d *Dispatcher) SetMasterKey(masterKey string) error {
+	key, err := security.ParseMasterKey(masterKey)
+	if err != nil {
+		return err
+	}
+
+	d.dissector.SetMasterKey(key)
+	return nil
+}
+
 func (d *Dispatcher) SetVisualizer(vis visualize.Visualizer) {
 	simplelogger.AssertNotNil(vis)
 	d.vis = vis
@@ -794,93 +1030,32 @@ func (d *Dispatcher) handleStatusPush(srcid NodeId, data string) {
 
 	statuses := strings.Split(data, ";")
 	for _, status := range statuses {
+		if status == "" {
+			continue
+		}
+
 		sp := strings.Split(status, "=")
 		if len(sp) != 2 {
+			d.onMalformed(srcnode, malformedStatusPush, errors.Errorf("invalid status push: %#v", status))
 			continue
 		}
-		if sp[0] == "transmit" {
-			d.visStatusPushTransmit(srcnode, sp[1])
-		} else if sp[0] == "role" {
-			role, err := strconv.Atoi(sp[1])
-			simplelogger.PanicIfError(err)
-			d.setNodeRole(srcid, OtDeviceRole(role))
-		} else if sp[0] == "rloc16" {
-			rloc16, err := strconv.Atoi(sp[1])
-			simplelogger.PanicIfError(err)
-			d.setNodeRloc16(srcid, uint16(rloc16))
-		} else if sp[0] == "ping_request" {
-			// e.x. ping_request=fdde:ad00:beef:0:556:90c8:ffaf:b7a3$0$4026600960
-			args := strings.Split(sp[1], ",")
-			dstaddr := args[0]
-			datasize, err := strconv.Atoi(args[1])
-			simplelogger.PanicIfError(err)
-			timestamp, err := strconv.ParseUint(args[2], 10, 64)
-			simplelogger.PanicIfError(err)
-			srcnode.onPingRequest(d.convertNodeMilliTime(srcnode, uint32(timestamp)), dstaddr, datasize)
-		} else if sp[0] == "ping_reply" {
-			//e.x.ping_reply=fdde:ad00:beef:0:556:90c8:ffaf:b7a3$0$0$64
-			args := strings.Split(sp[1], ",")
-			dstaddr := args[0]
-			datasize, err := strconv.Atoi(args[1])
-			simplelogger.PanicIfError(err)
-			timestamp, err := strconv.ParseUint(args[2], 10, 64)
-			simplelogger.PanicIfError(err)
-			hoplimit, err := strconv.Atoi(args[3])
-			simplelogger.PanicIfError(err)
-			srcnode.onPingReply(d.convertNodeMilliTime(srcnode, uint32(timestamp)), dstaddr, datasize, hoplimit)
-		} else if sp[0] == "coap" {
-			d.handleCoapEvent(srcnode, sp[1])
-		} else if sp[0] == "parid" {
-			// set partition id
-			parid, err := strconv.ParseUint(sp[1], 16, 32)
-			simplelogger.PanicIfError(err)
-			srcnode.PartitionId = uint32(parid)
-			d.vis.SetNodePartitionId(srcid, uint32(parid))
-		} else if sp[0] == "router_added" {
-			extaddr, err := strconv.ParseUint(sp[1], 16, 64)
-			simplelogger.PanicIfError(err)
-			if d.visOptions.RouterTable {
-				d.vis.AddRouterTable(srcid, extaddr)
-			}
-		} else if sp[0] == "router_removed" {
-			extaddr, err := strconv.ParseUint(sp[1], 16, 64)
-			simplelogger.PanicIfError(err)
-			if d.visOptions.RouterTable {
-				d.vis.RemoveRouterTable(srcid, extaddr)
-			}
-		} else if sp[0] == "child_added" {
-			extaddr, err := strconv.ParseUint(sp[1], 16, 64)
-			simplelogger.PanicIfError(err)
-			if d.visOptions.ChildTable {
-				d.vis.AddChildTable(srcid, extaddr)
-			}
-		} else if sp[0] == "child_removed" {
-			extaddr, err := strconv.ParseUint(sp[1], 16, 64)
-			simplelogger.PanicIfError(err)
-			if d.visOptions.ChildTable {
-				d.vis.RemoveChildTable(srcid, extaddr)
-			}
-		} else if sp[0] == "parent" {
-			extaddr, err := strconv.ParseUint(sp[1], 16, 64)
-			simplelogger.PanicIfError(err)
-			d.vis.SetParent(srcid, extaddr)
-		} else if sp[0] == "joiner_state" {
-			joinerState, err := strconv.Atoi(sp[1])
-			simplelogger.PanicIfError(err)
-			srcnode.onJoinerState(OtJoinerState(joinerState))
-		} else if sp[0] == "extaddr" {
-			extaddr, err := strconv.ParseUint(sp[1], 16, 64)
-			simplelogger.PanicIfError(err)
-			srcnode.onStatusPushExtAddr(extaddr)
-		} else if sp[0] == "mode" {
-			mode := ParseNodeMode(sp[1])
-			d.vis.SetNodeMode(srcid, mode)
-		} else {
-			simplelogger.Warnf("unknown status push: %s=%s", sp[0], sp[1])
+
+		if err := d.handleStatusPushEntry(srcnode, sp[0], sp[1]); err != nil {
+			d.onMalformed(srcnode, malformedStatusPush, errors.Wrapf(err, "%s=%s", sp[0], sp[1]))
 		}
 	}
 }
 
+func (d *Dispatcher) handleStatusPushEntry(srcnode *Node, key string, value string) error {
+	handler := getStatusPushHandler(key)
+	if handler == nil {
+		simplelogger.Warnf("unknown status push: %s=%s", key, value)
+		return nil
+	}
+
+	return handler(d, srcnode, value)
+}
+
 func (d *Dispatcher) AddNode(nodeid NodeId, x, y int, radioRange int) {
 	simplelogger.AssertNil(d.nodes[nodeid])
 	simplelogger.Infof("dispatcher add node %d", nodeid)
@@ -891,11 +1066,13 @@ func (d *Dispatcher) AddNode(nodeid NodeId, x, y int, radioRange int) {
 		// This helps OTNS to make sure that the child process is ready to receive UDP events
 		t0 := time.Now()
 		deadline := t0.Add(time.Second * 10)
-		for node.ExtAddr == InvalidExtAddr && time.Now().Before(deadline) {
+		for node.ExtAddr == InvalidExtAddr && !node.Crashed && time.Now().Before(deadline) {
 			d.RecvEvents()
 		}
 
-		if node.ExtAddr == InvalidExtAddr {
+		if node.Crashed {
+			simplelogger.Panicf("node %d crashed on startup", nodeid)
+		} else if node.ExtAddr == InvalidExtAddr {
 			simplelogger.Panicf("expect node %d's extaddr to be valid, but failed", nodeid)
 		} else {
 			takeTime := time.Since(t0)
@@ -924,30 +1101,39 @@ func (d *Dispatcher) setNodeRloc16(srcid NodeId, rloc16 uint16) {
 	d.vis.SetNodeRloc16(srcid, rloc16)
 }
 
-func (d *Dispatcher) visStatusPushTransmit(srcnode *Node, s string) {
+func (d *Dispatcher) visStatusPushTransmit(srcnode *Node, s string) error {
 	var fcf wpan.FrameControl
 
 	// only visualize `transmit` status emitting in real mode because simulation nodes already have radio events visualized
 	if !d.cfg.Real {
-		return
+		return nil
 	}
 
 	parts := strings.Split(s, ",")
 
 	if len(parts) < 3 {
-		simplelogger.Panicf("invalid status push: transmit=%s", s)
+		return errors.Errorf("expect at least 3 arguments, but got %d", len(parts))
 	}
 
-	channel, err := strconv.Atoi(parts[0])
-	simplelogger.PanicIfError(err)
+	channel, err := strconv.ParseUint(parts[0], 10, 8)
+	if err != nil {
+		return err
+	}
 	fcfval, err := strconv.ParseUint(parts[1], 16, 16)
-	simplelogger.PanicIfError(err)
+	if err != nil {
+		return err
+	}
 	fcf = wpan.FrameControl(fcfval)
 
-	seq, err := strconv.Atoi(parts[2])
-	simplelogger.PanicIfError(err)
+	seq, err := strconv.ParseUint(parts[2], 10, 8)
+	if err != nil {
+		return err
+	}
 
 	dstAddrMode := fcf.DstAddrMode()
+	if (dstAddrMode == wpan.DstAddrModeExtended || dstAddrMode == wpan.DstAddrModeShort) && len(parts) < 4 {
+		return errors.Errorf("missing destination address")
+	}
 
 	visInfo := &visualize.MsgVisualizeInfo{
 		Channel:      uint8(channel),
@@ -955,9 +1141,18 @@ func (d *Dispatcher) visStatusPushTransmit(srcnode *Node, s string) {
 		Seq:          uint8(seq),
 	}
 
+	// only the frame control is known for transmissions of real devices, so guess the frame type from it
+	if fcf.FrameType() == wpan.FrameTypeAck {
+		visInfo.PktType = dissectpkt.PktTypeAck
+	} else if !fcf.SecurityEnabled() {
+		visInfo.PktType = dissectpkt.PktTypeMle
+	}
+
 	if dstAddrMode == wpan.DstAddrModeExtended {
 		dstExtend, err := strconv.ParseUint(parts[3], 16, 64)
-		simplelogger.PanicIfError(err)
+		if err != nil {
+			return err
+		}
 
 		visInfo.DstAddrExtended = dstExtend
 
@@ -969,7 +1164,9 @@ func (d *Dispatcher) visStatusPushTransmit(srcnode *Node, s string) {
 		}
 	} else if dstAddrMode == wpan.DstAddrModeShort {
 		dstShortVal, err := strconv.ParseUint(parts[3], 16, 16)
-		simplelogger.PanicIfError(err)
+		if err != nil {
+			return err
+		}
 
 		dstShort := uint16(dstShortVal)
 		visInfo.DstAddrShort = dstShort
@@ -991,16 +1188,24 @@ func (d *Dispatcher) visStatusPushTransmit(srcnode *Node, s string) {
 	} else {
 		d.vis.Send(srcnode.Id, BroadcastNodeId, visInfo)
 	}
+
+	return nil
 }
 
-func (d *Dispatcher) visSendFrame(srcid NodeId, dstid NodeId, pktframe *wpan.MacFrame) {
-	d.visSend(srcid, dstid, &visualize.MsgVisualizeInfo{
+func (d *Dispatcher) visSendFrame(srcid NodeId, dstid NodeId, pktinfo *dissectpkt.PktInfo) {
+	pktframe := pktinfo.MacFrame
+	visInfo := &visualize.MsgVisualizeInfo{
 		Channel:         pktframe.Channel,
 		FrameControl:    pktframe.FrameControl,
 		Seq:             pktframe.Seq,
 		DstAddrShort:    pktframe.DstAddrShort,
 		DstAddrExtended: pktframe.DstAddrExtended,
-	})
+		PktType:         pktinfo.Type,
+	}
+	if pktinfo.Mle != nil {
+		visInfo.MleCommand = &pktinfo.Mle.Command
+	}
+	d.visSend(srcid, dstid, visInfo)
 }
 
 func (d *Dispatcher) visSend(srcid NodeId, dstid NodeId, visInfo *visualize.MsgVisualizeInfo) {
@@ -1023,17 +1228,26 @@ func (d *Dispatcher) visSend(srcid NodeId, dstid NodeId, visInfo *visualize.MsgV
 	d.vis.Send(srcid, dstid, visInfo)
 }
 
+// GetCurTime returns the current virtual time. It is safe to call from any goroutine.
+func (d *Dispatcher) GetCurTime() uint64 {
+	return atomic.LoadUint64(&d.CurTime)
+}
+
 func (d *Dispatcher) advanceTime(ts uint64) {
 	simplelogger.AssertTrue(d.CurTime <= ts, "%v > %v", d.CurTime, ts)
 	if d.CurTime < ts {
 		oldTime := d.CurTime
-		d.CurTime = ts
+		atomic.StoreUint64(&d.CurTime, ts)
 		elapsedTime := int64(d.CurTime - d.speedStartTime)
 		elapsedRealTime := time.Since(d.speedStartRealTime) / time.Microsecond
 		if elapsedRealTime > 0 && ts/1000000 != oldTime/1000000 {
 			d.vis.AdvanceTime(ts, float64(elapsedTime)/float64(elapsedRealTime))
 		}
 
+		if d.pingExpiryTime != 0 && d.pingExpiryTime <= ts {
+			d.expirePings()
+		}
+
 		if d.cfg.Real {
 			d.syncAllNodes()
 		}
@@ -1159,6 +1373,12 @@ func (d *Dispatcher) SetSpeed(f float64) {
 	d.vis.SetSpeed(ns)
 }
 
+// Pause pauses the simulation by setting the speed to 0 and ending the current Go (if any) at the current time.
+func (d *Dispatcher) Pause() {
+	d.SetSpeed(0)
+	d.pauseRequested = d.pauseTime > d.CurTime
+}
+
 func (d *Dispatcher) normalizeSpeed(f float64) float64 {
 	if f <= 0 {
 		f = 0
@@ -1256,53 +1476,61 @@ func (d *Dispatcher) setNodeRole(id NodeId, role OtDeviceRole) {
 	d.vis.SetNodeRole(id, role)
 }
 
-func (d *Dispatcher) handleCoapEvent(node *Node, argsStr string) {
+func (d *Dispatcher) handleCoapEvent(node *Node, argsStr string) error {
 	var err error
 
 	if d.coaps == nil {
 		// Coaps not enabled
-		return
+		return nil
 	}
 
 	args := strings.Split(argsStr, ",")
-
-	simplelogger.AssertTrue(len(args) > 0)
 	action := args[0]
 
 	if action == "send" || action == "recv" || action == "send_error" {
 		var messageId, coapType, coapCode, port int
 
-		simplelogger.AssertTrue(len(args) >= 7)
+		if len(args) < 7 {
+			return errors.Errorf("expect at least 7 arguments, but got %d", len(args))
+		}
 
-		messageId, err = strconv.Atoi(args[1])
-		simplelogger.PanicIfError(err)
+		if messageId, err = strconv.Atoi(args[1]); err != nil {
+			return err
+		}
 
-		coapType, err = strconv.Atoi(args[2])
-		simplelogger.PanicIfError(err)
+		if coapType, err = strconv.Atoi(args[2]); err != nil {
+			return err
+		}
 
-		coapCode, err = strconv.Atoi(args[3])
-		simplelogger.PanicIfError(err)
+		if coapCode, err = strconv.Atoi(args[3]); err != nil {
+			return err
+		}
 
 		uri := args[4]
 
 		ip := args[5]
 
-		port, err = strconv.Atoi(args[6])
-		simplelogger.PanicIfError(err)
+		if port, err = strconv.Atoi(args[6]); err != nil {
+			return err
+		}
 
 		if action == "send" {
 			d.coaps.OnSend(d.CurTime, node.Id, messageId, CoapType(coapType), CoapCode(coapCode), uri, ip, port)
 		} else if action == "recv" {
 			d.coaps.OnRecv(d.CurTime, node.Id, messageId, CoapType(coapType), CoapCode(coapCode), uri, ip, port)
 		} else {
-			simplelogger.AssertTrue(len(args) >= 7)
-			threadError := args[6]
+			if len(args) < 8 {
+				return errors.Errorf("expect 8 arguments, but got %d", len(args))
+			}
+			threadError := args[7]
 
 			d.coaps.OnSendError(node.Id, messageId, CoapType(coapType), CoapCode(coapCode), uri, ip, port, threadError)
 		}
 	} else {
-		simplelogger.Warnf("unknown coap event: %+v", args)
+		return errors.Errorf("unknown coap event: %s", action)
 	}
+
+	return nil
 }
 
 func (d *Dispatcher) EnableCoaps() {
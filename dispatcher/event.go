@@ -39,6 +39,9 @@ const (
 	eventTypeStatusPush    = 5
 )
 
+// eventHeaderSize is the size of the event header: delay (8 bytes), type (1 byte) and data length (2 bytes).
+const eventHeaderSize = 11
+
 type eventType = uint8
 
 type event struct {
@@ -48,4 +51,6 @@ type event struct {
 	DataLen uint16
 	Data    []byte
 	SrcAddr *net.UDPAddr
+	// err is set if the event can not be decoded.
+	err error
 }
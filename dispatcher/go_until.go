@@ -0,0 +1,122 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"net"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+// GoCondition is a condition on the dispatcher state, which is evaluated in the dispatcher routine.
+type GoCondition func(d *Dispatcher) bool
+
+// UntilPartitions is met when the attached nodes that are running are in the number of partitions.
+func UntilPartitions(n int) GoCondition {
+	return func(d *Dispatcher) bool {
+		return CountPartitions(d) == n
+	}
+}
+
+// CountPartitions returns the number of partitions of the attached nodes that are running.
+// Detached and down nodes keep their last partition ID, so they are not counted.
+func CountPartitions(d *Dispatcher) int {
+	pars := map[uint32]struct{}{}
+	for _, node := range d.nodes {
+		if node.Role < OtDeviceRoleChild || node.isDown() {
+			continue
+		}
+		pars[node.PartitionId] = struct{}{}
+	}
+	return len(pars)
+}
+
+// UntilRole is met when the node has the role.
+func UntilRole(nodeid NodeId, role OtDeviceRole) GoCondition {
+	return func(d *Dispatcher) bool {
+		node := d.nodes[nodeid]
+		return node != nil && node.Role == role
+	}
+}
+
+// UntilAttached is met when all the nodes, or all nodes if none is specified, are attached as children, routers
+// or leaders.
+func UntilAttached(nodeids ...NodeId) GoCondition {
+	return func(d *Dispatcher) bool {
+		if len(nodeids) == 0 {
+			for _, node := range d.nodes {
+				if node.Role < OtDeviceRoleChild {
+					return false
+				}
+			}
+			return true
+		}
+
+		for _, nodeid := range nodeids {
+			node := d.nodes[nodeid]
+			if node == nil || node.Role < OtDeviceRoleChild {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// UntilPingReplied is met when the source node receives a ping reply since the virtual time, from any of the addresses
+// or from any address if none is specified.
+func UntilPingReplied(src NodeId, addrs []string, since uint64) GoCondition {
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ip := net.ParseIP(addr); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+
+	return func(d *Dispatcher) bool {
+		node := d.nodes[src]
+		if node == nil {
+			return false
+		}
+
+		for _, ping := range node.pingResults {
+			if ping.Timeout || ping.Timestamp+ping.Delay < since {
+				continue
+			}
+			if len(addrs) == 0 {
+				return true
+			}
+
+			replyIp := net.ParseIP(ping.ReplyAddr)
+			for _, ip := range ips {
+				if ip.Equal(replyIp) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUntilPartitionsAndRoles(t *testing.T) {
+	d, node1 := newTestDispatcher()
+	node2 := d.newNode(2, 0, 0, 100)
+
+	node1.Role, node2.Role = OtDeviceRoleLeader, OtDeviceRoleLeader
+	node1.PartitionId, node2.PartitionId = 1, 2
+	assert.False(t, UntilPartitions(1)(d))
+	assert.True(t, UntilPartitions(2)(d))
+	node2.PartitionId = 1
+	assert.True(t, UntilPartitions(1)(d))
+
+	// detached and down nodes are not counted in any partition
+	node2.PartitionId, node2.Role = 2, OtDeviceRoleDetached
+	assert.True(t, UntilPartitions(1)(d))
+	node2.Role, node2.Crashed = OtDeviceRoleLeader, true
+	assert.True(t, UntilPartitions(1)(d))
+	node2.Crashed, node2.PoweredOff = false, true
+	assert.True(t, UntilPartitions(1)(d))
+	node1.Crashed = true
+	assert.True(t, UntilPartitions(0)(d))
+	node1.Crashed, node2.PoweredOff, node2.PartitionId = false, false, 1
+
+	node1.Role, node2.Role = OtDeviceRoleLeader, OtDeviceRoleDetached
+	assert.True(t, UntilRole(1, OtDeviceRoleLeader)(d))
+	assert.False(t, UntilRole(2, OtDeviceRoleLeader)(d))
+	assert.False(t, UntilRole(3, OtDeviceRoleLeader)(d))
+
+	assert.True(t, UntilAttached(1)(d))
+	assert.False(t, UntilAttached(1, 2)(d))
+	assert.False(t, UntilAttached()(d))
+	assert.False(t, UntilAttached(3)(d))
+	node2.Role = OtDeviceRoleChild
+	assert.True(t, UntilAttached()(d))
+}
+
+func TestUntilPingReplied(t *testing.T) {
+	d, node := newTestDispatcher()
+
+	cond := UntilPingReplied(1, []string{"fdde:ad00:beef:0::2"}, 1000)
+	anyReply := UntilPingReplied(1, nil, 1000)
+	assert.False(t, cond(d))
+
+	node.onPingRequest(500, "fdde:ad00:beef:0:0:0:0:2", 10)
+	node.onPingRequest(500, "fdde:ad00:beef:0::3", 10)
+	d.advanceTime(2000)
+	node.onPingReply(500, "fdde:ad00:beef:0::3", 10, 64)
+	assert.False(t, cond(d))
+	assert.True(t, anyReply(d))
+
+	// addresses are compared regardless of their formats
+	node.onPingReply(500, "fdde:ad00:beef:0:0:0:0:2", 10, 64)
+	assert.True(t, cond(d))
+	// replies before the virtual time are ignored
+	assert.False(t, UntilPingReplied(1, nil, 3000)(d))
+	assert.False(t, UntilPingReplied(2, nil, 0)(d))
+}
+
+func TestFinishGo(t *testing.T) {
+	d, node := newTestDispatcher()
+
+	duration := goDuration{until: UntilRole(node.Id, OtDeviceRoleLeader), done: make(chan struct{}), met: make(chan bool, 1)}
+	d.finishGo(duration)
+	assert.False(t, <-duration.met)
+	<-duration.done
+
+	node.Role = OtDeviceRoleLeader
+	duration = goDuration{until: UntilRole(node.Id, OtDeviceRoleLeader), done: make(chan struct{}), met: make(chan bool, 1)}
+	d.finishGo(duration)
+	assert.True(t, <-duration.met)
+
+	// Go without condition
+	duration = goDuration{done: make(chan struct{})}
+	d.finishGo(duration)
+	<-duration.done
+}
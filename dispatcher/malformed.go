@@ -0,0 +1,137 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	// malformedWarnInterval is the minimal real time between two warnings about malformed messages from one node.
+	malformedWarnInterval = time.Second
+	// quarantineThreshold is the number of malformed messages within quarantineWindow that gets a node quarantined.
+	quarantineThreshold = 50
+	// quarantineWindow is the virtual time window (in us) for counting malformed messages towards quarantine.
+	quarantineWindow = 10000000
+)
+
+type malformedKind int
+
+const (
+	malformedEvent malformedKind = iota
+	malformedStatusPush
+	malformedFrame
+)
+
+func (k malformedKind) String() string {
+	switch k {
+	case malformedEvent:
+		return "event"
+	case malformedStatusPush:
+		return "status push"
+	case malformedFrame:
+		return "frame"
+	default:
+		return "message"
+	}
+}
+
+// malformedTracker rate-limits the warnings about malformed messages from a node,
+// and tracks whether the node reaches the quarantine threshold.
+type malformedTracker struct {
+	windowStart uint64
+	windowCount int
+	lastWarn    time.Time
+	suppressed  int
+}
+
+// add records a malformed message received at virtual time `ts` and real time `now`.
+// It returns whether a warning should be logged, how many warnings were suppressed since the last one,
+// and whether the quarantine threshold is reached.
+func (t *malformedTracker) add(ts uint64, now time.Time) (warn bool, suppressed int, exceeded bool) {
+	if t.windowCount == 0 || ts >= t.windowStart+quarantineWindow {
+		t.windowStart = ts
+		t.windowCount = 0
+	}
+	t.windowCount++
+	exceeded = t.windowCount >= quarantineThreshold
+
+	if t.lastWarn.IsZero() || now.Sub(t.lastWarn) >= malformedWarnInterval {
+		warn, suppressed = true, t.suppressed
+		t.lastWarn = now
+		t.suppressed = 0
+	} else {
+		t.suppressed++
+	}
+	return
+}
+
+// onMalformed counts a malformed message from the node, logs a rate-limited warning,
+// and quarantines the node if it keeps sending malformed messages.
+func (d *Dispatcher) onMalformed(node *Node, kind malformedKind, err error) {
+	switch kind {
+	case malformedEvent:
+		d.Counters.MalformedEvents++
+	case malformedStatusPush:
+		d.Counters.MalformedStatusPushes++
+	case malformedFrame:
+		d.Counters.MalformedFrames++
+	}
+
+	node.MalformedCount++
+	warn, suppressed, exceeded := node.malformed.add(d.CurTime, time.Now())
+	if warn {
+		if suppressed > 0 {
+			simplelogger.Warnf("node %d: malformed %s: %v (%d similar warnings suppressed)", node.Id, kind, err, suppressed)
+		} else {
+			simplelogger.Warnf("node %d: malformed %s: %v", node.Id, kind, err)
+		}
+	}
+
+	if exceeded && !node.Quarantined {
+		node.Quarantined = true
+		d.Counters.QuarantinedNodes++
+		simplelogger.Errorf("node %d quarantined after %d malformed messages within %dms, its radio and status push events are dropped",
+			node.Id, quarantineThreshold, quarantineWindow/1000)
+	}
+}
+
+// LiftQuarantine lets the node send radio and status push events again. Its malformed messages are counted towards
+// quarantine from scratch, while MalformedCount keeps the total.
+func (d *Dispatcher) LiftQuarantine(id NodeId) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+
+	if node.Quarantined {
+		simplelogger.Infof("node %d quarantine lifted", id)
+	}
+	node.Quarantined = false
+	node.malformed = malformedTracker{}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMalformedTracker_RateLimit(t *testing.T) {
+	var tracker malformedTracker
+	now := time.Now()
+
+	warn, suppressed, _ := tracker.add(0, now)
+	assert.True(t, warn)
+	assert.Equal(t, 0, suppressed)
+
+	warn, _, _ = tracker.add(1, now.Add(time.Millisecond))
+	assert.False(t, warn)
+	warn, _, _ = tracker.add(2, now.Add(time.Millisecond*2))
+	assert.False(t, warn)
+
+	warn, suppressed, _ = tracker.add(3, now.Add(malformedWarnInterval))
+	assert.True(t, warn)
+	assert.Equal(t, 2, suppressed)
+}
+
+func TestMalformedTracker_Quarantine(t *testing.T) {
+	var tracker malformedTracker
+	now := time.Now()
+
+	for i := 0; i < quarantineThreshold-1; i++ {
+		_, _, exceeded := tracker.add(uint64(i), now)
+		assert.False(t, exceeded)
+	}
+
+	// the window restarts after quarantineWindow
+	_, _, exceeded := tracker.add(quarantineWindow, now)
+	assert.False(t, exceeded)
+
+	for i := 1; i < quarantineThreshold; i++ {
+		_, _, exceeded = tracker.add(quarantineWindow+uint64(i), now)
+	}
+	assert.True(t, exceeded)
+}
+
+func TestHandleStatusPush_Malformed(t *testing.T) {
+	d, node := newTestDispatcher()
+	d.EnableCoaps()
+
+	d.handleStatusPush(node.Id, "role=x;rloc16=70000;extaddr=zz;ping_reply=fdde::1,0;coap=send,1;joiner_state=9;garbage;")
+	assert.Equal(t, uint64(7), d.Counters.MalformedStatusPushes)
+	assert.Equal(t, uint64(7), node.MalformedCount)
+	assert.Equal(t, OtDeviceRoleDisabled, node.Role)
+
+	d.handleStatusPush(node.Id, "role=3;rloc16=1024;extaddr=0102030405060708;")
+	assert.Equal(t, uint64(7), d.Counters.MalformedStatusPushes)
+	assert.Equal(t, OtDeviceRoleRouter, node.Role)
+	assert.Equal(t, uint16(1024), node.Rloc16)
+	assert.Equal(t, uint64(0x0102030405060708), node.ExtAddr)
+}
+
+func TestHandleRecvEvent_Quarantine(t *testing.T) {
+	d, node := newTestDispatcher()
+
+	for i := 0; i < quarantineThreshold; i++ {
+		d.handleRecvEvent(&event{NodeId: node.Id, Type: 0xff})
+	}
+	assert.Equal(t, uint64(quarantineThreshold), d.Counters.MalformedEvents)
+	assert.True(t, node.Quarantined)
+	assert.Equal(t, uint64(1), d.Counters.QuarantinedNodes)
+
+	d.handleRecvEvent(&event{NodeId: node.Id, Type: eventTypeStatusPush, Data: []byte("role=4")})
+	assert.Equal(t, uint64(1), d.Counters.QuarantineDroppedEvents)
+	assert.Equal(t, OtDeviceRoleDisabled, node.Role)
+}
+
+func TestLiftQuarantine(t *testing.T) {
+	d, node := newTestDispatcher()
+
+	for i := 0; i < quarantineThreshold; i++ {
+		d.handleRecvEvent(&event{NodeId: node.Id, Type: 0xff})
+	}
+	assert.True(t, node.Quarantined)
+
+	d.LiftQuarantine(node.Id)
+	assert.False(t, node.Quarantined)
+	assert.Equal(t, uint64(quarantineThreshold), node.MalformedCount)
+
+	// the node is quarantined again only after another threshold of malformed messages
+	d.handleRecvEvent(&event{NodeId: node.Id, Type: 0xff})
+	assert.False(t, node.Quarantined)
+	d.handleRecvEvent(&event{NodeId: node.Id, Type: eventTypeStatusPush, Data: []byte("role=4")})
+	assert.Equal(t, OtDeviceRoleLeader, node.Role)
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+)
+
+// The network state status pushes are not emitted by upstream OpenThread builds. Builds that emit them report the
+// network state as it changes, while the network state of other builds is read by their CLI commands on demand.
+func registerNetworkStateStatusPushHandlers() {
+	// e.x. prefix_added=fd00:dead:beef:cafe::/64 paros med 4000
+	for _, key := range []string{"prefix_added", "prefix_removed"} {
+		added := key == "prefix_added"
+		registerNetworkStatePushHandler(key, func(d *Dispatcher, node *Node, value string) error {
+			prefix, err := ParseNetDataPrefix(value)
+			if err != nil {
+				return err
+			}
+			node.onNetDataPrefix(prefix, added)
+			return nil
+		})
+	}
+	// e.x. route_added=fd00:1234::/64 s med 4000
+	for _, key := range []string{"route_added", "route_removed"} {
+		added := key == "route_added"
+		registerNetworkStatePushHandler(key, func(d *Dispatcher, node *Node, value string) error {
+			route, err := ParseNetDataRoute(value)
+			if err != nil {
+				return err
+			}
+			node.onNetDataRoute(route, added)
+			return nil
+		})
+	}
+	// e.x. service_added=44970 5d c000 s 4000
+	for _, key := range []string{"service_added", "service_removed"} {
+		added := key == "service_added"
+		registerNetworkStatePushHandler(key, func(d *Dispatcher, node *Node, value string) error {
+			service, err := ParseNetDataService(value)
+			if err != nil {
+				return err
+			}
+			node.onNetDataService(service, added)
+			return nil
+		})
+	}
+
+	registerNetworkStatePushHandler("leader", StatusPushInt(0, InvalidRouterId, func(d *Dispatcher, node *Node, routerId int) error {
+		node.NetworkState.LeaderRouterId = routerId
+		node.visNetworkState()
+		return nil
+	}))
+	registerNetworkStatePushHandler("key_seq", StatusPushUint(10, 32, func(d *Dispatcher, node *Node, keySeq uint64) error {
+		node.NetworkState.KeySequence = uint32(keySeq)
+		node.visNetworkState()
+		return nil
+	}))
+	registerNetworkStatePushHandler("channel", StatusPushInt(11, 26, func(d *Dispatcher, node *Node, channel int) error {
+		node.NetworkState.Channel = channel
+		node.visNetworkState()
+		return nil
+	}))
+
+	// e.x. srp_host=host1,fd00::1,fd00::2
+	registerNetworkStatePushHandler("srp_host", func(d *Dispatcher, node *Node, value string) error {
+		host, err := parseSrpHost(value)
+		if err != nil {
+			return err
+		}
+		node.onSrpHost(host)
+		return nil
+	})
+	registerNetworkStatePushHandler("srp_host_removed", func(d *Dispatcher, node *Node, value string) error {
+		node.onSrpHostRemoved(value)
+		return nil
+	})
+	// e.x. srp_service=ins1._ipps._tcp,host1,631
+	registerNetworkStatePushHandler("srp_service", func(d *Dispatcher, node *Node, value string) error {
+		service, err := parseSrpService(value)
+		if err != nil {
+			return err
+		}
+		node.onSrpService(service)
+		return nil
+	})
+	registerNetworkStatePushHandler("srp_service_removed", func(d *Dispatcher, node *Node, value string) error {
+		node.onSrpServiceRemoved(value)
+		return nil
+	})
+}
+
+// registerNetworkStatePushHandler registers the handler of a network state status push, which marks that the node
+// pushes its network state.
+func registerNetworkStatePushHandler(key string, handler StatusPushHandler) {
+	RegisterStatusPushHandler(key, func(d *Dispatcher, node *Node, value string) error {
+		node.NetworkStatePushed = true
+		return handler(d, node, value)
+	})
+}
+
+// SetNetworkState sets the network state read from the node.
+func (node *Node) SetNetworkState(state NodeNetworkState) {
+	node.NetworkState = state
+	node.visNetworkState()
+}
+
+func (node *Node) onNetDataPrefix(prefix NetDataPrefix, added bool) {
+	netdata := &node.NetworkState.NetData
+	for i, p := range netdata.Prefixes {
+		if p.Prefix.String() == prefix.Prefix.String() && p.Rloc16 == prefix.Rloc16 {
+			netdata.Prefixes = append(netdata.Prefixes[:i], netdata.Prefixes[i+1:]...)
+			break
+		}
+	}
+
+	if added {
+		netdata.Prefixes = append(netdata.Prefixes, prefix)
+	}
+	node.visNetworkState()
+}
+
+func (node *Node) onNetDataRoute(route NetDataRoute, added bool) {
+	netdata := &node.NetworkState.NetData
+	for i, r := range netdata.Routes {
+		if r.Prefix.String() == route.Prefix.String() && r.Rloc16 == route.Rloc16 {
+			netdata.Routes = append(netdata.Routes[:i], netdata.Routes[i+1:]...)
+			break
+		}
+	}
+
+	if added {
+		netdata.Routes = append(netdata.Routes, route)
+	}
+	node.visNetworkState()
+}
+
+func (node *Node) onNetDataService(service NetDataService, added bool) {
+	netdata := &node.NetworkState.NetData
+	for i, s := range netdata.Services {
+		if s.EnterpriseNumber == service.EnterpriseNumber && bytes.Equal(s.ServiceData, service.ServiceData) &&
+			s.Rloc16 == service.Rloc16 {
+			netdata.Services = append(netdata.Services[:i], netdata.Services[i+1:]...)
+			break
+		}
+	}
+
+	if added {
+		netdata.Services = append(netdata.Services, service)
+	}
+	node.visNetworkState()
+}
+
+func (node *Node) onSrpHost(host SrpHost) {
+	node.removeSrpHost(host.Name)
+	node.NetworkState.SrpHosts = append(node.NetworkState.SrpHosts, host)
+	node.visNetworkState()
+}
+
+func (node *Node) onSrpHostRemoved(name string) {
+	node.removeSrpHost(name)
+
+	// the services of the host are removed with it
+	services := node.NetworkState.SrpServices[:0]
+	for _, service := range node.NetworkState.SrpServices {
+		if service.Host != name {
+			services = append(services, service)
+		}
+	}
+	node.NetworkState.SrpServices = services
+	node.visNetworkState()
+}
+
+func (node *Node) removeSrpHost(name string) {
+	for i, host := range node.NetworkState.SrpHosts {
+		if host.Name == name {
+			node.NetworkState.SrpHosts = append(node.NetworkState.SrpHosts[:i], node.NetworkState.SrpHosts[i+1:]...)
+			return
+		}
+	}
+}
+
+func (node *Node) onSrpService(service SrpService) {
+	node.removeSrpService(service.Name)
+	node.NetworkState.SrpServices = append(node.NetworkState.SrpServices, service)
+	node.visNetworkState()
+}
+
+func (node *Node) onSrpServiceRemoved(name string) {
+	node.removeSrpService(name)
+	node.visNetworkState()
+}
+
+func (node *Node) removeSrpService(name string) {
+	for i, service := range node.NetworkState.SrpServices {
+		if service.Name == name {
+			node.NetworkState.SrpServices = append(node.NetworkState.SrpServices[:i], node.NetworkState.SrpServices[i+1:]...)
+			return
+		}
+	}
+}
+
+func (node *Node) visNetworkState() {
+	node.D.vis.SetNodeNetworkState(node.Id, node.NetworkState)
+}
+
+// parseSrpHost parses a SRP host, e.g. `host1,fd00::1,fd00::2`.
+func parseSrpHost(value string) (host SrpHost, err error) {
+	fields := strings.Split(value, ",")
+	if fields[0] == "" {
+		err = errors.Errorf("empty host name")
+		return
+	}
+
+	host.Name = fields[0]
+	host.Addresses = []string{}
+	for _, addr := range fields[1:] {
+		if net.ParseIP(addr) == nil {
+			err = errors.Errorf("invalid address: %s", addr)
+			return
+		}
+		host.Addresses = append(host.Addresses, addr)
+	}
+	return
+}
+
+// parseSrpService parses a SRP service instance, e.g. `ins1._ipps._tcp,host1,631`.
+func parseSrpService(value string) (service SrpService, err error) {
+	fields := strings.Split(value, ",")
+	if len(fields) != 3 {
+		err = errors.Errorf("expect 3 fields, but got %d", len(fields))
+		return
+	}
+
+	if fields[0] == "" || fields[1] == "" {
+		err = errors.Errorf("empty service or host name")
+		return
+	}
+
+	port, err := strconv.ParseUint(fields[2], 10, 16)
+	if err != nil {
+		return
+	}
+
+	service = SrpService{Name: fields[0], Host: fields[1], Port: int(port)}
+	return
+}
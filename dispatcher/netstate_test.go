@@ -0,0 +1,123 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleStatusPush_NetData(t *testing.T) {
+	d, node := newTestDispatcher()
+	state := &node.NetworkState
+
+	d.handleStatusPush(node.Id, "prefix_added=fd00:dead:beef:cafe::/64 paros med 4000;route_added=fd00:1234::/64 s med 4000;"+
+		"service_added=44970 5d c000 s 4000")
+	assert.Equal(t, []string{"fd00:dead:beef:cafe::/64 paros med 4000"}, netDataStrings(state.NetData.Prefixes))
+	assert.Equal(t, 1, len(state.NetData.Routes))
+	assert.Equal(t, 1, len(state.NetData.Services))
+
+	// an added entry replaces the entry of the same prefix and RLOC16
+	d.handleStatusPush(node.Id, "prefix_added=fd00:dead:beef:cafe::/64 paos high 4000;prefix_added=fd00:dead:beef:cafe::/64 paros med c000")
+	assert.Equal(t, []string{"fd00:dead:beef:cafe::/64 paos high 4000", "fd00:dead:beef:cafe::/64 paros med c000"},
+		netDataStrings(state.NetData.Prefixes))
+
+	d.handleStatusPush(node.Id, "prefix_removed=fd00:dead:beef:cafe::/64 paos high 4000;route_removed=fd00:1234::/64 s med 4000;"+
+		"service_removed=44970 5d c000 s 4000")
+	assert.Equal(t, []string{"fd00:dead:beef:cafe::/64 paros med c000"}, netDataStrings(state.NetData.Prefixes))
+	assert.Empty(t, state.NetData.Routes)
+	assert.Empty(t, state.NetData.Services)
+
+	d.handleStatusPush(node.Id, "prefix_added=fd00::/129 paros med 4000")
+	assert.Equal(t, uint64(1), node.MalformedCount)
+}
+
+func TestHandleStatusPush_LeaderKeySeqChannel(t *testing.T) {
+	d, node := newTestDispatcher()
+	assert.Equal(t, InvalidRouterId, node.NetworkState.LeaderRouterId)
+
+	d.handleStatusPush(node.Id, "leader=12;key_seq=3;channel=15")
+	assert.Equal(t, 12, node.NetworkState.LeaderRouterId)
+	assert.Equal(t, uint32(3), node.NetworkState.KeySequence)
+	assert.Equal(t, 15, node.NetworkState.Channel)
+
+	d.handleStatusPush(node.Id, "leader=64;channel=27")
+	assert.Equal(t, uint64(2), node.MalformedCount)
+	assert.Equal(t, 12, node.NetworkState.LeaderRouterId)
+	assert.Equal(t, 15, node.NetworkState.Channel)
+}
+
+func TestHandleStatusPush_Srp(t *testing.T) {
+	d, node := newTestDispatcher()
+	state := &node.NetworkState
+
+	d.handleStatusPush(node.Id, "srp_host=host1,fd00::1,fd00::2;srp_host=host2;srp_service=ins1._ipps._tcp,host1,631;"+
+		"srp_service=ins2._ipps._tcp,host2,632")
+	assert.Equal(t, []SrpHost{{Name: "host1", Addresses: []string{"fd00::1", "fd00::2"}}, {Name: "host2", Addresses: []string{}}}, state.SrpHosts)
+	assert.Equal(t, []SrpService{{Name: "ins1._ipps._tcp", Host: "host1", Port: 631}, {Name: "ins2._ipps._tcp", Host: "host2", Port: 632}}, state.SrpServices)
+
+	d.handleStatusPush(node.Id, "srp_host=host1,fd00::3;srp_service=ins2._ipps._tcp,host2,633")
+	assert.Equal(t, []SrpHost{{Name: "host2", Addresses: []string{}}, {Name: "host1", Addresses: []string{"fd00::3"}}}, state.SrpHosts)
+	assert.Equal(t, []SrpService{{Name: "ins1._ipps._tcp", Host: "host1", Port: 631}, {Name: "ins2._ipps._tcp", Host: "host2", Port: 633}}, state.SrpServices)
+
+	// the services of a removed host are removed
+	d.handleStatusPush(node.Id, "srp_host_removed=host1")
+	assert.Equal(t, []SrpHost{{Name: "host2", Addresses: []string{}}}, state.SrpHosts)
+	assert.Equal(t, []SrpService{{Name: "ins2._ipps._tcp", Host: "host2", Port: 633}}, state.SrpServices)
+
+	d.handleStatusPush(node.Id, "srp_service_removed=ins2._ipps._tcp")
+	assert.Empty(t, state.SrpServices)
+
+	d.handleStatusPush(node.Id, "srp_host=host3,fd00::zz;srp_service=ins3,host3")
+	assert.Equal(t, uint64(2), node.MalformedCount)
+}
+
+func TestHandleStatusPush_NetworkStatePushed(t *testing.T) {
+	d, node := newTestDispatcher()
+	assert.False(t, node.NetworkStatePushed)
+
+	d.handleStatusPush(node.Id, "role=4")
+	assert.False(t, node.NetworkStatePushed)
+
+	d.handleStatusPush(node.Id, "channel=15")
+	assert.True(t, node.NetworkStatePushed)
+
+	state := NewNodeNetworkState()
+	state.Channel = 11
+	node.SetNetworkState(state)
+	assert.Equal(t, 11, node.NetworkState.Channel)
+}
+
+func netDataStrings(prefixes []NetDataPrefix) []string {
+	var strs []string
+	for _, prefix := range prefixes {
+		strs = append(strs, prefix.String())
+	}
+	return strs
+}
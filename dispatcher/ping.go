@@ -0,0 +1,327 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"net"
+	"sort"
+
+	. "github.com/openthread/ot-ns/types"
+)
+
+const (
+	maxPingResultCount = 1000
+	// PingTimeout is the virtual time (in us) after which a pending ping request is recorded as lost.
+	PingTimeout uint64 = 10 * 1000000
+)
+
+type pingRequest struct {
+	Timestamp uint64
+	Dst       string
+	DataSize  int
+	// Multicast is set if Dst is a multicast address, so that the request is replied by any number of nodes.
+	Multicast bool
+	Replies   int
+}
+
+type PingResult struct {
+	// Src is the node that sent the ping request.
+	Src      NodeId
+	Dst      string
+	DataSize int
+	// Timestamp is the virtual time (in us) when the ping request was sent.
+	Timestamp uint64
+	// Delay is the round-trip time (in us), or PingTimeout if the ping timed out.
+	Delay uint64
+	// HopLimit is the hop limit of the ping reply, or 0 if the ping timed out.
+	HopLimit int
+	// ReplyAddr is the address of the node that replied, which differs from Dst for multicast pings,
+	// or empty if the ping timed out.
+	ReplyAddr string
+	// Timeout is set if no ping reply was received within PingTimeout.
+	Timeout bool
+}
+
+// PingStats is the aggregate statistics of the ping results from a source node to a destination address.
+// Each ping request is counted once, and its delay is the delay of its first reply.
+type PingStats struct {
+	Src      NodeId
+	Dst      string
+	Sent     int
+	Lost     int
+	MinDelay uint64
+	AvgDelay uint64
+	MaxDelay uint64
+	P50Delay uint64
+	P95Delay uint64
+	P99Delay uint64
+	// Repliers is the statistics of each replying address of multicast pings, sorted by address.
+	Repliers []*PingReplierStats
+}
+
+// PingReplierStats is the aggregate statistics of the replies from a replying address to multicast pings.
+type PingReplierStats struct {
+	Addr     string
+	Replies  int
+	MinDelay uint64
+	AvgDelay uint64
+	MaxDelay uint64
+}
+
+// LossRate returns the percentage of the lost pings.
+func (s *PingStats) LossRate() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Lost) * 100 / float64(s.Sent)
+}
+
+// ComputePingStats aggregates the ping results per source node and destination address.
+// Ping results of the same request (e.g. multiple replies to a multicast ping) are counted as one sent ping.
+// The returned statistics are sorted by source node and destination address.
+func ComputePingStats(results []*PingResult) []*PingStats {
+	type pingPair struct {
+		Src NodeId
+		Dst string
+	}
+	type pingRequestKey struct {
+		pingPair
+		Timestamp uint64
+	}
+
+	var allStats []*PingStats
+	statsByPair := map[pingPair]*PingStats{}
+	requestDelays := map[pingRequestKey]uint64{}
+	replierDelays := map[pingPair]map[string][]uint64{}
+	sentRequests := map[pingRequestKey]struct{}{}
+	for _, res := range results {
+		pair := pingPair{res.Src, res.Dst}
+		stats := statsByPair[pair]
+		if stats == nil {
+			stats = &PingStats{Src: res.Src, Dst: res.Dst}
+			statsByPair[pair] = stats
+			allStats = append(allStats, stats)
+		}
+
+		key := pingRequestKey{pair, res.Timestamp}
+		if _, ok := sentRequests[key]; !ok {
+			sentRequests[key] = struct{}{}
+			stats.Sent += 1
+		}
+
+		if res.Timeout {
+			stats.Lost += 1
+			continue
+		}
+
+		if delay, ok := requestDelays[key]; !ok || res.Delay < delay {
+			requestDelays[key] = res.Delay
+		}
+
+		if ip := net.ParseIP(res.Dst); ip != nil && ip.IsMulticast() {
+			if replierDelays[pair] == nil {
+				replierDelays[pair] = map[string][]uint64{}
+			}
+			replierDelays[pair][res.ReplyAddr] = append(replierDelays[pair][res.ReplyAddr], res.Delay)
+		}
+	}
+
+	delays := map[pingPair][]uint64{}
+	for key, delay := range requestDelays {
+		delays[key.pingPair] = append(delays[key.pingPair], delay)
+	}
+
+	for pair, pairDelays := range delays {
+		sortDelays(pairDelays)
+
+		stats := statsByPair[pair]
+		stats.MinDelay = pairDelays[0]
+		stats.MaxDelay = pairDelays[len(pairDelays)-1]
+		stats.AvgDelay = averageDelay(pairDelays)
+		stats.P50Delay = percentile(pairDelays, 50)
+		stats.P95Delay = percentile(pairDelays, 95)
+		stats.P99Delay = percentile(pairDelays, 99)
+	}
+
+	for pair, repliers := range replierDelays {
+		stats := statsByPair[pair]
+		for addr, addrDelays := range repliers {
+			sortDelays(addrDelays)
+			stats.Repliers = append(stats.Repliers, &PingReplierStats{
+				Addr:     addr,
+				Replies:  len(addrDelays),
+				MinDelay: addrDelays[0],
+				AvgDelay: averageDelay(addrDelays),
+				MaxDelay: addrDelays[len(addrDelays)-1],
+			})
+		}
+		sort.Slice(stats.Repliers, func(i, j int) bool {
+			return stats.Repliers[i].Addr < stats.Repliers[j].Addr
+		})
+	}
+
+	sort.Slice(allStats, func(i, j int) bool {
+		if allStats[i].Src != allStats[j].Src {
+			return allStats[i].Src < allStats[j].Src
+		}
+		return allStats[i].Dst < allStats[j].Dst
+	})
+	return allStats
+}
+
+func sortDelays(delays []uint64) {
+	sort.Slice(delays, func(i, j int) bool {
+		return delays[i] < delays[j]
+	})
+}
+
+func averageDelay(delays []uint64) uint64 {
+	var sum uint64
+	for _, delay := range delays {
+		sum += delay
+	}
+	return sum / uint64(len(delays))
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []uint64, p int) uint64 {
+	rank := (len(sorted)*p + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func (node *Node) onPingRequest(timestamp uint64, dstaddr string, datasize int) {
+	if datasize < 4 {
+		// if datasize < 4, timestamp is 0, these ping requests are ignored
+		return
+	}
+
+	ip := net.ParseIP(dstaddr)
+	node.pendingPings = append(node.pendingPings, &pingRequest{
+		Timestamp: timestamp,
+		Dst:       dstaddr,
+		DataSize:  datasize,
+		Multicast: ip != nil && ip.IsMulticast(),
+	})
+	node.D.schedulePingExpiry(timestamp + PingTimeout)
+}
+
+func (node *Node) onPingReply(timestamp uint64, dstaddr string, datasize int, hoplimit int) {
+	if datasize < 4 {
+		// if datasize < 4, timestamp is 0, these ping replies are ignored
+		return
+	}
+
+	for i, req := range node.pendingPings {
+		if req.Timestamp != timestamp {
+			continue
+		}
+
+		if req.Multicast {
+			// multicast pings are replied by the unicast addresses of any number of nodes until timeout
+			req.Replies += 1
+			node.addPingResult(req, node.D.CurTime-req.Timestamp, hoplimit, dstaddr)
+			return
+		} else if req.Dst == dstaddr {
+			// ping replied
+			node.addPingResult(req, node.D.CurTime-req.Timestamp, hoplimit, dstaddr)
+			node.pendingPings = append(node.pendingPings[:i], node.pendingPings[i+1:]...)
+			return
+		}
+	}
+}
+
+// expirePings records the pending pings that are not replied within PingTimeout as lost.
+// It returns the expiry time of the earliest remaining pending ping, or 0 if there is none.
+func (node *Node) expirePings(curTime uint64) uint64 {
+	var nextExpiryTime uint64
+	var leftPingRequests []*pingRequest
+	for _, req := range node.pendingPings {
+		expiryTime := req.Timestamp + PingTimeout
+		if expiryTime <= curTime {
+			if req.Replies == 0 {
+				// ping timeout
+				node.addPingResult(req, PingTimeout, 0, "")
+			}
+		} else {
+			leftPingRequests = append(leftPingRequests, req)
+			if nextExpiryTime == 0 || expiryTime < nextExpiryTime {
+				nextExpiryTime = expiryTime
+			}
+		}
+	}
+
+	node.pendingPings = leftPingRequests
+	return nextExpiryTime
+}
+
+// addPingResult records a result of the ping request, which timed out if replyAddr is empty.
+func (node *Node) addPingResult(req *pingRequest, delay uint64, hoplimit int, replyAddr string) {
+	node.pingResults = append(node.pingResults, &PingResult{
+		Src:       node.Id,
+		Dst:       req.Dst,
+		DataSize:  req.DataSize,
+		Timestamp: req.Timestamp,
+		Delay:     delay,
+		HopLimit:  hoplimit,
+		ReplyAddr: replyAddr,
+		Timeout:   replyAddr == "",
+	})
+
+	if len(node.pingResults) > maxPingResultCount {
+		node.pingResults = node.pingResults[1:]
+	}
+}
+
+// Pings returns the finished pings of the node without collecting them.
+func (node *Node) Pings() []*PingResult {
+	return append([]*PingResult(nil), node.pingResults...)
+}
+
+func (node *Node) CollectPings() []*PingResult {
+	ret := node.pingResults
+	node.pingResults = nil
+	return ret
+}
+
+func (d *Dispatcher) schedulePingExpiry(expiryTime uint64) {
+	if d.pingExpiryTime == 0 || expiryTime < d.pingExpiryTime {
+		d.pingExpiryTime = expiryTime
+	}
+}
+
+// expirePings records the pending pings of all nodes that have timed out at the current time as lost.
+func (d *Dispatcher) expirePings() {
+	d.pingExpiryTime = 0
+	for _, node := range d.nodes {
+		if expiryTime := node.expirePings(d.CurTime); expiryTime != 0 {
+			d.schedulePingExpiry(expiryTime)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPingReplied(t *testing.T) {
+	d, node := newTestDispatcher()
+
+	node.onPingRequest(1000, "fdde:ad00:beef:0::1", 10)
+	d.advanceTime(3500)
+	node.onPingReply(1000, "fdde:ad00:beef:0::1", 10, 63)
+	assert.Empty(t, node.pendingPings)
+
+	pings := node.CollectPings()
+	assert.Equal(t, []*PingResult{{
+		Src: 1, Dst: "fdde:ad00:beef:0::1", DataSize: 10, Timestamp: 1000, Delay: 2500, HopLimit: 63, ReplyAddr: "fdde:ad00:beef:0::1",
+	}}, pings)
+	assert.Empty(t, node.CollectPings())
+}
+
+func TestPingTimeout(t *testing.T) {
+	d, node := newTestDispatcher()
+
+	node.onPingRequest(1000, "fdde:ad00:beef:0::1", 10)
+	node.onPingRequest(2000, "fdde:ad00:beef:0::1", 10)
+	// ping requests with a data size less than 4 bytes are ignored
+	node.onPingRequest(2000, "fdde:ad00:beef:0::1", 2)
+	assert.Equal(t, 1000+PingTimeout, d.pingExpiryTime)
+
+	d.advanceTime(1000 + PingTimeout - 1)
+	assert.Empty(t, node.Pings())
+
+	// pending pings expire as the virtual time advances, without any ping reply
+	d.advanceTime(1000 + PingTimeout)
+	assert.Equal(t, []*PingResult{{
+		Src: 1, Dst: "fdde:ad00:beef:0::1", DataSize: 10, Timestamp: 1000, Delay: PingTimeout, Timeout: true,
+	}}, node.Pings())
+	assert.Equal(t, 2000+PingTimeout, d.pingExpiryTime)
+
+	// the ping reply after the timeout is ignored
+	node.onPingReply(1000, "fdde:ad00:beef:0::1", 10, 64)
+	assert.Len(t, node.Pings(), 1)
+
+	d.advanceTime(3000 + PingTimeout)
+	assert.Len(t, node.CollectPings(), 2)
+	assert.Empty(t, node.pendingPings)
+	assert.Equal(t, uint64(0), d.pingExpiryTime)
+}
+
+func TestPingMulticast(t *testing.T) {
+	d, node := newTestDispatcher()
+
+	node.onPingRequest(1000, "ff03::1", 10)
+	node.onPingRequest(2000, "ff03::1", 10)
+	d.advanceTime(3000)
+	// multicast pings are replied by unicast addresses of multiple nodes
+	node.onPingReply(1000, "fdde:ad00:beef:0::2", 10, 64)
+	node.onPingReply(1000, "fdde:ad00:beef:0::3", 10, 63)
+	assert.Len(t, node.pendingPings, 2)
+
+	d.advanceTime(2000 + PingTimeout)
+	assert.Empty(t, node.pendingPings)
+
+	// replied multicast pings are not recorded as lost after the timeout
+	pings := node.CollectPings()
+	assert.Len(t, pings, 3)
+	assert.Equal(t, []string{"fdde:ad00:beef:0::2", "fdde:ad00:beef:0::3", ""},
+		[]string{pings[0].ReplyAddr, pings[1].ReplyAddr, pings[2].ReplyAddr})
+	assert.Equal(t, uint64(2000), pings[0].Delay)
+	assert.Equal(t, "ff03::1", pings[1].Dst)
+	assert.True(t, pings[2].Timeout)
+	assert.Equal(t, uint64(2000), pings[2].Timestamp)
+}
+
+func TestComputePingStats(t *testing.T) {
+	var results []*PingResult
+	for i := 1; i <= 100; i++ {
+		results = append(results, &PingResult{
+			Src: 2, Dst: "fdde:ad00:beef:0::1", Timestamp: uint64(i * 1000000), Delay: uint64(i * 1000),
+		})
+	}
+	results = append(results,
+		&PingResult{Src: 2, Dst: "fdde:ad00:beef:0::1", Timestamp: 101000000, Delay: PingTimeout, Timeout: true},
+		&PingResult{Src: 1, Dst: "fdde:ad00:beef:0::2", Delay: PingTimeout, Timeout: true},
+	)
+
+	stats := ComputePingStats(results)
+	assert.Equal(t, []*PingStats{
+		{Src: 1, Dst: "fdde:ad00:beef:0::2", Sent: 1, Lost: 1},
+		{
+			Src: 2, Dst: "fdde:ad00:beef:0::1", Sent: 101, Lost: 1,
+			MinDelay: 1000, AvgDelay: 50500, MaxDelay: 100000, P50Delay: 50000, P95Delay: 95000, P99Delay: 99000,
+		},
+	}, stats)
+	assert.Equal(t, float64(100), stats[0].LossRate())
+	assert.InDelta(t, 0.99, stats[1].LossRate(), 0.01)
+	assert.Empty(t, ComputePingStats(nil))
+}
+
+func TestComputePingStats_Multicast(t *testing.T) {
+	results := []*PingResult{
+		{Src: 1, Dst: "ff03::1", Timestamp: 1000000, Delay: 3000, ReplyAddr: "fdde:ad00:beef:0::2"},
+		{Src: 1, Dst: "ff03::1", Timestamp: 1000000, Delay: 5000, ReplyAddr: "fdde:ad00:beef:0::3"},
+		{Src: 1, Dst: "ff03::1", Timestamp: 2000000, Delay: 7000, ReplyAddr: "fdde:ad00:beef:0::3"},
+		{Src: 1, Dst: "ff03::1", Timestamp: 3000000, Delay: PingTimeout, Timeout: true},
+	}
+
+	stats := ComputePingStats(results)
+	assert.Equal(t, []*PingStats{
+		{
+			Src: 1, Dst: "ff03::1", Sent: 3, Lost: 1,
+			MinDelay: 3000, AvgDelay: 5000, MaxDelay: 7000, P50Delay: 3000, P95Delay: 7000, P99Delay: 7000,
+			Repliers: []*PingReplierStats{
+				{Addr: "fdde:ad00:beef:0::2", Replies: 1, MinDelay: 3000, AvgDelay: 3000, MaxDelay: 3000},
+				{Addr: "fdde:ad00:beef:0::3", Replies: 2, MinDelay: 5000, AvgDelay: 6000, MaxDelay: 7000},
+			},
+		},
+	}, stats)
+}
+
+func TestPercentile(t *testing.T) {
+	assert.Equal(t, uint64(5), percentile([]uint64{5}, 50))
+	assert.Equal(t, uint64(5), percentile([]uint64{5}, 0))
+	assert.Equal(t, uint64(1), percentile([]uint64{1, 2}, 50))
+	assert.Equal(t, uint64(2), percentile([]uint64{1, 2}, 99))
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"time"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// PowerOffNode marks the node as powered off after its process is shut down.
+// The simulation is notified to power on the node after the virtual `duration` (in us), unless `duration` is Ever.
+func (d *Dispatcher) PowerOffNode(id NodeId, duration uint64) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+
+	node.PoweredOff = true
+	if !d.cfg.Real {
+		d.setSleeping(id)
+	}
+
+	powerOnTime := d.CurTime + duration
+	if duration >= Ever || powerOnTime < d.CurTime {
+		powerOnTime = Ever
+	}
+	d.alarmMgr.SetTimestamp(id, powerOnTime)
+	d.setNodeRole(id, OtDeviceRoleDisabled)
+
+	if !node.isFailed {
+		d.vis.OnNodeFail(id)
+	}
+}
+
+// onPowerOnTime notifies the simulation to start a new process for the powered off node.
+func (d *Dispatcher) onPowerOnTime(id NodeId) {
+	d.alarmMgr.SetNotified(id)
+	d.cbHandler.OnNodePowerOn(id)
+}
+
+// RestartNode clears the crashed, powered off and quarantined state of the node after a new process has been started for it,
+// and waits until the new process is ready to receive events.
+func (d *Dispatcher) RestartNode(id NodeId) {
+	node := d.nodes[id]
+	simplelogger.AssertNotNil(node)
+
+	crashed, poweredOff := node.Crashed, node.PoweredOff
+	node.Crashed, node.PoweredOff = false, false
+	node.Process++
+	// the new process starts from the current time
+	node.CurTime = d.CurTime
+	node.peerAddr = nil
+	// the new process gets a clean record of malformed messages
+	node.MalformedCount, node.Quarantined = 0, false
+	node.malformed = malformedTracker{}
+	// the new process reports its network state again
+	node.NetworkState, node.NetworkStatePushed = NewNodeNetworkState(), false
+	node.visNetworkState()
+	d.alarmMgr.SetNotified(id)
+	d.setAlive(id)
+
+	if !d.cfg.Real {
+		deadline := time.Now().Add(time.Second * 10)
+		for node.peerAddr == nil && !node.Crashed && time.Now().Before(deadline) {
+			d.RecvEvents()
+		}
+
+		if node.peerAddr == nil {
+			simplelogger.Warnf("node %d does not send any event after restart", id)
+		}
+	}
+
+	if crashed {
+		d.vis.OnNodeRestart(id)
+	} else if poweredOff && !node.isFailed {
+		d.vis.OnNodeRecover(id)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPowerOffNode(t *testing.T) {
+	d, node := newTestDispatcher()
+	cb := d.cbHandler.(*testCallbackHandler)
+	d.setSleeping(node.Id)
+	node.Role = OtDeviceRoleRouter
+
+	d.PowerOffNode(node.Id, 500)
+	assert.True(t, node.PoweredOff)
+	assert.Equal(t, OtDeviceRoleDisabled, node.Role)
+	assert.Equal(t, uint64(1500), d.alarmMgr.GetTimestamp(node.Id))
+
+	// UART input is dropped while the node is powered off
+	d.SendToUART(node.Id, []byte("state\n"))
+	assert.Equal(t, uint64(1500), d.alarmMgr.GetTimestamp(node.Id))
+	assert.Equal(t, 0, d.GetAliveCount())
+
+	d.pauseTime = 1400
+	d.processNextEvent()
+	assert.Empty(t, cb.poweredOn)
+
+	d.pauseTime = 2000
+	d.processNextEvent()
+	assert.Equal(t, []NodeId{1}, cb.poweredOn)
+	assert.Equal(t, uint64(1500), d.CurTime)
+	assert.Equal(t, Ever, d.alarmMgr.GetTimestamp(node.Id))
+	assert.True(t, node.PoweredOff)
+
+	d.eventChan <- &event{NodeId: node.Id, Type: eventTypeAlarmFired, Delay: 10, SrcAddr: &net.UDPAddr{Port: 9001}}
+	d.RestartNode(node.Id)
+	assert.False(t, node.PoweredOff)
+	assert.Equal(t, uint64(1500), node.CurTime)
+	assert.Equal(t, uint64(1510), d.alarmMgr.GetTimestamp(node.Id))
+}
+
+func TestPowerOffNode_Ever(t *testing.T) {
+	d, node := newTestDispatcher()
+
+	d.PowerOffNode(node.Id, Ever)
+	assert.Equal(t, Ever, d.alarmMgr.GetTimestamp(node.Id))
+}
+
+func TestRestartNode_Quarantined(t *testing.T) {
+	d, node := newTestDispatcher()
+	for i := 0; i < quarantineThreshold; i++ {
+		d.handleRecvEvent(&event{NodeId: node.Id, Type: 0xff})
+	}
+	assert.True(t, node.Quarantined)
+
+	d.PowerOffNode(node.Id, Ever)
+	d.eventChan <- &event{NodeId: node.Id, Type: eventTypeAlarmFired, Delay: 10, SrcAddr: &net.UDPAddr{Port: 9001}}
+	d.RestartNode(node.Id)
+	assert.False(t, node.Quarantined)
+	assert.Equal(t, uint64(0), node.MalformedCount)
+	assert.NotNil(t, node.peerAddr)
+}
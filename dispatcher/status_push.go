@@ -0,0 +1,214 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// StatusPushHandler handles the value of a status push key sent by a node. It is called in the dispatcher routine.
+// A returned error counts the status push as malformed.
+type StatusPushHandler func(d *Dispatcher, node *Node, value string) error
+
+var (
+	statusPushHandlersLock sync.RWMutex
+	statusPushHandlers     = map[string]StatusPushHandler{}
+)
+
+// RegisterStatusPushHandler registers the handler of a status push key, e.g. a custom key pushed by a forked
+// OpenThread build. It panics if the key is already registered.
+func RegisterStatusPushHandler(key string, handler StatusPushHandler) {
+	simplelogger.AssertNotNil(handler)
+
+	statusPushHandlersLock.Lock()
+	defer statusPushHandlersLock.Unlock()
+
+	if _, ok := statusPushHandlers[key]; ok {
+		simplelogger.Panicf("status push handler of %s is already registered", key)
+	}
+	statusPushHandlers[key] = handler
+}
+
+// UnregisterStatusPushHandler unregisters the handler of a status push key.
+func UnregisterStatusPushHandler(key string) {
+	statusPushHandlersLock.Lock()
+	delete(statusPushHandlers, key)
+	statusPushHandlersLock.Unlock()
+}
+
+func getStatusPushHandler(key string) StatusPushHandler {
+	statusPushHandlersLock.RLock()
+	defer statusPushHandlersLock.RUnlock()
+
+	return statusPushHandlers[key]
+}
+
+// StatusPushInt returns a status push handler which parses the value as a decimal integer in [min, max].
+func StatusPushInt(min, max int, handler func(d *Dispatcher, node *Node, value int) error) StatusPushHandler {
+	return func(d *Dispatcher, node *Node, value string) error {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		if v < min || v > max {
+			return errors.Errorf("%d out of range [%d, %d]", v, min, max)
+		}
+		return handler(d, node, v)
+	}
+}
+
+// StatusPushUint returns a status push handler which parses the value as an unsigned integer of the base and bit size.
+func StatusPushUint(base int, bitSize int, handler func(d *Dispatcher, node *Node, value uint64) error) StatusPushHandler {
+	return func(d *Dispatcher, node *Node, value string) error {
+		v, err := strconv.ParseUint(value, base, bitSize)
+		if err != nil {
+			return err
+		}
+		return handler(d, node, v)
+	}
+}
+
+// StatusPushArgs returns a status push handler which splits the value into at least minArgs comma-separated arguments.
+func StatusPushArgs(minArgs int, handler func(d *Dispatcher, node *Node, args []string) error) StatusPushHandler {
+	return func(d *Dispatcher, node *Node, value string) error {
+		args := strings.Split(value, ",")
+		if len(args) < minArgs {
+			return errors.Errorf("expect %d arguments, but got %d", minArgs, len(args))
+		}
+		return handler(d, node, args)
+	}
+}
+
+func init() {
+	RegisterStatusPushHandler("transmit", func(d *Dispatcher, node *Node, value string) error {
+		return d.visStatusPushTransmit(node, value)
+	})
+	minRole, maxRole := int(OtDeviceRoleDisabled), int(OtDeviceRoleLeader)
+	RegisterStatusPushHandler("role", StatusPushInt(minRole, maxRole, func(d *Dispatcher, node *Node, role int) error {
+		d.setNodeRole(node.Id, OtDeviceRole(role))
+		return nil
+	}))
+	RegisterStatusPushHandler("rloc16", StatusPushUint(10, 16, func(d *Dispatcher, node *Node, rloc16 uint64) error {
+		d.setNodeRloc16(node.Id, uint16(rloc16))
+		return nil
+	}))
+	// e.x. ping_request=fdde:ad00:beef:0:556:90c8:ffaf:b7a3,0,4026600960
+	RegisterStatusPushHandler("ping_request", StatusPushArgs(3, func(d *Dispatcher, node *Node, args []string) error {
+		dstaddr := args[0]
+		datasize, err := strconv.Atoi(args[1])
+		if err != nil {
+			return err
+		}
+		timestamp, err := strconv.ParseUint(args[2], 10, 32)
+		if err != nil {
+			return err
+		}
+		node.onPingRequest(d.convertNodeMilliTime(node, uint32(timestamp)), dstaddr, datasize)
+		return nil
+	}))
+	// e.x. ping_reply=fdde:ad00:beef:0:556:90c8:ffaf:b7a3,0,0,64
+	RegisterStatusPushHandler("ping_reply", StatusPushArgs(4, func(d *Dispatcher, node *Node, args []string) error {
+		dstaddr := args[0]
+		datasize, err := strconv.Atoi(args[1])
+		if err != nil {
+			return err
+		}
+		timestamp, err := strconv.ParseUint(args[2], 10, 32)
+		if err != nil {
+			return err
+		}
+		hoplimit, err := strconv.Atoi(args[3])
+		if err != nil {
+			return err
+		}
+		node.onPingReply(d.convertNodeMilliTime(node, uint32(timestamp)), dstaddr, datasize, hoplimit)
+		return nil
+	}))
+	RegisterStatusPushHandler("coap", func(d *Dispatcher, node *Node, value string) error {
+		return d.handleCoapEvent(node, value)
+	})
+	RegisterStatusPushHandler("parid", StatusPushUint(16, 32, func(d *Dispatcher, node *Node, parid uint64) error {
+		node.PartitionId = uint32(parid)
+		d.vis.SetNodePartitionId(node.Id, uint32(parid))
+		return nil
+	}))
+	RegisterStatusPushHandler("router_added", StatusPushUint(16, 64, func(d *Dispatcher, node *Node, extaddr uint64) error {
+		if d.visOptions.RouterTable {
+			d.vis.AddRouterTable(node.Id, extaddr)
+		}
+		return nil
+	}))
+	RegisterStatusPushHandler("router_removed", StatusPushUint(16, 64, func(d *Dispatcher, node *Node, extaddr uint64) error {
+		if d.visOptions.RouterTable {
+			d.vis.RemoveRouterTable(node.Id, extaddr)
+		}
+		return nil
+	}))
+	RegisterStatusPushHandler("child_added", StatusPushUint(16, 64, func(d *Dispatcher, node *Node, extaddr uint64) error {
+		if d.visOptions.ChildTable {
+			d.vis.AddChildTable(node.Id, extaddr)
+		}
+		return nil
+	}))
+	RegisterStatusPushHandler("child_removed", StatusPushUint(16, 64, func(d *Dispatcher, node *Node, extaddr uint64) error {
+		if d.visOptions.ChildTable {
+			d.vis.RemoveChildTable(node.Id, extaddr)
+		}
+		return nil
+	}))
+	RegisterStatusPushHandler("parent", StatusPushUint(16, 64, func(d *Dispatcher, node *Node, extaddr uint64) error {
+		d.vis.SetParent(node.Id, extaddr)
+		return nil
+	}))
+	minJoinerState, maxJoinerState := int(OtJoinerStateIdle), int(OtJoinerStateJoined)
+	RegisterStatusPushHandler("joiner_state", StatusPushInt(minJoinerState, maxJoinerState, func(d *Dispatcher, node *Node, state int) error {
+		node.onJoinerState(OtJoinerState(state))
+		return nil
+	}))
+	RegisterStatusPushHandler("extaddr", StatusPushUint(16, 64, func(d *Dispatcher, node *Node, extaddr uint64) error {
+		if extaddr == InvalidExtAddr {
+			return errors.Errorf("invalid extaddr")
+		}
+		if other := d.extaddrMap[extaddr]; other != nil && other != node {
+			return errors.Errorf("extaddr already used by node %d", other.Id)
+		}
+		node.onStatusPushExtAddr(extaddr)
+		return nil
+	}))
+	RegisterStatusPushHandler("mode", func(d *Dispatcher, node *Node, value string) error {
+		d.vis.SetNodeMode(node.Id, ParseNodeMode(value))
+		return nil
+	})
+
+	registerNetworkStateStatusPushHandlers()
+}
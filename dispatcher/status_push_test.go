@@ -0,0 +1,90 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterStatusPushHandler(t *testing.T) {
+	d, node := newTestDispatcher()
+
+	var temperatures []int
+	RegisterStatusPushHandler("test_temp", StatusPushInt(-40, 125, func(d *Dispatcher, n *Node, temp int) error {
+		assert.Equal(t, node, n)
+		temperatures = append(temperatures, temp)
+		return nil
+	}))
+	defer UnregisterStatusPushHandler("test_temp")
+
+	var readings [][]string
+	RegisterStatusPushHandler("test_readings", StatusPushArgs(2, func(d *Dispatcher, n *Node, args []string) error {
+		readings = append(readings, args)
+		return nil
+	}))
+	defer UnregisterStatusPushHandler("test_readings")
+
+	d.handleStatusPush(node.Id, "test_temp=25;test_readings=a,b,c;role=3")
+	assert.Equal(t, []int{25}, temperatures)
+	assert.Equal(t, [][]string{{"a", "b", "c"}}, readings)
+	assert.Equal(t, uint64(0), node.MalformedCount)
+
+	// errors of typed parsing count the status push as malformed
+	d.handleStatusPush(node.Id, "test_temp=x;test_temp=126;test_readings=a")
+	assert.Equal(t, []int{25}, temperatures)
+	assert.Equal(t, 1, len(readings))
+	assert.Equal(t, uint64(3), node.MalformedCount)
+	assert.Equal(t, uint64(3), d.Counters.MalformedStatusPushes)
+
+	assert.Panics(t, func() {
+		RegisterStatusPushHandler("test_temp", StatusPushInt(0, 1, nil))
+	})
+	assert.Panics(t, func() {
+		RegisterStatusPushHandler("role", StatusPushInt(0, 1, nil))
+	})
+
+	// unknown keys are ignored
+	UnregisterStatusPushHandler("test_temp")
+	d.handleStatusPush(node.Id, "test_temp=30")
+	assert.Equal(t, []int{25}, temperatures)
+	assert.Equal(t, uint64(3), node.MalformedCount)
+}
+
+func TestStatusPushUint(t *testing.T) {
+	var values []uint64
+	handler := StatusPushUint(16, 16, func(d *Dispatcher, node *Node, value uint64) error {
+		values = append(values, value)
+		return nil
+	})
+
+	assert.Nil(t, handler(nil, nil, "fffe"))
+	assert.NotNil(t, handler(nil, nil, "10000"))
+	assert.NotNil(t, handler(nil, nil, "zz"))
+	assert.Equal(t, []uint64{0xfffe}, values)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"container/heap"
+
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+type scheduledTask struct {
+	Timestamp uint64
+	Seq       uint64
+	Task      func()
+}
+
+// taskQueue keeps the tasks scheduled at virtual time, in order of timestamp and then of scheduling.
+type taskQueue struct {
+	q       []*scheduledTask
+	nextSeq uint64
+}
+
+func (tq taskQueue) Len() int {
+	return len(tq.q)
+}
+
+func (tq taskQueue) Less(i, j int) bool {
+	if tq.q[i].Timestamp != tq.q[j].Timestamp {
+		return tq.q[i].Timestamp < tq.q[j].Timestamp
+	}
+	return tq.q[i].Seq < tq.q[j].Seq
+}
+
+func (tq taskQueue) Swap(i, j int) {
+	tq.q[i], tq.q[j] = tq.q[j], tq.q[i]
+}
+
+func (tq *taskQueue) Push(x interface{}) {
+	tq.q = append(tq.q, x.(*scheduledTask))
+}
+
+func (tq *taskQueue) Pop() (elem interface{}) {
+	tqlen := len(tq.q)
+	elem = tq.q[tqlen-1]
+	tq.q = tq.q[:tqlen-1]
+	return
+}
+
+func (tq taskQueue) NextTimestamp() uint64 {
+	if len(tq.q) > 0 {
+		return tq.q[0].Timestamp
+	} else {
+		return Ever
+	}
+}
+
+func (tq *taskQueue) Add(timestamp uint64, task func()) {
+	heap.Push(tq, &scheduledTask{
+		Timestamp: timestamp,
+		Seq:       tq.nextSeq,
+		Task:      task,
+	})
+	tq.nextSeq += 1
+}
+
+func (tq *taskQueue) PopNext() *scheduledTask {
+	return heap.Pop(tq).(*scheduledTask)
+}
+
+func newTaskQueue() *taskQueue {
+	tq := &taskQueue{
+		q: []*scheduledTask{},
+	}
+	heap.Init(tq)
+	return tq
+}
+
+// ScheduleTask schedules the task to run in the dispatcher routine when the virtual time reaches the timestamp (in us).
+// Tasks scheduled in the past run at the current time. It must be called in the dispatcher routine.
+func (d *Dispatcher) ScheduleTask(timestamp uint64, task func()) {
+	if timestamp < d.CurTime {
+		timestamp = d.CurTime
+	}
+	d.scheduledTasks.Add(timestamp, task)
+}
+
+// handleScheduledTasks runs the scheduled tasks that are due at the current time.
+func (d *Dispatcher) handleScheduledTasks() {
+	defer func() {
+		err := recover()
+		if err != nil {
+			simplelogger.Errorf("dispatcher handle scheduled task failed: %+v", err)
+		}
+	}()
+
+	for d.scheduledTasks.NextTimestamp() <= d.CurTime {
+		d.scheduledTasks.PopNext().Task()
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskQueue_PopNext(t *testing.T) {
+	q := newTaskQueue()
+	assert.Equal(t, Ever, q.NextTimestamp())
+
+	var order []int
+	q.Add(2, func() { order = append(order, 1) })
+	q.Add(1, func() { order = append(order, 2) })
+	q.Add(2, func() { order = append(order, 3) })
+	assert.Equal(t, 3, q.Len())
+	assert.Equal(t, uint64(1), q.NextTimestamp())
+
+	for q.Len() > 0 {
+		q.PopNext().Task()
+	}
+	// tasks with the same timestamp run in order of scheduling
+	assert.Equal(t, []int{2, 1, 3}, order)
+}
+
+func TestScheduleTask(t *testing.T) {
+	d, node := newTestDispatcher()
+	d.alarmMgr.SetTimestamp(node.Id, 5000)
+
+	var runTimes []uint64
+	d.ScheduleTask(3000, func() {
+		runTimes = append(runTimes, d.CurTime)
+	})
+	// tasks scheduled in the past run at the current time
+	d.ScheduleTask(500, func() {
+		runTimes = append(runTimes, d.CurTime)
+	})
+
+	d.pauseTime = 10000
+	d.handleScheduledTasks()
+	assert.Equal(t, []uint64{1000}, runTimes)
+
+	// the virtual time advances to the scheduled task rather than to the later alarm
+	d.processNextEvent()
+	assert.Equal(t, uint64(3000), d.CurTime)
+	d.handleScheduledTasks()
+	assert.Equal(t, []uint64{1000, 3000}, runTimes)
+	assert.Equal(t, Ever, d.scheduledTasks.NextTimestamp())
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dispatcher
+
+import (
+	. "github.com/openthread/ot-ns/types"
+	"github.com/openthread/ot-ns/visualize"
+)
+
+// testCallbackHandler records the node crashes and power-ons notified by the dispatcher.
+type testCallbackHandler struct {
+	crashed   []NodeId
+	poweredOn []NodeId
+}
+
+func (h *testCallbackHandler) OnNodeFail(nodeid NodeId) {
+}
+
+func (h *testCallbackHandler) OnNodeRecover(nodeid NodeId) {
+}
+
+func (h *testCallbackHandler) OnNodeCrash(nodeid NodeId) {
+	h.crashed = append(h.crashed, nodeid)
+}
+
+func (h *testCallbackHandler) OnNodePowerOn(nodeid NodeId) {
+	h.poweredOn = append(h.poweredOn, nodeid)
+}
+
+func (h *testCallbackHandler) OnUartWrite(nodeid NodeId, data []byte) {
+}
+
+// newTestDispatcher creates a dispatcher without a network connection, with node 1 added at virtual time 1000.
+// Its callback handler is a *testCallbackHandler.
+func newTestDispatcher() (*Dispatcher, *Node) {
+	d := &Dispatcher{
+		cbHandler:      &testCallbackHandler{},
+		nodes:          map[NodeId]*Node{},
+		aliveNodes:     map[NodeId]struct{}{},
+		extaddrMap:     map[uint64]*Node{},
+		rloc16Map:      rloc16Map{},
+		alarmMgr:       newAlarmMgr(),
+		sendQueue:      newSendQueue(),
+		scheduledTasks: newTaskQueue(),
+		eventChan:      make(chan *event, 10),
+		crashChan:      make(chan crashNotification, 10),
+		vis:            visualize.NewNopVisualizer(),
+		speed:          MaxSimulateSpeed,
+		CurTime:        1000,
+		pauseTime:      1000,
+	}
+	node := d.newNode(1, 0, 0, 100)
+	return d, node
+}
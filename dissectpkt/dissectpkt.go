@@ -27,26 +27,245 @@
 package dissectpkt
 
 import (
+	"fmt"
+	"net"
+
+	"github.com/openthread/ot-ns/dissectpkt/lowpan"
+	"github.com/openthread/ot-ns/dissectpkt/mle"
+	"github.com/openthread/ot-ns/dissectpkt/security"
 	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	"github.com/openthread/ot-ns/threadconst"
+	"github.com/simonlingoogle/go-simplelogger"
 )
 
-type WpanFrameType int
+// PktType is the classification of a dissected frame.
+type PktType int
 
 const (
-	MLE WpanFrameType = iota
-	MAC               = iota
+	PktTypeUnknown PktType = iota
+	PktTypeAck
+	PktTypeBeacon
+	PktTypeMacCommand
+	PktTypeMle
+	PktTypeTmf
+	PktTypeIcmp6
+	PktTypeData
+	PktTypeFragment
+	PktTypeEncrypted
 )
 
+func (t PktType) String() string {
+	switch t {
+	case PktTypeAck:
+		return "ACK"
+	case PktTypeBeacon:
+		return "Beacon"
+	case PktTypeMacCommand:
+		return "MAC Command"
+	case PktTypeMle:
+		return "MLE"
+	case PktTypeTmf:
+		return "TMF"
+	case PktTypeIcmp6:
+		return "ICMPv6"
+	case PktTypeData:
+		return "Data"
+	case PktTypeFragment:
+		return "Fragment"
+	case PktTypeEncrypted:
+		return "Encrypted"
+	default:
+		return "Unknown"
+	}
+}
+
 type PktInfo struct {
 	MacFrame *wpan.MacFrame
+	// MacPayload is the plaintext MAC payload (excluding Payload IEs), which is decrypted if the frame is secured.
+	MacPayload []byte
+	// Decrypted is true if the MAC payload of the secured frame was successfully decrypted.
+	Decrypted bool
+	// DecryptError is the error of decrypting the MAC payload, if any.
+	DecryptError error
+	LowpanFrame  *lowpan.Frame
+	Type         PktType
+	// LowpanError is the error of dissecting the MAC payload, if any.
+	LowpanError error
+	// MlePayload is the plaintext MLE message (command type and TLVs) if the frame is MLE.
+	MlePayload []byte
+	// MleError is the error of decrypting or dissecting the MLE message, if any.
+	MleError error
+	// Mle is the dissected MLE message if the frame carries a complete MLE message.
+	Mle *mle.Message
+
+	// plaintext is the decrypted MAC payload including Payload IEs.
+	plaintext []byte
+}
+
+// Datagram returns the IPv6 datagram carried in the frame, or the reassembled datagram if the frame completes one.
+func (pi *PktInfo) Datagram() *lowpan.Datagram {
+	if pi.LowpanFrame == nil {
+		return nil
+	}
+	if pi.LowpanFrame.Reassembled != nil {
+		return pi.LowpanFrame.Reassembled
+	}
+	return pi.LowpanFrame.Datagram
+}
+
+// PlainPsdu returns the PSDU with the decrypted payload and without security, or nil if the frame was not decrypted.
+func (pi *PktInfo) PlainPsdu() []byte {
+	if !pi.Decrypted {
+		return nil
+	}
+	return pi.MacFrame.UnsecuredPsdu(pi.plaintext)
+}
+
+func (pi *PktInfo) String() string {
+	s := fmt.Sprintf("%s %s", pi.Type, pi.MacFrame)
+	if pi.LowpanFrame != nil {
+		s += " " + pi.LowpanFrame.String()
+	}
+	if pi.Mle != nil {
+		s += " " + pi.Mle.String()
+	}
+	return s
+}
+
+// Dissector dissects frames and keeps the state (6LoWPAN contexts, fragment reassembly and keys) across frames.
+type Dissector struct {
+	lowpan      *lowpan.Dissector
+	keys        *security.KeyManager
+	keySequence uint32
+}
+
+// NewDissector creates a new Dissector using the default mesh-local prefix.
+func NewDissector() *Dissector {
+	_, prefix, err := net.ParseCIDR(threadconst.DefaultMeshLocalPrefix)
+	simplelogger.PanicIfError(err)
+
+	return &Dissector{
+		lowpan: lowpan.NewDissector(lowpan.NewContextTable(*prefix)),
+	}
+}
+
+// SetMeshLocalPrefix sets the mesh-local prefix used as 6LoWPAN context 0.
+func (d *Dissector) SetMeshLocalPrefix(prefix net.IPNet) {
+	d.lowpan.Contexts.Set(0, prefix)
+}
+
+// SetMasterKey sets the master key used to decrypt secured frames and MLE messages.
+func (d *Dissector) SetMasterKey(masterKey []byte) {
+	d.keys = security.NewKeyManager(masterKey)
+}
+
+// SetKeySequence sets the current key sequence, which is used to resolve the key index of secured frames.
+func (d *Dissector) SetKeySequence(keySequence uint32) {
+	d.keySequence = keySequence
 }
 
-func Dissect(data []byte) *PktInfo {
-	macFrame := wpan.Dissect(data)
+// Dissect dissects the frame (with the channel byte) sent by the node with extended address srcExtAddr at time ts (in us).
+// The srcExtAddr is used to decrypt secured frames with a short source address. It can be 0 if unknown.
+func (d *Dissector) Dissect(ts uint64, srcExtAddr uint64, data []byte) (*PktInfo, error) {
+	macFrame, err := wpan.Dissect(data)
+	if err != nil {
+		return nil, err
+	}
 
 	pktinfo := &PktInfo{
-		MacFrame: macFrame,
+		MacFrame:   macFrame,
+		MacPayload: macFrame.Payload,
+	}
+
+	if macFrame.SecurityHeader != nil {
+		d.decryptMacFrame(pktinfo, srcExtAddr)
+	}
+
+	switch macFrame.FrameControl.FrameType() {
+	case wpan.FrameTypeAck:
+		pktinfo.Type = PktTypeAck
+	case wpan.FrameTypeBeacon:
+		pktinfo.Type = PktTypeBeacon
+	case wpan.FrameTypeCommand:
+		pktinfo.Type = PktTypeMacCommand
+	case wpan.FrameTypeData:
+		d.dissectData(ts, pktinfo)
+	}
+
+	return pktinfo, nil
+}
+
+func (d *Dissector) dissectData(ts uint64, pktinfo *PktInfo) {
+	macFrame := pktinfo.MacFrame
+	if macFrame.SecurityHeader != nil && macFrame.SecurityHeader.Encrypted() && !pktinfo.Decrypted {
+		pktinfo.Type = PktTypeEncrypted
+		return
+	}
+
+	if len(pktinfo.MacPayload) == 0 {
+		return
+	}
+
+	pktinfo.LowpanFrame, pktinfo.LowpanError = d.lowpan.Dissect(ts, linkSrcAddr(macFrame), linkDstAddr(macFrame), pktinfo.MacPayload)
+	if pktinfo.LowpanError != nil {
+		return
+	}
+
+	datagram := pktinfo.Datagram()
+	if datagram == nil {
+		pktinfo.Type = PktTypeFragment
+		return
+	}
+
+	datagram = datagram.Innermost()
+	pktinfo.Type = classifyDatagram(datagram)
+
+	lowpanFrame := pktinfo.LowpanFrame
+	isComplete := lowpanFrame.Fragment == nil || lowpanFrame.Reassembled != nil
+	if pktinfo.Type == PktTypeMle && isComplete {
+		pktinfo.MlePayload, pktinfo.MleError = d.decryptMle(datagram)
+		if pktinfo.MlePayload != nil {
+			pktinfo.Mle, pktinfo.MleError = mle.Dissect(pktinfo.MlePayload)
+		}
+	}
+}
+
+func classifyDatagram(datagram *lowpan.Datagram) PktType {
+	switch {
+	case datagram.Icmp6 != nil:
+		return PktTypeIcmp6
+	case datagram.Udp != nil && (datagram.Udp.SrcPort == threadconst.MlePort || datagram.Udp.DstPort == threadconst.MlePort):
+		return PktTypeMle
+	case datagram.Udp != nil && (datagram.Udp.SrcPort == threadconst.TmfPort || datagram.Udp.DstPort == threadconst.TmfPort):
+		return PktTypeTmf
+	default:
+		return PktTypeData
 	}
+}
+
+func linkSrcAddr(frame *wpan.MacFrame) lowpan.LinkAddr {
+	switch frame.FrameControl.SourceAddrMode() {
+	case wpan.SrcAddrModeShort:
+		return lowpan.LinkAddr{Mode: lowpan.LinkAddrShort, Short: frame.SrcAddrShort}
+	case wpan.SrcAddrModeExtended:
+		return lowpan.LinkAddr{Mode: lowpan.LinkAddrExtended, Extended: frame.SrcAddrExtended}
+	default:
+		return lowpan.LinkAddr{}
+	}
+}
+
+func linkDstAddr(frame *wpan.MacFrame) lowpan.LinkAddr {
+	switch frame.FrameControl.DstAddrMode() {
+	case wpan.DstAddrModeShort:
+		return lowpan.LinkAddr{Mode: lowpan.LinkAddrShort, Short: frame.DstAddrShort}
+	case wpan.DstAddrModeExtended:
+		return lowpan.LinkAddr{Mode: lowpan.LinkAddrExtended, Extended: frame.DstAddrExtended}
+	default:
+		return lowpan.LinkAddr{}
+	}
+}
 
-	return pktinfo
+// Dissect dissects the frame (with the channel byte) without keys and without keeping any state across frames.
+func Dissect(data []byte) (*PktInfo, error) {
+	return NewDissector().Dissect(0, 0, data)
 }
@@ -0,0 +1,159 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dissectpkt
+
+import (
+	"encoding/hex"
+	"net"
+	"testing"
+
+	"github.com/openthread/ot-ns/dissectpkt/mle"
+	"github.com/openthread/ot-ns/dissectpkt/security"
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSrcExtAddr = 0x1122334455667788
+
+func mustDecodeHex(s string) []byte {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func newTestDissector() *Dissector {
+	masterKey, err := security.ParseMasterKey("00112233445566778899aabbccddeeff")
+	if err != nil {
+		panic(err)
+	}
+
+	d := NewDissector()
+	d.SetMasterKey(masterKey)
+	return d
+}
+
+// buildSecuredFrame builds a secured data frame from 0x0401 to 0x0400 with key id mode 1 and security level 5.
+func buildSecuredFrame(plaintext []byte) []byte {
+	header := mustDecodeHex("6998" + "05" + "cefa" + "0004" + "0104" + "0d" + "07000000" + "01")
+	km := security.NewKeyManager(mustDecodeHex("00112233445566778899aabbccddeeff"))
+	nonce := security.Nonce(testSrcExtAddr, 7, wpan.SecurityLevelEncMic32)
+	ciphertext, mic, err := security.EncryptCcm(km.MacKey(0), nonce, header, plaintext, 4)
+	if err != nil {
+		panic(err)
+	}
+
+	frame := append([]byte{11}, header...)
+	frame = append(frame, ciphertext...)
+	frame = append(frame, mic...)
+	return append(frame, 0, 0)
+}
+
+func TestDissectUnsecuredMle(t *testing.T) {
+	// MLE Discovery Request with security suite 255 from ext addr to ff02::1
+	pktinfo, err := newTestDissector().Dissect(0, 0, mustDecodeHex("0b"+"41d8"+"01"+"cefa"+"ffff"+"8877665544332211"+
+		"7f3b01"+"f0"+"4d4c4d4c"+"0000"+"ff"+"10"+"1a00"+"0000"))
+	assert.Nil(t, err)
+	assert.Equal(t, PktTypeMle, pktinfo.Type)
+	assert.Nil(t, pktinfo.MleError)
+	assert.Equal(t, []byte{0x10, 0x1a, 0x00}, pktinfo.MlePayload)
+	assert.Equal(t, mle.CommandDiscoveryRequest, pktinfo.Mle.Command)
+	assert.Equal(t, "fe80::1322:3344:5566:7788", pktinfo.Datagram().Src.String())
+}
+
+func TestDecryptMacFrame(t *testing.T) {
+	plaintext := mustDecodeHex("7a33" + "3a" + "8000abcd")
+	data := buildSecuredFrame(plaintext)
+
+	// without the master key
+	pktinfo, err := NewDissector().Dissect(0, testSrcExtAddr, data)
+	assert.Nil(t, err)
+	assert.Equal(t, PktTypeEncrypted, pktinfo.Type)
+	assert.Equal(t, ErrNoMasterKey, pktinfo.DecryptError)
+	assert.Nil(t, pktinfo.PlainPsdu())
+
+	// without the source extended address
+	pktinfo, err = newTestDissector().Dissect(0, 0, data)
+	assert.Nil(t, err)
+	assert.Equal(t, PktTypeEncrypted, pktinfo.Type)
+	assert.NotNil(t, pktinfo.DecryptError)
+
+	pktinfo, err = newTestDissector().Dissect(0, testSrcExtAddr, data)
+	assert.Nil(t, err)
+	assert.Nil(t, pktinfo.DecryptError)
+	assert.True(t, pktinfo.Decrypted)
+	assert.Equal(t, plaintext, pktinfo.MacPayload)
+	assert.Equal(t, PktTypeIcmp6, pktinfo.Type)
+	assert.Equal(t, "fe80::ff:fe00:401", pktinfo.Datagram().Src.String())
+
+	plain, err := wpan.Dissect(append([]byte{11}, pktinfo.PlainPsdu()...))
+	assert.Nil(t, err)
+	assert.False(t, plain.FrameControl.SecurityEnabled())
+	assert.Nil(t, plain.SecurityHeader)
+	assert.Equal(t, plaintext, plain.Payload)
+	assert.Equal(t, wpan.ComputeFcs(pktinfo.PlainPsdu()[:len(pktinfo.PlainPsdu())-wpan.FcsSize]), plain.Fcs)
+
+	// tampered frame
+	data[len(data)-3] ^= 1
+	pktinfo, err = newTestDissector().Dissect(0, testSrcExtAddr, data)
+	assert.Nil(t, err)
+	assert.Equal(t, security.ErrMicMismatch, pktinfo.DecryptError)
+	assert.Equal(t, PktTypeEncrypted, pktinfo.Type)
+}
+
+func TestDecryptMle(t *testing.T) {
+	src := net.ParseIP("fe80::1322:3344:5566:7788")
+	dst := net.ParseIP("ff02::1")
+	header := mustDecodeHex("15" + "01000000" + "00000000" + "01")
+	plaintext := mustDecodeHex("0b" + "020102")
+
+	aad := append(append(append([]byte(nil), src...), dst...), header...)
+	km := security.NewKeyManager(mustDecodeHex("00112233445566778899aabbccddeeff"))
+	nonce := security.Nonce(testSrcExtAddr, 1, wpan.SecurityLevelEncMic32)
+	ciphertext, mic, err := security.EncryptCcm(km.MleKey(0), nonce, aad, plaintext, 4)
+	assert.Nil(t, err)
+
+	data := mustDecodeHex("0b" + "41d8" + "01" + "cefa" + "ffff" + "8877665544332211" + "7f3b01" + "f0" + "4d4c4d4c" + "0000" + "00")
+	data = append(data, header...)
+	data = append(data, ciphertext...)
+	data = append(data, mic...)
+	data = append(data, 0, 0)
+
+	pktinfo, err := newTestDissector().Dissect(0, 0, data)
+	assert.Nil(t, err)
+	assert.Equal(t, PktTypeMle, pktinfo.Type)
+	assert.Nil(t, pktinfo.MleError)
+	assert.Equal(t, plaintext, pktinfo.MlePayload)
+	assert.Equal(t, mle.CommandChildIdRequest, pktinfo.Mle.Command)
+	assert.Equal(t, []byte{2}, pktinfo.Mle.FindTlv(mle.TlvTimeout).Value)
+
+	pktinfo, err = NewDissector().Dissect(0, 0, data)
+	assert.Nil(t, err)
+	assert.Equal(t, ErrNoMasterKey, pktinfo.MleError)
+	assert.Nil(t, pktinfo.MlePayload)
+}
@@ -0,0 +1,455 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package lowpan
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	nhcUdpMask     = 0xf8
+	nhcUdp         = 0xf0
+	nhcExtHdrMask  = 0xf0
+	nhcExtHdr      = 0xe0
+	ipv6HeaderSize = 40
+	udpHeaderSize  = 8
+)
+
+// nhcExtHdrProtocols maps NHC Extension Header IDs to IPv6 protocol numbers.
+var nhcExtHdrProtocols = [8]uint8{
+	ProtoHopOpts, ProtoRouting, ProtoFragment, ProtoDstOpts, ProtoMobility, 0, 0, ProtoIpv6,
+}
+
+// lengthField is a length field that can only be filled after the whole datagram is decompressed.
+type lengthField struct {
+	pos  int
+	base int
+}
+
+type decompressor struct {
+	r            *reader
+	contexts     *ContextTable
+	out          []byte
+	lengthFields []lengthField
+}
+
+// decompress decompresses the IPv6 headers and returns the uncompressed datagram.
+// If datagramSize is non-zero, the datagram is the first fragment of a datagram of that size.
+func decompress(r *reader, src, dst LinkAddr, contexts *ContextTable, datagramSize int) ([]byte, error) {
+	dc := &decompressor{
+		r:        r,
+		contexts: contexts,
+	}
+
+	if err := dc.decompressIpv6(src, dst); err != nil {
+		return nil, err
+	}
+
+	dc.out = append(dc.out, r.remaining()...)
+
+	totalSize := len(dc.out)
+	if datagramSize > 0 {
+		totalSize = datagramSize
+	}
+
+	for _, lf := range dc.lengthFields {
+		binary.BigEndian.PutUint16(dc.out[lf.pos:], uint16(totalSize-lf.base))
+	}
+
+	return dc.out, nil
+}
+
+func (dc *decompressor) decompressIpv6(src, dst LinkAddr) error {
+	dispatch, err := dc.r.peek()
+	if err != nil {
+		return err
+	}
+
+	if dispatch == dispatchIpv6 {
+		// uncompressed IPv6 header
+		dc.r.pos += 1
+		hdr, err := dc.r.readBytes(ipv6HeaderSize)
+		if err != nil {
+			return err
+		}
+		dc.out = append(dc.out, hdr...)
+		return nil
+	}
+
+	nextHeaderPos, nhc, err := dc.decompressIphc(src, dst)
+	if err != nil {
+		return err
+	}
+
+	for nhc {
+		if nextHeaderPos, nhc, err = dc.decompressNhc(nextHeaderPos, src, dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decompressIphc decompresses a LOWPAN_IPHC header (RFC 6282 Section 3).
+func (dc *decompressor) decompressIphc(src, dst LinkAddr) (nextHeaderPos int, nhc bool, err error) {
+	r := dc.r
+	var iphc uint16
+	if iphc, err = r.readUint16(); err != nil {
+		return
+	}
+
+	var sci, dci uint8
+	if iphc&0x0080 != 0 {
+		var cid uint8
+		if cid, err = r.readUint8(); err != nil {
+			return
+		}
+		sci, dci = cid>>4, cid&0x0f
+	}
+
+	base := len(dc.out)
+	hdr := make([]byte, ipv6HeaderSize)
+	hdr[0] = 0x60
+
+	// Traffic Class and Flow Label
+	var ecn, dscp uint8
+	var flowLabel uint32
+	switch (iphc >> 11) & 0x03 {
+	case 0:
+		var b []byte
+		if b, err = r.readBytes(4); err != nil {
+			return
+		}
+		ecn, dscp = b[0]>>6, b[0]&0x3f
+		flowLabel = uint32(b[1]&0x0f)<<16 | uint32(b[2])<<8 | uint32(b[3])
+	case 1:
+		var b []byte
+		if b, err = r.readBytes(3); err != nil {
+			return
+		}
+		ecn = b[0] >> 6
+		flowLabel = uint32(b[0]&0x0f)<<16 | uint32(b[1])<<8 | uint32(b[2])
+	case 2:
+		var b uint8
+		if b, err = r.readUint8(); err != nil {
+			return
+		}
+		ecn, dscp = b>>6, b&0x3f
+	}
+	trafficClass := dscp<<2 | ecn
+	binary.BigEndian.PutUint32(hdr[0:4], 6<<28|uint32(trafficClass)<<20|flowLabel)
+
+	// Next Header
+	nhc = iphc&0x0400 != 0
+	if !nhc {
+		if hdr[6], err = r.readUint8(); err != nil {
+			return
+		}
+	}
+
+	// Hop Limit
+	switch iphc & 0x0300 {
+	case 0x0000:
+		if hdr[7], err = r.readUint8(); err != nil {
+			return
+		}
+	case 0x0100:
+		hdr[7] = 1
+	case 0x0200:
+		hdr[7] = 64
+	case 0x0300:
+		hdr[7] = 255
+	}
+
+	// Source Address
+	sac, sam := iphc&0x0040 != 0, uint8(iphc>>4)&0x03
+	if sac && sam == 0 {
+		// the unspecified address
+	} else if err = dc.decompressUnicastAddr(hdr[8:24], sac, sam, sci, src); err != nil {
+		return
+	}
+
+	// Destination Address
+	m, dac, dam := iphc&0x0008 != 0, iphc&0x0004 != 0, uint8(iphc)&0x03
+	if !m {
+		if dac && dam == 0 {
+			err = errors.Errorf("reserved destination address mode")
+			return
+		}
+		if err = dc.decompressUnicastAddr(hdr[24:40], dac, dam, dci, dst); err != nil {
+			return
+		}
+	} else if err = dc.decompressMulticastAddr(hdr[24:40], dac, dam, dci); err != nil {
+		return
+	}
+
+	dc.out = append(dc.out, hdr...)
+	dc.lengthFields = append(dc.lengthFields, lengthField{pos: base + 4, base: base + ipv6HeaderSize})
+	nextHeaderPos = base + 6
+	return
+}
+
+func (dc *decompressor) contextPrefix(id uint8) (net.IPNet, error) {
+	if dc.contexts != nil {
+		if prefix, ok := dc.contexts.Get(id); ok {
+			return prefix, nil
+		}
+	}
+	return net.IPNet{}, errors.Errorf("6LoWPAN context %d not found", id)
+}
+
+func (dc *decompressor) decompressUnicastAddr(addr []byte, stateful bool, mode uint8, ctxId uint8, linkAddr LinkAddr) error {
+	r := dc.r
+
+	if !stateful {
+		// link-local prefix
+		addr[0], addr[1] = 0xfe, 0x80
+	} else if mode != 0 {
+		prefix, err := dc.contextPrefix(ctxId)
+		if err != nil {
+			return err
+		}
+		copy(addr[:8], prefix.IP.To16()[:8])
+	}
+
+	switch mode {
+	case 0:
+		// full address carried inline
+		b, err := r.readBytes(16)
+		if err != nil {
+			return err
+		}
+		copy(addr, b)
+	case 1:
+		b, err := r.readBytes(8)
+		if err != nil {
+			return err
+		}
+		copy(addr[8:], b)
+	case 2:
+		b, err := r.readBytes(2)
+		if err != nil {
+			return err
+		}
+		copy(addr[8:], []byte{0, 0, 0, 0xff, 0xfe, 0, b[0], b[1]})
+	case 3:
+		iid, err := linkAddr.iid()
+		if err != nil {
+			return err
+		}
+		copy(addr[8:], iid)
+	}
+
+	if stateful && mode != 0 {
+		// the context prefix overrides the bits derived from the IID if the prefix is longer than 64 bits
+		prefix, _ := dc.contextPrefix(ctxId)
+		ones, _ := prefix.Mask.Size()
+		for i := 64; i < ones; i++ {
+			bit := byte(0x80) >> uint(i%8)
+			addr[i/8] = addr[i/8]&^bit | prefix.IP.To16()[i/8]&bit
+		}
+	}
+
+	return nil
+}
+
+func (dc *decompressor) decompressMulticastAddr(addr []byte, stateful bool, mode uint8, ctxId uint8) error {
+	r := dc.r
+
+	if stateful {
+		if mode != 0 {
+			return errors.Errorf("reserved multicast destination address mode")
+		}
+
+		// Unicast-Prefix-based IPv6 Multicast Address: ffXX:XXLL:PPPP:PPPP:PPPP:PPPP:XXXX:XXXX
+		b, err := r.readBytes(6)
+		if err != nil {
+			return err
+		}
+		prefix, err := dc.contextPrefix(ctxId)
+		if err != nil {
+			return err
+		}
+		ones, _ := prefix.Mask.Size()
+		addr[0], addr[1], addr[2], addr[3] = 0xff, b[0], b[1], uint8(ones)
+		copy(addr[4:12], prefix.IP.To16()[:8])
+		copy(addr[12:16], b[2:6])
+		return nil
+	}
+
+	addr[0] = 0xff
+	switch mode {
+	case 0:
+		b, err := r.readBytes(16)
+		if err != nil {
+			return err
+		}
+		copy(addr, b)
+	case 1:
+		// ffXX::00XX:XXXX:XXXX
+		b, err := r.readBytes(6)
+		if err != nil {
+			return err
+		}
+		addr[1] = b[0]
+		copy(addr[11:], b[1:])
+	case 2:
+		// ffXX::00XX:XXXX
+		b, err := r.readBytes(4)
+		if err != nil {
+			return err
+		}
+		addr[1] = b[0]
+		copy(addr[13:], b[1:])
+	case 3:
+		// ff02::00XX
+		b, err := r.readUint8()
+		if err != nil {
+			return err
+		}
+		addr[1] = 0x02
+		addr[15] = b
+	}
+	return nil
+}
+
+// decompressNhc decompresses one LOWPAN_NHC header (RFC 6282 Section 4).
+func (dc *decompressor) decompressNhc(nextHeaderPos int, src, dst LinkAddr) (int, bool, error) {
+	r := dc.r
+	nhcId, err := r.readUint8()
+	if err != nil {
+		return 0, false, err
+	}
+
+	if nhcId&nhcUdpMask == nhcUdp {
+		dc.out[nextHeaderPos] = ProtoUdp
+		return 0, false, dc.decompressUdp(nhcId)
+	}
+
+	if nhcId&nhcExtHdrMask != nhcExtHdr {
+		return 0, false, errors.Wrapf(ErrUnsupported, "NHC 0x%02x", nhcId)
+	}
+
+	eid := (nhcId >> 1) & 0x07
+	proto := nhcExtHdrProtocols[eid]
+	if eid == 5 || eid == 6 {
+		return 0, false, errors.Errorf("reserved NHC extension header ID %d", eid)
+	}
+	dc.out[nextHeaderPos] = proto
+
+	if proto == ProtoIpv6 {
+		// IPv6 encapsulation: the inner header is compressed with LOWPAN_IPHC
+		if err := dc.decompressIpv6(src, dst); err != nil {
+			return 0, false, err
+		}
+		return 0, false, nil
+	}
+
+	nhc := nhcId&0x01 != 0
+	base := len(dc.out)
+	hdr := []byte{0, 0}
+	if !nhc {
+		if hdr[0], err = r.readUint8(); err != nil {
+			return 0, false, err
+		}
+	}
+
+	length, err := r.readUint8()
+	if err != nil {
+		return 0, false, err
+	}
+	data, err := r.readBytes(int(length))
+	if err != nil {
+		return 0, false, err
+	}
+	hdr = append(hdr, data...)
+
+	// restore the elided trailing Pad1 or PadN option
+	if pad := (8 - len(hdr)%8) % 8; pad == 1 {
+		hdr = append(hdr, 0)
+	} else if pad > 1 {
+		hdr = append(hdr, 1, uint8(pad-2))
+		hdr = append(hdr, make([]byte, pad-2)...)
+	}
+	hdr[1] = uint8(len(hdr)/8 - 1)
+
+	dc.out = append(dc.out, hdr...)
+	return base, nhc, nil
+}
+
+// decompressUdp decompresses the UDP header (RFC 6282 Section 4.3).
+func (dc *decompressor) decompressUdp(nhcId uint8) error {
+	r := dc.r
+	var srcPort, dstPort, checksum uint16
+	var err error
+
+	switch nhcId & 0x03 {
+	case 0:
+		if srcPort, err = r.readUint16(); err == nil {
+			dstPort, err = r.readUint16()
+		}
+	case 1:
+		var b uint8
+		if srcPort, err = r.readUint16(); err == nil {
+			b, err = r.readUint8()
+			dstPort = 0xf000 | uint16(b)
+		}
+	case 2:
+		var b uint8
+		if b, err = r.readUint8(); err == nil {
+			srcPort = 0xf000 | uint16(b)
+			dstPort, err = r.readUint16()
+		}
+	case 3:
+		var b uint8
+		if b, err = r.readUint8(); err == nil {
+			srcPort = 0xf0b0 | uint16(b>>4)
+			dstPort = 0xf0b0 | uint16(b&0x0f)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if nhcId&0x04 == 0 {
+		if checksum, err = r.readUint16(); err != nil {
+			return err
+		}
+	}
+
+	base := len(dc.out)
+	hdr := make([]byte, udpHeaderSize)
+	binary.BigEndian.PutUint16(hdr[0:], srcPort)
+	binary.BigEndian.PutUint16(hdr[2:], dstPort)
+	binary.BigEndian.PutUint16(hdr[6:], checksum)
+	dc.out = append(dc.out, hdr...)
+	dc.lengthFields = append(dc.lengthFields, lengthField{pos: base + 4, base: base})
+	return nil
+}
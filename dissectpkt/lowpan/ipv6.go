@@ -0,0 +1,182 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package lowpan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// IPv6 Next Header values.
+const (
+	ProtoHopOpts  = 0
+	ProtoUdp      = 17
+	ProtoIpv6     = 41
+	ProtoRouting  = 43
+	ProtoFragment = 44
+	ProtoIcmp6    = 58
+	ProtoNone     = 59
+	ProtoDstOpts  = 60
+	ProtoMobility = 135
+)
+
+// UdpHeader represents the UDP header.
+type UdpHeader struct {
+	SrcPort  uint16
+	DstPort  uint16
+	Length   uint16
+	Checksum uint16
+}
+
+// Icmp6Header represents the ICMPv6 header.
+type Icmp6Header struct {
+	Type uint8
+	Code uint8
+}
+
+// Datagram represents a dissected IPv6 datagram.
+type Datagram struct {
+	Src           net.IP
+	Dst           net.IP
+	HopLimit      uint8
+	PayloadLength uint16
+	NextHeader    uint8
+	// ExtHeaders contains the protocol numbers of the extension headers in order.
+	ExtHeaders []uint8
+	// Protocol is the upper-layer protocol after all extension headers.
+	Protocol uint8
+	Udp      *UdpHeader
+	Icmp6    *Icmp6Header
+	// Inner is the encapsulated datagram for IPv6-in-IPv6.
+	Inner *Datagram
+	// Payload is the upper-layer payload, which may be partial for the first fragment of a datagram.
+	Payload []byte
+}
+
+// Innermost returns the innermost datagram of IPv6-in-IPv6 encapsulation.
+func (d *Datagram) Innermost() *Datagram {
+	for d.Inner != nil {
+		d = d.Inner
+	}
+	return d
+}
+
+func (d *Datagram) String() string {
+	s := fmt.Sprintf("IPv6(src:%s,dst:%s,hl:%d)", d.Src, d.Dst, d.HopLimit)
+	switch {
+	case d.Inner != nil:
+		s += "," + d.Inner.String()
+	case d.Udp != nil:
+		s += fmt.Sprintf(",UDP(sport:%d,dport:%d,len:%d)", d.Udp.SrcPort, d.Udp.DstPort, d.Udp.Length)
+	case d.Icmp6 != nil:
+		s += fmt.Sprintf(",ICMPv6(type:%d,code:%d)", d.Icmp6.Type, d.Icmp6.Code)
+	default:
+		s += fmt.Sprintf(",proto:%d", d.Protocol)
+	}
+	return s
+}
+
+// ParseDatagram parses the uncompressed IPv6 datagram.
+// The datagram may be partial, in which case only the headers present are parsed.
+func ParseDatagram(raw []byte) (*Datagram, error) {
+	if len(raw) < ipv6HeaderSize {
+		return nil, errors.Wrapf(ErrTruncated, "IPv6 header needs %d bytes, length %d", ipv6HeaderSize, len(raw))
+	}
+
+	if raw[0]>>4 != 6 {
+		return nil, errors.Errorf("invalid IP version %d", raw[0]>>4)
+	}
+
+	d := &Datagram{
+		PayloadLength: binary.BigEndian.Uint16(raw[4:]),
+		NextHeader:    raw[6],
+		HopLimit:      raw[7],
+		Src:           net.IP(append([]byte(nil), raw[8:24]...)),
+		Dst:           net.IP(append([]byte(nil), raw[24:40]...)),
+	}
+
+	proto := d.NextHeader
+	data := raw[ipv6HeaderSize:]
+
+	for isExtHeader(proto) {
+		if len(data) < 8 {
+			return nil, errors.Wrapf(ErrTruncated, "IPv6 extension header %d", proto)
+		}
+
+		size := 8
+		if proto != ProtoFragment {
+			size = (int(data[1]) + 1) * 8
+		}
+		if len(data) < size {
+			return nil, errors.Wrapf(ErrTruncated, "IPv6 extension header %d needs %d bytes, length %d", proto, size, len(data))
+		}
+
+		d.ExtHeaders = append(d.ExtHeaders, proto)
+		proto = data[0]
+		data = data[size:]
+	}
+
+	d.Protocol = proto
+	d.Payload = data
+
+	switch proto {
+	case ProtoUdp:
+		if len(data) >= udpHeaderSize {
+			d.Udp = &UdpHeader{
+				SrcPort:  binary.BigEndian.Uint16(data[0:]),
+				DstPort:  binary.BigEndian.Uint16(data[2:]),
+				Length:   binary.BigEndian.Uint16(data[4:]),
+				Checksum: binary.BigEndian.Uint16(data[6:]),
+			}
+			d.Payload = data[udpHeaderSize:]
+		}
+	case ProtoIcmp6:
+		if len(data) >= 2 {
+			d.Icmp6 = &Icmp6Header{Type: data[0], Code: data[1]}
+		}
+	case ProtoIpv6:
+		inner, err := ParseDatagram(data)
+		if err != nil {
+			return nil, err
+		}
+		d.Inner = inner
+	}
+
+	return d, nil
+}
+
+func isExtHeader(proto uint8) bool {
+	switch proto {
+	case ProtoHopOpts, ProtoRouting, ProtoFragment, ProtoDstOpts, ProtoMobility:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package lowpan
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	dispatchIpv6     = 0x41
+	dispatchIphcMask = 0xe0
+	dispatchIphc     = 0x60
+	dispatchMeshMask = 0xc0
+	dispatchMesh     = 0x80
+	dispatchFragMask = 0xf8
+	dispatchFrag1    = 0xc0
+	dispatchFragN    = 0xe0
+	dispatchBc0      = 0x50
+)
+
+var (
+	ErrTruncated   = errors.New("6LoWPAN frame truncated")
+	ErrUnsupported = errors.New("6LoWPAN dispatch not supported")
+)
+
+// LinkAddrMode defines the type of a link-layer address.
+type LinkAddrMode int
+
+const (
+	LinkAddrNone     LinkAddrMode = 0
+	LinkAddrShort    LinkAddrMode = 2
+	LinkAddrExtended LinkAddrMode = 3
+)
+
+// LinkAddr represents an IEEE 802.15.4 short or extended address.
+type LinkAddr struct {
+	Mode     LinkAddrMode
+	Short    uint16
+	Extended uint64
+}
+
+func (a LinkAddr) String() string {
+	switch a.Mode {
+	case LinkAddrShort:
+		return fmt.Sprintf("%04x", a.Short)
+	case LinkAddrExtended:
+		return fmt.Sprintf("%016x", a.Extended)
+	default:
+		return "-"
+	}
+}
+
+// iid returns the Interface Identifier derived from the link-layer address.
+func (a LinkAddr) iid() ([]byte, error) {
+	iid := make([]byte, 8)
+	switch a.Mode {
+	case LinkAddrShort:
+		iid[3] = 0xff
+		iid[4] = 0xfe
+		binary.BigEndian.PutUint16(iid[6:], a.Short)
+	case LinkAddrExtended:
+		binary.BigEndian.PutUint64(iid, a.Extended)
+		iid[0] ^= 0x02
+	default:
+		return nil, errors.Errorf("can not derive IID from link address")
+	}
+	return iid, nil
+}
+
+// MeshHeader represents the 6LoWPAN Mesh Addressing Header.
+type MeshHeader struct {
+	HopsLeft   uint8
+	Originator LinkAddr
+	Final      LinkAddr
+}
+
+// FragmentHeader represents the 6LoWPAN FRAG1 or FRAGN header.
+type FragmentHeader struct {
+	DatagramSize uint16
+	DatagramTag  uint16
+	// Offset is the datagram offset in bytes, which is always 0 for FRAG1.
+	Offset  uint16
+	IsFirst bool
+}
+
+// Frame represents a dissected 6LoWPAN frame.
+type Frame struct {
+	Mesh     *MeshHeader
+	Fragment *FragmentHeader
+	// Datagram is the (partial) IPv6 datagram carried in the frame. It is nil for FRAGN frames.
+	Datagram *Datagram
+	// FragmentPayload is the payload following the FRAGN header.
+	FragmentPayload []byte
+	// Reassembled is the complete IPv6 datagram when this fragment completes a reassembly.
+	Reassembled *Datagram
+}
+
+// IsFragment returns if the frame carries a fragment of a datagram.
+func (f *Frame) IsFragment() bool {
+	return f.Fragment != nil
+}
+
+func (f *Frame) String() string {
+	s := "6LoWPAN"
+	if f.Fragment != nil {
+		s += fmt.Sprintf(",Frag(size:%d,tag:%d,off:%d)", f.Fragment.DatagramSize, f.Fragment.DatagramTag, f.Fragment.Offset)
+	}
+	if f.Datagram != nil {
+		s += "," + f.Datagram.String()
+	}
+	return s
+}
+
+// Dissector dissects 6LoWPAN frames using the context table and reassembles fragmented datagrams.
+type Dissector struct {
+	Contexts    *ContextTable
+	reassembler *reassembler
+}
+
+// NewDissector creates a new 6LoWPAN Dissector.
+func NewDissector(contexts *ContextTable) *Dissector {
+	return &Dissector{
+		Contexts:    contexts,
+		reassembler: newReassembler(),
+	}
+}
+
+// Dissect dissects the MAC payload of a data frame sent from src to dst at time ts (in us).
+func (d *Dissector) Dissect(ts uint64, src, dst LinkAddr, payload []byte) (*Frame, error) {
+	frame := &Frame{}
+	r := &reader{data: payload}
+
+	for {
+		b, err := r.peek()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case b&dispatchMeshMask == dispatchMesh:
+			if frame.Mesh, err = readMeshHeader(r); err != nil {
+				return nil, err
+			}
+			src, dst = frame.Mesh.Originator, frame.Mesh.Final
+		case b == dispatchBc0:
+			if _, err = r.readBytes(2); err != nil {
+				return nil, err
+			}
+		case b&dispatchFragMask == dispatchFrag1 || b&dispatchFragMask == dispatchFragN:
+			if frame.Fragment, err = readFragmentHeader(r); err != nil {
+				return nil, err
+			}
+
+			if !frame.Fragment.IsFirst {
+				frame.FragmentPayload = r.remaining()
+				if d.reassembler != nil {
+					frame.Reassembled = d.reassembler.addFragmentN(ts, src, dst, frame.Fragment, frame.FragmentPayload)
+				}
+				return frame, nil
+			}
+		case b&dispatchIphcMask == dispatchIphc || b == dispatchIpv6:
+			datagramSize := 0
+			if frame.Fragment != nil {
+				datagramSize = int(frame.Fragment.DatagramSize)
+			}
+
+			raw, err := decompress(r, src, dst, d.Contexts, datagramSize)
+			if err != nil {
+				return nil, err
+			}
+
+			if frame.Datagram, err = ParseDatagram(raw); err != nil {
+				return nil, err
+			}
+
+			if frame.Fragment != nil && d.reassembler != nil {
+				d.reassembler.addFragment1(ts, src, dst, frame.Fragment, raw)
+			}
+			return frame, nil
+		default:
+			return nil, errors.Wrapf(ErrUnsupported, "dispatch 0x%02x", b)
+		}
+	}
+}
+
+func readMeshHeader(r *reader) (*MeshHeader, error) {
+	b, err := r.readUint8()
+	if err != nil {
+		return nil, err
+	}
+
+	mh := &MeshHeader{HopsLeft: b & 0x0f}
+	if mh.HopsLeft == 0x0f {
+		if mh.HopsLeft, err = r.readUint8(); err != nil {
+			return nil, err
+		}
+	}
+
+	readAddr := func(isShort bool) (addr LinkAddr, err error) {
+		if isShort {
+			addr.Mode = LinkAddrShort
+			addr.Short, err = r.readUint16()
+		} else {
+			addr.Mode = LinkAddrExtended
+			addr.Extended, err = r.readUint64()
+		}
+		return
+	}
+
+	if mh.Originator, err = readAddr(b&0x20 != 0); err != nil {
+		return nil, err
+	}
+	if mh.Final, err = readAddr(b&0x10 != 0); err != nil {
+		return nil, err
+	}
+	return mh, nil
+}
+
+func readFragmentHeader(r *reader) (*FragmentHeader, error) {
+	v, err := r.readUint16()
+	if err != nil {
+		return nil, err
+	}
+
+	fh := &FragmentHeader{
+		DatagramSize: v & 0x07ff,
+		IsFirst:      uint8(v>>8)&dispatchFragMask == dispatchFrag1,
+	}
+
+	if fh.DatagramTag, err = r.readUint16(); err != nil {
+		return nil, err
+	}
+
+	if !fh.IsFirst {
+		offset, err := r.readUint8()
+		if err != nil {
+			return nil, err
+		}
+		fh.Offset = uint16(offset) * 8
+	}
+
+	return fh, nil
+}
+
+// reader reads big-endian fields from 6LoWPAN frames with bounds checking.
+type reader struct {
+	data []byte
+	pos  int
+}
+
+func (r *reader) need(n int) error {
+	if r.pos+n > len(r.data) {
+		return errors.Wrapf(ErrTruncated, "need %d bytes at offset %d, length %d", n, r.pos, len(r.data))
+	}
+	return nil
+}
+
+func (r *reader) peek() (uint8, error) {
+	if err := r.need(1); err != nil {
+		return 0, err
+	}
+	return r.data[r.pos], nil
+}
+
+func (r *reader) readUint8() (uint8, error) {
+	if err := r.need(1); err != nil {
+		return 0, err
+	}
+	v := r.data[r.pos]
+	r.pos += 1
+	return v, nil
+}
+
+func (r *reader) readUint16() (uint16, error) {
+	if err := r.need(2); err != nil {
+		return 0, err
+	}
+	v := binary.BigEndian.Uint16(r.data[r.pos:])
+	r.pos += 2
+	return v, nil
+}
+
+// readUint64 reads an extended address in canonical (big-endian) byte order.
+func (r *reader) readUint64() (uint64, error) {
+	if err := r.need(8); err != nil {
+		return 0, err
+	}
+	v := binary.BigEndian.Uint64(r.data[r.pos:])
+	r.pos += 8
+	return v, nil
+}
+
+func (r *reader) readBytes(n int) ([]byte, error) {
+	if err := r.need(n); err != nil {
+		return nil, err
+	}
+	v := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return v, nil
+}
+
+func (r *reader) remaining() []byte {
+	return r.data[r.pos:]
+}
+
+// ContextTable stores the 6LoWPAN contexts used for address compression.
+type ContextTable struct {
+	prefixes map[uint8]net.IPNet
+}
+
+// NewContextTable creates a context table with the mesh-local prefix as context 0.
+func NewContextTable(meshLocalPrefix net.IPNet) *ContextTable {
+	ct := &ContextTable{
+		prefixes: map[uint8]net.IPNet{},
+	}
+	ct.Set(0, meshLocalPrefix)
+	return ct
+}
+
+// Set sets the prefix of the context.
+func (ct *ContextTable) Set(id uint8, prefix net.IPNet) {
+	ct.prefixes[id] = prefix
+}
+
+// Remove removes the context.
+func (ct *ContextTable) Remove(id uint8) {
+	delete(ct.prefixes, id)
+}
+
+// Get returns the prefix of the context.
+func (ct *ContextTable) Get(id uint8) (net.IPNet, bool) {
+	prefix, ok := ct.prefixes[id]
+	return prefix, ok
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package lowpan
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	extSrc   = LinkAddr{Mode: LinkAddrExtended, Extended: 0x1122334455667788}
+	shortSrc = LinkAddr{Mode: LinkAddrShort, Short: 0x0400}
+	shortDst = LinkAddr{Mode: LinkAddrShort, Short: 0xfc00}
+)
+
+func mustDecodeHex(s string) []byte {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func newTestDissector() *Dissector {
+	_, prefix, err := net.ParseCIDR("fdde:ad00:beef:0::/64")
+	if err != nil {
+		panic(err)
+	}
+	return NewDissector(NewContextTable(*prefix))
+}
+
+func TestDissectLinkLocalMulticastUdp(t *testing.T) {
+	// IPHC: TF elided, NH compressed, HLIM 255, src from ext addr, dst ff02::1; UDP with ports and checksum inline
+	frame, err := newTestDissector().Dissect(0, extSrc, LinkAddr{Mode: LinkAddrShort, Short: 0xffff},
+		mustDecodeHex("7f3b01"+"f0"+"4d4c4d4c"+"1234"+"000102"))
+	assert.Nil(t, err)
+	d := frame.Datagram
+	assert.NotNil(t, d)
+	assert.Equal(t, "fe80::1322:3344:5566:7788", d.Src.String())
+	assert.Equal(t, "ff02::1", d.Dst.String())
+	assert.Equal(t, uint8(255), d.HopLimit)
+	assert.Equal(t, uint16(11), d.PayloadLength)
+	assert.Equal(t, uint8(ProtoUdp), d.Protocol)
+	assert.Equal(t, UdpHeader{SrcPort: 19788, DstPort: 19788, Length: 11, Checksum: 0x1234}, *d.Udp)
+	assert.Equal(t, []byte{0, 1, 2}, d.Payload)
+}
+
+func TestDissectMeshLocalContext(t *testing.T) {
+	// IPHC: HLIM 64, src from short addr with context 0, dst 16 bits inline with context 0; UDP ports 0xf0bf compressed
+	frame, err := newTestDissector().Dissect(0, shortSrc, shortDst, mustDecodeHex("7e76"+"fc00"+"f7"+"ff"+"616263"))
+	assert.Nil(t, err)
+	d := frame.Datagram
+	assert.Equal(t, "fdde:ad00:beef::ff:fe00:400", d.Src.String())
+	assert.Equal(t, "fdde:ad00:beef::ff:fe00:fc00", d.Dst.String())
+	assert.Equal(t, uint8(64), d.HopLimit)
+	assert.Equal(t, uint16(61631), d.Udp.SrcPort)
+	assert.Equal(t, uint16(61631), d.Udp.DstPort)
+	assert.Equal(t, []byte("abc"), d.Payload)
+}
+
+func TestDissectMissingContext(t *testing.T) {
+	// context 1 is not in the table
+	_, err := newTestDissector().Dissect(0, shortSrc, shortDst, mustDecodeHex("7ef6"+"10"+"fc00"+"f7"+"ff"))
+	assert.NotNil(t, err)
+}
+
+func TestDissectIcmp6(t *testing.T) {
+	// IPHC: NH inline (ICMPv6), HLIM 64, link-local addresses from link addresses
+	frame, err := newTestDissector().Dissect(0, shortSrc, shortDst, mustDecodeHex("7a33"+"3a"+"8000"+"abcd"))
+	assert.Nil(t, err)
+	d := frame.Datagram
+	assert.Equal(t, "fe80::ff:fe00:400", d.Src.String())
+	assert.Equal(t, "fe80::ff:fe00:fc00", d.Dst.String())
+	assert.Equal(t, Icmp6Header{Type: 128, Code: 0}, *d.Icmp6)
+}
+
+func TestDissectExtHeader(t *testing.T) {
+	// IPHC followed by compressed Hop-by-Hop Options header (MPL option) and UDP
+	frame, err := newTestDissector().Dissect(0, extSrc, LinkAddr{Mode: LinkAddrShort, Short: 0xffff},
+		mustDecodeHex("7f3b03"+"e1"+"04"+"6d024001"+"f0"+"4d4c4d4c"+"0000"+"01"))
+	assert.Nil(t, err)
+	d := frame.Datagram
+	assert.Equal(t, "ff02::3", d.Dst.String())
+	assert.Equal(t, uint8(ProtoHopOpts), d.NextHeader)
+	assert.Equal(t, []uint8{ProtoHopOpts}, d.ExtHeaders)
+	assert.Equal(t, uint8(ProtoUdp), d.Protocol)
+	assert.Equal(t, uint16(9), d.Udp.Length)
+	assert.Equal(t, uint16(17), d.PayloadLength)
+}
+
+func TestDissectMeshHeader(t *testing.T) {
+	// mesh header with short originator 0x0400 and final 0xfc00, then IPHC with addresses from the mesh header
+	frame, err := newTestDissector().Dissect(0, LinkAddr{Mode: LinkAddrShort, Short: 0x0800}, LinkAddr{Mode: LinkAddrShort, Short: 0x0c00},
+		mustDecodeHex("be"+"0400"+"fc00"+"7a33"+"3a"+"8100"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(14), frame.Mesh.HopsLeft)
+	assert.Equal(t, shortSrc, frame.Mesh.Originator)
+	assert.Equal(t, shortDst, frame.Mesh.Final)
+	assert.Equal(t, "fe80::ff:fe00:400", frame.Datagram.Src.String())
+}
+
+func TestDissectFragments(t *testing.T) {
+	dissector := newTestDissector()
+	payload := bytes.Repeat([]byte{0x5a}, 100)
+	// 40 bytes IPv6 header + 8 bytes UDP header + 100 bytes payload
+	datagramSize := 148
+
+	frag1 := append(mustDecodeHex("c094"+"1234"+"7f3b01"+"f0"+"4d4c4d4c"+"0000"), payload[:48]...)
+	frame, err := dissector.Dissect(0, extSrc, shortDst, frag1)
+	assert.Nil(t, err)
+	assert.True(t, frame.IsFragment())
+	assert.True(t, frame.Fragment.IsFirst)
+	assert.Equal(t, uint16(datagramSize), frame.Fragment.DatagramSize)
+	assert.Equal(t, uint16(datagramSize-40), frame.Datagram.PayloadLength)
+	assert.Equal(t, uint16(datagramSize-40), frame.Datagram.Udp.Length)
+	assert.Nil(t, frame.Reassembled)
+
+	// offset 96 bytes
+	fragN := append(mustDecodeHex("e094"+"1234"+"0c"), payload[48:]...)
+	frame, err = dissector.Dissect(1000, extSrc, shortDst, fragN)
+	assert.Nil(t, err)
+	assert.False(t, frame.Fragment.IsFirst)
+	assert.Equal(t, uint16(96), frame.Fragment.Offset)
+	assert.Nil(t, frame.Datagram)
+	assert.NotNil(t, frame.Reassembled)
+	assert.Equal(t, payload, frame.Reassembled.Payload)
+	assert.Equal(t, uint16(19788), frame.Reassembled.Udp.DstPort)
+
+	// FRAGN without FRAG1 can not be reassembled
+	frame, err = dissector.Dissect(2000, extSrc, shortDst, fragN)
+	assert.Nil(t, err)
+	assert.Nil(t, frame.Reassembled)
+}
+
+func TestDissectFragmentsTimeout(t *testing.T) {
+	dissector := newTestDissector()
+	payload := bytes.Repeat([]byte{0x5a}, 100)
+
+	frag1 := append(mustDecodeHex("c094"+"1234"+"7f3b01"+"f0"+"4d4c4d4c"+"0000"), payload[:48]...)
+	_, err := dissector.Dissect(0, extSrc, shortDst, frag1)
+	assert.Nil(t, err)
+
+	fragN := append(mustDecodeHex("e094"+"1234"+"0c"), payload[48:]...)
+	frame, err := dissector.Dissect(reassemblyTimeout+1, extSrc, shortDst, fragN)
+	assert.Nil(t, err)
+	assert.Nil(t, frame.Reassembled)
+}
+
+func TestDissectLowpanTruncated(t *testing.T) {
+	full := mustDecodeHex("7f3b01" + "f0" + "4d4c4d4c" + "1234")
+	for n := 0; n < len(full); n++ {
+		_, err := newTestDissector().Dissect(0, extSrc, shortDst, full[:n])
+		assert.True(t, errors.Is(err, ErrTruncated), "length %d: %v", n, err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package lowpan
+
+// reassemblyTimeout is the time (in us) after which an incomplete datagram is discarded.
+const reassemblyTimeout = 5000000
+
+type reassemblyKey struct {
+	src  LinkAddr
+	dst  LinkAddr
+	size uint16
+	tag  uint16
+}
+
+type reassemblyEntry struct {
+	data      []byte
+	received  []bool
+	remaining int
+	timestamp uint64
+}
+
+// reassembler reassembles fragmented datagrams (RFC 4944 Section 5.3).
+type reassembler struct {
+	entries map[reassemblyKey]*reassemblyEntry
+}
+
+func newReassembler() *reassembler {
+	return &reassembler{
+		entries: map[reassemblyKey]*reassemblyEntry{},
+	}
+}
+
+// addFragment1 adds the decompressed first fragment of a datagram.
+func (ra *reassembler) addFragment1(ts uint64, src, dst LinkAddr, fh *FragmentHeader, raw []byte) {
+	ra.expire(ts)
+
+	key := reassemblyKey{src: src, dst: dst, size: fh.DatagramSize, tag: fh.DatagramTag}
+	size := int(fh.DatagramSize)
+	if len(raw) > size {
+		return
+	}
+
+	entry := &reassemblyEntry{
+		data:      make([]byte, size),
+		received:  make([]bool, size),
+		remaining: size,
+		timestamp: ts,
+	}
+	ra.entries[key] = entry
+	entry.add(0, raw)
+}
+
+// addFragmentN adds a subsequent fragment and returns the datagram if it is complete.
+func (ra *reassembler) addFragmentN(ts uint64, src, dst LinkAddr, fh *FragmentHeader, payload []byte) *Datagram {
+	ra.expire(ts)
+
+	key := reassemblyKey{src: src, dst: dst, size: fh.DatagramSize, tag: fh.DatagramTag}
+	entry := ra.entries[key]
+	if entry == nil || int(fh.Offset)+len(payload) > len(entry.data) {
+		return nil
+	}
+
+	entry.add(int(fh.Offset), payload)
+	if entry.remaining > 0 {
+		return nil
+	}
+
+	delete(ra.entries, key)
+	datagram, err := ParseDatagram(entry.data)
+	if err != nil {
+		return nil
+	}
+	return datagram
+}
+
+func (ra *reassembler) expire(ts uint64) {
+	for key, entry := range ra.entries {
+		if entry.timestamp+reassemblyTimeout < ts {
+			delete(ra.entries, key)
+		}
+	}
+}
+
+func (e *reassemblyEntry) add(offset int, data []byte) {
+	copy(e.data[offset:], data)
+	for i := offset; i < offset+len(data); i++ {
+		if !e.received[i] {
+			e.received[i] = true
+			e.remaining -= 1
+		}
+	}
+}
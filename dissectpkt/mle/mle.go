@@ -0,0 +1,191 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package mle
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// CommandType is the MLE command type.
+type CommandType uint8
+
+const (
+	CommandLinkRequest                   CommandType = 0
+	CommandLinkAccept                    CommandType = 1
+	CommandLinkAcceptAndRequest          CommandType = 2
+	CommandLinkReject                    CommandType = 3
+	CommandAdvertisement                 CommandType = 4
+	CommandUpdate                        CommandType = 5
+	CommandUpdateRequest                 CommandType = 6
+	CommandDataRequest                   CommandType = 7
+	CommandDataResponse                  CommandType = 8
+	CommandParentRequest                 CommandType = 9
+	CommandParentResponse                CommandType = 10
+	CommandChildIdRequest                CommandType = 11
+	CommandChildIdResponse               CommandType = 12
+	CommandChildUpdateRequest            CommandType = 13
+	CommandChildUpdateResponse           CommandType = 14
+	CommandAnnounce                      CommandType = 15
+	CommandDiscoveryRequest              CommandType = 16
+	CommandDiscoveryResponse             CommandType = 17
+	CommandLinkMetricsManagementRequest  CommandType = 18
+	CommandLinkMetricsManagementResponse CommandType = 19
+	CommandLinkProbe                     CommandType = 20
+)
+
+var commandNames = map[CommandType]string{
+	CommandLinkRequest:                   "Link Request",
+	CommandLinkAccept:                    "Link Accept",
+	CommandLinkAcceptAndRequest:          "Link Accept And Request",
+	CommandLinkReject:                    "Link Reject",
+	CommandAdvertisement:                 "Advertisement",
+	CommandUpdate:                        "Update",
+	CommandUpdateRequest:                 "Update Request",
+	CommandDataRequest:                   "Data Request",
+	CommandDataResponse:                  "Data Response",
+	CommandParentRequest:                 "Parent Request",
+	CommandParentResponse:                "Parent Response",
+	CommandChildIdRequest:                "Child ID Request",
+	CommandChildIdResponse:               "Child ID Response",
+	CommandChildUpdateRequest:            "Child Update Request",
+	CommandChildUpdateResponse:           "Child Update Response",
+	CommandAnnounce:                      "Announce",
+	CommandDiscoveryRequest:              "Discovery Request",
+	CommandDiscoveryResponse:             "Discovery Response",
+	CommandLinkMetricsManagementRequest:  "Link Metrics Management Request",
+	CommandLinkMetricsManagementResponse: "Link Metrics Management Response",
+	CommandLinkProbe:                     "Link Probe",
+}
+
+func (c CommandType) String() string {
+	if name, ok := commandNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("Unknown(%d)", uint8(c))
+}
+
+// TlvType is the MLE TLV type.
+type TlvType uint8
+
+const (
+	TlvSourceAddress         TlvType = 0
+	TlvMode                  TlvType = 1
+	TlvTimeout               TlvType = 2
+	TlvChallenge             TlvType = 3
+	TlvResponse              TlvType = 4
+	TlvLinkLayerFrameCounter TlvType = 5
+	TlvLinkQuality           TlvType = 6
+	TlvNetworkParameter      TlvType = 7
+	TlvMleFrameCounter       TlvType = 8
+	TlvRoute                 TlvType = 9
+	TlvAddress16             TlvType = 10
+	TlvLeaderData            TlvType = 11
+	TlvNetworkData           TlvType = 12
+	TlvTlvRequest            TlvType = 13
+	TlvScanMask              TlvType = 14
+	TlvConnectivity          TlvType = 15
+	TlvLinkMargin            TlvType = 16
+	TlvStatus                TlvType = 17
+	TlvVersion               TlvType = 18
+	TlvAddressRegistration   TlvType = 19
+	TlvChannel               TlvType = 20
+	TlvPanId                 TlvType = 21
+	TlvActiveTimestamp       TlvType = 22
+	TlvPendingTimestamp      TlvType = 23
+	TlvActiveDataset         TlvType = 24
+	TlvPendingDataset        TlvType = 25
+	TlvDiscovery             TlvType = 26
+	TlvCslChannel            TlvType = 80
+	TlvCslTimeout            TlvType = 85
+)
+
+// Tlv represents an MLE TLV.
+type Tlv struct {
+	Type  TlvType
+	Value []byte
+}
+
+// Message represents a dissected MLE message.
+type Message struct {
+	Command CommandType
+	Tlvs    []Tlv
+}
+
+// FindTlv returns the first TLV of the type, or nil if not found.
+func (m *Message) FindTlv(tlvType TlvType) *Tlv {
+	for i := range m.Tlvs {
+		if m.Tlvs[i].Type == tlvType {
+			return &m.Tlvs[i]
+		}
+	}
+	return nil
+}
+
+func (m *Message) String() string {
+	return fmt.Sprintf("MLE(%s,tlvs:%d)", m.Command, len(m.Tlvs))
+}
+
+// Dissect dissects the plaintext MLE message (command type followed by TLVs).
+func Dissect(payload []byte) (*Message, error) {
+	if len(payload) < 1 {
+		return nil, errors.Errorf("MLE message is empty")
+	}
+
+	msg := &Message{
+		Command: CommandType(payload[0]),
+	}
+
+	data := payload[1:]
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, errors.Errorf("MLE TLV truncated")
+		}
+
+		tlvType, length := TlvType(data[0]), int(data[1])
+		data = data[2:]
+
+		if length == 0xff {
+			// extended TLV with 16 bits length
+			if len(data) < 2 {
+				return nil, errors.Errorf("MLE extended TLV %d truncated", tlvType)
+			}
+			length = int(data[0])<<8 | int(data[1])
+			data = data[2:]
+		}
+
+		if len(data) < length {
+			return nil, errors.Errorf("MLE TLV %d needs %d bytes, length %d", tlvType, length, len(data))
+		}
+
+		msg.Tlvs = append(msg.Tlvs, Tlv{Type: tlvType, Value: data[:length]})
+		data = data[length:]
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package mle
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDissectParentRequest(t *testing.T) {
+	// Parent Request: Mode, Challenge, Scan Mask, Version
+	payload, _ := hex.DecodeString("09" + "01010f" + "03080102030405060708" + "0e0180" + "12020003")
+	msg, err := Dissect(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, CommandParentRequest, msg.Command)
+	assert.Equal(t, "Parent Request", msg.Command.String())
+	assert.Len(t, msg.Tlvs, 4)
+	assert.Equal(t, []byte{0x0f}, msg.FindTlv(TlvMode).Value)
+	assert.Equal(t, []byte{0x80}, msg.FindTlv(TlvScanMask).Value)
+	assert.Nil(t, msg.FindTlv(TlvRoute))
+}
+
+func TestDissectInvalid(t *testing.T) {
+	_, err := Dissect(nil)
+	assert.NotNil(t, err)
+
+	_, err = Dissect([]byte{0x09, 0x01})
+	assert.NotNil(t, err)
+
+	_, err = Dissect([]byte{0x09, 0x03, 0x08, 0x01})
+	assert.NotNil(t, err)
+
+	assert.Equal(t, "Unknown(99)", CommandType(99).String())
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package dissectpkt
+
+import (
+	"encoding/binary"
+
+	"github.com/openthread/ot-ns/dissectpkt/lowpan"
+	"github.com/openthread/ot-ns/dissectpkt/security"
+	"github.com/openthread/ot-ns/dissectpkt/wpan"
+	"github.com/pkg/errors"
+)
+
+const (
+	MleSecuritySuiteSecured    = 0
+	MleSecuritySuiteNoSecurity = 255
+)
+
+var (
+	ErrNoMasterKey = errors.New("master key not set")
+)
+
+// decryptMacFrame decrypts the payload of the secured MAC frame.
+func (d *Dissector) decryptMacFrame(pktinfo *PktInfo, srcExtAddr uint64) {
+	frame := pktinfo.MacFrame
+	sh := frame.SecurityHeader
+
+	if frame.FrameControl.SourceAddrMode() == wpan.SrcAddrModeExtended {
+		srcExtAddr = frame.SrcAddrExtended
+	}
+
+	var key []byte
+	switch sh.KeyIdMode() {
+	case wpan.KeyIdMode1:
+		if d.keys == nil {
+			pktinfo.DecryptError = ErrNoMasterKey
+			return
+		}
+		key = d.keys.MacKey(security.KeySequenceForIndex(d.keySequence, sh.KeyIndex))
+	case wpan.KeyIdMode2:
+		key = security.Mode2Key
+		srcExtAddr = security.Mode2ExtAddr
+	default:
+		pktinfo.DecryptError = errors.Errorf("key id mode %d not supported", sh.KeyIdMode())
+		return
+	}
+
+	if srcExtAddr == 0 {
+		pktinfo.DecryptError = errors.Errorf("source extended address unknown")
+		return
+	}
+
+	nonce := security.Nonce(srcExtAddr, sh.FrameCounter, sh.SecurityLevel())
+	plaintext, err := security.DecryptCcm(key, nonce, frame.Header, frame.Payload, frame.Mic, sh.Encrypted())
+	if err != nil {
+		pktinfo.DecryptError = err
+		return
+	}
+
+	_, payload, err := frame.SplitPayloadIEs(plaintext)
+	if err != nil {
+		pktinfo.DecryptError = err
+		return
+	}
+
+	pktinfo.plaintext = plaintext
+	pktinfo.MacPayload = payload
+	pktinfo.Decrypted = true
+}
+
+// decryptMle returns the plaintext of the MLE message carried in the datagram.
+func (d *Dissector) decryptMle(datagram *lowpan.Datagram) ([]byte, error) {
+	msg := datagram.Payload
+	if len(msg) < 1 {
+		return nil, errors.Wrapf(lowpan.ErrTruncated, "MLE security suite")
+	}
+
+	switch msg[0] {
+	case MleSecuritySuiteNoSecurity:
+		return msg[1:], nil
+	case MleSecuritySuiteSecured:
+	default:
+		return nil, errors.Errorf("invalid MLE security suite %d", msg[0])
+	}
+
+	if d.keys == nil {
+		return nil, ErrNoMasterKey
+	}
+
+	// security control (1) + frame counter (4) + key source (4) + key index (1)
+	const headerSize = 10
+	const micSize = 4
+	if len(msg) < 1+headerSize+micSize {
+		return nil, errors.Wrapf(lowpan.ErrTruncated, "MLE message of %d bytes", len(msg))
+	}
+
+	header := msg[1 : 1+headerSize]
+	securityControl := header[0]
+	if (securityControl>>3)&0x03 != wpan.KeyIdMode2 {
+		return nil, errors.Errorf("MLE key id mode %d not supported", (securityControl>>3)&0x03)
+	}
+	frameCounter := binary.LittleEndian.Uint32(header[1:])
+	keySequence := binary.BigEndian.Uint32(header[5:])
+
+	src := datagram.Src.To16()
+	srcExtAddr := binary.BigEndian.Uint64(src[8:]) ^ 0x0200000000000000
+
+	aad := make([]byte, 0, 32+headerSize)
+	aad = append(aad, src...)
+	aad = append(aad, datagram.Dst.To16()...)
+	aad = append(aad, header...)
+
+	nonce := security.Nonce(srcExtAddr, frameCounter, securityControl&0x07)
+	ciphertext := msg[1+headerSize : len(msg)-micSize]
+	plaintext, err := security.DecryptCcm(d.keys.MleKey(keySequence), nonce, aad, ciphertext, msg[len(msg)-micSize:], true)
+	if err != nil {
+		return nil, err
+	}
+
+	if keySequence > d.keySequence {
+		// follow key rotation observed in MLE messages
+		d.keySequence = keySequence
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// NonceSize is the size of the AES-CCM* nonce used by IEEE 802.15.4 and MLE.
+	NonceSize = 13
+	// ccmL is the size of the length field (15 - NonceSize).
+	ccmL = 2
+)
+
+var (
+	ErrMicMismatch = errors.New("MIC mismatch")
+)
+
+// Nonce builds the AES-CCM* nonce from the source extended address, the frame counter and the security level.
+func Nonce(extAddr uint64, frameCounter uint32, securityLevel uint8) []byte {
+	nonce := make([]byte, NonceSize)
+	binary.BigEndian.PutUint64(nonce[0:], extAddr)
+	binary.BigEndian.PutUint32(nonce[8:], frameCounter)
+	nonce[12] = securityLevel
+	return nonce
+}
+
+// DecryptCcm authenticates and decrypts the ciphertext with AES-CCM* and returns the plaintext.
+// The MIC size can be 0, 4, 8 or 16 bytes. The plaintext is not encrypted if encrypted is false,
+// in which case it is authenticated as part of the additional data.
+func DecryptCcm(key []byte, nonce []byte, aad []byte, ciphertext []byte, mic []byte, encrypted bool) ([]byte, error) {
+	block, err := newCcmBlock(key, nonce, len(mic))
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := ciphertext
+	if encrypted {
+		plaintext = ccmCtr(block, nonce, ciphertext)
+	} else {
+		aad = append(append([]byte(nil), aad...), ciphertext...)
+		plaintext = nil
+	}
+
+	tag := ccmTag(block, nonce, aad, plaintext, len(mic))
+	if subtle.ConstantTimeCompare(tag, mic) != 1 {
+		return nil, ErrMicMismatch
+	}
+
+	if !encrypted {
+		return ciphertext, nil
+	}
+	return plaintext, nil
+}
+
+// EncryptCcm encrypts and authenticates the plaintext with AES-CCM* and returns the ciphertext and MIC.
+func EncryptCcm(key []byte, nonce []byte, aad []byte, plaintext []byte, micSize int) ([]byte, []byte, error) {
+	block, err := newCcmBlock(key, nonce, micSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tag := ccmTag(block, nonce, aad, plaintext, micSize)
+	return ccmCtr(block, nonce, plaintext), tag, nil
+}
+
+func newCcmBlock(key []byte, nonce []byte, micSize int) (cipher.Block, error) {
+	if len(nonce) != NonceSize {
+		return nil, errors.Errorf("invalid nonce size %d", len(nonce))
+	}
+
+	switch micSize {
+	case 0, 4, 8, 16:
+	default:
+		return nil, errors.Errorf("invalid MIC size %d", micSize)
+	}
+
+	return aes.NewCipher(key)
+}
+
+// ccmCtr encrypts or decrypts the data in CTR mode starting from counter 1.
+func ccmCtr(block cipher.Block, nonce []byte, data []byte) []byte {
+	out := make([]byte, len(data))
+	ctr := make([]byte, aes.BlockSize)
+	ctr[0] = ccmL - 1
+	copy(ctr[1:], nonce)
+	ctr[aes.BlockSize-1] = 1
+	cipher.NewCTR(block, ctr).XORKeyStream(out, data)
+	return out
+}
+
+// ccmTag computes the encrypted CBC-MAC of the additional data and the plaintext.
+func ccmTag(block cipher.Block, nonce []byte, aad []byte, plaintext []byte, micSize int) []byte {
+	if micSize == 0 {
+		return []byte{}
+	}
+
+	b0 := make([]byte, aes.BlockSize)
+	b0[0] = uint8((micSize-2)/2)<<3 | (ccmL - 1)
+	if len(aad) > 0 {
+		b0[0] |= 0x40
+	}
+	copy(b0[1:], nonce)
+	binary.BigEndian.PutUint16(b0[aes.BlockSize-ccmL:], uint16(len(plaintext)))
+
+	x := make([]byte, aes.BlockSize)
+	block.Encrypt(x, b0)
+
+	mac := func(data []byte) {
+		for len(data) > 0 {
+			n := aes.BlockSize
+			if len(data) < n {
+				n = len(data)
+			}
+			for i := 0; i < n; i++ {
+				x[i] ^= data[i]
+			}
+			block.Encrypt(x, x)
+			data = data[n:]
+		}
+	}
+
+	if len(aad) > 0 {
+		// additional data shorter than 0xff00 bytes is prefixed with its 2 bytes length
+		a := make([]byte, 2, 2+len(aad))
+		binary.BigEndian.PutUint16(a, uint16(len(aad)))
+		mac(append(a, aad...))
+	}
+	mac(plaintext)
+
+	a0 := make([]byte, aes.BlockSize)
+	a0[0] = ccmL - 1
+	copy(a0[1:], nonce)
+	s0 := make([]byte, aes.BlockSize)
+	block.Encrypt(s0, a0)
+
+	tag := make([]byte, micSize)
+	for i := range tag {
+		tag[i] = x[i] ^ s0[i]
+	}
+	return tag
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	KeySize = 16
+)
+
+// Mode2Key is the well-known key used for frames secured with Key Identifier Mode 2.
+var Mode2Key = []byte{0x78, 0x58, 0x16, 0x86, 0xfd, 0xb4, 0x58, 0x0f, 0xb0, 0x92, 0x54, 0x6a, 0xec, 0xbd, 0x15, 0x66}
+
+// Mode2ExtAddr is the well-known source address used in the nonce of frames secured with Key Identifier Mode 2.
+const Mode2ExtAddr uint64 = 0x3506feb823d48712
+
+// ParseMasterKey parses the master key in hex format (e.g. "00112233445566778899aabbccddeeff").
+func ParseMasterKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid master key %s", s)
+	}
+	if len(key) != KeySize {
+		return nil, errors.Errorf("invalid master key size %d", len(key))
+	}
+	return key, nil
+}
+
+type keyPair struct {
+	mac []byte
+	mle []byte
+}
+
+// KeyManager derives the Thread MAC and MLE keys from the master key and caches them by key sequence.
+type KeyManager struct {
+	masterKey []byte
+	keys      map[uint32]keyPair
+}
+
+// NewKeyManager creates a new KeyManager with the master key.
+func NewKeyManager(masterKey []byte) *KeyManager {
+	return &KeyManager{
+		masterKey: masterKey,
+		keys:      map[uint32]keyPair{},
+	}
+}
+
+// MasterKey returns the master key.
+func (km *KeyManager) MasterKey() []byte {
+	return km.masterKey
+}
+
+// MacKey returns the MAC key for the key sequence.
+func (km *KeyManager) MacKey(keySequence uint32) []byte {
+	return km.derive(keySequence).mac
+}
+
+// MleKey returns the MLE key for the key sequence.
+func (km *KeyManager) MleKey(keySequence uint32) []byte {
+	return km.derive(keySequence).mle
+}
+
+// derive computes HMAC-SHA256(masterKey, keySequence || "Thread"), of which the first half is
+// the MLE key and the second half is the MAC key.
+func (km *KeyManager) derive(keySequence uint32) keyPair {
+	if keys, ok := km.keys[keySequence]; ok {
+		return keys
+	}
+
+	seq := make([]byte, 4)
+	binary.BigEndian.PutUint32(seq, keySequence)
+
+	h := hmac.New(sha256.New, km.masterKey)
+	h.Write(seq)
+	h.Write([]byte("Thread"))
+	sum := h.Sum(nil)
+
+	keys := keyPair{
+		mle: sum[:KeySize],
+		mac: sum[KeySize:],
+	}
+	km.keys[keySequence] = keys
+	return keys
+}
+
+// KeyIndex returns the key index of the key sequence used in Key Identifier Mode 1.
+func KeyIndex(keySequence uint32) uint8 {
+	return uint8(keySequence&0x7f) + 1
+}
+
+// KeySequenceForIndex returns the key sequence nearest to the current key sequence that matches the key index.
+func KeySequenceForIndex(current uint32, keyIndex uint8) uint32 {
+	seq := current&^0x7f | uint32(keyIndex-1)&0x7f
+	if seq > current && seq-current > 0x40 && seq >= 0x80 {
+		seq -= 0x80
+	} else if seq < current && current-seq > 0x40 {
+		seq += 0x80
+	}
+	return seq
+}
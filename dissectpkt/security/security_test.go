@@ -0,0 +1,100 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package security
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustDecodeHex(s string) []byte {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func TestCcmRfc3610Vector1(t *testing.T) {
+	key := mustDecodeHex("c0c1c2c3c4c5c6c7c8c9cacbcccdcecf")
+	nonce := mustDecodeHex("00000003020100a0a1a2a3a4a5")
+	aad := mustDecodeHex("0001020304050607")
+	plaintext := mustDecodeHex("08090a0b0c0d0e0f101112131415161718191a1b1c1d1e")
+
+	ciphertext, mic, err := EncryptCcm(key, nonce, aad, plaintext, 8)
+	assert.Nil(t, err)
+	assert.Equal(t, mustDecodeHex("588c979a61c663d2f066d0c2c0f989806d5f6b61dac384"), ciphertext)
+	assert.Equal(t, mustDecodeHex("17e8d12cfdf926e0"), mic)
+
+	decrypted, err := DecryptCcm(key, nonce, aad, ciphertext, mic, true)
+	assert.Nil(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	mic[0] ^= 1
+	_, err = DecryptCcm(key, nonce, aad, ciphertext, mic, true)
+	assert.Equal(t, ErrMicMismatch, err)
+}
+
+func TestCcmMicOnly(t *testing.T) {
+	key := mustDecodeHex("c0c1c2c3c4c5c6c7c8c9cacbcccdcecf")
+	nonce := Nonce(0x0102030405060708, 1, 2)
+	aad := mustDecodeHex("0001020304050607")
+	payload := []byte("payload")
+
+	_, mic, err := EncryptCcm(key, nonce, append(append([]byte(nil), aad...), payload...), nil, 8)
+	assert.Nil(t, err)
+
+	plaintext, err := DecryptCcm(key, nonce, aad, payload, mic, false)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, plaintext)
+}
+
+func TestKeyDerivation(t *testing.T) {
+	masterKey, err := ParseMasterKey("00112233445566778899aabbccddeeff")
+	assert.Nil(t, err)
+
+	km := NewKeyManager(masterKey)
+	assert.Equal(t, mustDecodeHex("5445f4158fd75912175809f8b57a66a4"), km.MleKey(0))
+	assert.Equal(t, mustDecodeHex("de89c53af382b421e0fde5a9bae3bef0"), km.MacKey(0))
+	assert.NotEqual(t, km.MacKey(0), km.MacKey(1))
+
+	_, err = ParseMasterKey("0011")
+	assert.NotNil(t, err)
+	_, err = ParseMasterKey("not a key")
+	assert.NotNil(t, err)
+}
+
+func TestKeySequenceForIndex(t *testing.T) {
+	assert.Equal(t, uint8(1), KeyIndex(0))
+	assert.Equal(t, uint8(1), KeyIndex(0x80))
+	assert.Equal(t, uint32(0), KeySequenceForIndex(0, 1))
+	assert.Equal(t, uint32(1), KeySequenceForIndex(0, 2))
+	assert.Equal(t, uint32(0x80), KeySequenceForIndex(0x7f, 1))
+	assert.Equal(t, uint32(0x7f), KeySequenceForIndex(0x80, 0x80))
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package wpan
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	HeaderIeVendorSpecific uint16 = 0x00
+	HeaderIeCslIe          uint16 = 0x1a
+	HeaderIeAckRequest     uint16 = 0x1b
+	HeaderIeTermination1   uint16 = 0x7e
+	HeaderIeTermination2   uint16 = 0x7f
+)
+
+const (
+	PayloadIeEsdu           uint16 = 0x0
+	PayloadIeMlme           uint16 = 0x1
+	PayloadIeVendorSpecific uint16 = 0x2
+	PayloadIeTermination    uint16 = 0xf
+)
+
+// InformationElement represents a Header IE or Payload IE.
+type InformationElement struct {
+	// Id is the Element ID of a Header IE, or the Group ID of a Payload IE.
+	Id        uint16
+	IsPayload bool
+	Content   []byte
+}
+
+func (ie InformationElement) String() string {
+	if ie.IsPayload {
+		return fmt.Sprintf("PIE(%d,len=%d)", ie.Id, len(ie.Content))
+	}
+	return fmt.Sprintf("HIE(0x%02x,len=%d)", ie.Id, len(ie.Content))
+}
+
+func dissectHeaderIEs(r *frameReader) (ies []InformationElement, err error) {
+	for r.pos < r.end {
+		var desc uint16
+		if desc, err = r.readUint16(); err != nil {
+			return
+		}
+
+		if desc&0x8000 != 0 {
+			err = errors.Errorf("unexpected payload IE in header IE list")
+			return
+		}
+
+		ie := InformationElement{
+			Id: (desc >> 7) & 0xff,
+		}
+		if ie.Content, err = r.readBytes(int(desc & 0x7f)); err != nil {
+			return
+		}
+
+		ies = append(ies, ie)
+		if ie.Id == HeaderIeTermination1 || ie.Id == HeaderIeTermination2 {
+			break
+		}
+	}
+
+	return
+}
+
+func dissectPayloadIEs(payload []byte) (ies []InformationElement, n int, err error) {
+	r := &frameReader{data: payload, pos: 0, end: len(payload)}
+
+	for r.pos < r.end {
+		var desc uint16
+		if desc, err = r.readUint16(); err != nil {
+			return
+		}
+
+		if desc&0x8000 == 0 {
+			err = errors.Errorf("unexpected header IE in payload IE list")
+			return
+		}
+
+		ie := InformationElement{
+			Id:        (desc >> 11) & 0x0f,
+			IsPayload: true,
+		}
+		if ie.Content, err = r.readBytes(int(desc & 0x07ff)); err != nil {
+			return
+		}
+
+		ies = append(ies, ie)
+		if ie.Id == PayloadIeTermination {
+			break
+		}
+	}
+
+	n = r.pos
+	return
+}
+
+// FindHeaderIE returns the first Header IE with the specified Element ID.
+func (f *MacFrame) FindHeaderIE(id uint16) *InformationElement {
+	for i := range f.HeaderIEs {
+		if f.HeaderIEs[i].Id == id {
+			return &f.HeaderIEs[i]
+		}
+	}
+	return nil
+}
+
+// VendorOui returns the OUI of a vendor specific IE.
+func (ie InformationElement) VendorOui() (uint32, bool) {
+	if len(ie.Content) < 3 {
+		return 0, false
+	}
+	var oui [4]byte
+	copy(oui[:3], ie.Content[:3])
+	return binary.LittleEndian.Uint32(oui[:]), true
+}
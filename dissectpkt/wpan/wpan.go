@@ -29,15 +29,18 @@ package wpan
 import (
 	"encoding/binary"
 	"fmt"
+
+	"github.com/pkg/errors"
 )
 
 type FrameType = uint16
 
 const (
-	FrameTypeBeacon  FrameType = 0
-	FrameTypeData    FrameType = 1
-	FrameTypeAck     FrameType = 2
-	FrameTypeCommand FrameType = 3
+	FrameTypeBeacon       FrameType = 0
+	FrameTypeData         FrameType = 1
+	FrameTypeAck          FrameType = 2
+	FrameTypeCommand      FrameType = 3
+	FrameTypeMultipurpose FrameType = 5
 )
 
 const (
@@ -47,6 +50,28 @@ const (
 	DstAddrModeExtended = 3
 )
 
+const (
+	SrcAddrModeNone     = 0
+	SrcAddrModeReserved = 1
+	SrcAddrModeShort    = 2
+	SrcAddrModeExtended = 3
+)
+
+const (
+	FrameVersion2003 = 0
+	FrameVersion2006 = 1
+	FrameVersion2015 = 2
+)
+
+const (
+	// FcsSize is the size of the Frame Check Sequence at the end of each frame.
+	FcsSize = 2
+)
+
+var (
+	ErrFrameTruncated = errors.New("frame truncated")
+)
+
 type FrameControl uint16
 
 func (fc FrameControl) String() string {
@@ -73,6 +98,10 @@ func (fc FrameControl) PanidCompression() bool {
 	return (fc & 0x0040) != 0
 }
 
+func (fc FrameControl) SeqNumSuppression() bool {
+	return (fc & 0x0100) != 0
+}
+
 func (fc FrameControl) IEPresent() bool {
 	return (fc & 0x0200) != 0
 }
@@ -89,10 +118,109 @@ func (fc FrameControl) FrameVersion() uint16 {
 	return uint16((fc & 0x3000) >> 12)
 }
 
+// SeqPresent returns if the Sequence Number field is present.
+func (fc FrameControl) SeqPresent() bool {
+	return fc.FrameVersion() != FrameVersion2015 || !fc.SeqNumSuppression()
+}
+
+// DstPanIdPresent returns if the Destination PAN ID field is present.
+func (fc FrameControl) DstPanIdPresent() bool {
+	if fc.FrameVersion() != FrameVersion2015 {
+		return fc.DstAddrMode() != DstAddrModeNone
+	}
+
+	// IEEE 802.15.4-2015 Table 7-2: PAN ID Compression field value
+	dstMode, srcMode, panidComp := fc.DstAddrMode(), fc.SourceAddrMode(), fc.PanidCompression()
+	switch {
+	case dstMode == DstAddrModeNone && srcMode == SrcAddrModeNone:
+		return panidComp
+	case dstMode == DstAddrModeNone:
+		return false
+	case srcMode == SrcAddrModeNone:
+		return !panidComp
+	case dstMode == DstAddrModeExtended && srcMode == SrcAddrModeExtended:
+		return !panidComp
+	default:
+		return true
+	}
+}
+
+// SrcPanIdPresent returns if the Source PAN ID field is present.
+func (fc FrameControl) SrcPanIdPresent() bool {
+	if fc.SourceAddrMode() == SrcAddrModeNone || fc.PanidCompression() {
+		return false
+	}
+
+	if fc.FrameVersion() == FrameVersion2015 {
+		// Dst and Src addresses are both extended: Src PAN ID is never present
+		return fc.DstAddrMode() != DstAddrModeExtended || fc.SourceAddrMode() != SrcAddrModeExtended
+	}
+
+	return true
+}
+
 func (fc *FrameControl) Dissect(bytes []byte) {
 	*fc = FrameControl(binary.LittleEndian.Uint16(bytes))
 }
 
+const (
+	KeyIdMode0 = 0
+	KeyIdMode1 = 1
+	KeyIdMode2 = 2
+	KeyIdMode3 = 3
+)
+
+const (
+	SecurityLevelNone      = 0
+	SecurityLevelMic32     = 1
+	SecurityLevelMic64     = 2
+	SecurityLevelMic128    = 3
+	SecurityLevelEnc       = 4
+	SecurityLevelEncMic32  = 5
+	SecurityLevelEncMic64  = 6
+	SecurityLevelEncMic128 = 7
+)
+
+// AuxSecurityHeader represents the Auxiliary Security Header of a secured frame.
+type AuxSecurityHeader struct {
+	SecurityControl     uint8
+	FrameCounter        uint32
+	KeySource           []byte
+	KeyIndex            uint8
+	FrameCounterPresent bool
+}
+
+func (sh *AuxSecurityHeader) SecurityLevel() uint8 {
+	return sh.SecurityControl & 0x07
+}
+
+func (sh *AuxSecurityHeader) KeyIdMode() uint8 {
+	return (sh.SecurityControl >> 3) & 0x03
+}
+
+// MicSize returns the size of the Message Integrity Code for the security level.
+func (sh *AuxSecurityHeader) MicSize() int {
+	switch sh.SecurityLevel() & 0x03 {
+	case 1:
+		return 4
+	case 2:
+		return 8
+	case 3:
+		return 16
+	default:
+		return 0
+	}
+}
+
+// Encrypted returns if the frame payload is encrypted.
+func (sh *AuxSecurityHeader) Encrypted() bool {
+	return sh.SecurityLevel()&0x04 != 0
+}
+
+func (sh *AuxSecurityHeader) String() string {
+	return fmt.Sprintf("Sec(lvl:%d,kim:%d,kidx:%d,fc:%d)", sh.SecurityLevel(), sh.KeyIdMode(), sh.KeyIndex, sh.FrameCounter)
+}
+
 type MacFrame struct {
 	Channel         uint8
 	FrameControl    FrameControl
@@ -100,43 +228,326 @@ type MacFrame struct {
 	DstPanId        uint16
 	DstAddrShort    uint16
 	DstAddrExtended uint64
+	SrcPanId        uint16
+	SrcAddrShort    uint16
+	SrcAddrExtended uint64
+	SecurityHeader  *AuxSecurityHeader
+	// SecurityHeaderOffset and SecurityHeaderLength locate the Auxiliary Security Header in the MAC header.
+	SecurityHeaderOffset int
+	SecurityHeaderLength int
+	HeaderIEs            []InformationElement
+	PayloadIEs           []InformationElement
+	// HeaderLength is the length of the MAC header (including the Auxiliary Security Header and Header IEs).
+	HeaderLength int
+	// Header is the MAC header, which is also the authentication data of secured frames.
+	Header  []byte
+	Payload []byte
+	Mic     []byte
+	Fcs     uint16
+}
+
+// PanId returns the PAN ID of the frame, which is the Destination PAN ID if present, or the Source PAN ID otherwise.
+func (f *MacFrame) PanId() uint16 {
+	if f.FrameControl.DstPanIdPresent() {
+		return f.DstPanId
+	}
+	return f.SrcPanId
 }
 
 func (f *MacFrame) String() string {
-	if f.FrameControl.FrameType() == FrameTypeAck {
+	if f.FrameControl.FrameType() == FrameTypeAck && f.FrameControl.FrameVersion() != FrameVersion2015 {
 		return fmt.Sprintf("ACK,FC:%s,Seq:%d", f.FrameControl, f.Seq)
 	}
 
-	var dstAddrS string
-	dstAddrMode := f.FrameControl.DstAddrMode()
-	if dstAddrMode == DstAddrModeShort {
-		dstAddrS = fmt.Sprintf("%04x", f.DstAddrShort)
-	} else if dstAddrMode == DstAddrModeExtended {
-		dstAddrS = fmt.Sprintf("%016x", f.DstAddrExtended)
-	} else {
-		dstAddrS = "-"
+	s := fmt.Sprintf("MAC,FC:%s,Seq:%d,Dst:%s,Src:%s", f.FrameControl, f.Seq, f.FormatDstAddr(), f.FormatSrcAddr())
+	if f.SecurityHeader != nil {
+		s += "," + f.SecurityHeader.String()
 	}
+	return s
+}
+
+// FormatDstAddr returns the destination address as a string.
+func (f *MacFrame) FormatDstAddr() string {
+	switch f.FrameControl.DstAddrMode() {
+	case DstAddrModeShort:
+		return fmt.Sprintf("%04x", f.DstAddrShort)
+	case DstAddrModeExtended:
+		return fmt.Sprintf("%016x", f.DstAddrExtended)
+	default:
+		return "-"
+	}
+}
+
+// FormatSrcAddr returns the source address as a string.
+func (f *MacFrame) FormatSrcAddr() string {
+	switch f.FrameControl.SourceAddrMode() {
+	case SrcAddrModeShort:
+		return fmt.Sprintf("%04x", f.SrcAddrShort)
+	case SrcAddrModeExtended:
+		return fmt.Sprintf("%016x", f.SrcAddrExtended)
+	default:
+		return "-"
+	}
+}
+
+// frameReader reads little-endian fields from a frame with bounds checking.
+type frameReader struct {
+	data []byte
+	pos  int
+	end  int
+}
+
+func (r *frameReader) need(n int) error {
+	if r.pos+n > r.end {
+		return errors.Wrapf(ErrFrameTruncated, "need %d bytes at offset %d, frame length %d", n, r.pos, r.end)
+	}
+	return nil
+}
 
-	return fmt.Sprintf("MAC,FC:%s,Seq:%d,Dst:%s", f.FrameControl, f.Seq, dstAddrS)
+func (r *frameReader) readUint8() (uint8, error) {
+	if err := r.need(1); err != nil {
+		return 0, err
+	}
+	v := r.data[r.pos]
+	r.pos += 1
+	return v, nil
+}
+
+func (r *frameReader) readUint16() (uint16, error) {
+	if err := r.need(2); err != nil {
+		return 0, err
+	}
+	v := binary.LittleEndian.Uint16(r.data[r.pos:])
+	r.pos += 2
+	return v, nil
 }
 
-func Dissect(data []byte) *MacFrame {
+func (r *frameReader) readUint32() (uint32, error) {
+	if err := r.need(4); err != nil {
+		return 0, err
+	}
+	v := binary.LittleEndian.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+func (r *frameReader) readUint64() (uint64, error) {
+	if err := r.need(8); err != nil {
+		return 0, err
+	}
+	v := binary.LittleEndian.Uint64(r.data[r.pos:])
+	r.pos += 8
+	return v, nil
+}
+
+func (r *frameReader) readBytes(n int) ([]byte, error) {
+	if err := r.need(n); err != nil {
+		return nil, err
+	}
+	v := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return v, nil
+}
+
+// Dissect dissects a radio frame which consists of the channel (1 byte) followed by the PSDU (including FCS).
+func Dissect(data []byte) (*MacFrame, error) {
+	if len(data) < 1 {
+		return nil, errors.Wrapf(ErrFrameTruncated, "missing channel")
+	}
+
 	frame := &MacFrame{}
 	frame.Channel = data[0]
-	frame.FrameControl.Dissect(data[1:3])
-	frame.Seq = data[3]
-	if frame.FrameControl.FrameType() == FrameTypeAck {
-		return frame
+	psdu := data[1:]
+
+	if len(psdu) < 2+FcsSize {
+		return nil, errors.Wrapf(ErrFrameTruncated, "PSDU length %d", len(psdu))
+	}
+
+	frame.FrameControl.Dissect(psdu[0:2])
+	frame.Fcs = binary.LittleEndian.Uint16(psdu[len(psdu)-FcsSize:])
+
+	r := &frameReader{data: psdu, pos: 2, end: len(psdu) - FcsSize}
+	if err := frame.dissectHeader(r); err != nil {
+		return nil, err
 	}
 
-	frame.DstPanId = binary.LittleEndian.Uint16(data[4:6])
-	dstAddrMode := frame.FrameControl.DstAddrMode()
+	frame.HeaderLength = r.pos
+	frame.Header = psdu[:r.pos]
+
+	micSize := 0
+	if frame.SecurityHeader != nil {
+		micSize = frame.SecurityHeader.MicSize()
+	}
 
-	if dstAddrMode == DstAddrModeShort { // SHORT
-		frame.DstAddrShort = binary.LittleEndian.Uint16(data[6:8])
-	} else if dstAddrMode == DstAddrModeExtended { // EXTEND
-		frame.DstAddrExtended = binary.LittleEndian.Uint64(data[6:14])
+	if r.end-r.pos < micSize {
+		return nil, errors.Wrapf(ErrFrameTruncated, "MIC of %d bytes", micSize)
 	}
 
-	return frame
+	frame.Payload = psdu[r.pos : r.end-micSize]
+	frame.Mic = psdu[r.end-micSize : r.end]
+
+	if frame.SecurityHeader == nil {
+		// Payload IEs are only readable if the frame is not secured
+		ies, payload, err := frame.SplitPayloadIEs(frame.Payload)
+		if err != nil {
+			return nil, err
+		}
+		frame.PayloadIEs = ies
+		frame.Payload = payload
+	}
+
+	return frame, nil
+}
+
+// SplitPayloadIEs splits the (decrypted) MAC payload into Payload IEs and the remaining payload.
+func (f *MacFrame) SplitPayloadIEs(payload []byte) ([]InformationElement, []byte, error) {
+	if !f.FrameControl.IEPresent() || !f.hasPayloadIEs() {
+		return nil, payload, nil
+	}
+
+	ies, n, err := dissectPayloadIEs(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ies, payload[n:], nil
+}
+
+// UnsecuredPsdu builds the PSDU of the frame with the plaintext payload (including Payload IEs) and
+// without security: the Security Enabled bit is cleared, the Auxiliary Security Header and MIC are
+// removed and the FCS is recomputed.
+func (f *MacFrame) UnsecuredPsdu(plaintext []byte) []byte {
+	psdu := make([]byte, 0, len(f.Header)+len(plaintext)+FcsSize)
+	if f.SecurityHeader == nil {
+		psdu = append(psdu, f.Header...)
+	} else {
+		psdu = append(psdu, f.Header[:f.SecurityHeaderOffset]...)
+		psdu = append(psdu, f.Header[f.SecurityHeaderOffset+f.SecurityHeaderLength:]...)
+		binary.LittleEndian.PutUint16(psdu, uint16(f.FrameControl)&^0x0008)
+	}
+	psdu = append(psdu, plaintext...)
+
+	fcs := make([]byte, FcsSize)
+	binary.LittleEndian.PutUint16(fcs, ComputeFcs(psdu))
+	return append(psdu, fcs...)
+}
+
+// ComputeFcs computes the FCS (ITU-T CRC-16) of the PSDU without FCS.
+func ComputeFcs(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0x8408
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+func (f *MacFrame) dissectHeader(r *frameReader) (err error) {
+	fc := f.FrameControl
+
+	if fc.SeqPresent() {
+		if f.Seq, err = r.readUint8(); err != nil {
+			return
+		}
+	}
+
+	if fc.FrameType() == FrameTypeAck && fc.FrameVersion() != FrameVersion2015 {
+		// Imm-Ack has no addressing fields
+		return
+	}
+
+	if fc.DstPanIdPresent() {
+		if f.DstPanId, err = r.readUint16(); err != nil {
+			return
+		}
+	}
+
+	switch fc.DstAddrMode() {
+	case DstAddrModeShort:
+		f.DstAddrShort, err = r.readUint16()
+	case DstAddrModeExtended:
+		f.DstAddrExtended, err = r.readUint64()
+	case DstAddrModeReserved:
+		err = errors.Errorf("reserved destination address mode")
+	}
+	if err != nil {
+		return
+	}
+
+	if fc.SrcPanIdPresent() {
+		if f.SrcPanId, err = r.readUint16(); err != nil {
+			return
+		}
+	} else if fc.SourceAddrMode() != SrcAddrModeNone {
+		f.SrcPanId = f.DstPanId
+	}
+
+	switch fc.SourceAddrMode() {
+	case SrcAddrModeShort:
+		f.SrcAddrShort, err = r.readUint16()
+	case SrcAddrModeExtended:
+		f.SrcAddrExtended, err = r.readUint64()
+	case SrcAddrModeReserved:
+		err = errors.Errorf("reserved source address mode")
+	}
+	if err != nil {
+		return
+	}
+
+	if fc.SecurityEnabled() && fc.FrameVersion() != FrameVersion2003 {
+		f.SecurityHeaderOffset = r.pos
+		if f.SecurityHeader, err = dissectAuxSecurityHeader(r, fc); err != nil {
+			return
+		}
+		f.SecurityHeaderLength = r.pos - f.SecurityHeaderOffset
+	}
+
+	if fc.IEPresent() {
+		if f.HeaderIEs, err = dissectHeaderIEs(r); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func dissectAuxSecurityHeader(r *frameReader, fc FrameControl) (sh *AuxSecurityHeader, err error) {
+	sh = &AuxSecurityHeader{}
+
+	if sh.SecurityControl, err = r.readUint8(); err != nil {
+		return
+	}
+
+	// Frame Counter Suppression is only defined in IEEE 802.15.4-2015
+	sh.FrameCounterPresent = fc.FrameVersion() != FrameVersion2015 || sh.SecurityControl&0x20 == 0
+	if sh.FrameCounterPresent {
+		if sh.FrameCounter, err = r.readUint32(); err != nil {
+			return
+		}
+	}
+
+	switch sh.KeyIdMode() {
+	case KeyIdMode1:
+		sh.KeyIndex, err = r.readUint8()
+	case KeyIdMode2:
+		if sh.KeySource, err = r.readBytes(4); err == nil {
+			sh.KeyIndex, err = r.readUint8()
+		}
+	case KeyIdMode3:
+		if sh.KeySource, err = r.readBytes(8); err == nil {
+			sh.KeyIndex, err = r.readUint8()
+		}
+	}
+
+	return
+}
+
+func (f *MacFrame) hasPayloadIEs() bool {
+	n := len(f.HeaderIEs)
+	return n > 0 && f.HeaderIEs[n-1].Id == HeaderIeTermination1
 }
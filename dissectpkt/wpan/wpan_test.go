@@ -0,0 +1,125 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package wpan
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustDecodeHex(s string) []byte {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func TestDissectAck(t *testing.T) {
+	frame, err := Dissect(mustDecodeHex("0b" + "0200" + "2a" + "1234"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(11), frame.Channel)
+	assert.Equal(t, FrameTypeAck, frame.FrameControl.FrameType())
+	assert.Equal(t, uint8(0x2a), frame.Seq)
+	assert.Equal(t, uint16(0x3412), frame.Fcs)
+	assert.Len(t, frame.Payload, 0)
+}
+
+func TestDissectUnsecuredBroadcast(t *testing.T) {
+	// 2006 data frame, PAN ID compression, dst 0xffff, src extended
+	frame, err := Dissect(mustDecodeHex("0b" + "41d8" + "01" + "cefa" + "ffff" + "0807060504030201" + "7f3312" + "0000"))
+	assert.Nil(t, err)
+	fc := frame.FrameControl
+	assert.Equal(t, FrameTypeData, fc.FrameType())
+	assert.Equal(t, uint16(FrameVersion2006), fc.FrameVersion())
+	assert.Equal(t, uint16(0xface), frame.DstPanId)
+	assert.Equal(t, uint16(0xface), frame.SrcPanId)
+	assert.Equal(t, uint16(0xffff), frame.DstAddrShort)
+	assert.Equal(t, uint64(0x0102030405060708), frame.SrcAddrExtended)
+	assert.Equal(t, "0102030405060708", frame.FormatSrcAddr())
+	assert.Nil(t, frame.SecurityHeader)
+	assert.Equal(t, 15, frame.HeaderLength)
+	assert.Equal(t, []byte{0x7f, 0x33, 0x12}, frame.Payload)
+}
+
+func TestDissectSecuredUnicast(t *testing.T) {
+	// 2006 secured data frame, dst short 0x0400, src short 0x0401, key id mode 1, level 5
+	frame, err := Dissect(mustDecodeHex("0b" + "6998" + "05" + "cefa" + "0004" + "0104" + "0d" + "78563412" + "01" + "aabbcc" + "11223344" + "0000"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(0x0400), frame.DstAddrShort)
+	assert.Equal(t, uint16(0x0401), frame.SrcAddrShort)
+	sh := frame.SecurityHeader
+	assert.NotNil(t, sh)
+	assert.Equal(t, uint8(SecurityLevelEncMic32), sh.SecurityLevel())
+	assert.Equal(t, uint8(KeyIdMode1), sh.KeyIdMode())
+	assert.Equal(t, uint32(0x12345678), sh.FrameCounter)
+	assert.Equal(t, uint8(1), sh.KeyIndex)
+	assert.True(t, sh.Encrypted())
+	assert.Equal(t, 4, sh.MicSize())
+	assert.Equal(t, []byte{0xaa, 0xbb, 0xcc}, frame.Payload)
+	assert.Equal(t, []byte{0x11, 0x22, 0x33, 0x44}, frame.Mic)
+	assert.Equal(t, frame.HeaderLength, len(frame.Header))
+}
+
+func TestDissect2015HeaderIE(t *testing.T) {
+	// 2015 data frame with CSL IE followed by HT2, dst short, src extended
+	frame, err := Dissect(mustDecodeHex("0b" + "41ea" + "07" + "cefa" + "0004" + "0807060504030201" + "040d" + "01020304" + "803f" + "99" + "0000"))
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(FrameVersion2015), frame.FrameControl.FrameVersion())
+	assert.True(t, frame.FrameControl.IEPresent())
+	assert.Len(t, frame.HeaderIEs, 2)
+	csl := frame.FindHeaderIE(HeaderIeCslIe)
+	assert.NotNil(t, csl)
+	assert.Equal(t, []byte{1, 2, 3, 4}, csl.Content)
+	assert.Equal(t, HeaderIeTermination2, frame.HeaderIEs[1].Id)
+	assert.Nil(t, frame.PayloadIEs)
+	assert.Equal(t, []byte{0x99}, frame.Payload)
+}
+
+func TestDissect2015PanIdPresence(t *testing.T) {
+	// dst extended, src extended, PAN ID compression not set: only Dst PAN ID present
+	fc := FrameControl(0x0001 | 0x0c00 | 0x2000 | 0xc000)
+	assert.True(t, fc.DstPanIdPresent())
+	assert.False(t, fc.SrcPanIdPresent())
+
+	// dst none, src short: only Src PAN ID present
+	fc = FrameControl(0x0001 | 0x2000 | 0x8000)
+	assert.False(t, fc.DstPanIdPresent())
+	assert.True(t, fc.SrcPanIdPresent())
+}
+
+func TestDissectTruncated(t *testing.T) {
+	full := mustDecodeHex("0b" + "6998" + "05" + "cefa" + "0004" + "0104" + "0d" + "78563412" + "01" + "aabbcc" + "11223344" + "0000")
+	for n := 0; n < 19; n++ {
+		_, err := Dissect(full[:n])
+		assert.NotNil(t, err, "length %d", n)
+		assert.True(t, errors.Is(err, ErrFrameTruncated), "length %d: %v", n, err)
+	}
+}
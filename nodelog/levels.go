@@ -0,0 +1,113 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package nodelog
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/openthread/ot-ns/otoutfilter"
+	. "github.com/openthread/ot-ns/types"
+)
+
+// LevelSetting is the minimum log level of a region of a node.
+// InvalidNodeId stands for all nodes, and an empty region stands for all regions.
+type LevelSetting struct {
+	NodeId NodeId
+	Region string
+	Level  otoutfilter.LogLevel
+}
+
+type levelKey struct {
+	nodeid NodeId
+	region string
+}
+
+// Levels keeps the minimum log levels of nodes and regions. It is safe for concurrent use.
+// The most specific setting applies to a log line: the node and the region, then the node, then the region,
+// and then the default level.
+type Levels struct {
+	sync.RWMutex
+	levels map[levelKey]otoutfilter.LogLevel
+}
+
+func NewLevels() *Levels {
+	return &Levels{
+		levels: map[levelKey]otoutfilter.LogLevel{},
+	}
+}
+
+// Set sets the minimum log level of the region of the node.
+func (l *Levels) Set(nodeid NodeId, region string, level otoutfilter.LogLevel) {
+	l.Lock()
+	l.levels[levelKey{nodeid, region}] = level
+	l.Unlock()
+}
+
+// Reset removes the minimum log level of the region of the node.
+func (l *Levels) Reset(nodeid NodeId, region string) {
+	l.Lock()
+	delete(l.levels, levelKey{nodeid, region})
+	l.Unlock()
+}
+
+// Enabled returns if a log line of the level in the region of the node passes the minimum log level.
+// All levels pass if no setting applies.
+func (l *Levels) Enabled(nodeid NodeId, region string, level otoutfilter.LogLevel) bool {
+	l.RLock()
+	defer l.RUnlock()
+
+	if len(l.levels) == 0 {
+		return true
+	}
+
+	for _, key := range [...]levelKey{{nodeid, region}, {nodeid, ""}, {InvalidNodeId, region}, {InvalidNodeId, ""}} {
+		if minLevel, ok := l.levels[key]; ok {
+			return level <= minLevel
+		}
+	}
+	return true
+}
+
+// List returns all level settings ordered by node and region.
+func (l *Levels) List() []LevelSetting {
+	l.RLock()
+	defer l.RUnlock()
+
+	settings := make([]LevelSetting, 0, len(l.levels))
+	for key, level := range l.levels {
+		settings = append(settings, LevelSetting{NodeId: key.nodeid, Region: key.region, Level: level})
+	}
+
+	sort.Slice(settings, func(i, j int) bool {
+		if settings[i].NodeId != settings[j].NodeId {
+			return settings[i].NodeId < settings[j].NodeId
+		}
+		return settings[i].Region < settings[j].Region
+	})
+	return settings
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package nodelog
+
+import (
+	"testing"
+
+	"github.com/openthread/ot-ns/otoutfilter"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevels(t *testing.T) {
+	l := NewLevels()
+	assert.True(t, l.Enabled(1, "MLE", otoutfilter.LogLevelDebg))
+
+	l.Set(InvalidNodeId, "", otoutfilter.LogLevelWarn)
+	l.Set(InvalidNodeId, "MLE", otoutfilter.LogLevelInfo)
+	l.Set(1, "", otoutfilter.LogLevelCrit)
+	l.Set(1, "MAC", otoutfilter.LogLevelDebg)
+
+	assert.False(t, l.Enabled(2, "MAC", otoutfilter.LogLevelInfo))
+	assert.True(t, l.Enabled(2, "MAC", otoutfilter.LogLevelWarn))
+	assert.True(t, l.Enabled(2, "MLE", otoutfilter.LogLevelInfo))
+	assert.False(t, l.Enabled(2, "MLE", otoutfilter.LogLevelDebg))
+	assert.False(t, l.Enabled(1, "MLE", otoutfilter.LogLevelWarn))
+	assert.True(t, l.Enabled(1, "MLE", otoutfilter.LogLevelCrit))
+	assert.True(t, l.Enabled(1, "MAC", otoutfilter.LogLevelDebg))
+
+	assert.Equal(t, []LevelSetting{
+		{InvalidNodeId, "", otoutfilter.LogLevelWarn},
+		{InvalidNodeId, "MLE", otoutfilter.LogLevelInfo},
+		{1, "", otoutfilter.LogLevelCrit},
+		{1, "MAC", otoutfilter.LogLevelDebg},
+	}, l.List())
+
+	l.Reset(1, "")
+	assert.True(t, l.Enabled(1, "MLE", otoutfilter.LogLevelInfo))
+	assert.Equal(t, 3, len(l.List()))
+}
@@ -0,0 +1,289 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package nodelog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	// DefaultSize is the default number of log lines kept for each node.
+	DefaultSize = 1000
+	// subscriptionBufferSize is the number of log lines buffered for a subscription before lines are dropped.
+	subscriptionBufferSize = 1000
+)
+
+// Entry is a log line of a node.
+type Entry struct {
+	NodeId NodeId
+	// Time is the virtual time (in us) when the line was captured.
+	Time uint64
+	Line string
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("%d.%06d %s", e.Time/1000000, e.Time%1000000, e.Line)
+}
+
+// nodeLog keeps the last log lines of a node in a ring buffer.
+type nodeLog struct {
+	entries []Entry
+	next    int
+	full    bool
+	file    *os.File
+}
+
+func (l *nodeLog) add(entry Entry) {
+	if l.full {
+		l.entries[l.next] = entry
+	} else {
+		l.entries = append(l.entries, entry)
+	}
+
+	l.next += 1
+	if l.next == cap(l.entries) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+// visit visits the log lines from the oldest to the latest.
+func (l *nodeLog) visit(cb func(entry *Entry)) {
+	if l.full {
+		for i := l.next; i < len(l.entries); i++ {
+			cb(&l.entries[i])
+		}
+	}
+	for i := 0; i < l.next; i++ {
+		cb(&l.entries[i])
+	}
+}
+
+// Capture captures the log lines of all nodes, optionally mirroring them to a file for each node.
+// It is safe to use from multiple goroutines.
+type Capture struct {
+	sync.Mutex
+	size          int
+	dir           string
+	logs          map[NodeId]*nodeLog
+	subscriptions map[*Subscription]struct{}
+}
+
+// NewCapture creates a Capture keeping the last `size` lines of each node.
+// If `dir` is not empty, the log lines of each node are also written to `node_<id>.log` in `dir`.
+func NewCapture(size int, dir string) *Capture {
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	return &Capture{
+		size:          size,
+		dir:           dir,
+		logs:          map[NodeId]*nodeLog{},
+		subscriptions: map[*Subscription]struct{}{},
+	}
+}
+
+// Add captures a log line of the node at virtual time `ts`.
+func (c *Capture) Add(nodeid NodeId, ts uint64, line string) {
+	entry := Entry{NodeId: nodeid, Time: ts, Line: line}
+
+	c.Lock()
+	defer c.Unlock()
+
+	l := c.logs[nodeid]
+	if l == nil {
+		l = &nodeLog{entries: make([]Entry, 0, c.size)}
+		l.file = c.openFile(nodeid)
+		c.logs[nodeid] = l
+	}
+
+	l.add(entry)
+	if l.file != nil {
+		_, _ = fmt.Fprintln(l.file, entry.String())
+	}
+
+	for sub := range c.subscriptions {
+		sub.offer(&entry)
+	}
+}
+
+func (c *Capture) openFile(nodeid NodeId) *os.File {
+	if c.dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		simplelogger.Errorf("create log directory %s failed: %v", c.dir, err)
+		return nil
+	}
+
+	filename := filepath.Join(c.dir, fmt.Sprintf("node_%d.log", nodeid))
+	file, err := os.Create(filename)
+	if err != nil {
+		simplelogger.Errorf("create log file %s failed: %v", filename, err)
+		return nil
+	}
+	return file
+}
+
+// Query returns the captured log lines of the node that match `pattern` (if not nil), from the oldest to the latest.
+// If `nodeid` is InvalidNodeId, the log lines of all nodes are returned ordered by time.
+// If `last` is positive, only the last `last` matching lines are returned.
+func (c *Capture) Query(nodeid NodeId, pattern *regexp.Regexp, last int) []Entry {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.query(nodeid, pattern, last)
+}
+
+func (c *Capture) query(nodeid NodeId, pattern *regexp.Regexp, last int) []Entry {
+	var entries []Entry
+	collect := func(entry *Entry) {
+		if pattern == nil || pattern.MatchString(entry.Line) {
+			entries = append(entries, *entry)
+		}
+	}
+
+	if nodeid != InvalidNodeId {
+		if l := c.logs[nodeid]; l != nil {
+			l.visit(collect)
+		}
+	} else {
+		for _, l := range c.logs {
+			l.visit(collect)
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Time != entries[j].Time {
+				return entries[i].Time < entries[j].Time
+			}
+			return entries[i].NodeId < entries[j].NodeId
+		})
+	}
+
+	if last > 0 && len(entries) > last {
+		entries = entries[len(entries)-last:]
+	}
+	return entries
+}
+
+// Subscribe subscribes to the log lines of the node that match `pattern` (if not nil), or of all nodes if `nodeid` is InvalidNodeId.
+// Log lines are dropped if the subscriber does not keep up.
+func (c *Capture) Subscribe(nodeid NodeId, pattern *regexp.Regexp) *Subscription {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.subscribe(nodeid, pattern)
+}
+
+// Follow returns the result of Query and a subscription to the log lines captured after it, so that no log line
+// is missed or received twice.
+func (c *Capture) Follow(nodeid NodeId, pattern *regexp.Regexp, last int) ([]Entry, *Subscription) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.query(nodeid, pattern, last), c.subscribe(nodeid, pattern)
+}
+
+func (c *Capture) subscribe(nodeid NodeId, pattern *regexp.Regexp) *Subscription {
+	ch := make(chan Entry, subscriptionBufferSize)
+	sub := &Subscription{
+		C:       ch,
+		c:       ch,
+		capture: c,
+		nodeid:  nodeid,
+		pattern: pattern,
+	}
+
+	c.subscriptions[sub] = struct{}{}
+	return sub
+}
+
+// Close closes all log files and subscriptions.
+func (c *Capture) Close() {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, l := range c.logs {
+		if l.file != nil {
+			_ = l.file.Close()
+			l.file = nil
+		}
+	}
+
+	for sub := range c.subscriptions {
+		delete(c.subscriptions, sub)
+		close(sub.c)
+	}
+}
+
+// Subscription receives the log lines captured after subscribing from C.
+type Subscription struct {
+	C       <-chan Entry
+	c       chan Entry
+	capture *Capture
+	nodeid  NodeId
+	pattern *regexp.Regexp
+	dropped uint64
+}
+
+func (sub *Subscription) offer(entry *Entry) {
+	if sub.nodeid != InvalidNodeId && sub.nodeid != entry.NodeId {
+		return
+	}
+
+	if sub.pattern != nil && !sub.pattern.MatchString(entry.Line) {
+		return
+	}
+
+	select {
+	case sub.c <- *entry:
+	default:
+		sub.dropped += 1
+	}
+}
+
+// Close unsubscribes and closes C. It returns the number of log lines dropped.
+func (sub *Subscription) Close() uint64 {
+	c := sub.capture
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.subscriptions[sub]; ok {
+		delete(c.subscriptions, sub)
+		close(sub.c)
+	}
+	return sub.dropped
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package nodelog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func lines(entries []Entry) []string {
+	var lines []string
+	for _, entry := range entries {
+		lines = append(lines, entry.Line)
+	}
+	return lines
+}
+
+func TestCapture_Query(t *testing.T) {
+	c := NewCapture(3, "")
+	c.Add(1, 100, "[INFO]-MLE-----: a")
+	c.Add(2, 150, "[WARN]-MAC-----: b")
+	c.Add(1, 200, "[INFO]-MAC-----: c")
+	c.Add(1, 300, "[INFO]-MLE-----: d")
+
+	assert.Equal(t, []string{"[INFO]-MLE-----: a", "[INFO]-MAC-----: c", "[INFO]-MLE-----: d"}, lines(c.Query(1, nil, 0)))
+	assert.Equal(t, []string{"[INFO]-MLE-----: d"}, lines(c.Query(1, nil, 1)))
+	assert.Equal(t, []string{"[INFO]-MLE-----: a", "[INFO]-MLE-----: d"}, lines(c.Query(1, regexp.MustCompile("MLE"), 0)))
+	assert.Empty(t, c.Query(3, nil, 0))
+
+	// the oldest line is dropped when the buffer is full
+	c.Add(1, 400, "[INFO]-MLE-----: e")
+	assert.Equal(t, []string{"[INFO]-MAC-----: c", "[INFO]-MLE-----: d", "[INFO]-MLE-----: e"}, lines(c.Query(1, nil, 0)))
+
+	all := c.Query(InvalidNodeId, nil, 0)
+	assert.Equal(t, []string{"[WARN]-MAC-----: b", "[INFO]-MAC-----: c", "[INFO]-MLE-----: d", "[INFO]-MLE-----: e"}, lines(all))
+	assert.Equal(t, NodeId(2), all[0].NodeId)
+	assert.Equal(t, "0.000150 [WARN]-MAC-----: b", all[0].String())
+}
+
+func TestCapture_Subscribe(t *testing.T) {
+	c := NewCapture(10, "")
+	sub := c.Subscribe(1, regexp.MustCompile("MLE"))
+	all := c.Subscribe(InvalidNodeId, nil)
+
+	c.Add(1, 100, "[INFO]-MLE-----: a")
+	c.Add(1, 200, "[INFO]-MAC-----: b")
+	c.Add(2, 300, "[INFO]-MLE-----: c")
+
+	assert.Equal(t, Entry{NodeId: 1, Time: 100, Line: "[INFO]-MLE-----: a"}, <-sub.C)
+	assert.Equal(t, 0, len(sub.C))
+	assert.Equal(t, 3, len(all.C))
+
+	assert.Equal(t, uint64(0), sub.Close())
+	_, ok := <-sub.C
+	assert.False(t, ok)
+
+	for i := 0; i < subscriptionBufferSize; i++ {
+		c.Add(1, 400, "[INFO]-MLE-----: d")
+	}
+	assert.Equal(t, uint64(3), all.Close())
+}
+
+func TestCapture_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodelog")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	logDir := filepath.Join(dir, "logs")
+	c := NewCapture(10, logDir)
+	c.Add(1, 1500000, "[INFO]-MLE-----: a")
+	c.Add(2, 2000000, "[INFO]-MLE-----: b")
+	c.Close()
+
+	data, err := ioutil.ReadFile(filepath.Join(logDir, "node_1.log"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1.500000 [INFO]-MLE-----: a\n", string(data))
+
+	data, err = ioutil.ReadFile(filepath.Join(logDir, "node_2.log"))
+	assert.Nil(t, err)
+	assert.Equal(t, "2.000000 [INFO]-MLE-----: b\n", string(data))
+}
+
+func TestCapture_Follow(t *testing.T) {
+	c := NewCapture(10, "")
+	c.Add(1, 100, "[INFO]-MLE-----: a")
+	c.Add(1, 100, "[INFO]-MLE-----: b")
+
+	entries, sub := c.Follow(1, nil, 1)
+	c.Add(1, 100, "[INFO]-MLE-----: c")
+
+	assert.Equal(t, []string{"[INFO]-MLE-----: b"}, lines(entries))
+	assert.Equal(t, "[INFO]-MLE-----: c", (<-sub.C).Line)
+	assert.Equal(t, 0, len(sub.C))
+	sub.Close()
+}
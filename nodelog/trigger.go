@@ -0,0 +1,161 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package nodelog
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/openthread/ot-ns/otoutfilter"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+)
+
+// TriggerAction is the action taken when a log line matches a trigger.
+type TriggerAction int
+
+const (
+	// TriggerCount only counts the matching log lines.
+	TriggerCount TriggerAction = iota
+	// TriggerPause counts the matching log lines and pauses the simulation.
+	TriggerPause
+)
+
+func (action TriggerAction) String() string {
+	switch action {
+	case TriggerCount:
+		return "count"
+	case TriggerPause:
+		return "pause"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTriggerAction parses a trigger action name.
+func ParseTriggerAction(s string) (TriggerAction, error) {
+	switch s {
+	case "count":
+		return TriggerCount, nil
+	case "pause":
+		return TriggerPause, nil
+	default:
+		return TriggerCount, errors.Errorf("invalid trigger action: %s", s)
+	}
+}
+
+// Trigger matches log lines of a node (or all nodes if NodeId is InvalidNodeId) in a region (or all regions if Region
+// is empty) at Level or more severe levels, that match Pattern (if not nil).
+type Trigger struct {
+	Id      int
+	NodeId  NodeId
+	Region  string
+	Level   otoutfilter.LogLevel
+	Pattern *regexp.Regexp
+	Action  TriggerAction
+	// Count is the number of matched log lines.
+	Count uint64
+}
+
+func (t *Trigger) match(nodeid NodeId, log *otoutfilter.Log) bool {
+	if t.NodeId != InvalidNodeId && t.NodeId != nodeid {
+		return false
+	}
+	if t.Region != "" && t.Region != log.Region {
+		return false
+	}
+	if log.Level > t.Level {
+		return false
+	}
+	return t.Pattern == nil || t.Pattern.MatchString(log.Message)
+}
+
+// Triggers keeps the log triggers. It is safe for concurrent use.
+type Triggers struct {
+	sync.Mutex
+	triggers []*Trigger
+	nextId   int
+}
+
+func NewTriggers() *Triggers {
+	return &Triggers{
+		nextId: 1,
+	}
+}
+
+// Add adds the trigger and returns its ID.
+func (ts *Triggers) Add(trigger Trigger) int {
+	ts.Lock()
+	defer ts.Unlock()
+
+	trigger.Id = ts.nextId
+	trigger.Count = 0
+	ts.nextId++
+	ts.triggers = append(ts.triggers, &trigger)
+	return trigger.Id
+}
+
+// Remove removes the trigger of the ID.
+func (ts *Triggers) Remove(id int) error {
+	ts.Lock()
+	defer ts.Unlock()
+
+	for i, trigger := range ts.triggers {
+		if trigger.Id == id {
+			ts.triggers = append(ts.triggers[:i], ts.triggers[i+1:]...)
+			return nil
+		}
+	}
+	return errors.Errorf("trigger %d not found", id)
+}
+
+// List returns all triggers in the order they were added.
+func (ts *Triggers) List() []Trigger {
+	ts.Lock()
+	defer ts.Unlock()
+
+	triggers := make([]Trigger, len(ts.triggers))
+	for i, trigger := range ts.triggers {
+		triggers[i] = *trigger
+	}
+	return triggers
+}
+
+// Match counts the log line of the node for all matching triggers and returns them.
+func (ts *Triggers) Match(nodeid NodeId, log *otoutfilter.Log) []Trigger {
+	ts.Lock()
+	defer ts.Unlock()
+
+	var matched []Trigger
+	for _, trigger := range ts.triggers {
+		if trigger.match(nodeid, log) {
+			trigger.Count++
+			matched = append(matched, *trigger)
+		}
+	}
+	return matched
+}
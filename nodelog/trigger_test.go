@@ -0,0 +1,62 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package nodelog
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/openthread/ot-ns/otoutfilter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriggers(t *testing.T) {
+	ts := NewTriggers()
+	failed := ts.Add(Trigger{Level: otoutfilter.LogLevelDebg, Pattern: regexp.MustCompile("Failed to send"), Action: TriggerCount})
+	crit := ts.Add(Trigger{NodeId: 2, Region: "MAC", Level: otoutfilter.LogLevelCrit, Action: TriggerPause})
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, 2, crit)
+
+	assert.Empty(t, ts.Match(1, otoutfilter.ParseLog("[INFO]-MLE-----: Role detached -> leader")))
+	matched := ts.Match(1, otoutfilter.ParseLog("[INFO]-MESH-CP-: Failed to send"))
+	assert.Equal(t, 1, len(matched))
+	assert.Equal(t, failed, matched[0].Id)
+	assert.Empty(t, ts.Match(1, otoutfilter.ParseLog("[CRIT]-MAC-----: Failed")))
+	assert.Empty(t, ts.Match(2, otoutfilter.ParseLog("[WARN]-MAC-----: Failed")))
+	matched = ts.Match(2, otoutfilter.ParseLog("[CRIT]-MAC-----: Failed to send"))
+	assert.Equal(t, 2, len(matched))
+	assert.Equal(t, TriggerPause, matched[1].Action)
+
+	triggers := ts.List()
+	assert.Equal(t, uint64(2), triggers[0].Count)
+	assert.Equal(t, uint64(1), triggers[1].Count)
+
+	assert.Nil(t, ts.Remove(failed))
+	assert.NotNil(t, ts.Remove(failed))
+	assert.Equal(t, 1, len(ts.List()))
+	assert.Equal(t, 3, ts.Add(Trigger{Level: otoutfilter.LogLevelCrit}))
+}
@@ -32,6 +32,7 @@ import (
 	"math/rand"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"syscall"
@@ -49,10 +50,12 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/openthread/ot-ns/nodelog"
 	"github.com/openthread/ot-ns/progctx"
 	"github.com/openthread/ot-ns/visualize"
 
 	visualizeGrpc "github.com/openthread/ot-ns/visualize/grpc"
+	"github.com/openthread/ot-ns/visualize/grpc/replay"
 
 	visualizeMulti "github.com/openthread/ot-ns/visualize/multi"
 
@@ -76,7 +79,14 @@ type MainArgs struct {
 	DispatcherPort int
 	DumpPackets    bool
 	NoPcap         bool
+	DecryptPcap    bool
 	NoReplay       bool
+	ReplayFormat   string
+	ReplayCompress string
+	NodeLogSize    int
+	NodeLogDir     string
+	Script         string
+	CliListen      string
 }
 
 var (
@@ -100,7 +110,14 @@ func parseArgs() {
 	flag.StringVar(&args.ListenAddr, "listen", fmt.Sprintf("localhost:%d", threadconst.InitialDispatcherPort), "specify listen address")
 	flag.BoolVar(&args.DumpPackets, "dump-packets", false, "dump packets")
 	flag.BoolVar(&args.NoPcap, "no-pcap", false, "do not generate Pcap")
+	flag.BoolVar(&args.DecryptPcap, "decrypt-pcap", false, "generate an additional Pcap with decrypted frames")
 	flag.BoolVar(&args.NoReplay, "no-replay", false, "do not generate Replay")
+	flag.StringVar(&args.ReplayFormat, "replay-format", "text", "set Replay format (text|binary)")
+	flag.StringVar(&args.ReplayCompress, "replay-compress", "none", "set Replay compression (none|gzip|zstd)")
+	flag.IntVar(&args.NodeLogSize, "node-log-size", nodelog.DefaultSize, "set the number of log lines kept for each node")
+	flag.StringVar(&args.NodeLogDir, "node-log-dir", "", "mirror node logs to node_<id>.log files in the directory")
+	flag.StringVar(&args.Script, "script", "", "run the OTNS-CLI script file before reading commands, and exit with code 1 if it fails")
+	flag.StringVar(&args.CliListen, "cli-listen", "", "serve remote CLI sessions on the address (<host>:<port> or unix:<path>)")
 
 	flag.Parse()
 }
@@ -133,14 +150,16 @@ func parseListenAddr() {
 	}
 }
 
-func Main(ctx *progctx.ProgCtx, visualizerCreator func(ctx *progctx.ProgCtx, args *MainArgs) visualize.Visualizer, cliOptions *runcli.CliOptions) {
+// Main runs OTNS until the program context is done, and returns the exit code.
+func Main(ctx *progctx.ProgCtx, visualizerCreator func(ctx *progctx.ProgCtx, args *MainArgs) visualize.Visualizer, cliOptions *runcli.CliOptions) int {
 	parseArgs()
 
 	simplelogger.SetLevel(simplelogger.ParseLevel(args.LogLevel))
 
 	parseListenAddr()
 
-	rand.Seed(time.Now().UnixNano())
+	seed := time.Now().UnixNano()
+	rand.Seed(seed)
 	// run console in the main goroutine
 	ctx.Defer(func() {
 		_ = os.Stdin.Close()
@@ -159,24 +178,34 @@ func Main(ctx *progctx.ProgCtx, visualizerCreator func(ctx *progctx.ProgCtx, arg
 	if !args.NoReplay {
 		replayFn = fmt.Sprintf("otns_%s.replay", os.Getenv("PORT_OFFSET"))
 	}
+	replayOptions := parseReplayOptions(seed)
 	if vis != nil {
 		vis = visualizeMulti.NewMultiVisualizer(
 			vis,
-			visualizeGrpc.NewGrpcVisualizer(visGrpcServerAddr, replayFn),
+			visualizeGrpc.NewGrpcVisualizer(visGrpcServerAddr, replayFn, replayOptions),
 		)
 	} else {
-		vis = visualizeGrpc.NewGrpcVisualizer(visGrpcServerAddr, replayFn)
+		vis = visualizeGrpc.NewGrpcVisualizer(visGrpcServerAddr, replayFn, replayOptions)
 	}
 
 	sim := createSimulation(ctx)
 	rt := cli.NewCmdRunner(ctx, sim)
 	sim.SetVisualizer(vis)
 	go sim.Run()
+	exitCode := 0
 	go func() {
-		err := cli.Run(rt, cliOptions)
+		err := cli.Run(rt, cliOptions, args.Script)
+		switch errors.Cause(err).(type) {
+		case *cli.ExpectError, *cli.ScriptError:
+			exitCode = 1
+		}
 		ctx.Cancel(errors.Wrapf(err, "console exit"))
 	}()
 
+	if args.CliListen != "" {
+		serveRemoteCli(ctx, rt)
+	}
+
 	go func() {
 		siteAddr := fmt.Sprintf("%s:%d", args.DispatcherHost, args.DispatcherPort-3)
 		err := webSite.Serve(siteAddr)
@@ -200,6 +229,25 @@ func Main(ctx *progctx.ProgCtx, visualizerCreator func(ctx *progctx.ProgCtx, arg
 
 	simplelogger.Infof("waiting for OTNS to stop gracefully ...")
 	ctx.Wait()
+	return exitCode
+}
+
+func serveRemoteCli(ctx *progctx.ProgCtx, rt *cli.CmdRunner) {
+	server, err := cli.NewServer(rt, args.CliListen)
+	if err != nil {
+		simplelogger.Errorf("%+v, remote CLI won't be available!", err)
+		return
+	}
+
+	ctx.Defer(func() {
+		_ = server.Close()
+	})
+
+	go func() {
+		if err := server.Serve(); err != nil {
+			simplelogger.Errorf("remote CLI server quited: %+v", err)
+		}
+	}()
 }
 
 func handleSignals(ctx *progctx.ProgCtx) {
@@ -230,6 +278,30 @@ func autoGo(prog *progctx.ProgCtx, sim *simulation.Simulation) {
 	}
 }
 
+func parseReplayOptions(seed int64) replay.Options {
+	var err error
+
+	options := replay.DefaultOptions()
+	options.Seed = seed
+	options.OtnsVersion = otnsVersion()
+
+	options.Format, err = replay.ParseFormat(args.ReplayFormat)
+	simplelogger.FatalIfError(err)
+	options.Compression, err = replay.ParseCompression(args.ReplayCompress)
+	simplelogger.FatalIfError(err)
+
+	return options
+}
+
+// otnsVersion returns the OTNS version recorded in the build info.
+func otnsVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Version
+	}
+
+	return "unknown"
+}
+
 func createSimulation(ctx *progctx.ProgCtx) *simulation.Simulation {
 	var speed float64
 	var err error
@@ -251,9 +323,12 @@ func createSimulation(ctx *progctx.ProgCtx) *simulation.Simulation {
 	simcfg.DispatcherHost = args.DispatcherHost
 	simcfg.DispatcherPort = args.DispatcherPort
 	simcfg.DumpPackets = args.DumpPackets
+	simcfg.NodeLogSize = args.NodeLogSize
+	simcfg.NodeLogDir = args.NodeLogDir
 
 	dispatcherCfg := dispatcher.DefaultConfig()
 	dispatcherCfg.NoPcap = args.NoPcap
+	dispatcherCfg.DecryptPcap = args.DecryptPcap
 
 	sim, err := simulation.NewSimulation(ctx, simcfg, dispatcherCfg)
 	simplelogger.FatalIfError(err)
@@ -30,6 +30,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -47,6 +48,7 @@ import (
 	"github.com/openthread/ot-ns/cli/runcli"
 	"github.com/openthread/ot-ns/otns_main"
 	"github.com/openthread/ot-ns/progctx"
+	"github.com/openthread/ot-ns/simulation"
 	. "github.com/openthread/ot-ns/types"
 	"github.com/openthread/ot-ns/visualize"
 	"github.com/simonlingoogle/go-simplelogger"
@@ -260,6 +262,29 @@ func (ot *OtnsTest) DeleteNode(ids ...NodeId) {
 	ot.executeCommand(cmd)
 }
 
+func (ot *OtnsTest) RebootNode(ids ...NodeId) {
+	ot.executeNodesCommand("reboot", ids, "")
+}
+
+func (ot *OtnsTest) PowerCycleNode(offTime time.Duration, ids ...NodeId) {
+	ot.executeNodesCommand("powercycle", ids, fmt.Sprintf(" off %f", offTime.Seconds()))
+}
+
+func (ot *OtnsTest) FactoryResetNode(ids ...NodeId) {
+	ot.executeNodesCommand("factoryreset", ids, "")
+}
+
+func (ot *OtnsTest) executeNodesCommand(cmd string, ids []NodeId, suffix string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	for _, id := range ids {
+		cmd = cmd + fmt.Sprintf(" %d", id)
+	}
+	ot.executeCommand(cmd + suffix)
+}
+
 func (ot *OtnsTest) executeCommand(cmd string) []string {
 	ot.sendCommand(cmd)
 	return ot.expectCommandResultLines()
@@ -271,6 +296,63 @@ func (ot *OtnsTest) GetNodeState(id NodeId) string {
 	return lines[0]
 }
 
+func (ot *OtnsTest) GetChildTable(id NodeId) []simulation.ChildTableEntry {
+	table, err := simulation.ParseChildTable(ot.executeCommandNodeContext(id, "child table"))
+	ot.ExpectNoError(err)
+	return table
+}
+
+func (ot *OtnsTest) GetRouterTable(id NodeId) []simulation.RouterTableEntry {
+	table, err := simulation.ParseRouterTable(ot.executeCommandNodeContext(id, "router table"))
+	ot.ExpectNoError(err)
+	return table
+}
+
+func (ot *OtnsTest) GetNeighborTable(id NodeId) []simulation.NeighborTableEntry {
+	table, err := simulation.ParseNeighborTable(ot.executeCommandNodeContext(id, "neighbor table"))
+	ot.ExpectNoError(err)
+	return table
+}
+
+func (ot *OtnsTest) GetNetData(id NodeId) *NetData {
+	netdata, err := ParseNetData(ot.executeCommandNodeContext(id, "netdata show"))
+	ot.ExpectNoError(err)
+	return netdata
+}
+
+// GetIpAddrs returns the unicast addresses of the node. The mesh-local prefix is taken from the ML-EID.
+func (ot *OtnsTest) GetIpAddrs(id NodeId) []simulation.IpAddr {
+	mleids, err := simulation.ParseIpAddrs(ot.executeCommandNodeContext(id, "ipaddr mleid"), nil)
+	ot.ExpectNoError(err)
+
+	var meshLocalPrefix *net.IPNet
+	if len(mleids) > 0 {
+		meshLocalPrefix = simulation.MeshLocalPrefix(mleids[0].IP)
+	}
+
+	addrs, err := simulation.ParseIpAddrs(ot.executeCommandNodeContext(id, "ipaddr"), meshLocalPrefix)
+	ot.ExpectNoError(err)
+	return addrs
+}
+
+func (ot *OtnsTest) GetIpMaddrs(id NodeId) []simulation.IpAddr {
+	addrs, err := simulation.ParseIpAddrs(ot.executeCommandNodeContext(id, "ipmaddr"), nil)
+	ot.ExpectNoError(err)
+	return addrs
+}
+
+func (ot *OtnsTest) GetMacCounters(id NodeId) *simulation.MacCounters {
+	counters := &simulation.MacCounters{}
+	ot.ExpectNoError(simulation.ParseCounters(ot.executeCommandNodeContext(id, "counters mac"), counters))
+	return counters
+}
+
+func (ot *OtnsTest) GetMleCounters(id NodeId) *simulation.MleCounters {
+	counters := &simulation.MleCounters{}
+	ot.ExpectNoError(simulation.ParseCounters(ot.executeCommandNodeContext(id, "counters mle"), counters))
+	return counters
+}
+
 func (ot *OtnsTest) executeCommandNodeContext(id NodeId, cmd string) []string {
 	return ot.executeCommand(fmt.Sprintf("node %d \"%s\"", id, cmd))
 }
@@ -42,6 +42,7 @@ type otOutFilter struct {
 	linebuf        string
 	subr           io.Reader
 	logPrintPrefix string
+	logHandler     func(log *Log)
 }
 
 func (cc *otOutFilter) Read(p []byte) (int, error) {
@@ -95,8 +96,12 @@ func (cc *otOutFilter) readFirstLine(p []byte) int {
 			} else {
 				// remove the log
 				simplelogger.AssertTrue(logIdx[1] == len(firstline))
-				logStr := strings.TrimSpace(firstline)
-				cc.printLog(logStr)
+				log := ParseLog(strings.TrimSpace(firstline))
+				if cc.logHandler != nil {
+					cc.logHandler(log)
+				} else {
+					PrintLog(cc.logPrintPrefix, log)
+				}
 				sn += logIdx[1]
 			}
 		}
@@ -109,26 +114,28 @@ func (cc *otOutFilter) readFirstLine(p []byte) int {
 	}
 }
 
-func (cc *otOutFilter) printLog(logStr string) {
-	logPrefix := logStr[:6]
-	switch logPrefix {
-	case "[NONE]":
-		simplelogger.Errorf("%s - %s", cc.logPrintPrefix, logStr)
-	case "[CRIT]":
-		simplelogger.Errorf("%s - %s", cc.logPrintPrefix, logStr)
-	case "[WARN]":
-		simplelogger.Warnf("%s - %s", cc.logPrintPrefix, logStr)
-	case "[NOTE]":
-		simplelogger.Infof("%s - %s", cc.logPrintPrefix, logStr)
-	case "[INFO]":
-		simplelogger.Infof("%s - %s", cc.logPrintPrefix, logStr)
-	case "[DEBG]":
-		simplelogger.Debugf("%s - %s", cc.logPrintPrefix, logStr)
+// PrintLog prints the log line to the global logger at the matching level.
+func PrintLog(logPrintPrefix string, log *Log) {
+	switch log.Level {
+	case LogLevelNone, LogLevelCrit:
+		simplelogger.Errorf("%s - %s", logPrintPrefix, log.Raw)
+	case LogLevelWarn:
+		simplelogger.Warnf("%s - %s", logPrintPrefix, log.Raw)
+	case LogLevelNote, LogLevelInfo:
+		simplelogger.Infof("%s - %s", logPrintPrefix, log.Raw)
+	case LogLevelDebg:
+		simplelogger.Debugf("%s - %s", logPrintPrefix, log.Raw)
 	default:
-		simplelogger.Errorf("%s - %s", cc.logPrintPrefix, logStr)
+		simplelogger.Errorf("%s - %s", logPrintPrefix, log.Raw)
 	}
 }
 
 func NewOTOutFilter(reader io.Reader, logPrintPrefix string) io.Reader {
 	return &otOutFilter{subr: reader, logPrintPrefix: logPrintPrefix}
 }
+
+// NewOTOutFilterWithLogHandler creates an OT output filter that passes every removed log line to logHandler instead of
+// printing it.
+func NewOTOutFilterWithLogHandler(reader io.Reader, logHandler func(log *Log)) io.Reader {
+	return &otOutFilter{subr: reader, logHandler: logHandler}
+}
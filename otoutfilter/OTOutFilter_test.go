@@ -68,3 +68,81 @@ func TestOTOutFilter(t *testing.T) {
 		t.Fatalf("output %#v, expect: %#v", string(output), expectOutput)
 	}
 }
+
+func TestOTOutFilterWithLogHandler(t *testing.T) {
+	input := "> cmd\n" +
+		"A[WARN]log1\n" +
+		"[INFO]-MLE-----: log2\n" +
+		"Done\n"
+
+	var logs []string
+	r := NewOTOutFilterWithLogHandler(strings.NewReader(input), func(log *Log) {
+		logs = append(logs, log.Raw)
+	})
+	output, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(output) != "cmd\nADone\n" {
+		t.Fatalf("output %#v", string(output))
+	}
+
+	expectLogs := []string{"[WARN]log1", "[INFO]-MLE-----: log2"}
+	if strings.Join(logs, "|") != strings.Join(expectLogs, "|") {
+		t.Fatalf("logs %#v, expect: %#v", logs, expectLogs)
+	}
+}
+
+func TestParseLog(t *testing.T) {
+	for _, c := range []struct {
+		logStr  string
+		level   LogLevel
+		region  string
+		message string
+	}{
+		{"[INFO]-MLE-----: Role detached -> leader", LogLevelInfo, "MLE", "Role detached -> leader"},
+		{"[WARN]-MESH-CP-: Failed to send", LogLevelWarn, "MESH-CP", "Failed to send"},
+		{"[NOTE]-NET-DIAG: Received diagnostic get", LogLevelNote, "NET-DIAG", "Received diagnostic get"},
+		{"[CRIT]-MAC-----:", LogLevelCrit, "MAC", ""},
+		{"[DEBG]log6", LogLevelDebg, "", "log6"},
+		{"[NONE] no region", LogLevelNone, "", "no region"},
+	} {
+		log := ParseLog(c.logStr)
+		if log.Level != c.level || log.Region != c.region || log.Message != c.message || log.Raw != c.logStr {
+			t.Fatalf("parse %#v: %+v", c.logStr, log)
+		}
+	}
+}
+
+func TestFindLog(t *testing.T) {
+	log := FindLog("> [INFO]-MLE-----: Role detached -> leader")
+	if log == nil || log.Level != LogLevelInfo || log.Region != "MLE" || log.Message != "Role detached -> leader" {
+		t.Fatalf("find log: %+v", log)
+	}
+
+	if log = FindLog("Done"); log != nil {
+		t.Fatalf("find log in non-log line: %+v", log)
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	for s, expectLevel := range map[string]LogLevel{
+		"none":  LogLevelNone,
+		"CRIT":  LogLevelCrit,
+		"warn":  LogLevelWarn,
+		"Note":  LogLevelNote,
+		"info":  LogLevelInfo,
+		"debg":  LogLevelDebg,
+		"debug": LogLevelDebg,
+	} {
+		level, err := ParseLogLevel(s)
+		if err != nil || level != expectLevel {
+			t.Fatalf("parse %#v: %v, %v", s, level, err)
+		}
+	}
+
+	if _, err := ParseLogLevel("verbose"); err == nil {
+		t.Fatalf("parse invalid level should fail")
+	}
+}
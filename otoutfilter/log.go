@@ -0,0 +1,111 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package otoutfilter
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LogLevel is the level of an OpenThread log line. Lower levels are more severe.
+type LogLevel int
+
+const (
+	LogLevelNone LogLevel = iota
+	LogLevelCrit
+	LogLevelWarn
+	LogLevelNote
+	LogLevelInfo
+	LogLevelDebg
+)
+
+var logLevelNames = [...]string{"NONE", "CRIT", "WARN", "NOTE", "INFO", "DEBG"}
+
+func (level LogLevel) String() string {
+	if level < LogLevelNone || level > LogLevelDebg {
+		return "UNKNOWN"
+	}
+	return logLevelNames[level]
+}
+
+// ParseLogLevel parses a log level name (e.g. "crit" or "DEBG") case-insensitively. "debug" is accepted for DEBG.
+func ParseLogLevel(s string) (LogLevel, error) {
+	name := strings.ToUpper(s)
+	if name == "DEBUG" {
+		return LogLevelDebg, nil
+	}
+
+	for level, levelName := range logLevelNames {
+		if name == levelName {
+			return LogLevel(level), nil
+		}
+	}
+	return LogLevelNone, errors.Errorf("invalid log level: %s", s)
+}
+
+// Log is a parsed OpenThread log line, e.g. "[INFO]-MLE-----: Role detached -> leader".
+type Log struct {
+	Level LogLevel
+	// Region is the OpenThread log region (e.g. "MLE" or "MESH-CP"), or empty if the line has no region.
+	Region  string
+	Message string
+	// Raw is the complete log line.
+	Raw string
+}
+
+// FindLog returns the log at the end of the output line, or nil if the line has no log.
+func FindLog(line string) *Log {
+	logIdx := logPattern.FindStringIndex(line + "\n")
+	if logIdx == nil {
+		return nil
+	}
+	return ParseLog(strings.TrimSpace(line[logIdx[0]:]))
+}
+
+// ParseLog parses a log line which starts with the level, e.g. "[WARN]".
+// The region is formatted as "-" followed by the region name padded with "-" to 8 characters and ": ".
+func ParseLog(logStr string) *Log {
+	log := &Log{Level: LogLevelNone, Raw: logStr}
+	if len(logStr) < 6 || logStr[0] != '[' || logStr[5] != ']' {
+		log.Message = logStr
+		return log
+	}
+
+	if level, err := ParseLogLevel(logStr[1:5]); err == nil {
+		log.Level = level
+	}
+
+	rest := logStr[6:]
+	if len(rest) >= 10 && rest[0] == '-' && rest[9] == ':' {
+		log.Region = strings.TrimRight(rest[1:9], "-")
+		rest = rest[10:]
+	}
+
+	log.Message = strings.TrimSpace(rest)
+	return log
+}
@@ -28,6 +28,7 @@
 import ipaddress
 import logging
 import os
+import shlex
 import shutil
 import signal
 import subprocess
@@ -96,6 +97,25 @@ class OTNS(object):
 
         self._do_command(cmd)
 
+    def go_until(self, condition: str, timeout: float = None, speed: float = None) -> float:
+        """
+        Continue the simulation until a condition is met.
+
+        :param condition: the condition, e.g. 'partitions 1', 'role 1 leader', 'attached 2 3', 'ping 1 2'
+        :param timeout: the timeout (in simulating time), or 3600 seconds if not specified.
+        :param speed: simulating speed. Use current simulating speed if not specified.
+
+        :return: the simulating time (in seconds) when the condition is met
+        """
+        cmd = f'go until {condition}'
+        if timeout is not None:
+            cmd += f' timeout {timeout}'
+
+        if speed is not None:
+            cmd += f' speed {speed}'
+
+        return self._expect_float(self._do_command(cmd))
+
     @property
     def speed(self) -> float:
         """
@@ -167,7 +187,7 @@ class OTNS(object):
             output.append(line)
 
     def add(self, type: str, x: float = None, y: float = None, id=None, radio_range=None, executable=None,
-            restore=False) -> int:
+            restore=False, restart=False, max_restarts=None) -> int:
         """
         Add a new node to the simulation.
 
@@ -178,6 +198,8 @@ class OTNS(object):
         :param radio_range: node radio range or None for default
         :param executable: specify the executable for the new node, or use default executable if None
         :param restore: whether the node restores network configuration from persistent storage
+        :param restart: whether the node is restarted from persistent storage when its process crashes
+        :param max_restarts: maximum number of restarts, or None for unlimited restarts
 
         :return: added node ID
         """
@@ -199,6 +221,11 @@ class OTNS(object):
         if restore:
             cmd += f' restore'
 
+        if restart:
+            cmd += f' restart'
+            if max_restarts is not None:
+                cmd += f' {max_restarts}'
+
         return self._expect_int(self._do_command(cmd))
 
     def delete(self, *nodeids: int) -> None:
@@ -210,6 +237,31 @@ class OTNS(object):
         cmd = f'del {" ".join(map(str, nodeids))}'
         self._do_command(cmd)
 
+    def reboot(self, *nodeids: int) -> None:
+        """
+        Restart node processes, restoring network configuration from persistent storage.
+
+        :param nodeids: node IDs
+        """
+        self._do_command(f'reboot {" ".join(map(str, nodeids))}')
+
+    def powercycle(self, *nodeids: int, off_time: float) -> None:
+        """
+        Power off nodes for a duration of simulation time, then restart their processes from persistent storage.
+
+        :param nodeids: node IDs
+        :param off_time: power off duration in seconds
+        """
+        self._do_command(f'powercycle {" ".join(map(str, nodeids))} off {off_time}')
+
+    def factoryreset(self, *nodeids: int) -> None:
+        """
+        Restart node processes with persistent storage erased.
+
+        :param nodeids: node IDs
+        """
+        self._do_command(f'factoryreset {" ".join(map(str, nodeids))}')
+
     def move(self, nodeid: int, x: int, y: int) -> None:
         """
         Move node to the target position.
@@ -282,7 +334,7 @@ class OTNS(object):
                     v = int(v)
                 elif k in ('extaddr', 'rloc16'):
                     v = int(v, 16)
-                elif k in ('failed',):
+                elif k in ('failed', 'crashed'):
                     v = v == 'true'
                 elif k in ('ct_interval', 'ct_delay'):
                     v = float(v)
@@ -344,6 +396,8 @@ class OTNS(object):
         Get ping results.
 
         :return: list of ping results, each of format (node ID, destination address, data size, delay)
+
+        Pings that are not replied within 10 seconds are reported with a delay of 10000ms.
         """
         output = self._do_command('pings')
         pings = []
@@ -358,6 +412,19 @@ class OTNS(object):
 
         return pings
 
+    def ping_stats(self) -> List[Dict[str, Any]]:
+        """
+        Get aggregate statistics of the ping results per source node and destination address.
+        The ping results are not collected, so they are still returned by pings().
+
+        :return: list of ping statistics, each of which contains the source node ID ('node'), the destination address
+                 ('dst'), the number of sent and lost pings ('sent' and 'lost'), the loss percentage ('loss') and,
+                 if any ping is replied, the delays in milliseconds ('min', 'avg', 'max', 'p50', 'p95' and 'p99');
+                 a multicast ping is counted once, and the number of replies and delays of each replying address are
+                 listed in 'repliers' ('addr', 'replies', 'min', 'avg' and 'max')
+        """
+        return yaml.safe_load('\n'.join(self._do_command('pings stats')))
+
     def joins(self) -> List[Tuple[int, float, float]]:
         """
         Get join results.
@@ -376,6 +443,138 @@ class OTNS(object):
 
         return joins
 
+    def crashes(self) -> List[Tuple[int, float, bool, str, str]]:
+        """
+        Get node process crashes.
+
+        :return: list of crashes, each of format (node ID, crash time, restarted, exit status, stderr)
+        """
+        output = self._do_command('crashes')
+        crashes = []
+        for line in output:
+            fields = dict(kv.split('=', 1) for kv in shlex.split(line))
+            crashes.append((
+                int(fields['node']),
+                float(fields['time'][:-1]),
+                fields['restarted'] == 'true',
+                fields['status'],
+                fields['stderr'],
+            ))
+
+        return crashes
+
+    def logs(self, nodeid: int, grep: Optional[str] = None, last: Optional[int] = None) -> List[Tuple[float, str]]:
+        """
+        Get the OpenThread log lines captured from a node.
+
+        :param nodeid: the node ID
+        :param grep: only get the log lines matching this regular expression
+        :param last: only get the last log lines
+
+        :return: list of log lines, each of format (virtual time in seconds, log line)
+        """
+        cmd = f'logs {nodeid}'
+        if grep is not None:
+            cmd += f' grep {self._quote(grep)}'
+        if last is not None:
+            cmd += f' last {last}'
+
+        logs = []
+        for line in self._do_command(cmd):
+            ts, log = line.split(' ', 1)
+            logs.append((float(ts), log))
+
+        return logs
+
+    def loglevel(self, level: Optional[str] = None, nodeid: Optional[int] = None, region: Optional[str] = None) -> \
+            List[Tuple[Optional[int], Optional[str], str]]:
+        """
+        Set the minimum log level of a node and/or a log region, or get the log level settings if level is not specified.
+
+        :param level: the minimum log level (none|crit|warn|note|info|debg), or 'default' to remove the setting
+        :param nodeid: the node ID, or None for all nodes
+        :param region: the log region (e.g. 'MLE'), or None for all regions
+
+        :return: list of log level settings if level is not specified, each of format (node ID, region, level),
+                 where node ID and region are None for all nodes and regions
+        """
+        cmd = 'loglevel'
+        if nodeid is not None:
+            cmd += f' node {nodeid}'
+        if region is not None:
+            cmd += f' region {self._quote(region)}'
+        if level is not None:
+            self._do_command(f'{cmd} {level}')
+            return []
+
+        settings = []
+        for line in self._do_command(cmd):
+            fields = dict(kv.split('=', 1) for kv in line.split())
+            settings.append((
+                None if fields['node'] == 'any' else int(fields['node']),
+                None if fields['region'] == 'any' else fields['region'],
+                fields['level'],
+            ))
+
+        return settings
+
+    def add_logtrigger(self, action: str, nodeid: Optional[int] = None, region: Optional[str] = None,
+                       level: Optional[str] = None, grep: Optional[str] = None) -> int:
+        """
+        Add a log trigger.
+
+        :param action: 'count' to count matched log lines, or 'pause' to also pause the simulation
+        :param nodeid: only match log lines of the node
+        :param region: only match log lines of the log region
+        :param level: only match log lines at the level or more severe levels
+        :param grep: only match log lines matching this regular expression
+
+        :return: the log trigger ID
+        """
+        assert action in ('count', 'pause'), action
+        cmd = 'logtrigger add'
+        if nodeid is not None:
+            cmd += f' node {nodeid}'
+        if region is not None:
+            cmd += f' region {self._quote(region)}'
+        if level is not None:
+            cmd += f' level {level}'
+        if grep is not None:
+            cmd += f' grep {self._quote(grep)}'
+        cmd += f' {action}'
+
+        return self._expect_int(self._do_command(cmd))
+
+    def del_logtrigger(self, triggerid: int) -> None:
+        """
+        Delete a log trigger.
+
+        :param triggerid: the log trigger ID
+        """
+        self._do_command(f'logtrigger del {triggerid}')
+
+    def logtriggers(self) -> List[Dict[str, Any]]:
+        """
+        Get log triggers.
+
+        :return: list of log triggers, each of format {'id': int, 'node': Optional[int], 'region': Optional[str],
+                 'level': str, 'action': str, 'count': int, 'grep': str}
+        """
+        triggers = []
+        for line in self._do_command('logtrigger'):
+            fields = dict(kv.split('=', 1) for kv in shlex.split(line))
+            triggers.append({
+                'id': int(fields['id']),
+                'node': None if fields['node'] == 'any' else int(fields['node']),
+                'region': None if fields['region'] == 'any' else fields['region'],
+                'level': fields['level'],
+                'action': fields['action'],
+                'count': int(fields['count']),
+                'grep': fields['grep'],
+            })
+
+        return triggers
+
     def counters(self) -> Dict[str, int]:
         """
         Get counters.
@@ -391,6 +590,30 @@ class OTNS(object):
 
         return counters
 
+    def malformed_counters(self, *nodeids: int) -> Dict[int, Tuple[int, bool]]:
+        """
+        Get the number of malformed messages sent by nodes, and whether the nodes are quarantined.
+
+        :param nodeids: node IDs, or all nodes if not specified
+
+        :return: dict of node ID to (malformed message count, quarantined)
+        """
+        output = self._do_command(f'counters malformed {" ".join(map(str, nodeids))}')
+        counters = {}
+        for line in output:
+            fields = dict(field.split('=') for field in line.split())
+            counters[int(fields['node'])] = (int(fields['malformed']), fields['quarantined'] == 'true')
+
+        return counters
+
+    def unquarantine(self, *nodeids: int) -> None:
+        """
+        Lift the quarantine of nodes.
+
+        :param nodeids: operating node IDs
+        """
+        self._do_command(f'unquarantine {" ".join(map(str, nodeids))}')
+
     def prefix_add(self, nodeid: int, prefix: str, preferred=True, slaac=True, dhcp=False, dhcp_other=False,
                    default_route=True, on_mesh=True, stable=True, prf='med') -> None:
         flags = ''
@@ -725,6 +948,103 @@ class OTNS(object):
         """
         self.node_cmd(nodeid, f'routerdowngradethreshold {val}')
 
+    def netstate(self, nodeid: int) -> Dict[str, Any]:
+        """
+        Get the network state of a node.
+
+        :param nodeid: the node ID
+
+        :return: dict of the leader router ID ('leader'), key sequence ('key_seq'), channel ('channel'), network data
+                 ('prefixes', 'routes' and 'services', formatted as in `netdata show`) and SRP registrations
+                 ('srp_hosts' and 'srp_services')
+        """
+        return yaml.safe_load('\n'.join(self._do_command(f'netstate {nodeid}')))
+
+    def add_traffic(self, pattern: str, srcid: int, dst: Union[int, str, ipaddress.IPv6Address],
+                    addrtype: str = 'any', rate: float = None, size: int = None, burst: int = None,
+                    count: int = None) -> int:
+        """
+        Add a traffic flow of UDP datagrams from the source node to the destination node or address.
+
+        :param pattern: 'cbr' to send a datagram every 1/rate seconds, 'poisson' to send datagrams at exponentially
+                        distributed intervals of 1/rate seconds on average, or 'burst' to send `burst` datagrams at
+                        once every 1/rate seconds
+        :param srcid: source node ID
+        :param dst: destination node ID or address (unicast or multicast, in which case the other nodes subscribed to
+                    the address are expected to receive the datagrams)
+        :param addrtype: address type for the destination node (only useful for destination node ID)
+        :param rate: datagrams (or bursts) per second
+        :param size: payload size of the datagrams
+        :param burst: datagrams of each burst
+        :param count: total number of datagrams to send, or None to send until the flow is stopped
+
+        :return: the traffic flow ID
+
+        Use traffic() to get the statistics of traffic flows.
+        """
+        assert pattern in ('cbr', 'poisson', 'burst'), pattern
+        if isinstance(dst, (str, ipaddress.IPv6Address)):
+            addrtype = ''  # addrtype only appliable for dst ID
+
+            if isinstance(dst, ipaddress.IPv6Address):
+                dst = dst.compressed
+
+            dst = self._quote(dst)
+
+        cmd = f'traffic add {pattern} {srcid} {dst} {addrtype}'
+        if rate is not None:
+            cmd += f' rate {rate}'
+        if size is not None:
+            cmd += f' size {size}'
+        if burst is not None:
+            cmd += f' burst {burst}'
+        if count is not None:
+            cmd += f' count {count}'
+
+        return self._expect_int(self._do_command(cmd))
+
+    def stop_traffic(self, flowid: int) -> None:
+        """
+        Stop a traffic flow, keeping its statistics.
+
+        :param flowid: the traffic flow ID
+        """
+        self._do_command(f'traffic stop {flowid}')
+
+    def del_traffic(self, flowid: int) -> None:
+        """
+        Stop and delete a traffic flow.
+
+        :param flowid: the traffic flow ID
+        """
+        self._do_command(f'traffic del {flowid}')
+
+    def traffic(self) -> List[Dict[str, Any]]:
+        """
+        Get the statistics of traffic flows.
+
+        :return: list of traffic flows, each of which contains the flow ID ('id'), the pattern ('pattern'), the source
+                 node ID ('src'), the destination address ('dst'), whether it is running ('running'), the duration in
+                 seconds ('duration'), the number of sent datagrams and bytes ('sent' and 'sent_bytes') and the
+                 statistics of each receiver ('receivers'), which contain the node ID ('node'), the number of received
+                 datagrams and bytes ('received' and 'received_bytes'), the loss percentage ('loss'), the throughput in
+                 bits per second ('throughput') and, if any datagram is received, the delays in milliseconds ('min',
+                 'avg' and 'max')
+        """
+        return yaml.safe_load('\n'.join(self._do_command('traffic')))
+
+    def mcasts(self) -> List[Dict[str, Any]]:
+        """
+        Get the delivery of the datagrams of multicast traffic flows sent since the last call.
+
+        :return: list of datagrams, each of which contains the virtual time when it was sent in us ('time'), the flow ID
+                 ('flow'), the sequence number ('seq'), the source node ID ('src'), the multicast address
+                 ('dst_addr'), the ratio of the expected receivers that received it ('delivery') and the receivers
+                 ('receivers'), each of which contains the virtual time when it received the datagram in us ('time'),
+                 the node ID ('dst') and the latency in us ('latency')
+        """
+        return yaml.safe_load('\n'.join(self._do_command('mcasts')))
+
     def coaps_enable(self) -> None:
         self._do_command('coaps enable')
 
@@ -756,6 +1076,17 @@ class OTNS(object):
         assert len(output) == 1, output
         return output[0].strip()
 
+    @staticmethod
+    def _quote(s: str) -> str:
+        """
+        Quote string as a CLI string argument.
+
+        :param s: string to quote
+
+        :return: the quoted string
+        """
+        return '"' + s.replace('\\', '\\\\').replace('"', '\\"') + '"'
+
     @staticmethod
     def _escape_whitespace(s: str) -> str:
         """
@@ -20,7 +20,7 @@ DESCRIPTOR = _descriptor.FileDescriptor(
   syntax='proto3',
   serialized_options=None,
   create_key=_descriptor._internal_create_key,
-  serialized_pb=b'\n\x14visualize_grpc.proto\x12\x11visualize_grpc_pb\"\x12\n\x10VisualizeRequest\"\xb7\x0b\n\x0eVisualizeEvent\x12\x33\n\x08\x61\x64\x64_node\x18\x01 \x01(\x0b\x32\x1f.visualize_grpc_pb.AddNodeEventH\x00\x12\x39\n\x0b\x64\x65lete_node\x18\x02 \x01(\x0b\x32\".visualize_grpc_pb.DeleteNodeEventH\x00\x12@\n\x0fset_node_rloc16\x18\x03 \x01(\x0b\x32%.visualize_grpc_pb.SetNodeRloc16EventH\x00\x12<\n\rset_node_role\x18\x04 \x01(\x0b\x32#.visualize_grpc_pb.SetNodeRoleEventH\x00\x12:\n\x0cset_node_pos\x18\x05 \x01(\x0b\x32\".visualize_grpc_pb.SetNodePosEventH\x00\x12K\n\x15set_node_partition_id\x18\x06 \x01(\x0b\x32*.visualize_grpc_pb.SetNodePartitionIdEventH\x00\x12:\n\x0con_node_fail\x18\x07 \x01(\x0b\x32\".visualize_grpc_pb.OnNodeFailEventH\x00\x12@\n\x0fon_node_recover\x18\x08 \x01(\x0b\x32%.visualize_grpc_pb.OnNodeRecoverEventH\x00\x12\x37\n\nset_parent\x18\t \x01(\x0b\x32!.visualize_grpc_pb.SetParentEventH\x00\x12\x37\n\ncount_down\x18\n \x01(\x0b\x32!.visualize_grpc_pb.CountDownEventH\x00\x12\x42\n\x10show_demo_legend\x18\x0b \x01(\x0b\x32&.visualize_grpc_pb.ShowDemoLegendEventH\x00\x12;\n\x0c\x61\x64vance_time\x18\x0c \x01(\x0b\x32#.visualize_grpc_pb.AdvanceTimeEventH\x00\x12\x42\n\x10\x61\x64\x64_router_table\x18\r \x01(\x0b\x32&.visualize_grpc_pb.AddRouterTableEventH\x00\x12H\n\x13remove_router_table\x18\x0e \x01(\x0b\x32).visualize_grpc_pb.RemoveRouterTableEventH\x00\x12@\n\x0f\x61\x64\x64_child_table\x18\x0f \x01(\x0b\x32%.visualize_grpc_pb.AddChildTableEventH\x00\x12\x46\n\x12remove_child_table\x18\x10 \x01(\x0b\x32(.visualize_grpc_pb.RemoveChildTableEventH\x00\x12,\n\x04send\x18\x11 \x01(\x0b\x32\x1c.visualize_grpc_pb.SendEventH\x00\x12\x35\n\tset_speed\x18\x12 \x01(\x0b\x32 .visualize_grpc_pb.SetSpeedEventH\x00\x12\x36\n\theartbeat\x18\x13 \x01(\x0b\x32!.visualize_grpc_pb.HeartbeatEventH\x00\x12\x45\n\x12on_ext_addr_change\x18\x14 \x01(\x0b\x32\'.visualize_grpc_pb.OnExtAddrChangeEventH\x00\x12\x35\n\tset_title\x18\x15 \x01(\x0b\x32 .visualize_grpc_pb.SetTitleEventH\x00\x12<\n\rset_node_mode\x18\x16 \x01(\x0b\x32#.visualize_grpc_pb.SetNodeModeEventH\x00\x12\x42\n\x10set_network_info\x18\x17 \x01(\x0b\x32&.visualize_grpc_pb.SetNetworkInfoEventH\x00\x42\x06\n\x04type\"a\n\tSendEvent\x12\x0e\n\x06src_id\x18\x01 \x01(\x05\x12\x0e\n\x06\x64st_id\x18\x02 \x01(\x05\x12\x34\n\x07mv_info\x18\x03 \x01(\x0b\x32#.visualize_grpc_pb.MsgVisualizeInfo\"z\n\x10MsgVisualizeInfo\x12\x0f\n\x07\x63hannel\x18\x01 \x01(\r\x12\x15\n\rframe_control\x18\x02 \x01(\r\x12\x0b\n\x03seq\x18\x03 \x01(\r\x12\x16\n\x0e\x64st_addr_short\x18\x04 \x01(\r\x12\x19\n\x11\x64st_addr_extended\x18\x05 \x01(\x04\"8\n\x13\x41\x64\x64RouterTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\";\n\x16RemoveRouterTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"7\n\x12\x41\x64\x64\x43hildTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\":\n\x15RemoveChildTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"\x1e\n\rSetSpeedEvent\x12\r\n\x05speed\x18\x01 \x01(\x01\"\x10\n\x0eHeartbeatEvent\"-\n\x10\x41\x64vanceTimeEvent\x12\n\n\x02ts\x18\x01 \x01(\x04\x12\r\n\x05speed\x18\x02 \x01(\x01\"3\n\x0eSetParentEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"3\n\x0e\x43ountDownEvent\x12\x13\n\x0b\x64uration_ms\x18\x01 \x01(\x03\x12\x0c\n\x04text\x18\x02 \x01(\t\":\n\x13ShowDemoLegendEvent\x12\t\n\x01x\x18\x01 \x01(\x05\x12\t\n\x01y\x18\x02 \x01(\x05\x12\r\n\x05title\x18\x03 \x01(\t\"8\n\x0fSetNodePosEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\"R\n\x10SetNodeRoleEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12-\n\x04role\x18\x02 \x01(\x0e\x32\x1f.visualize_grpc_pb.OtDeviceRole\"@\n\x17SetNodePartitionIdEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x14\n\x0cpartition_id\x18\x02 \x01(\r\"\"\n\x0fOnNodeFailEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"%\n\x12OnNodeRecoverEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"\"\n\x0f\x44\x65leteNodeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"J\n\x0c\x41\x64\x64NodeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\x12\x13\n\x0bradio_range\x18\x04 \x01(\x05\"x\n\x08NodeMode\x12\x17\n\x0frx_on_when_idle\x18\x01 \x01(\x08\x12\x1c\n\x14secure_data_requests\x18\x02 \x01(\x08\x12\x1a\n\x12\x66ull_thread_device\x18\x03 \x01(\x08\x12\x19\n\x11\x66ull_network_data\x18\x04 \x01(\x08\"5\n\x12SetNodeRloc16Event\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x0e\n\x06rloc16\x18\x02 \x01(\r\"9\n\x14OnExtAddrChangeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"G\n\rSetTitleEvent\x12\r\n\x05title\x18\x01 \x01(\t\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\x12\x11\n\tfont_size\x18\x04 \x01(\x05\"S\n\x10SetNodeModeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12.\n\tnode_mode\x18\x02 \x01(\x0b\x32\x1b.visualize_grpc_pb.NodeMode\"D\n\x13SetNetworkInfoEvent\x12\x0c\n\x04real\x18\x01 \x01(\x08\x12\x0f\n\x07version\x18\x02 \x01(\t\x12\x0e\n\x06\x63ommit\x18\x03 \x01(\t\"!\n\x0e\x43ommandRequest\x12\x0f\n\x07\x63ommand\x18\x01 \x01(\t\"!\n\x0f\x43ommandResponse\x12\x0e\n\x06output\x18\x01 \x03(\t\"R\n\x0bReplayEntry\x12\x11\n\ttimestamp\x18\x01 \x01(\x04\x12\x30\n\x05\x65vent\x18\x02 \x01(\x0b\x32!.visualize_grpc_pb.VisualizeEvent\"\x07\n\x05\x45mpty*\x98\x01\n\x0cOtDeviceRole\x12\x1b\n\x17OT_DEVICE_ROLE_DISABLED\x10\x00\x12\x1b\n\x17OT_DEVICE_ROLE_DETACHED\x10\x01\x12\x18\n\x14OT_DEVICE_ROLE_CHILD\x10\x02\x12\x19\n\x15OT_DEVICE_ROLE_ROUTER\x10\x03\x12\x19\n\x15OT_DEVICE_ROLE_LEADER\x10\x04\x32\xbf\x01\n\x14VisualizeGrpcService\x12U\n\tVisualize\x12#.visualize_grpc_pb.VisualizeRequest\x1a!.visualize_grpc_pb.VisualizeEvent0\x01\x12P\n\x07\x43ommand\x12!.visualize_grpc_pb.CommandRequest\x1a\".visualize_grpc_pb.CommandResponseb\x06proto3'
+  serialized_pb=b'\n\x14visualize_grpc.proto\x12\x11visualize_grpc_pb\"\x12\n\x10VisualizeRequest\"\x86\r\n\x0eVisualizeEvent\x12\x33\n\x08\x61\x64\x64_node\x18\x01 \x01(\x0b\x32\x1f.visualize_grpc_pb.AddNodeEventH\x00\x12\x39\n\x0b\x64\x65lete_node\x18\x02 \x01(\x0b\x32\".visualize_grpc_pb.DeleteNodeEventH\x00\x12@\n\x0fset_node_rloc16\x18\x03 \x01(\x0b\x32%.visualize_grpc_pb.SetNodeRloc16EventH\x00\x12<\n\rset_node_role\x18\x04 \x01(\x0b\x32#.visualize_grpc_pb.SetNodeRoleEventH\x00\x12:\n\x0cset_node_pos\x18\x05 \x01(\x0b\x32\".visualize_grpc_pb.SetNodePosEventH\x00\x12K\n\x15set_node_partition_id\x18\x06 \x01(\x0b\x32*.visualize_grpc_pb.SetNodePartitionIdEventH\x00\x12:\n\x0con_node_fail\x18\x07 \x01(\x0b\x32\".visualize_grpc_pb.OnNodeFailEventH\x00\x12@\n\x0fon_node_recover\x18\x08 \x01(\x0b\x32%.visualize_grpc_pb.OnNodeRecoverEventH\x00\x12\x37\n\nset_parent\x18\t \x01(\x0b\x32!.visualize_grpc_pb.SetParentEventH\x00\x12\x37\n\ncount_down\x18\n \x01(\x0b\x32!.visualize_grpc_pb.CountDownEventH\x00\x12\x42\n\x10show_demo_legend\x18\x0b \x01(\x0b\x32&.visualize_grpc_pb.ShowDemoLegendEventH\x00\x12;\n\x0c\x61\x64vance_time\x18\x0c \x01(\x0b\x32#.visualize_grpc_pb.AdvanceTimeEventH\x00\x12\x42\n\x10\x61\x64\x64_router_table\x18\r \x01(\x0b\x32&.visualize_grpc_pb.AddRouterTableEventH\x00\x12H\n\x13remove_router_table\x18\x0e \x01(\x0b\x32).visualize_grpc_pb.RemoveRouterTableEventH\x00\x12@\n\x0f\x61\x64\x64_child_table\x18\x0f \x01(\x0b\x32%.visualize_grpc_pb.AddChildTableEventH\x00\x12\x46\n\x12remove_child_table\x18\x10 \x01(\x0b\x32(.visualize_grpc_pb.RemoveChildTableEventH\x00\x12,\n\x04send\x18\x11 \x01(\x0b\x32\x1c.visualize_grpc_pb.SendEventH\x00\x12\x35\n\tset_speed\x18\x12 \x01(\x0b\x32 .visualize_grpc_pb.SetSpeedEventH\x00\x12\x36\n\theartbeat\x18\x13 \x01(\x0b\x32!.visualize_grpc_pb.HeartbeatEventH\x00\x12\x45\n\x12on_ext_addr_change\x18\x14 \x01(\x0b\x32\'.visualize_grpc_pb.OnExtAddrChangeEventH\x00\x12\x35\n\tset_title\x18\x15 \x01(\x0b\x32 .visualize_grpc_pb.SetTitleEventH\x00\x12<\n\rset_node_mode\x18\x16 \x01(\x0b\x32#.visualize_grpc_pb.SetNodeModeEventH\x00\x12\x42\n\x10set_network_info\x18\x17 \x01(\x0b\x32&.visualize_grpc_pb.SetNetworkInfoEventH\x00\x12<\n\ron_node_crash\x18\x18 \x01(\x0b\x32#.visualize_grpc_pb.OnNodeCrashEventH\x00\x12@\n\x0fon_node_restart\x18\x19 \x01(\x0b\x32%.visualize_grpc_pb.OnNodeRestartEventH\x00\x12M\n\x16set_node_network_state\x18\x1a \x01(\x0b\x32+.visualize_grpc_pb.SetNodeNetworkStateEventH\x00\x42\x06\n\x04type\"a\n\tSendEvent\x12\x0e\n\x06src_id\x18\x01 \x01(\x05\x12\x0e\n\x06\x64st_id\x18\x02 \x01(\x05\x12\x34\n\x07mv_info\x18\x03 \x01(\x0b\x32#.visualize_grpc_pb.MsgVisualizeInfo\"\x89\x01\n\x10MsgVisualizeInfo\x12\x0f\n\x07\x63hannel\x18\x01 \x01(\r\x12\x15\n\rframe_control\x18\x02 \x01(\r\x12\x0b\n\x03seq\x18\x03 \x01(\r\x12\x16\n\x0e\x64st_addr_short\x18\x04 \x01(\r\x12\x19\n\x11\x64st_addr_extended\x18\x05 \x01(\x04\x12\r\n\x05label\x18\x06 \x01(\t\"8\n\x13\x41\x64\x64RouterTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\";\n\x16RemoveRouterTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"7\n\x12\x41\x64\x64\x43hildTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\":\n\x15RemoveChildTableEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"\x1e\n\rSetSpeedEvent\x12\r\n\x05speed\x18\x01 \x01(\x01\"\x10\n\x0eHeartbeatEvent\"-\n\x10\x41\x64vanceTimeEvent\x12\n\n\x02ts\x18\x01 \x01(\x04\x12\r\n\x05speed\x18\x02 \x01(\x01\"3\n\x0eSetParentEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"3\n\x0e\x43ountDownEvent\x12\x13\n\x0b\x64uration_ms\x18\x01 \x01(\x03\x12\x0c\n\x04text\x18\x02 \x01(\t\":\n\x13ShowDemoLegendEvent\x12\t\n\x01x\x18\x01 \x01(\x05\x12\t\n\x01y\x18\x02 \x01(\x05\x12\r\n\x05title\x18\x03 \x01(\t\"8\n\x0fSetNodePosEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\"R\n\x10SetNodeRoleEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12-\n\x04role\x18\x02 \x01(\x0e\x32\x1f.visualize_grpc_pb.OtDeviceRole\"@\n\x17SetNodePartitionIdEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x14\n\x0cpartition_id\x18\x02 \x01(\r\"\"\n\x0fOnNodeFailEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"%\n\x12OnNodeRecoverEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"3\n\x10OnNodeCrashEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x0e\n\x06reason\x18\x02 \x01(\t\"%\n\x12OnNodeRestartEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"\x84\x02\n\x18SetNodeNetworkStateEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x18\n\x10leader_router_id\x18\x02 \x01(\r\x12\x14\n\x0ckey_sequence\x18\x03 \x01(\r\x12\x0f\n\x07\x63hannel\x18\x04 \x01(\r\x12\x10\n\x08prefixes\x18\x05 \x03(\t\x12\x0e\n\x06routes\x18\x06 \x03(\t\x12\x10\n\x08services\x18\x07 \x03(\t\x12-\n\tsrp_hosts\x18\x08 \x03(\x0b\x32\x1a.visualize_grpc_pb.SrpHost\x12\x33\n\x0csrp_services\x18\t \x03(\x0b\x32\x1d.visualize_grpc_pb.SrpService\"*\n\x07SrpHost\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x11\n\taddresses\x18\x02 \x03(\t\"6\n\nSrpService\x12\x0c\n\x04name\x18\x01 \x01(\t\x12\x0c\n\x04host\x18\x02 \x01(\t\x12\x0c\n\x04port\x18\x03 \x01(\r\"\"\n\x0f\x44\x65leteNodeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\"J\n\x0c\x41\x64\x64NodeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\x12\x13\n\x0bradio_range\x18\x04 \x01(\x05\"x\n\x08NodeMode\x12\x17\n\x0frx_on_when_idle\x18\x01 \x01(\x08\x12\x1c\n\x14secure_data_requests\x18\x02 \x01(\x08\x12\x1a\n\x12\x66ull_thread_device\x18\x03 \x01(\x08\x12\x19\n\x11\x66ull_network_data\x18\x04 \x01(\x08\"5\n\x12SetNodeRloc16Event\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x0e\n\x06rloc16\x18\x02 \x01(\r\"9\n\x14OnExtAddrChangeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x10\n\x08\x65xt_addr\x18\x02 \x01(\x04\"G\n\rSetTitleEvent\x12\r\n\x05title\x18\x01 \x01(\t\x12\t\n\x01x\x18\x02 \x01(\x05\x12\t\n\x01y\x18\x03 \x01(\x05\x12\x11\n\tfont_size\x18\x04 \x01(\x05\"S\n\x10SetNodeModeEvent\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12.\n\tnode_mode\x18\x02 \x01(\x0b\x32\x1b.visualize_grpc_pb.NodeMode\"D\n\x13SetNetworkInfoEvent\x12\x0c\n\x04real\x18\x01 \x01(\x08\x12\x0f\n\x07version\x18\x02 \x01(\t\x12\x0e\n\x06\x63ommit\x18\x03 \x01(\t\"!\n\x0e\x43ommandRequest\x12\x0f\n\x07\x63ommand\x18\x01 \x01(\t\"!\n\x0f\x43ommandResponse\x12\x0e\n\x06output\x18\x01 \x03(\t\">\n\x0fNodeLogsRequest\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x0c\n\x04grep\x18\x02 \x01(\t\x12\x0c\n\x04last\x18\x03 \x01(\x05\"C\n\x0cNodeLogEntry\x12\x0f\n\x07node_id\x18\x01 \x01(\x05\x12\x14\n\x0cvirtual_time\x18\x02 \x01(\x04\x12\x0c\n\x04line\x18\x03 \x01(\t\"\x88\x01\n\x0cReplayHeader\x12\x16\n\x0e\x66ormat_version\x18\x01 \x01(\r\x12\x14\n\x0cotns_version\x18\x02 \x01(\t\x12\x0c\n\x04seed\x18\x03 \x01(\x03\x12<\n\x0cnetwork_info\x18\x04 \x01(\x0b\x32&.visualize_grpc_pb.SetNetworkInfoEvent\"\x9d\x01\n\x0bReplayEntry\x12\x11\n\ttimestamp\x18\x01 \x01(\x04\x12\x30\n\x05\x65vent\x18\x02 \x01(\x0b\x32!.visualize_grpc_pb.VisualizeEvent\x12\x33\n\x08keyframe\x18\x03 \x01(\x0b\x32!.visualize_grpc_pb.ReplayKeyframe\x12\x14\n\x0cvirtual_time\x18\x04 \x01(\x04\"C\n\x0eReplayKeyframe\x12\x31\n\x06\x65vents\x18\x01 \x03(\x0b\x32!.visualize_grpc_pb.VisualizeEvent\"Z\n\x0bReplayIndex\x12\x13\n\x0breplay_size\x18\x01 \x01(\x04\x12\x36\n\tkeyframes\x18\x02 \x03(\x0b\x32#.visualize_grpc_pb.ReplayIndexEntry\"K\n\x10ReplayIndexEntry\x12\x11\n\ttimestamp\x18\x01 \x01(\x04\x12\x0e\n\x06offset\x18\x02 \x01(\x04\x12\x14\n\x0cvirtual_time\x18\x03 \x01(\x04\"\x07\n\x05\x45mpty*\x98\x01\n\x0cOtDeviceRole\x12\x1b\n\x17OT_DEVICE_ROLE_DISABLED\x10\x00\x12\x1b\n\x17OT_DEVICE_ROLE_DETACHED\x10\x01\x12\x18\n\x14OT_DEVICE_ROLE_CHILD\x10\x02\x12\x19\n\x15OT_DEVICE_ROLE_ROUTER\x10\x03\x12\x19\n\x15OT_DEVICE_ROLE_LEADER\x10\x04\x32\x92\x02\n\x14VisualizeGrpcService\x12U\n\tVisualize\x12#.visualize_grpc_pb.VisualizeRequest\x1a!.visualize_grpc_pb.VisualizeEvent0\x01\x12P\n\x07\x43ommand\x12!.visualize_grpc_pb.CommandRequest\x1a\".visualize_grpc_pb.CommandResponse\x12Q\n\x08NodeLogs\x12\".visualize_grpc_pb.NodeLogsRequest\x1a\x1f.visualize_grpc_pb.NodeLogEntry0\x01\x62\x06proto3'
 )
 
 _OTDEVICEROLE = _descriptor.EnumDescriptor(
@@ -58,8 +58,8 @@ _OTDEVICEROLE = _descriptor.EnumDescriptor(
   ],
   containing_type=None,
   serialized_options=None,
-  serialized_start=3274,
-  serialized_end=3426,
+  serialized_start=4538,
+  serialized_end=4690,
 )
 _sym_db.RegisterEnumDescriptor(_OTDEVICEROLE)
 
@@ -266,6 +266,27 @@ _VISUALIZEEVENT = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='on_node_crash', full_name='visualize_grpc_pb.VisualizeEvent.on_node_crash', index=23,
+      number=24, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='on_node_restart', full_name='visualize_grpc_pb.VisualizeEvent.on_node_restart', index=24,
+      number=25, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='set_node_network_state', full_name='visualize_grpc_pb.VisualizeEvent.set_node_network_state', index=25,
+      number=26, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -284,7 +305,7 @@ _VISUALIZEEVENT = _descriptor.Descriptor(
     fields=[]),
   ],
   serialized_start=64,
-  serialized_end=1527,
+  serialized_end=1734,
 )
 
 
@@ -329,8 +350,8 @@ _SENDEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1529,
-  serialized_end=1626,
+  serialized_start=1736,
+  serialized_end=1833,
 )
 
 
@@ -377,6 +398,13 @@ _MSGVISUALIZEINFO = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='label', full_name='visualize_grpc_pb.MsgVisualizeInfo.label', index=5,
+      number=6, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -389,8 +417,8 @@ _MSGVISUALIZEINFO = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1628,
-  serialized_end=1750,
+  serialized_start=1836,
+  serialized_end=1973,
 )
 
 
@@ -428,8 +456,8 @@ _ADDROUTERTABLEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1752,
-  serialized_end=1808,
+  serialized_start=1975,
+  serialized_end=2031,
 )
 
 
@@ -467,8 +495,8 @@ _REMOVEROUTERTABLEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1810,
-  serialized_end=1869,
+  serialized_start=2033,
+  serialized_end=2092,
 )
 
 
@@ -506,8 +534,8 @@ _ADDCHILDTABLEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1871,
-  serialized_end=1926,
+  serialized_start=2094,
+  serialized_end=2149,
 )
 
 
@@ -545,8 +573,8 @@ _REMOVECHILDTABLEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1928,
-  serialized_end=1986,
+  serialized_start=2151,
+  serialized_end=2209,
 )
 
 
@@ -577,8 +605,8 @@ _SETSPEEDEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=1988,
-  serialized_end=2018,
+  serialized_start=2211,
+  serialized_end=2241,
 )
 
 
@@ -602,8 +630,8 @@ _HEARTBEATEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2020,
-  serialized_end=2036,
+  serialized_start=2243,
+  serialized_end=2259,
 )
 
 
@@ -641,8 +669,8 @@ _ADVANCETIMEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2038,
-  serialized_end=2083,
+  serialized_start=2261,
+  serialized_end=2306,
 )
 
 
@@ -680,8 +708,8 @@ _SETPARENTEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2085,
-  serialized_end=2136,
+  serialized_start=2308,
+  serialized_end=2359,
 )
 
 
@@ -719,8 +747,8 @@ _COUNTDOWNEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2138,
-  serialized_end=2189,
+  serialized_start=2361,
+  serialized_end=2412,
 )
 
 
@@ -765,8 +793,8 @@ _SHOWDEMOLEGENDEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2191,
-  serialized_end=2249,
+  serialized_start=2414,
+  serialized_end=2472,
 )
 
 
@@ -811,8 +839,8 @@ _SETNODEPOSEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2251,
-  serialized_end=2307,
+  serialized_start=2474,
+  serialized_end=2530,
 )
 
 
@@ -850,8 +878,8 @@ _SETNODEROLEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2309,
-  serialized_end=2391,
+  serialized_start=2532,
+  serialized_end=2614,
 )
 
 
@@ -889,8 +917,8 @@ _SETNODEPARTITIONIDEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2393,
-  serialized_end=2457,
+  serialized_start=2616,
+  serialized_end=2680,
 )
 
 
@@ -921,8 +949,8 @@ _ONNODEFAILEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2459,
-  serialized_end=2493,
+  serialized_start=2682,
+  serialized_end=2716,
 )
 
 
@@ -953,8 +981,252 @@ _ONNODERECOVEREVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2495,
-  serialized_end=2532,
+  serialized_start=2718,
+  serialized_end=2755,
+)
+
+
+_ONNODECRASHEVENT = _descriptor.Descriptor(
+  name='OnNodeCrashEvent',
+  full_name='visualize_grpc_pb.OnNodeCrashEvent',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='node_id', full_name='visualize_grpc_pb.OnNodeCrashEvent.node_id', index=0,
+      number=1, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='reason', full_name='visualize_grpc_pb.OnNodeCrashEvent.reason', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2757,
+  serialized_end=2808,
+)
+
+
+_ONNODERESTARTEVENT = _descriptor.Descriptor(
+  name='OnNodeRestartEvent',
+  full_name='visualize_grpc_pb.OnNodeRestartEvent',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='node_id', full_name='visualize_grpc_pb.OnNodeRestartEvent.node_id', index=0,
+      number=1, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2810,
+  serialized_end=2847,
+)
+
+
+_SETNODENETWORKSTATEEVENT = _descriptor.Descriptor(
+  name='SetNodeNetworkStateEvent',
+  full_name='visualize_grpc_pb.SetNodeNetworkStateEvent',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='node_id', full_name='visualize_grpc_pb.SetNodeNetworkStateEvent.node_id', index=0,
+      number=1, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='leader_router_id', full_name='visualize_grpc_pb.SetNodeNetworkStateEvent.leader_router_id', index=1,
+      number=2, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='key_sequence', full_name='visualize_grpc_pb.SetNodeNetworkStateEvent.key_sequence', index=2,
+      number=3, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='channel', full_name='visualize_grpc_pb.SetNodeNetworkStateEvent.channel', index=3,
+      number=4, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='prefixes', full_name='visualize_grpc_pb.SetNodeNetworkStateEvent.prefixes', index=4,
+      number=5, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='routes', full_name='visualize_grpc_pb.SetNodeNetworkStateEvent.routes', index=5,
+      number=6, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='services', full_name='visualize_grpc_pb.SetNodeNetworkStateEvent.services', index=6,
+      number=7, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='srp_hosts', full_name='visualize_grpc_pb.SetNodeNetworkStateEvent.srp_hosts', index=7,
+      number=8, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='srp_services', full_name='visualize_grpc_pb.SetNodeNetworkStateEvent.srp_services', index=8,
+      number=9, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=2850,
+  serialized_end=3110,
+)
+
+
+_SRPHOST = _descriptor.Descriptor(
+  name='SrpHost',
+  full_name='visualize_grpc_pb.SrpHost',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='name', full_name='visualize_grpc_pb.SrpHost.name', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='addresses', full_name='visualize_grpc_pb.SrpHost.addresses', index=1,
+      number=2, type=9, cpp_type=9, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3112,
+  serialized_end=3154,
+)
+
+
+_SRPSERVICE = _descriptor.Descriptor(
+  name='SrpService',
+  full_name='visualize_grpc_pb.SrpService',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='name', full_name='visualize_grpc_pb.SrpService.name', index=0,
+      number=1, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='host', full_name='visualize_grpc_pb.SrpService.host', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='port', full_name='visualize_grpc_pb.SrpService.port', index=2,
+      number=3, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3156,
+  serialized_end=3210,
 )
 
 
@@ -985,8 +1257,8 @@ _DELETENODEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2534,
-  serialized_end=2568,
+  serialized_start=3212,
+  serialized_end=3246,
 )
 
 
@@ -1038,8 +1310,8 @@ _ADDNODEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2570,
-  serialized_end=2644,
+  serialized_start=3248,
+  serialized_end=3322,
 )
 
 
@@ -1091,8 +1363,8 @@ _NODEMODE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2646,
-  serialized_end=2766,
+  serialized_start=3324,
+  serialized_end=3444,
 )
 
 
@@ -1130,8 +1402,8 @@ _SETNODERLOC16EVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2768,
-  serialized_end=2821,
+  serialized_start=3446,
+  serialized_end=3499,
 )
 
 
@@ -1169,8 +1441,8 @@ _ONEXTADDRCHANGEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2823,
-  serialized_end=2880,
+  serialized_start=3501,
+  serialized_end=3558,
 )
 
 
@@ -1222,8 +1494,8 @@ _SETTITLEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2882,
-  serialized_end=2953,
+  serialized_start=3560,
+  serialized_end=3631,
 )
 
 
@@ -1261,8 +1533,8 @@ _SETNODEMODEEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=2955,
-  serialized_end=3038,
+  serialized_start=3633,
+  serialized_end=3716,
 )
 
 
@@ -1307,8 +1579,8 @@ _SETNETWORKINFOEVENT = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=3040,
-  serialized_end=3108,
+  serialized_start=3718,
+  serialized_end=3786,
 )
 
 
@@ -1339,8 +1611,8 @@ _COMMANDREQUEST = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=3110,
-  serialized_end=3143,
+  serialized_start=3788,
+  serialized_end=3821,
 )
 
 
@@ -1371,8 +1643,153 @@ _COMMANDRESPONSE = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=3145,
-  serialized_end=3178,
+  serialized_start=3823,
+  serialized_end=3856,
+)
+
+
+_NODELOGSREQUEST = _descriptor.Descriptor(
+  name='NodeLogsRequest',
+  full_name='visualize_grpc_pb.NodeLogsRequest',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='node_id', full_name='visualize_grpc_pb.NodeLogsRequest.node_id', index=0,
+      number=1, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='grep', full_name='visualize_grpc_pb.NodeLogsRequest.grep', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='last', full_name='visualize_grpc_pb.NodeLogsRequest.last', index=2,
+      number=3, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3858,
+  serialized_end=3920,
+)
+
+
+_NODELOGENTRY = _descriptor.Descriptor(
+  name='NodeLogEntry',
+  full_name='visualize_grpc_pb.NodeLogEntry',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='node_id', full_name='visualize_grpc_pb.NodeLogEntry.node_id', index=0,
+      number=1, type=5, cpp_type=1, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='virtual_time', full_name='visualize_grpc_pb.NodeLogEntry.virtual_time', index=1,
+      number=2, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='line', full_name='visualize_grpc_pb.NodeLogEntry.line', index=2,
+      number=3, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3922,
+  serialized_end=3989,
+)
+
+
+_REPLAYHEADER = _descriptor.Descriptor(
+  name='ReplayHeader',
+  full_name='visualize_grpc_pb.ReplayHeader',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='format_version', full_name='visualize_grpc_pb.ReplayHeader.format_version', index=0,
+      number=1, type=13, cpp_type=3, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='otns_version', full_name='visualize_grpc_pb.ReplayHeader.otns_version', index=1,
+      number=2, type=9, cpp_type=9, label=1,
+      has_default_value=False, default_value=b"".decode('utf-8'),
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='seed', full_name='visualize_grpc_pb.ReplayHeader.seed', index=2,
+      number=3, type=3, cpp_type=2, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='network_info', full_name='visualize_grpc_pb.ReplayHeader.network_info', index=3,
+      number=4, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=3992,
+  serialized_end=4128,
 )
 
 
@@ -1398,6 +1815,137 @@ _REPLAYENTRY = _descriptor.Descriptor(
       message_type=None, enum_type=None, containing_type=None,
       is_extension=False, extension_scope=None,
       serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='keyframe', full_name='visualize_grpc_pb.ReplayEntry.keyframe', index=2,
+      number=3, type=11, cpp_type=10, label=1,
+      has_default_value=False, default_value=None,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='virtual_time', full_name='visualize_grpc_pb.ReplayEntry.virtual_time', index=3,
+      number=4, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4131,
+  serialized_end=4288,
+)
+
+
+_REPLAYKEYFRAME = _descriptor.Descriptor(
+  name='ReplayKeyframe',
+  full_name='visualize_grpc_pb.ReplayKeyframe',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='events', full_name='visualize_grpc_pb.ReplayKeyframe.events', index=0,
+      number=1, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4290,
+  serialized_end=4357,
+)
+
+
+_REPLAYINDEX = _descriptor.Descriptor(
+  name='ReplayIndex',
+  full_name='visualize_grpc_pb.ReplayIndex',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='replay_size', full_name='visualize_grpc_pb.ReplayIndex.replay_size', index=0,
+      number=1, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='keyframes', full_name='visualize_grpc_pb.ReplayIndex.keyframes', index=1,
+      number=2, type=11, cpp_type=10, label=3,
+      has_default_value=False, default_value=[],
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+  ],
+  extensions=[
+  ],
+  nested_types=[],
+  enum_types=[
+  ],
+  serialized_options=None,
+  is_extendable=False,
+  syntax='proto3',
+  extension_ranges=[],
+  oneofs=[
+  ],
+  serialized_start=4359,
+  serialized_end=4449,
+)
+
+
+_REPLAYINDEXENTRY = _descriptor.Descriptor(
+  name='ReplayIndexEntry',
+  full_name='visualize_grpc_pb.ReplayIndexEntry',
+  filename=None,
+  file=DESCRIPTOR,
+  containing_type=None,
+  create_key=_descriptor._internal_create_key,
+  fields=[
+    _descriptor.FieldDescriptor(
+      name='timestamp', full_name='visualize_grpc_pb.ReplayIndexEntry.timestamp', index=0,
+      number=1, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='offset', full_name='visualize_grpc_pb.ReplayIndexEntry.offset', index=1,
+      number=2, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
+    _descriptor.FieldDescriptor(
+      name='virtual_time', full_name='visualize_grpc_pb.ReplayIndexEntry.virtual_time', index=2,
+      number=3, type=4, cpp_type=4, label=1,
+      has_default_value=False, default_value=0,
+      message_type=None, enum_type=None, containing_type=None,
+      is_extension=False, extension_scope=None,
+      serialized_options=None, file=DESCRIPTOR,  create_key=_descriptor._internal_create_key),
   ],
   extensions=[
   ],
@@ -1410,8 +1958,8 @@ _REPLAYENTRY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=3180,
-  serialized_end=3262,
+  serialized_start=4451,
+  serialized_end=4526,
 )
 
 
@@ -1435,8 +1983,8 @@ _EMPTY = _descriptor.Descriptor(
   extension_ranges=[],
   oneofs=[
   ],
-  serialized_start=3264,
-  serialized_end=3271,
+  serialized_start=4528,
+  serialized_end=4535,
 )
 
 _VISUALIZEEVENT.fields_by_name['add_node'].message_type = _ADDNODEEVENT
@@ -1462,6 +2010,9 @@ _VISUALIZEEVENT.fields_by_name['on_ext_addr_change'].message_type = _ONEXTADDRCH
 _VISUALIZEEVENT.fields_by_name['set_title'].message_type = _SETTITLEEVENT
 _VISUALIZEEVENT.fields_by_name['set_node_mode'].message_type = _SETNODEMODEEVENT
 _VISUALIZEEVENT.fields_by_name['set_network_info'].message_type = _SETNETWORKINFOEVENT
+_VISUALIZEEVENT.fields_by_name['on_node_crash'].message_type = _ONNODECRASHEVENT
+_VISUALIZEEVENT.fields_by_name['on_node_restart'].message_type = _ONNODERESTARTEVENT
+_VISUALIZEEVENT.fields_by_name['set_node_network_state'].message_type = _SETNODENETWORKSTATEEVENT
 _VISUALIZEEVENT.oneofs_by_name['type'].fields.append(
   _VISUALIZEEVENT.fields_by_name['add_node'])
 _VISUALIZEEVENT.fields_by_name['add_node'].containing_oneof = _VISUALIZEEVENT.oneofs_by_name['type']
@@ -1531,10 +2082,25 @@ _VISUALIZEEVENT.fields_by_name['set_node_mode'].containing_oneof = _VISUALIZEEVE
 _VISUALIZEEVENT.oneofs_by_name['type'].fields.append(
   _VISUALIZEEVENT.fields_by_name['set_network_info'])
 _VISUALIZEEVENT.fields_by_name['set_network_info'].containing_oneof = _VISUALIZEEVENT.oneofs_by_name['type']
+_VISUALIZEEVENT.oneofs_by_name['type'].fields.append(
+  _VISUALIZEEVENT.fields_by_name['on_node_crash'])
+_VISUALIZEEVENT.fields_by_name['on_node_crash'].containing_oneof = _VISUALIZEEVENT.oneofs_by_name['type']
+_VISUALIZEEVENT.oneofs_by_name['type'].fields.append(
+  _VISUALIZEEVENT.fields_by_name['on_node_restart'])
+_VISUALIZEEVENT.fields_by_name['on_node_restart'].containing_oneof = _VISUALIZEEVENT.oneofs_by_name['type']
+_VISUALIZEEVENT.oneofs_by_name['type'].fields.append(
+  _VISUALIZEEVENT.fields_by_name['set_node_network_state'])
+_VISUALIZEEVENT.fields_by_name['set_node_network_state'].containing_oneof = _VISUALIZEEVENT.oneofs_by_name['type']
 _SENDEVENT.fields_by_name['mv_info'].message_type = _MSGVISUALIZEINFO
 _SETNODEROLEEVENT.fields_by_name['role'].enum_type = _OTDEVICEROLE
+_SETNODENETWORKSTATEEVENT.fields_by_name['srp_hosts'].message_type = _SRPHOST
+_SETNODENETWORKSTATEEVENT.fields_by_name['srp_services'].message_type = _SRPSERVICE
 _SETNODEMODEEVENT.fields_by_name['node_mode'].message_type = _NODEMODE
+_REPLAYHEADER.fields_by_name['network_info'].message_type = _SETNETWORKINFOEVENT
 _REPLAYENTRY.fields_by_name['event'].message_type = _VISUALIZEEVENT
+_REPLAYENTRY.fields_by_name['keyframe'].message_type = _REPLAYKEYFRAME
+_REPLAYKEYFRAME.fields_by_name['events'].message_type = _VISUALIZEEVENT
+_REPLAYINDEX.fields_by_name['keyframes'].message_type = _REPLAYINDEXENTRY
 DESCRIPTOR.message_types_by_name['VisualizeRequest'] = _VISUALIZEREQUEST
 DESCRIPTOR.message_types_by_name['VisualizeEvent'] = _VISUALIZEEVENT
 DESCRIPTOR.message_types_by_name['SendEvent'] = _SENDEVENT
@@ -1554,6 +2120,11 @@ DESCRIPTOR.message_types_by_name['SetNodeRoleEvent'] = _SETNODEROLEEVENT
 DESCRIPTOR.message_types_by_name['SetNodePartitionIdEvent'] = _SETNODEPARTITIONIDEVENT
 DESCRIPTOR.message_types_by_name['OnNodeFailEvent'] = _ONNODEFAILEVENT
 DESCRIPTOR.message_types_by_name['OnNodeRecoverEvent'] = _ONNODERECOVEREVENT
+DESCRIPTOR.message_types_by_name['OnNodeCrashEvent'] = _ONNODECRASHEVENT
+DESCRIPTOR.message_types_by_name['OnNodeRestartEvent'] = _ONNODERESTARTEVENT
+DESCRIPTOR.message_types_by_name['SetNodeNetworkStateEvent'] = _SETNODENETWORKSTATEEVENT
+DESCRIPTOR.message_types_by_name['SrpHost'] = _SRPHOST
+DESCRIPTOR.message_types_by_name['SrpService'] = _SRPSERVICE
 DESCRIPTOR.message_types_by_name['DeleteNodeEvent'] = _DELETENODEEVENT
 DESCRIPTOR.message_types_by_name['AddNodeEvent'] = _ADDNODEEVENT
 DESCRIPTOR.message_types_by_name['NodeMode'] = _NODEMODE
@@ -1564,7 +2135,13 @@ DESCRIPTOR.message_types_by_name['SetNodeModeEvent'] = _SETNODEMODEEVENT
 DESCRIPTOR.message_types_by_name['SetNetworkInfoEvent'] = _SETNETWORKINFOEVENT
 DESCRIPTOR.message_types_by_name['CommandRequest'] = _COMMANDREQUEST
 DESCRIPTOR.message_types_by_name['CommandResponse'] = _COMMANDRESPONSE
+DESCRIPTOR.message_types_by_name['NodeLogsRequest'] = _NODELOGSREQUEST
+DESCRIPTOR.message_types_by_name['NodeLogEntry'] = _NODELOGENTRY
+DESCRIPTOR.message_types_by_name['ReplayHeader'] = _REPLAYHEADER
 DESCRIPTOR.message_types_by_name['ReplayEntry'] = _REPLAYENTRY
+DESCRIPTOR.message_types_by_name['ReplayKeyframe'] = _REPLAYKEYFRAME
+DESCRIPTOR.message_types_by_name['ReplayIndex'] = _REPLAYINDEX
+DESCRIPTOR.message_types_by_name['ReplayIndexEntry'] = _REPLAYINDEXENTRY
 DESCRIPTOR.message_types_by_name['Empty'] = _EMPTY
 DESCRIPTOR.enum_types_by_name['OtDeviceRole'] = _OTDEVICEROLE
 _sym_db.RegisterFileDescriptor(DESCRIPTOR)
@@ -1702,6 +2279,41 @@ OnNodeRecoverEvent = _reflection.GeneratedProtocolMessageType('OnNodeRecoverEven
   })
 _sym_db.RegisterMessage(OnNodeRecoverEvent)
 
+OnNodeCrashEvent = _reflection.GeneratedProtocolMessageType('OnNodeCrashEvent', (_message.Message,), {
+  'DESCRIPTOR' : _ONNODECRASHEVENT,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.OnNodeCrashEvent)
+  })
+_sym_db.RegisterMessage(OnNodeCrashEvent)
+
+OnNodeRestartEvent = _reflection.GeneratedProtocolMessageType('OnNodeRestartEvent', (_message.Message,), {
+  'DESCRIPTOR' : _ONNODERESTARTEVENT,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.OnNodeRestartEvent)
+  })
+_sym_db.RegisterMessage(OnNodeRestartEvent)
+
+SetNodeNetworkStateEvent = _reflection.GeneratedProtocolMessageType('SetNodeNetworkStateEvent', (_message.Message,), {
+  'DESCRIPTOR' : _SETNODENETWORKSTATEEVENT,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.SetNodeNetworkStateEvent)
+  })
+_sym_db.RegisterMessage(SetNodeNetworkStateEvent)
+
+SrpHost = _reflection.GeneratedProtocolMessageType('SrpHost', (_message.Message,), {
+  'DESCRIPTOR' : _SRPHOST,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.SrpHost)
+  })
+_sym_db.RegisterMessage(SrpHost)
+
+SrpService = _reflection.GeneratedProtocolMessageType('SrpService', (_message.Message,), {
+  'DESCRIPTOR' : _SRPSERVICE,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.SrpService)
+  })
+_sym_db.RegisterMessage(SrpService)
+
 DeleteNodeEvent = _reflection.GeneratedProtocolMessageType('DeleteNodeEvent', (_message.Message,), {
   'DESCRIPTOR' : _DELETENODEEVENT,
   '__module__' : 'visualize_grpc_pb2'
@@ -1772,6 +2384,27 @@ CommandResponse = _reflection.GeneratedProtocolMessageType('CommandResponse', (_
   })
 _sym_db.RegisterMessage(CommandResponse)
 
+NodeLogsRequest = _reflection.GeneratedProtocolMessageType('NodeLogsRequest', (_message.Message,), {
+  'DESCRIPTOR' : _NODELOGSREQUEST,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.NodeLogsRequest)
+  })
+_sym_db.RegisterMessage(NodeLogsRequest)
+
+NodeLogEntry = _reflection.GeneratedProtocolMessageType('NodeLogEntry', (_message.Message,), {
+  'DESCRIPTOR' : _NODELOGENTRY,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.NodeLogEntry)
+  })
+_sym_db.RegisterMessage(NodeLogEntry)
+
+ReplayHeader = _reflection.GeneratedProtocolMessageType('ReplayHeader', (_message.Message,), {
+  'DESCRIPTOR' : _REPLAYHEADER,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.ReplayHeader)
+  })
+_sym_db.RegisterMessage(ReplayHeader)
+
 ReplayEntry = _reflection.GeneratedProtocolMessageType('ReplayEntry', (_message.Message,), {
   'DESCRIPTOR' : _REPLAYENTRY,
   '__module__' : 'visualize_grpc_pb2'
@@ -1779,6 +2412,27 @@ ReplayEntry = _reflection.GeneratedProtocolMessageType('ReplayEntry', (_message.
   })
 _sym_db.RegisterMessage(ReplayEntry)
 
+ReplayKeyframe = _reflection.GeneratedProtocolMessageType('ReplayKeyframe', (_message.Message,), {
+  'DESCRIPTOR' : _REPLAYKEYFRAME,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.ReplayKeyframe)
+  })
+_sym_db.RegisterMessage(ReplayKeyframe)
+
+ReplayIndex = _reflection.GeneratedProtocolMessageType('ReplayIndex', (_message.Message,), {
+  'DESCRIPTOR' : _REPLAYINDEX,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.ReplayIndex)
+  })
+_sym_db.RegisterMessage(ReplayIndex)
+
+ReplayIndexEntry = _reflection.GeneratedProtocolMessageType('ReplayIndexEntry', (_message.Message,), {
+  'DESCRIPTOR' : _REPLAYINDEXENTRY,
+  '__module__' : 'visualize_grpc_pb2'
+  # @@protoc_insertion_point(class_scope:visualize_grpc_pb.ReplayIndexEntry)
+  })
+_sym_db.RegisterMessage(ReplayIndexEntry)
+
 Empty = _reflection.GeneratedProtocolMessageType('Empty', (_message.Message,), {
   'DESCRIPTOR' : _EMPTY,
   '__module__' : 'visualize_grpc_pb2'
@@ -1795,8 +2449,8 @@ _VISUALIZEGRPCSERVICE = _descriptor.ServiceDescriptor(
   index=0,
   serialized_options=None,
   create_key=_descriptor._internal_create_key,
-  serialized_start=3429,
-  serialized_end=3620,
+  serialized_start=4693,
+  serialized_end=4967,
   methods=[
   _descriptor.MethodDescriptor(
     name='Visualize',
@@ -1818,6 +2472,16 @@ _VISUALIZEGRPCSERVICE = _descriptor.ServiceDescriptor(
     serialized_options=None,
     create_key=_descriptor._internal_create_key,
   ),
+  _descriptor.MethodDescriptor(
+    name='NodeLogs',
+    full_name='visualize_grpc_pb.VisualizeGrpcService.NodeLogs',
+    index=2,
+    containing_service=None,
+    input_type=_NODELOGSREQUEST,
+    output_type=_NODELOGENTRY,
+    serialized_options=None,
+    create_key=_descriptor._internal_create_key,
+  ),
 ])
 _sym_db.RegisterServiceDescriptor(_VISUALIZEGRPCSERVICE)
 
@@ -113,6 +113,99 @@ class BasicTests(OTNSTestCase):
             self.assertFormPartitions(1)
             self.assertEqual(rloc16, ns.get_rloc16(nodeid))
 
+    def testRebootNode(self):
+        ns = self.ns
+        ns.add("router")
+        self.go(3)
+        self.assertEqual(ns.get_state(1), "leader")
+
+        for type in ("router", "fed", "med", "sed"):
+            nodeid = ns.add(type, x=100, y=100)
+            self.go(10)
+            self.assertFormPartitions(1)
+            rloc16 = ns.get_rloc16(nodeid)
+
+            ns.reboot(nodeid)
+            self.go(1)
+            self.assertFormPartitions(1)
+            self.assertEqual(rloc16, ns.get_rloc16(nodeid))
+            self.assertEqual(100, ns.nodes()[nodeid]['x'])
+
+    def testPowerCycleNode(self):
+        ns = self.ns
+        ns.add("router")
+        ns.add("router")
+        self.go(10)
+        self.assertFormPartitions(1)
+
+        ns.powercycle(2, off_time=30)
+        self.go(10)
+        self.assertEqual('disabled', ns.nodes()[2]['state'])
+        self.go(30)
+        self.assertFormPartitions(1)
+        self.assertEqual(2, len(ns.nodes()))
+
+    def testFactoryResetNode(self):
+        ns = self.ns
+        ns.add("router")
+        ns.add("router")
+        self.go(10)
+        self.assertFormPartitions(1)
+
+        ns.factoryreset(2)
+        self.go(10)
+        self.assertFormPartitions(1)
+
+    def testLogs(self):
+        ns = self.ns
+        ns.add("router")
+        self.go(10)
+
+        logs = ns.logs(1)
+        self.assertTrue(logs)
+        self.assertTrue(all(0 <= ts <= 10 for ts, _ in logs))
+        self.assertEqual(logs[-1:], ns.logs(1, last=1))
+        self.assertTrue(all('MLE' in log for _, log in ns.logs(1, grep='MLE')))
+        self.assertEqual([], ns.logs(2))
+
+    def testLogLevelsAndTriggers(self):
+        ns = self.ns
+        ns.loglevel('warn')
+        ns.loglevel('debg', nodeid=1, region='MLE')
+        self.assertEqual([(None, None, 'WARN'), (1, 'MLE', 'DEBG')], ns.loglevel())
+        ns.loglevel('default', nodeid=1, region='MLE')
+        self.assertEqual([(None, None, 'WARN')], ns.loglevel())
+
+        tid = ns.add_logtrigger('count', region='MLE')
+        ns.add("router")
+        self.go(10)
+        self.assertTrue(all(log.split(']', 1)[0] in ('[NONE', '[CRIT', '[WARN') for _, log in ns.logs(1)))
+
+        triggers = ns.logtriggers()
+        self.assertEqual(1, len(triggers))
+        self.assertEqual(tid, triggers[0]['id'])
+        self.assertGreater(triggers[0]['count'], 0)
+        ns.del_logtrigger(tid)
+        self.assertEqual([], ns.logtriggers())
+
+    def testNetState(self):
+        ns = self.ns
+        ns.add("router")
+        self.go(10)
+
+        ns.prefix_add(1, 'fd00:dead:beef:cafe::/64')
+        self.go(10)
+
+        state = ns.netstate(1)
+        self.assertEqual(ns.get_rloc16(1) >> 10, state['leader'])
+        self.assertEqual(0, state['key_seq'])
+        self.assertEqual(int(ns.node_cmd(1, 'channel')[0]), state['channel'])
+        self.assertEqual(1, len(state['prefixes']))
+        self.assertTrue(state['prefixes'][0].startswith('fd00:dead:beef:cafe::/64 '))
+        self.assertEqual([], state['routes'])
+        self.assertEqual([], state['srp_hosts'])
+        self.assertEqual([], state['srp_services'])
+
     def testDelNode(self):
         ns = self.ns
         ns.add("router")
@@ -162,6 +255,28 @@ class BasicTests(OTNSTestCase):
         self.go(10)
         self.assertFormPartitions(2)
 
+    def testGoUntil(self):
+        ns = self.ns
+        ns.add("router")
+        ns.add("router")
+        ns.add("router", 0, 300)
+
+        t1 = ns.go_until('role 1 leader', timeout=30)
+        self.assertGreater(t1, 0)
+        self.assertEqual(ns.get_state(1), 'leader')
+
+        t2 = ns.go_until('role 2 router', timeout=300)
+        self.assertGreater(t2, t1)
+        self.assertEqual(ns.get_state(2), 'router')
+
+        ns.go_until('attached 1 2', timeout=10)
+        ns.go_until('partitions 2', timeout=10)
+        with self.assertRaises(errors.OTNSCliError):
+            ns.go_until('partitions 1', timeout=10)
+
+        ns.ping(1, 2)
+        ns.go_until('ping 1 2', timeout=10)
+
     def testNodeFailRecover(self):
         ns = self.ns
         ns.add("router")
@@ -25,6 +25,7 @@
 # ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
 # POSSIBILITY OF SUCH DAMAGE.
 
+import ipaddress
 import tracemalloc
 import unittest
 
@@ -45,6 +46,13 @@ class BasicTests(OTNSTestCase):
             ns.ping(2, 1, datasize=10)
             ns.go(1)
 
+        stats = ns.ping_stats()
+        self.assertEqual(2, len(stats))
+        for s in stats:
+            assert s['node'] in (1, 2)
+            assert 0 < s['sent'] <= 100
+            assert s['min'] <= s['p50'] <= s['p95'] <= s['p99'] <= s['max']
+
         pings = ns.pings()
         self.assertTrue(pings)
         for srcid, dst, datasize, delay in pings:
@@ -53,6 +61,41 @@ class BasicTests(OTNSTestCase):
 
         self.assertFalse(ns.pings())
 
+    def testPingMulticast(self):
+        ns = self.ns
+        for _ in range(3):
+            ns.add("router")
+        ns.go(10)
+
+        # each multicast ping is replied by the other nodes
+        ns.ping(1, "ff03::1", datasize=10)
+        ns.go(11)
+
+        pings = ns.pings()
+        self.assertEqual(2, len(pings))
+        for srcid, dst, datasize, delay in pings:
+            self.assertEqual((1, ipaddress.IPv6Address('ff03::1'), 10), (srcid, ipaddress.IPv6Address(dst), datasize))
+            self.assertLess(delay, 10000)
+
+    def testPingTimeout(self):
+        ns = self.ns
+        ns.add("router")
+        ns.go(5)
+
+        # nobody replies the ping, so it is recorded as lost after the ping timeout
+        ns.ping(1, "fdde:ad00:beef:0:0:ff:fe00:fc10", datasize=10)
+        ns.go(5)
+        self.assertFalse(ns.pings())
+        ns.go(6)
+
+        stats = ns.ping_stats()
+        self.assertEqual(1, len(stats))
+        self.assertEqual((1, 1, 1, 100.0), (stats[0]['node'], stats[0]['sent'], stats[0]['lost'], stats[0]['loss']))
+        self.assertNotIn('avg', stats[0])
+        pings = ns.pings()
+        self.assertEqual(1, len(pings))
+        self.assertEqual(10000, pings[0][3])
+
 
 if __name__ == '__main__':
     unittest.main()
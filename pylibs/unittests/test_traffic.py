@@ -0,0 +1,95 @@
+#!/usr/bin/env python3
+# Copyright (c) 2020, The OTNS Authors.
+# All rights reserved.
+#
+# Redistribution and use in source and binary forms, with or without
+# modification, are permitted provided that the following conditions are met:
+# 1. Redistributions of source code must retain the above copyright
+#    notice, this list of conditions and the following disclaimer.
+# 2. Redistributions in binary form must reproduce the above copyright
+#    notice, this list of conditions and the following disclaimer in the
+#    documentation and/or other materials provided with the distribution.
+# 3. Neither the name of the copyright holder nor the
+#    names of its contributors may be used to endorse or promote products
+#    derived from this software without specific prior written permission.
+#
+# THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+# AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+# IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+# ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+# LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+# CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+# SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+# INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+# CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+# ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+# POSSIBILITY OF SUCH DAMAGE.
+
+import tracemalloc
+import unittest
+
+from OTNSTestCase import OTNSTestCase
+
+tracemalloc.start()
+
+
+class TrafficTests(OTNSTestCase):
+
+    def testUnicastTraffic(self):
+        ns = self.ns
+        ns.add("router")
+        ns.add("router")
+        ns.go(10)
+
+        flowid = ns.add_traffic('cbr', 1, 2, rate=10, size=40, count=50)
+        ns.go(10)
+
+        flows = ns.traffic()
+        self.assertEqual(1, len(flows))
+        flow = flows[0]
+        self.assertEqual((flowid, 'cbr', 1, False, 50, 2000),
+                         (flow['id'], flow['pattern'], flow['src'], flow['running'], flow['sent'], flow['sent_bytes']))
+        self.assertEqual(1, len(flow['receivers']))
+        receiver = flow['receivers'][0]
+        self.assertEqual(2, receiver['node'])
+        self.assertGreater(receiver['received'], 0)
+        self.assertLessEqual(receiver['min'], receiver['avg'])
+        self.assertLessEqual(receiver['avg'], receiver['max'])
+
+        ns.del_traffic(flowid)
+        self.assertEqual([], ns.traffic())
+
+    def testMulticastTraffic(self):
+        ns = self.ns
+        for _ in range(3):
+            ns.add("router")
+        ns.go(10)
+
+        flowid = ns.add_traffic('burst', 1, 'ff03::1', rate=1, burst=3)
+        ns.add_traffic('poisson', 2, 'ff03::1', rate=2)
+        ns.go(5)
+        ns.stop_traffic(flowid)
+        ns.go(1)
+
+        flows = ns.traffic()
+        self.assertEqual(2, len(flows))
+        self.assertFalse(flows[0]['running'])
+        self.assertTrue(flows[1]['running'])
+        self.assertEqual(15, flows[0]['sent'])
+        # all other nodes are subscribed to ff03::1, so they are expected to receive multicast datagrams
+        self.assertEqual([2, 3], [r['node'] for r in flows[0]['receivers']])
+        self.assertEqual([1, 3], [r['node'] for r in flows[1]['receivers']])
+
+        deliveries = [d for d in ns.mcasts() if d['flow'] == flowid]
+        self.assertEqual(list(range(1, 16)), [d['seq'] for d in deliveries])
+        for d in deliveries:
+            self.assertEqual(1, d['src'])
+            self.assertTrue(0 <= d['delivery'] <= 1)
+            for r in d['receivers']:
+                self.assertIn(r['dst'], (2, 3))
+                self.assertEqual(r['time'] - d['time'], r['latency'])
+        self.assertEqual([], ns.mcasts())
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,466 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+)
+
+// ChildTableEntry is a row of `child table`.
+type ChildTableEntry struct {
+	Id            int
+	Rloc16        uint16
+	Timeout       int
+	Age           int
+	LinkQualityIn int
+	Mode          NodeMode
+	ExtAddr       uint64
+	// Version is the Thread version of the child, or 0 if not printed by the OpenThread version
+	Version int
+}
+
+// RouterTableEntry is a row of `router table`.
+type RouterTableEntry struct {
+	Id             int
+	Rloc16         uint16
+	NextHop        int
+	PathCost       int
+	LinkQualityIn  int
+	LinkQualityOut int
+	Age            int
+	ExtAddr        uint64
+}
+
+// NeighborTableEntry is a row of `neighbor table`.
+type NeighborTableEntry struct {
+	// Role is OtDeviceRoleChild or OtDeviceRoleRouter
+	Role     OtDeviceRole
+	Rloc16   uint16
+	Age      int
+	AvgRssi  int
+	LastRssi int
+	Mode     NodeMode
+	ExtAddr  uint64
+}
+
+// IpAddrScope is the IPv6 address scope (RFC 4291 and RFC 7346).
+type IpAddrScope int
+
+const (
+	IpAddrScopeInterfaceLocal IpAddrScope = 0x1
+	IpAddrScopeLinkLocal      IpAddrScope = 0x2
+	// IpAddrScopeRealmLocal is the scope of the Thread mesh.
+	IpAddrScopeRealmLocal   IpAddrScope = 0x3
+	IpAddrScopeAdminLocal   IpAddrScope = 0x4
+	IpAddrScopeSiteLocal    IpAddrScope = 0x5
+	IpAddrScopeOrganization IpAddrScope = 0x8
+	IpAddrScopeGlobal       IpAddrScope = 0xe
+)
+
+func (s IpAddrScope) String() string {
+	switch s {
+	case IpAddrScopeInterfaceLocal:
+		return "interface-local"
+	case IpAddrScopeLinkLocal:
+		return "link-local"
+	case IpAddrScopeRealmLocal:
+		return "realm-local"
+	case IpAddrScopeAdminLocal:
+		return "admin-local"
+	case IpAddrScopeSiteLocal:
+		return "site-local"
+	case IpAddrScopeOrganization:
+		return "organization-local"
+	case IpAddrScopeGlobal:
+		return "global"
+	default:
+		return strconv.Itoa(int(s))
+	}
+}
+
+// IpAddr is an IPv6 address printed by `ipaddr` or `ipmaddr`.
+type IpAddr struct {
+	IP    net.IP
+	Type  AddrType
+	Scope IpAddrScope
+}
+
+func (a IpAddr) String() string {
+	return a.IP.String()
+}
+
+// MacCounters is the output of `counters mac`.
+type MacCounters struct {
+	TxTotal                uint64
+	TxUnicast              uint64
+	TxBroadcast            uint64
+	TxAckRequested         uint64
+	TxAcked                uint64
+	TxNoAckRequested       uint64
+	TxData                 uint64
+	TxDataPoll             uint64
+	TxBeacon               uint64
+	TxBeaconRequest        uint64
+	TxOther                uint64
+	TxRetry                uint64
+	TxErrCca               uint64
+	TxErrAbort             uint64
+	TxErrBusyChannel       uint64
+	RxTotal                uint64
+	RxUnicast              uint64
+	RxBroadcast            uint64
+	RxData                 uint64
+	RxDataPoll             uint64
+	RxBeacon               uint64
+	RxBeaconRequest        uint64
+	RxOther                uint64
+	RxAddressFiltered      uint64
+	RxDestAddrFiltered     uint64
+	RxDuplicated           uint64
+	RxErrNoFrame           uint64
+	RxErrNoUnknownNeighbor uint64
+	RxErrInvalidSrcAddr    uint64
+	RxErrSec               uint64
+	RxErrFcs               uint64
+	RxErrOther             uint64
+}
+
+// MleCounters is the output of `counters mle`.
+type MleCounters struct {
+	RoleDisabled                  uint64
+	RoleDetached                  uint64
+	RoleChild                     uint64
+	RoleRouter                    uint64
+	RoleLeader                    uint64
+	AttachAttempts                uint64
+	PartitionIdChanges            uint64
+	BetterPartitionAttachAttempts uint64
+	ParentChanges                 uint64
+}
+
+// tableRow is a row of a CLI table, which keeps the first error of reading the columns.
+type tableRow struct {
+	cols map[string]string
+	err  error
+}
+
+func (r *tableRow) str(name string) string {
+	v, ok := r.cols[name]
+	if !ok && r.err == nil {
+		r.err = errors.Errorf("missing column %#v", name)
+	}
+	return v
+}
+
+func (r *tableRow) int(name string) int {
+	s := r.str(name)
+	if r.err != nil {
+		return 0
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		r.err = errors.Wrapf(err, "column %#v", name)
+	}
+	return v
+}
+
+// optionalInt returns 0 if the column is not printed by the OpenThread version.
+func (r *tableRow) optionalInt(name string) int {
+	if _, ok := r.cols[name]; !ok {
+		return 0
+	}
+	return r.int(name)
+}
+
+func (r *tableRow) rloc16(name string) uint16 {
+	s := r.str(name)
+	if r.err != nil {
+		return 0
+	}
+
+	v, err := ParseRloc16(s)
+	if err != nil {
+		r.err = errors.Wrapf(err, "column %#v", name)
+	}
+	return v
+}
+
+func (r *tableRow) extAddr(name string) uint64 {
+	s := r.str(name)
+	if r.err != nil {
+		return 0
+	}
+
+	v, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		r.err = errors.Wrapf(err, "column %#v", name)
+	}
+	return v
+}
+
+// flag returns if a mode flag column (e.g. `R`) is 1, or false if the column is not printed.
+func (r *tableRow) flag(name string) bool {
+	return r.cols[name] == "1"
+}
+
+func (r *tableRow) mode() NodeMode {
+	return NodeMode{
+		RxOnWhenIdle:     r.flag("R"),
+		FullThreadDevice: r.flag("D"),
+		FullNetworkData:  r.flag("N"),
+	}
+}
+
+// parseTable parses a table printed by the OpenThread CLI, which starts with a header row of column names
+// (e.g. `| ID  | RLOC16 |`) and a separator row (e.g. `+-----+--------+`). The columns are looked up by name, so that
+// columns added by newer OpenThread versions are ignored.
+func parseTable(output []string) ([]*tableRow, error) {
+	var header []string
+	var rows []*tableRow
+
+	for _, line := range output {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "+") || line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "|") {
+			return nil, errors.Errorf("unexpected table line: %#v", line)
+		}
+
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+
+		if header == nil {
+			header = cells
+			continue
+		}
+
+		if len(cells) != len(header) {
+			return nil, errors.Errorf("expected %d columns, but read %d: %#v", len(header), len(cells), line)
+		}
+
+		row := &tableRow{cols: map[string]string{}}
+		for i, name := range header {
+			row.cols[name] = cells[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ParseChildTable parses the output of `child table`.
+func ParseChildTable(output []string) ([]ChildTableEntry, error) {
+	rows, err := parseTable(output)
+	if err != nil {
+		return nil, err
+	}
+
+	table := []ChildTableEntry{}
+	for _, row := range rows {
+		entry := ChildTableEntry{
+			Id:            row.int("ID"),
+			Rloc16:        row.rloc16("RLOC16"),
+			Timeout:       row.int("Timeout"),
+			Age:           row.int("Age"),
+			LinkQualityIn: row.int("LQ In"),
+			Mode:          row.mode(),
+			ExtAddr:       row.extAddr("Extended MAC"),
+			Version:       row.optionalInt("Ver"),
+		}
+
+		if row.err != nil {
+			return nil, errors.Wrapf(row.err, "child table")
+		}
+		table = append(table, entry)
+	}
+
+	return table, nil
+}
+
+// ParseRouterTable parses the output of `router table`.
+func ParseRouterTable(output []string) ([]RouterTableEntry, error) {
+	rows, err := parseTable(output)
+	if err != nil {
+		return nil, err
+	}
+
+	table := []RouterTableEntry{}
+	for _, row := range rows {
+		entry := RouterTableEntry{
+			Id:             row.int("ID"),
+			Rloc16:         row.rloc16("RLOC16"),
+			NextHop:        row.int("Next Hop"),
+			PathCost:       row.int("Path Cost"),
+			LinkQualityIn:  row.int("LQ In"),
+			LinkQualityOut: row.int("LQ Out"),
+			Age:            row.int("Age"),
+			ExtAddr:        row.extAddr("Extended MAC"),
+		}
+
+		if row.err != nil {
+			return nil, errors.Wrapf(row.err, "router table")
+		}
+		table = append(table, entry)
+	}
+
+	return table, nil
+}
+
+// ParseNeighborTable parses the output of `neighbor table`.
+func ParseNeighborTable(output []string) ([]NeighborTableEntry, error) {
+	rows, err := parseTable(output)
+	if err != nil {
+		return nil, err
+	}
+
+	table := []NeighborTableEntry{}
+	for _, row := range rows {
+		entry := NeighborTableEntry{
+			Rloc16:   row.rloc16("RLOC16"),
+			Age:      row.int("Age"),
+			AvgRssi:  row.int("Avg RSSI"),
+			LastRssi: row.int("Last RSSI"),
+			Mode:     row.mode(),
+			ExtAddr:  row.extAddr("Extended MAC"),
+		}
+
+		switch role := row.str("Role"); role {
+		case "C":
+			entry.Role = OtDeviceRoleChild
+		case "R":
+			entry.Role = OtDeviceRoleRouter
+		default:
+			if row.err == nil {
+				row.err = errors.Errorf("unexpected role: %#v", role)
+			}
+		}
+
+		if row.err != nil {
+			return nil, errors.Wrapf(row.err, "neighbor table")
+		}
+		table = append(table, entry)
+	}
+
+	return table, nil
+}
+
+// ParseIpAddrs parses the output of `ipaddr` or `ipmaddr`. The mesh-local prefix is used to tell the ML-EID, RLOC and
+// ALOC from other unicast addresses, which are treated as global addresses if the prefix is nil.
+func ParseIpAddrs(output []string, meshLocalPrefix *net.IPNet) ([]IpAddr, error) {
+	addrs := []IpAddr{}
+
+	for _, line := range output {
+		s := strings.TrimSpace(line)
+		ip := net.ParseIP(s)
+		if ip == nil || ip.To4() != nil {
+			return nil, errors.Errorf("invalid IPv6 address: %#v", s)
+		}
+
+		addrs = append(addrs, classifyIpAddr(ip, meshLocalPrefix))
+	}
+
+	return addrs, nil
+}
+
+// MeshLocalPrefix returns the mesh-local prefix of the ML-EID.
+func MeshLocalPrefix(mleid net.IP) *net.IPNet {
+	mask := net.CIDRMask(64, 128)
+	return &net.IPNet{IP: mleid.Mask(mask), Mask: mask}
+}
+
+func classifyIpAddr(ip net.IP, meshLocalPrefix *net.IPNet) IpAddr {
+	addr := IpAddr{IP: ip}
+
+	switch {
+	case ip.IsMulticast():
+		addr.Type = AddrTypeMulticast
+		addr.Scope = IpAddrScope(ip[1] & 0x0f)
+	case ip.IsLinkLocalUnicast():
+		addr.Type = AddrTypeLinkLocal
+		addr.Scope = IpAddrScopeLinkLocal
+	case meshLocalPrefix != nil && meshLocalPrefix.Contains(ip):
+		addr.Scope = IpAddrScopeRealmLocal
+		// the RLOC and ALOC use the IID 0000:00ff:fe00:<rloc16 or aloc16>
+		if ip[8] == 0 && ip[9] == 0 && ip[10] == 0 && ip[11] == 0xff && ip[12] == 0xfe && ip[13] == 0 {
+			if ip[14] >= 0xfc {
+				addr.Type = AddrTypeAloc
+			} else {
+				addr.Type = AddrTypeRloc
+			}
+		} else {
+			addr.Type = AddrTypeMleid
+		}
+	default:
+		addr.Type = AddrTypeGlobal
+		addr.Scope = IpAddrScopeGlobal
+	}
+
+	return addr
+}
+
+// ParseCounters parses the output of `counters mac` or `counters mle` into MacCounters or MleCounters.
+// Each line `<name>: <value>` sets the field named after the counter without spaces (e.g. `Role Detached` sets
+// RoleDetached). Counters without a field are ignored.
+func ParseCounters(output []string, counters interface{}) error {
+	v := reflect.ValueOf(counters)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("counters must be a pointer to struct: %T", counters)
+	}
+	v = v.Elem()
+
+	for _, line := range output {
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			return errors.Errorf("unexpected counter line: %#v", line)
+		}
+
+		name := strings.ReplaceAll(strings.TrimSpace(kv[0]), " ", "")
+		field := v.FieldByName(name)
+		if !field.IsValid() || field.Kind() != reflect.Uint64 {
+			continue
+		}
+
+		value, err := strconv.ParseUint(strings.TrimSpace(kv[1]), 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "counter %s", name)
+		}
+		field.SetUint(value)
+	}
+
+	return nil
+}
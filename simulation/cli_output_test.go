@@ -0,0 +1,177 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"net"
+	"testing"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChildTable(t *testing.T) {
+	table, err := ParseChildTable([]string{
+		"| ID  | RLOC16 | Timeout    | Age        | LQ In | C_VN |R|S|D|N| Extended MAC     |",
+		"+-----+--------+------------+------------+-------+------+-+-+-+-+------------------+",
+		"|   1 | 0xc801 |        240 |         24 |     3 |  131 |1|0|0|1| 4ecede68435358ac |",
+		"|   2 | 0xc802 |        240 |          2 |     3 |  131 |0|0|0|0| a672a601d2ce37d8 |",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []ChildTableEntry{
+		{Id: 1, Rloc16: 0xc801, Timeout: 240, Age: 24, LinkQualityIn: 3, Mode: NodeMode{RxOnWhenIdle: true, FullNetworkData: true}, ExtAddr: 0x4ecede68435358ac},
+		{Id: 2, Rloc16: 0xc802, Timeout: 240, Age: 2, LinkQualityIn: 3, ExtAddr: 0xa672a601d2ce37d8},
+	}, table)
+
+	// newer OpenThread versions print more columns
+	table, err = ParseChildTable([]string{
+		"| ID  | RLOC16 | Timeout    | Age        | LQ In | C_VN |R|D|N|Ver|CSL|QMsgCnt| Extended MAC     |",
+		"+-----+--------+------------+------------+-------+------+-+-+-+---+---+-------+------------------+",
+		"|   1 | 0xc801 |        240 |         24 |     3 |  131 |1|1|1|  3| 0 |     0 | 4ecede68435358ac |",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, table[0].Version)
+	assert.Equal(t, DefaultNodeMode(), table[0].Mode)
+
+	table, err = ParseChildTable([]string{
+		"| ID  | RLOC16 |",
+		"+-----+--------+",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(table))
+
+	_, err = ParseChildTable([]string{
+		"| ID  | RLOC16 | Timeout    | Age        | LQ In | C_VN |R|S|D|N| Extended MAC     |",
+		"|   1 | 0xc801 |        240 |",
+	})
+	assert.NotNil(t, err)
+}
+
+func TestParseRouterTable(t *testing.T) {
+	table, err := ParseRouterTable([]string{
+		"| ID | RLOC16 | Next Hop | Path Cost | LQ In | LQ Out | Age | Extended MAC     |",
+		"+----+--------+----------+-----------+-------+--------+-----+------------------+",
+		"| 21 | 0x5400 |       21 |         0 |     3 |      3 |   5 | d28d7f875888fccb |",
+		"| 56 | 0xe000 |       56 |         0 |     0 |      0 | 182 | f2d92a82c8d8fe43 |",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []RouterTableEntry{
+		{Id: 21, Rloc16: 0x5400, NextHop: 21, LinkQualityIn: 3, LinkQualityOut: 3, Age: 5, ExtAddr: 0xd28d7f875888fccb},
+		{Id: 56, Rloc16: 0xe000, NextHop: 56, Age: 182, ExtAddr: 0xf2d92a82c8d8fe43},
+	}, table)
+}
+
+func TestParseNeighborTable(t *testing.T) {
+	table, err := ParseNeighborTable([]string{
+		"| Role | RLOC16 | Age | Avg RSSI | Last RSSI |R|S|D|N| Extended MAC     |",
+		"+------+--------+-----+----------+-----------+-+-+-+-+------------------+",
+		"|   C  | 0xcc01 |  96 |      -46 |       -46 |1|1|1|1| 1eb9ba8a6522636b |",
+		"|   R  | 0xc800 |   2 |      -29 |       -29 |1|0|1|1| 9a91556102c39ddb |",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []NeighborTableEntry{
+		{Role: OtDeviceRoleChild, Rloc16: 0xcc01, Age: 96, AvgRssi: -46, LastRssi: -46, Mode: DefaultNodeMode(), ExtAddr: 0x1eb9ba8a6522636b},
+		{Role: OtDeviceRoleRouter, Rloc16: 0xc800, Age: 2, AvgRssi: -29, LastRssi: -29, Mode: DefaultNodeMode(), ExtAddr: 0x9a91556102c39ddb},
+	}, table)
+
+	_, err = ParseNeighborTable([]string{
+		"| Role | RLOC16 | Age | Avg RSSI | Last RSSI |R|S|D|N| Extended MAC     |",
+		"|   X  | 0xcc01 |  96 |      -46 |       -46 |1|1|1|1| 1eb9ba8a6522636b |",
+	})
+	assert.NotNil(t, err)
+}
+
+func TestParseIpAddrs(t *testing.T) {
+	output := []string{
+		"fd00:db8:0:0:0:ff:fe00:fc00",
+		"fd00:db8:0:0:0:ff:fe00:c800",
+		"fd00:db8:0:0:6e8f:7b7f:2ab5:5e6f",
+		"2001:db8::1",
+		"fe80:0:0:0:3c33:5ff2:a1c4:3c5c",
+	}
+
+	_, prefix, _ := net.ParseCIDR("fd00:db8::/64")
+	addrs, err := ParseIpAddrs(output, prefix)
+	assert.Nil(t, err)
+
+	expected := []struct {
+		addrType AddrType
+		scope    IpAddrScope
+	}{
+		{AddrTypeAloc, IpAddrScopeRealmLocal},
+		{AddrTypeRloc, IpAddrScopeRealmLocal},
+		{AddrTypeMleid, IpAddrScopeRealmLocal},
+		{AddrTypeGlobal, IpAddrScopeGlobal},
+		{AddrTypeLinkLocal, IpAddrScopeLinkLocal},
+	}
+	assert.Equal(t, len(expected), len(addrs))
+	for i, e := range expected {
+		assert.Equal(t, e.addrType, addrs[i].Type, output[i])
+		assert.Equal(t, e.scope, addrs[i].Scope, output[i])
+		assert.True(t, net.ParseIP(output[i]).Equal(addrs[i].IP))
+	}
+
+	assert.Equal(t, "fd00:db8::/64", MeshLocalPrefix(addrs[2].IP).String())
+
+	maddrs, err := ParseIpAddrs([]string{"ff02::1", "ff03::fc", "ff33:40:fd00:db8:0:0:0:1"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, AddrTypeMulticast, maddrs[0].Type)
+	assert.Equal(t, IpAddrScopeLinkLocal, maddrs[0].Scope)
+	assert.Equal(t, IpAddrScopeRealmLocal, maddrs[1].Scope)
+	assert.Equal(t, IpAddrScopeRealmLocal, maddrs[2].Scope)
+
+	_, err = ParseIpAddrs([]string{"10.0.0.1"}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestParseCounters(t *testing.T) {
+	mac := &MacCounters{}
+	assert.Nil(t, ParseCounters([]string{
+		"TxTotal: 10",
+		"    TxUnicast: 3",
+		"    TxBroadcast: 7",
+		"    TxUnknownCounter: 1",
+		"RxTotal: 11",
+		"    RxErrFcs: 2",
+	}, mac))
+	assert.Equal(t, MacCounters{TxTotal: 10, TxUnicast: 3, TxBroadcast: 7, RxTotal: 11, RxErrFcs: 2}, *mac)
+
+	mle := &MleCounters{}
+	assert.Nil(t, ParseCounters([]string{
+		"Role Disabled: 0",
+		"Role Detached: 1",
+		"Role Leader: 1",
+		"Attach Attempts: 1",
+		"Partition Id Changes: 1",
+		"Better Partition Attach Attempts: 0",
+		"Parent Changes: 0",
+	}, mle))
+	assert.Equal(t, MleCounters{RoleDetached: 1, RoleLeader: 1, AttachAttempts: 1, PartitionIdChanges: 1}, *mle)
+
+	assert.NotNil(t, ParseCounters([]string{"TxTotal: x"}, mac))
+	assert.NotNil(t, ParseCounters([]string{"TxTotal: 1"}, *mac))
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"sync"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+const (
+	// stderrTailSize is the number of bytes of the node process stderr kept for crash reports.
+	stderrTailSize = 4096
+)
+
+// CrashRecord describes an unexpected exit of a node process.
+type CrashRecord struct {
+	NodeId NodeId
+	// Time is the virtual time (in us) when the crash was detected.
+	Time       uint64
+	ExitStatus string
+	// Stderr is the tail of the process stderr.
+	Stderr    string
+	Restarted bool
+}
+
+// tailBuffer is a writer that keeps the last bytes written to it.
+type tailBuffer struct {
+	sync.Mutex
+	data []byte
+	size int
+}
+
+func newTailBuffer(size int) *tailBuffer {
+	return &tailBuffer{size: size}
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.data = append(b.data, p...)
+	if len(b.data) > b.size {
+		b.data = append(b.data[:0], b.data[len(b.data)-b.size:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+
+	return string(b.data)
+}
+
+// OnNodeCrash records the crash of the node process and restarts the node according to its restart policy.
+// It is part of implementation of dispatcher.CallbackHandler.
+func (s *Simulation) OnNodeCrash(nodeid NodeId) {
+	node := s.nodes[nodeid]
+	if node == nil {
+		return
+	}
+
+	record := &CrashRecord{
+		NodeId:     nodeid,
+		Time:       s.d.CurTime,
+		ExitStatus: node.exitStatus(),
+		Stderr:     node.stderr.String(),
+	}
+	s.crashes = append(s.crashes, record)
+
+	if node.cfg.RestartPolicy != RestartOnCrash {
+		return
+	}
+
+	if !node.started {
+		// restarting would most likely crash again
+		simplelogger.Warnf("%v is not restarted: crashed on startup", node)
+		return
+	}
+
+	if node.cfg.MaxRestarts > 0 && node.restarts >= node.cfg.MaxRestarts {
+		simplelogger.Warnf("%v is not restarted: already restarted %d times", node, node.restarts)
+		return
+	}
+
+	// the crash might be detected in the middle of a node command, so restart the node in a separate task
+	s.PostAsync(false, func() {
+		if s.nodes[nodeid] != node {
+			// the node was deleted or already replaced
+			return
+		}
+
+		node.restarts++
+		if err := s.powerOnNode(node, true); err != nil {
+			simplelogger.Errorf("%v restart failed: %v", node, err)
+			return
+		}
+
+		record.Restarted = true
+		simplelogger.Infof("%v restarted (%d restarts)", node, node.restarts)
+	})
+}
+
+// Crashes returns the records of all node crashes in the order they were detected.
+func (s *Simulation) Crashes() []CrashRecord {
+	crashes := make([]CrashRecord, len(s.crashes))
+	for i, record := range s.crashes {
+		crashes[i] = *record
+	}
+	return crashes
+}
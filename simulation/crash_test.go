@@ -0,0 +1,69 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailBuffer(t *testing.T) {
+	b := newTailBuffer(8)
+	_, _ = b.Write([]byte("abc"))
+	assert.Equal(t, "abc", b.String())
+
+	_, _ = b.Write([]byte("defgh"))
+	assert.Equal(t, "abcdefgh", b.String())
+
+	n, err := b.Write([]byte("ijklmnopqrst"))
+	assert.Nil(t, err)
+	assert.Equal(t, 12, n)
+	assert.Equal(t, "mnopqrst", b.String())
+}
+
+func TestNode_CheckRunning(t *testing.T) {
+	node := &Node{
+		Id:           1,
+		stderr:       newTailBuffer(stderrTailSize),
+		pendingLines: make(chan string, 1),
+		exited:       make(chan struct{}),
+	}
+	assert.Nil(t, node.CheckRunning())
+
+	close(node.exited)
+	_, _ = node.stderr.Write([]byte("assertion failed\n"))
+	assert.EqualError(t, node.CheckRunning(), "Node<1> crashed: exit status 0: assertion failed")
+
+	found, _, err := node.TryExpectLine("Done", time.Second)
+	assert.False(t, found)
+	assert.NotNil(t, err)
+
+	node.exiting = 1
+	assert.EqualError(t, node.CheckRunning(), "Node<1> is not running")
+}
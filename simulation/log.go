@@ -0,0 +1,93 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"github.com/openthread/ot-ns/nodelog"
+	"github.com/openthread/ot-ns/otoutfilter"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// NodeLogs returns the captured OpenThread logs of all nodes.
+func (s *Simulation) NodeLogs() *nodelog.Capture {
+	return s.logs
+}
+
+// LogLevels returns the minimum log levels of nodes and regions.
+func (s *Simulation) LogLevels() *nodelog.Levels {
+	return s.logLevels
+}
+
+// LogTriggers returns the log triggers.
+func (s *Simulation) LogTriggers() *nodelog.Triggers {
+	return s.logTriggers
+}
+
+// onNodeLog handles a log line of the node. It is called from the line reader routine of the node.
+// Log lines of the virtual time UART are handled by onUartLog at the virtual time they were written, so only
+// log lines of the real time UART are handled here.
+func (s *Simulation) onNodeLog(node *Node, uartType NodeUartType, log *otoutfilter.Log) {
+	if uartType == NodeUartTypeVirtualTime {
+		return
+	}
+
+	for _, trigger := range s.logTriggers.Match(node.Id, log) {
+		if trigger.Action == nodelog.TriggerPause {
+			simplelogger.Warnf("log trigger %d pauses the simulation: %s - %s", trigger.Id, node, log.Raw)
+			// the real time UART output is not synchronized with the dispatcher, so post asynchronously
+			// so that the line reader never blocks on a busy dispatcher
+			go s.d.PostAsync(false, s.d.Pause)
+		}
+	}
+
+	// the real time UART output is not synchronized with the dispatcher, so stamp it with the latest virtual time
+	s.handleLog(node, log, s.d.GetCurTime())
+}
+
+// onUartLog handles a log line written to the virtual time UART of the node.
+// It is called from the dispatcher goroutine, so the log line is stamped and pause triggers pause the simulation at
+// the virtual time of the log line.
+func (s *Simulation) onUartLog(node *Node, log *otoutfilter.Log) {
+	for _, trigger := range s.logTriggers.Match(node.Id, log) {
+		if trigger.Action == nodelog.TriggerPause {
+			simplelogger.Warnf("log trigger %d pauses the simulation: %s - %s", trigger.Id, node, log.Raw)
+			s.d.Pause()
+		}
+	}
+
+	s.handleLog(node, log, s.d.CurTime)
+}
+
+// handleLog prints and captures the log line of the node if it passes the minimum log levels.
+func (s *Simulation) handleLog(node *Node, log *otoutfilter.Log, ts uint64) {
+	if !s.logLevels.Enabled(node.Id, log.Region, log.Level) {
+		return
+	}
+
+	otoutfilter.PrintLog(node.String(), log)
+	s.logs.Add(node.Id, ts, log.Raw)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/nodelog"
+	"github.com/openthread/ot-ns/otoutfilter"
+	"github.com/openthread/ot-ns/traffic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNode_OnUartWrite(t *testing.T) {
+	s := &Simulation{
+		d:           &dispatcher.Dispatcher{CurTime: 1000},
+		traffic:     traffic.NewGenerator(),
+		logs:        nodelog.NewCapture(0, ""),
+		logLevels:   nodelog.NewLevels(),
+		logTriggers: nodelog.NewTriggers(),
+	}
+	node := &Node{S: s, Id: 1}
+	reader, writer := io.Pipe()
+	node.virtualUartPipe = writer
+	output := make(chan string)
+	go func() {
+		b, _ := ioutil.ReadAll(reader)
+		output <- string(b)
+	}()
+
+	node.onUartWrite([]byte("[INFO]-MLE-----: Role detached -> ch"))
+	assert.Empty(t, s.logs.Query(1, nil, 0))
+
+	// log lines are stamped with the virtual time at which they are completed
+	s.d.CurTime = 2000
+	node.onUartWrite([]byte("ild\r\nDone\r\n[NOTE]-CORE----: Started\n"))
+	entries := s.logs.Query(1, nil, 0)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, uint64(2000), entries[0].Time)
+		assert.Equal(t, "[INFO]-MLE-----: Role detached -> child", entries[0].Line)
+		assert.Equal(t, uint64(2000), entries[1].Time)
+		assert.Equal(t, "[NOTE]-CORE----: Started", entries[1].Line)
+	}
+
+	// received datagrams of traffic flows are not command output
+	node.onUartWrite([]byte("> 20 bytes from fdde:ad00:beef:0:0:ff:fe00:fc00 49153 otns:1:1:xxxxxxxxxxx\r\n> "))
+
+	// log lines of the virtual time UART are captured by onUartLog only
+	s.d.CurTime = 3000
+	s.onNodeLog(node, NodeUartTypeVirtualTime, otoutfilter.ParseLog("[NOTE]-CORE----: Started"))
+	assert.Len(t, s.logs.Query(1, nil, 0), 2)
+
+	_ = writer.Close()
+	assert.Equal(t, "[INFO]-MLE-----: Role detached -> child\r\nDone\r\n[NOTE]-CORE----: Started\n", <-output)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// RefreshNetworkState reads the network state of the node by its CLI commands, unless the node pushes its network
+// state.
+func (s *Simulation) RefreshNetworkState(nodeid NodeId) error {
+	node := s.nodes[nodeid]
+	dnode := s.d.GetNode(nodeid)
+	if node == nil || dnode == nil {
+		return errors.Errorf("node %d not found", nodeid)
+	}
+
+	if dnode.NetworkStatePushed {
+		return nil
+	}
+
+	if err := node.CheckRunning(); err != nil {
+		return err
+	}
+
+	state, err := node.readNetworkState()
+	if err != nil {
+		return err
+	}
+
+	dnode.SetNetworkState(state)
+	return nil
+}
+
+func (node *Node) readNetworkState() (state NodeNetworkState, err error) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			err = errors.Errorf("%v", rerr)
+		}
+	}()
+
+	state = NewNodeNetworkState()
+	state.Channel = node.GetChannel()
+	state.KeySequence = uint32(node.GetKeySequenceCounter())
+
+	if role := node.GetState(); role == "disabled" || role == "detached" {
+		// the leader and network data are unknown until the node is attached
+		return
+	}
+
+	state.LeaderRouterId = node.GetLeaderData().LeaderRouterID
+
+	netdata, err := node.GetNetData()
+	if err != nil {
+		return
+	}
+	state.NetData = *netdata
+
+	// the SRP server is not supported by all OpenThread builds, whose SRP registrations are left empty
+	if output, cerr := node.tryCommand("srp server host"); cerr == nil {
+		if state.SrpHosts, err = ParseSrpServerHosts(output); err != nil {
+			return
+		}
+	}
+	if output, cerr := node.tryCommand("srp server service"); cerr == nil {
+		if state.SrpServices, err = ParseSrpServerServices(output); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// tryCommand runs the command, and returns the error of the command rather than panicking.
+func (node *Node) tryCommand(cmd string) (output []string, err error) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			simplelogger.Debugf("%v: %s failed: %v", node, cmd, rerr)
+			err = errors.Errorf("%v", rerr)
+		}
+	}()
+
+	return node.Command(cmd, DefaultCommandTimeout), nil
+}
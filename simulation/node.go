@@ -28,15 +28,17 @@ package simulation
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -44,6 +46,7 @@ import (
 
 	"github.com/openthread/ot-ns/otoutfilter"
 	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
 	"github.com/simonlingoogle/go-simplelogger"
 )
 
@@ -63,7 +66,7 @@ const (
 	NodeUartTypeVirtualTime NodeUartType = iota
 )
 
-func newNode(s *Simulation, id NodeId, cfg *NodeConfig) (*Node, error) {
+func newNode(s *Simulation, id NodeId, cfg *NodeConfig, process int) (*Node, error) {
 	var err error
 
 	if !cfg.Restore {
@@ -85,9 +88,13 @@ func newNode(s *Simulation, id NodeId, cfg *NodeConfig) (*Node, error) {
 		S:            s,
 		Id:           id,
 		cfg:          cfg,
+		process:      process,
 		cmd:          cmd,
 		pendingLines: make(chan string, 100),
 		uartType:     NodeUartTypeUndefined,
+		stderr:       newTailBuffer(stderrTailSize),
+		stdoutDone:   make(chan struct{}),
+		exited:       make(chan struct{}),
 	}
 
 	node.virtualUartReader, node.virtualUartPipe = io.Pipe()
@@ -110,8 +117,12 @@ func newNode(s *Simulation, id NodeId, cfg *NodeConfig) (*Node, error) {
 		return nil, err
 	}
 
-	go node.lineReader(node.pipeOut, NodeUartTypeRealTime)
+	go func() {
+		node.lineReader(node.pipeOut, NodeUartTypeRealTime)
+		close(node.stdoutDone)
+	}()
 	go node.lineReader(node.virtualUartReader, NodeUartTypeVirtualTime)
+	go node.waitRoutine()
 	return node, nil
 }
 
@@ -120,8 +131,21 @@ type Node struct {
 	Id  int
 	cfg *NodeConfig
 
-	cmd       *exec.Cmd
-	outputErr io.Reader
+	cmd *exec.Cmd
+	// process is the Process of the dispatcher node for this node process
+	process int
+	// stderr keeps the tail of the process stderr for crash reports
+	stderr     *tailBuffer
+	stdoutDone chan struct{}
+	// exited is closed when the process has exited, and exitErr is the result of waiting for it
+	exited   chan struct{}
+	exitErr  error
+	exiting  int32
+	started  bool
+	restarts int
+	// udpOpened and udpBound are set once the UDP socket of the OT CLI is opened and bound for traffic flows
+	udpOpened bool
+	udpBound  bool
 
 	pendingLines      chan string
 	pipeIn            io.WriteCloser
@@ -130,6 +154,8 @@ type Node struct {
 	virtualUartReader *io.PipeReader
 	virtualUartPipe   *io.PipeWriter
 	uartType          NodeUartType
+	// uartLine buffers the incomplete last line written to the virtual time UART
+	uartLine []byte
 }
 
 func (node *Node) String() string {
@@ -162,25 +188,82 @@ func (node *Node) Stop() {
 }
 
 func (node *Node) Exit() error {
-	node.inputCommand("exit")
-	_ = node.cmd.Process.Signal(syscall.SIGTERM)
+	atomic.StoreInt32(&node.exiting, 1)
+	if !node.IsExited() {
+		node.inputCommand("exit")
+		_ = node.cmd.Process.Signal(syscall.SIGTERM)
+	}
 	_ = node.virtualUartReader.Close()
 
-	err := node.cmd.Wait()
+	<-node.exited
 	node.S.Dispatcher().NotifyExit(node.Id)
 
-	return err
+	return node.exitErr
 }
 
-func (node *Node) AssurePrompt() {
-	node.inputCommand("")
-	if found, _ := node.TryExpectLine("", time.Second); found {
-		return
+// IsExited returns if the node process has exited.
+func (node *Node) IsExited() bool {
+	select {
+	case <-node.exited:
+		return true
+	default:
+		return false
 	}
+}
 
-	node.inputCommand("")
-	if found, _ := node.TryExpectLine("", time.Second); found {
-		return
+// CheckRunning returns an error if the node process is not running, because it was shut down or it has crashed.
+func (node *Node) CheckRunning() error {
+	if !node.IsExited() {
+		return nil
+	}
+
+	if atomic.LoadInt32(&node.exiting) != 0 {
+		return errors.Errorf("%s is not running", node)
+	}
+	return errors.Errorf("%s crashed: %s", node, node.exitReason())
+}
+
+// waitRoutine waits for the node process to exit, and notifies the dispatcher if the exit is unexpected.
+func (node *Node) waitRoutine() {
+	// all reads from the pipes must be completed before waiting for the process
+	_, _ = io.Copy(node.stderr, node.pipeErr)
+	<-node.stdoutDone
+
+	node.exitErr = node.cmd.Wait()
+	close(node.exited)
+
+	if atomic.LoadInt32(&node.exiting) == 0 {
+		node.S.Dispatcher().NotifyCrash(node.Id, node.process, node.exitReason())
+	}
+}
+
+// exitStatus returns the exit status of the exited node process.
+func (node *Node) exitStatus() string {
+	if node.exitErr != nil {
+		return node.exitErr.Error()
+	}
+	return "exit status 0"
+}
+
+// exitReason returns the exit status of the exited node process along with the tail of its stderr.
+func (node *Node) exitReason() string {
+	stderr := strings.TrimSpace(node.stderr.String())
+	if stderr == "" {
+		return node.exitStatus()
+	}
+	return fmt.Sprintf("%s: %s", node.exitStatus(), stderr)
+}
+
+func (node *Node) AssurePrompt() {
+	for i := 0; i < 2; i++ {
+		node.inputCommand("")
+		found, _, err := node.TryExpectLine("", time.Second)
+		if err != nil {
+			panic(err)
+		}
+		if found {
+			return
+		}
 	}
 
 	node.inputCommand("")
@@ -278,8 +361,36 @@ func (node *Node) GetChildList() (childlist []int) {
 	return
 }
 
-func (node *Node) GetChildTable() {
-	// todo: not implemented yet
+func (node *Node) GetChildTable() ([]ChildTableEntry, error) {
+	return ParseChildTable(node.Command("child table", DefaultCommandTimeout))
+}
+
+func (node *Node) GetRouterTable() ([]RouterTableEntry, error) {
+	return ParseRouterTable(node.Command("router table", DefaultCommandTimeout))
+}
+
+func (node *Node) GetNeighborTable() ([]NeighborTableEntry, error) {
+	return ParseNeighborTable(node.Command("neighbor table", DefaultCommandTimeout))
+}
+
+func (node *Node) GetNetData() (*NetData, error) {
+	return ParseNetData(node.Command("netdata show", DefaultCommandTimeout))
+}
+
+func (node *Node) GetMacCounters() (*MacCounters, error) {
+	counters := &MacCounters{}
+	if err := ParseCounters(node.Command("counters mac", DefaultCommandTimeout), counters); err != nil {
+		return nil, err
+	}
+	return counters, nil
+}
+
+func (node *Node) GetMleCounters() (*MleCounters, error) {
+	counters := &MleCounters{}
+	if err := ParseCounters(node.Command("counters mle", DefaultCommandTimeout), counters); err != nil {
+		return nil, err
+	}
+	return counters, nil
 }
 
 func (node *Node) GetChildTimeout() int {
@@ -346,19 +457,16 @@ func (node *Node) IfconfigDown() {
 }
 
 func (node *Node) GetIpAddr() []string {
-	// todo: parse IPv6 addresses
 	addrs := node.Command("ipaddr", DefaultCommandTimeout)
 	return addrs
 }
 
 func (node *Node) GetIpAddrLinkLocal() []string {
-	// todo: parse IPv6 addresses
 	addrs := node.Command("ipaddr linklocal", DefaultCommandTimeout)
 	return addrs
 }
 
 func (node *Node) GetIpAddrMleid() []string {
-	// todo: parse IPv6 addresses
 	addrs := node.Command("ipaddr mleid", DefaultCommandTimeout)
 	return addrs
 }
@@ -368,8 +476,32 @@ func (node *Node) GetIpAddrRloc() []string {
 	return addrs
 }
 
+// GetMeshLocalPrefix returns the mesh-local prefix of the ML-EID, or nil if the node has no ML-EID.
+func (node *Node) GetMeshLocalPrefix() (*net.IPNet, error) {
+	mleids, err := ParseIpAddrs(node.GetIpAddrMleid(), nil)
+	if err != nil || len(mleids) == 0 {
+		return nil, err
+	}
+
+	return MeshLocalPrefix(mleids[0].IP), nil
+}
+
+// GetIpAddrs returns the unicast addresses of the node with their types and scopes.
+func (node *Node) GetIpAddrs() ([]IpAddr, error) {
+	meshLocalPrefix, err := node.GetMeshLocalPrefix()
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseIpAddrs(node.GetIpAddr(), meshLocalPrefix)
+}
+
+// GetIpMaddrs returns the multicast addresses subscribed by the node with their scopes.
+func (node *Node) GetIpMaddrs() ([]IpAddr, error) {
+	return ParseIpAddrs(node.GetIpMaddr(), nil)
+}
+
 func (node *Node) GetIpMaddr() []string {
-	// todo: parse IPv6 addresses
 	addrs := node.Command("ipmaddr", DefaultCommandTimeout)
 	return addrs
 }
@@ -587,7 +719,9 @@ func (node *Node) GetSingleton() bool {
 
 func (node *Node) lineReader(reader io.Reader, uartType NodeUartType) {
 	// close the line channel after line reader routine exit
-	scanner := bufio.NewScanner(otoutfilter.NewOTOutFilter(bufio.NewReader(reader), node.String()))
+	scanner := bufio.NewScanner(otoutfilter.NewOTOutFilterWithLogHandler(bufio.NewReader(reader), func(log *otoutfilter.Log) {
+		node.S.onNodeLog(node, uartType, log)
+	}))
 	scanner.Split(bufio.ScanLines)
 
 	for scanner.Scan() {
@@ -598,6 +732,12 @@ func (node *Node) lineReader(reader io.Reader, uartType NodeUartType) {
 			node.uartType = uartType
 		}
 
+		// received datagrams of traffic flows are matched by onUartLine at the virtual time they are written to the
+		// virtual time UART, but the real time UART output is not synchronized with the dispatcher
+		if uartType == NodeUartTypeRealTime && node.S.traffic.HandleLine(node.Id, line, node.S.d.GetCurTime()) {
+			continue
+		}
+
 		select {
 		case node.pendingLines <- line:
 			break
@@ -614,7 +754,9 @@ func (node *Node) lineReader(reader io.Reader, uartType NodeUartType) {
 	}
 }
 
-func (node *Node) TryExpectLine(line interface{}, timeout time.Duration) (bool, []string) {
+// TryExpectLine reads the node output until the line is found or the timeout expires.
+// It returns an error if the node process is not running.
+func (node *Node) TryExpectLine(line interface{}, timeout time.Duration) (bool, []string, error) {
 	var outputLines []string
 
 	deadline := time.After(timeout)
@@ -622,12 +764,10 @@ func (node *Node) TryExpectLine(line interface{}, timeout time.Duration) (bool,
 	for {
 		select {
 		case <-deadline:
-			return false, outputLines
+			return false, outputLines, nil
 		case readLine, ok := <-node.pendingLines:
 			if !ok {
-				errmsg, _ := ioutil.ReadAll(node.outputErr)
-				simplelogger.Panicf("%s EOF: %s", node, string(errmsg))
-				return false, outputLines
+				return false, outputLines, errors.Errorf("%s EOF: %s", node, node.stderr.String())
 			}
 
 			simplelogger.Debugf("%v - %s", node, readLine)
@@ -635,7 +775,7 @@ func (node *Node) TryExpectLine(line interface{}, timeout time.Duration) (bool,
 			outputLines = append(outputLines, readLine)
 			if node.isLineMatch(readLine, line) {
 				// found the exact line
-				return true, outputLines
+				return true, outputLines, nil
 			} else {
 				// hack: output scan result here, should have better implementation
 				//| J | Network Name     | Extended PAN     | PAN  | MAC Address      | Ch | dBm | LQI |
@@ -644,13 +784,19 @@ func (node *Node) TryExpectLine(line interface{}, timeout time.Duration) (bool,
 				}
 			}
 		default:
+			if err := node.CheckRunning(); err != nil {
+				return false, outputLines, err
+			}
 			node.S.Dispatcher().RecvEvents()
 		}
 	}
 }
 
 func (node *Node) expectLine(line interface{}, timeout time.Duration) []string {
-	found, output := node.TryExpectLine(line, timeout)
+	found, output, err := node.TryExpectLine(line, timeout)
+	if err != nil {
+		panic(err)
+	}
 	if !found {
 		simplelogger.Panicf("expect line timeout: %#v", line)
 	}
@@ -677,6 +823,20 @@ func (node *Node) Ping(addr string, payloadSize int, count int, interval int, ho
 	node.AssurePrompt()
 }
 
+func (node *Node) UdpOpen() {
+	node.Command("udp open", DefaultCommandTimeout)
+	node.udpOpened = true
+}
+
+func (node *Node) UdpBind(port int) {
+	node.Command(fmt.Sprintf("udp bind :: %d", port), DefaultCommandTimeout)
+	node.udpBound = true
+}
+
+func (node *Node) UdpSend(addr string, port int, payload string) {
+	node.Command(fmt.Sprintf("udp send %s %d %s", addr, port, payload), DefaultCommandTimeout)
+}
+
 func (node *Node) isLineMatch(line string, _expectedLine interface{}) bool {
 	switch expectedLine := _expectedLine.(type) {
 	case string:
@@ -728,7 +888,22 @@ func (node *Node) setupMode() {
 }
 
 func (node *Node) onUartWrite(data []byte) {
-	_, _ = node.virtualUartPipe.Write(data)
+	node.uartLine = append(node.uartLine, data...)
+	for {
+		idx := bytes.IndexByte(node.uartLine, '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := node.uartLine[:idx+1]
+		node.uartLine = node.uartLine[idx+1:]
+
+		if node.S.onUartLine(node, strings.TrimRight(string(line), "\r\n")) {
+			continue
+		}
+
+		_, _ = node.virtualUartPipe.Write(line)
+	}
 }
 
 func (node *Node) detectVirtualTimeUART() {
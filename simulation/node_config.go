@@ -26,6 +26,16 @@
 
 package simulation
 
+// RestartPolicy decides whether a node is restarted after its process crashed.
+type RestartPolicy int
+
+const (
+	// RestartNever leaves the crashed node down.
+	RestartNever RestartPolicy = iota
+	// RestartOnCrash restarts the crashed node from its flash file.
+	RestartOnCrash
+)
+
 type NodeConfig struct {
 	ID             int
 	X, Y           int
@@ -35,6 +45,9 @@ type NodeConfig struct {
 	RadioRange     int
 	ExecutablePath string
 	Restore        bool
+	RestartPolicy  RestartPolicy
+	// MaxRestarts limits the restarts of the node under RestartOnCrash, 0 for unlimited restarts.
+	MaxRestarts int
 }
 
 func DefaultNodeConfig() *NodeConfig {
@@ -48,5 +61,7 @@ func DefaultNodeConfig() *NodeConfig {
 		RadioRange:     160,
 		ExecutablePath: "",
 		Restore:        false,
+		RestartPolicy:  RestartNever,
+		MaxRestarts:    0,
 	}
 }
@@ -0,0 +1,126 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"time"
+
+	"github.com/openthread/ot-ns/dispatcher"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// RebootNode restarts the node process, keeping its flash file.
+func (s *Simulation) RebootNode(nodeid NodeId) error {
+	node, err := s.powerOffNode(nodeid, dispatcher.Ever)
+	if err != nil {
+		return err
+	}
+
+	return s.powerOnNode(node, true)
+}
+
+// FactoryResetNode restarts the node process with its flash file erased.
+func (s *Simulation) FactoryResetNode(nodeid NodeId) error {
+	node, err := s.powerOffNode(nodeid, dispatcher.Ever)
+	if err != nil {
+		return err
+	}
+
+	return s.powerOnNode(node, false)
+}
+
+// PowerCycleNode shuts down the node process, and restarts it keeping its flash file after the virtual duration `offTime`.
+func (s *Simulation) PowerCycleNode(nodeid NodeId, offTime time.Duration) error {
+	_, err := s.powerOffNode(nodeid, uint64(offTime/time.Microsecond))
+	return err
+}
+
+// OnNodePowerOn restarts the process of the powered off node.
+// It is part of implementation of dispatcher.CallbackHandler.
+func (s *Simulation) OnNodePowerOn(nodeid NodeId) {
+	node := s.nodes[nodeid]
+	if node == nil {
+		return
+	}
+
+	// the power-on time might be reached in the middle of a node command, so start the node in a separate task
+	s.PostAsync(false, func() {
+		if s.nodes[nodeid] != node {
+			return
+		}
+
+		if err := s.powerOnNode(node, true); err != nil {
+			simplelogger.Errorf("%v power on failed: %v", node, err)
+		}
+	})
+}
+
+// powerOffNode shuts down the node process, and keeps the node powered off for the virtual `duration` (in us).
+func (s *Simulation) powerOffNode(nodeid NodeId, duration uint64) (*Node, error) {
+	node := s.nodes[nodeid]
+	if node == nil {
+		return nil, errors.Errorf("node %d not found", nodeid)
+	}
+
+	if s.cfg.Real {
+		return nil, errors.Errorf("can not power off real devices")
+	}
+
+	_ = node.Exit()
+	s.d.PowerOffNode(nodeid, duration)
+	return node, nil
+}
+
+// powerOnNode starts a new process for the node, keeping its ID, configuration and dispatcher-side state.
+// The node settings are restored from the flash file if `restore` is true, otherwise the flash file is erased.
+func (s *Simulation) powerOnNode(old *Node, restore bool) (err error) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			err = errors.Errorf("%v", rerr)
+		}
+	}()
+
+	// release the old process, which might have crashed rather than been shut down
+	_ = old.Exit()
+
+	cfg := *old.cfg
+	cfg.Restore = restore
+
+	// the dispatcher counts the new process in RestartNode
+	node, err := newNode(s, old.Id, &cfg, old.process+1)
+	if err != nil {
+		return err
+	}
+
+	node.restarts = old.restarts
+	s.nodes[node.Id] = node
+	s.d.RestartNode(node.Id)
+	s.startNode(node)
+	return nil
+}
@@ -29,11 +29,15 @@ package simulation
 import (
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/openthread/ot-ns/progctx"
 
 	"github.com/openthread/ot-ns/dispatcher"
+	"github.com/openthread/ot-ns/nodelog"
+	"github.com/openthread/ot-ns/otoutfilter"
+	"github.com/openthread/ot-ns/traffic"
 	. "github.com/openthread/ot-ns/types"
 	"github.com/openthread/ot-ns/visualize"
 	"github.com/pkg/errors"
@@ -49,6 +53,11 @@ type Simulation struct {
 	cmdRunner   CmdRunner
 	rawMode     bool
 	networkInfo visualize.NetworkInfo
+	crashes     []*CrashRecord
+	logs        *nodelog.Capture
+	logLevels   *nodelog.Levels
+	logTriggers *nodelog.Triggers
+	traffic     *traffic.Generator
 }
 
 func NewSimulation(ctx *progctx.ProgCtx, cfg *Config, dispatcherCfg *dispatcher.Config) (*Simulation, error) {
@@ -58,6 +67,10 @@ func NewSimulation(ctx *progctx.ProgCtx, cfg *Config, dispatcherCfg *dispatcher.
 		nodes:       map[NodeId]*Node{},
 		rawMode:     cfg.RawMode,
 		networkInfo: visualize.DefaultNetworkInfo(),
+		logs:        nodelog.NewCapture(cfg.NodeLogSize, cfg.NodeLogDir),
+		logLevels:   nodelog.NewLevels(),
+		logTriggers: nodelog.NewTriggers(),
+		traffic:     traffic.NewGenerator(),
 	}
 	s.networkInfo.Real = cfg.Real
 
@@ -73,6 +86,9 @@ func NewSimulation(ctx *progctx.ProgCtx, cfg *Config, dispatcherCfg *dispatcher.
 	dispatcherCfg.DumpPackets = cfg.DumpPackets
 
 	s.d = dispatcher.NewDispatcher(s.ctx, dispatcherCfg, s)
+	if err := s.d.SetMasterKey(cfg.MasterKey); err != nil {
+		simplelogger.Warnf("frames can not be decrypted: %v", err)
+	}
 	s.vis = s.d.GetVisualizer()
 	if err := s.removeTmpDir(); err != nil {
 		simplelogger.Panicf("remove tmp directory failed: %+v", err)
@@ -95,7 +111,7 @@ func (s *Simulation) AddNode(cfg *NodeConfig) (*Node, error) {
 		return nil, errors.Errorf("node %d already exists", nodeid)
 	}
 
-	node, err := newNode(s, nodeid, cfg)
+	node, err := newNode(s, nodeid, cfg, 0)
 	if err != nil {
 		simplelogger.Errorf("simulation add node failed: %v", err)
 		return nil, err
@@ -106,6 +122,12 @@ func (s *Simulation) AddNode(cfg *NodeConfig) (*Node, error) {
 	simplelogger.Infof("simulation:CtrlAddNode: %+v, rawMode=%v", cfg, s.rawMode)
 	s.d.AddNode(nodeid, cfg.X, cfg.Y, cfg.RadioRange)
 
+	s.startNode(node)
+	return node, nil
+}
+
+// startNode detects the UART of the node and starts it unless in raw mode.
+func (s *Simulation) startNode(node *Node) {
 	node.detectVirtualTimeUART()
 
 	node.setupMode()
@@ -115,7 +137,7 @@ func (s *Simulation) AddNode(cfg *NodeConfig) (*Node, error) {
 		node.Start()
 	}
 
-	return node, nil
+	node.started = true
 }
 
 func (s *Simulation) genNodeId() NodeId {
@@ -165,6 +187,7 @@ func (s *Simulation) Stop() {
 	s.nodes = nil
 
 	s.d.Stop()
+	s.logs.Close()
 }
 
 func (s *Simulation) SetVisualizer(vis visualize.Visualizer) {
@@ -197,6 +220,18 @@ func (s *Simulation) OnUartWrite(nodeid NodeId, data []byte) {
 	node.onUartWrite(data)
 }
 
+// onUartLine handles a line written to the virtual time UART of the node. It is called from the dispatcher goroutine.
+// It returns true if the line reports a received datagram of a traffic flow, which is not command output.
+func (s *Simulation) onUartLine(node *Node, line string) bool {
+	if log := otoutfilter.FindLog(line); log != nil {
+		s.onUartLog(node, log)
+		return false
+	}
+
+	// remove > (the input prompt) as the output filter of the line reader does
+	return s.traffic.HandleLine(node.Id, strings.TrimPrefix(line, "> "), s.d.CurTime)
+}
+
 func (s *Simulation) PostAsync(trivial bool, f func()) {
 	s.d.PostAsync(trivial, f)
 }
@@ -262,6 +297,11 @@ func (s *Simulation) Go(duration time.Duration) <-chan struct{} {
 	return s.d.Go(duration)
 }
 
+// GoUntil runs the simulation for the duration, or until the condition is met.
+func (s *Simulation) GoUntil(cond dispatcher.GoCondition, duration time.Duration) <-chan bool {
+	return s.d.GoUntil(cond, duration)
+}
+
 func (s *Simulation) removeTmpDir() error {
 	// tmp directory is used by nodes for saving *.flash files. Need to be removed when simulation started
 	return os.RemoveAll("tmp")
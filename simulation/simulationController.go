@@ -29,6 +29,7 @@ package simulation
 import (
 	"strings"
 
+	"github.com/openthread/ot-ns/nodelog"
 	"github.com/openthread/ot-ns/visualize"
 	"github.com/pkg/errors"
 )
@@ -52,7 +53,16 @@ func (sc *simulationController) Command(cmd string) ([]string, error) {
 	return output, nil
 }
 
+func (sc *simulationController) NodeLogs() *nodelog.Capture {
+	return sc.sim.NodeLogs()
+}
+
+func (sc *simulationController) CurTime() uint64 {
+	return sc.sim.d.GetCurTime()
+}
+
 type readonlySimulationController struct {
+	sim *Simulation
 }
 
 var readonlySimulationError = errors.Errorf("simulation is readonly")
@@ -61,10 +71,18 @@ func (r readonlySimulationController) Command(cmd string) (output []string, err
 	return nil, readonlySimulationError
 }
 
+func (r readonlySimulationController) NodeLogs() *nodelog.Capture {
+	return r.sim.NodeLogs()
+}
+
+func (r readonlySimulationController) CurTime() uint64 {
+	return r.sim.d.GetCurTime()
+}
+
 func NewSimulationController(sim *Simulation) visualize.SimulationController {
 	if !sim.cfg.ReadOnly {
 		return &simulationController{sim}
 	} else {
-		return readonlySimulationController{}
+		return readonlySimulationController{sim}
 	}
 }
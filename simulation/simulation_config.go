@@ -26,7 +26,10 @@
 
 package simulation
 
-import "github.com/openthread/ot-ns/threadconst"
+import (
+	"github.com/openthread/ot-ns/nodelog"
+	"github.com/openthread/ot-ns/threadconst"
+)
 
 const (
 	DefaultNetworkName = "OTSIM"
@@ -48,6 +51,8 @@ type Config struct {
 	DispatcherHost string
 	DispatcherPort int
 	DumpPackets    bool
+	NodeLogSize    int
+	NodeLogDir     string
 }
 
 func DefaultConfig() *Config {
@@ -63,5 +68,6 @@ func DefaultConfig() *Config {
 		Real:           false,
 		DispatcherHost: "localhost",
 		DispatcherPort: threadconst.InitialDispatcherPort,
+		NodeLogSize:    nodelog.DefaultSize,
 	}
 }
@@ -0,0 +1,215 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package simulation
+
+import (
+	"net"
+	"sort"
+
+	"github.com/openthread/ot-ns/traffic"
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+	"github.com/simonlingoogle/go-simplelogger"
+)
+
+// Traffic returns the traffic generator.
+func (s *Simulation) Traffic() *traffic.Generator {
+	return s.traffic
+}
+
+// AddTrafficFlow starts a traffic flow at the current virtual time and returns its ID.
+// The source node opens its UDP socket and the receivers bind theirs to traffic.Port. If no receiver is expected,
+// e.g. when sending to a unicast address that is not resolved to a node, all other nodes bind their UDP sockets.
+func (s *Simulation) AddTrafficFlow(cfg traffic.FlowConfig) (int, error) {
+	if err := cfg.Validate(); err != nil {
+		return 0, err
+	}
+
+	if s.nodes[cfg.Src] == nil {
+		return 0, errors.Errorf("node %d not found", cfg.Src)
+	}
+
+	if err := s.prepareTrafficNodes(cfg); err != nil {
+		return 0, err
+	}
+
+	id, err := s.traffic.Add(cfg, s.d.CurTime)
+	if err != nil {
+		return 0, err
+	}
+
+	s.d.ScheduleTask(s.d.CurTime, func() {
+		s.sendTraffic(id)
+	})
+	return id, nil
+}
+
+// prepareTrafficNodes opens and binds the UDP sockets of the running nodes of the flow that are not prepared yet,
+// including the nodes restarted since they were prepared.
+func (s *Simulation) prepareTrafficNodes(cfg traffic.FlowConfig) (err error) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			err = errors.Errorf("%v", rerr)
+		}
+	}()
+
+	receivers := cfg.Receivers
+	if len(receivers) == 0 {
+		for nodeid := range s.nodes {
+			if nodeid != cfg.Src {
+				receivers = append(receivers, nodeid)
+			}
+		}
+		sort.Ints(receivers)
+	}
+
+	if src := s.nodes[cfg.Src]; s.isNodeRunning(cfg.Src) && !src.udpOpened {
+		src.UdpOpen()
+	}
+
+	for _, nodeid := range receivers {
+		node := s.nodes[nodeid]
+		if !s.isNodeRunning(nodeid) || node.udpBound {
+			continue
+		}
+
+		if !node.udpOpened {
+			node.UdpOpen()
+		}
+		node.UdpBind(traffic.Port)
+	}
+	return nil
+}
+
+// McastSubscribers returns the running nodes subscribed to the multicast address, sorted by node ID.
+func (s *Simulation) McastSubscribers(addr string) []NodeId {
+	group := net.ParseIP(addr)
+	var subscribers []NodeId
+	for nodeid, node := range s.nodes {
+		if s.isNodeRunning(nodeid) && s.isMcastSubscriber(node, group) {
+			subscribers = append(subscribers, nodeid)
+		}
+	}
+
+	sort.Ints(subscribers)
+	return subscribers
+}
+
+// UnicastOwner returns the running node that has the unicast address, or InvalidNodeId if no node has it.
+func (s *Simulation) UnicastOwner(addr string) NodeId {
+	ip := net.ParseIP(addr)
+	var owner NodeId = InvalidNodeId
+	s.VisitNodesInOrder(func(node *Node) {
+		if owner == InvalidNodeId && s.isNodeRunning(node.Id) && s.hasUnicastAddr(node, ip) {
+			owner = node.Id
+		}
+	})
+	return owner
+}
+
+func (s *Simulation) hasUnicastAddr(node *Node, ip net.IP) (found bool) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			simplelogger.Warnf("%v: get addresses failed: %v", node, rerr)
+			found = false
+		}
+	}()
+
+	for _, addr := range node.GetIpAddr() {
+		if ip.Equal(net.ParseIP(addr)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Simulation) isMcastSubscriber(node *Node, group net.IP) (subscribed bool) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			simplelogger.Warnf("%v: get multicast addresses failed: %v", node, rerr)
+			subscribed = false
+		}
+	}()
+
+	for _, maddr := range node.GetIpMaddr() {
+		if group.Equal(net.ParseIP(maddr)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Simulation) isNodeRunning(nodeid NodeId) bool {
+	node, dnode := s.nodes[nodeid], s.d.GetNode(nodeid)
+	return node != nil && dnode != nil && node.started && !dnode.Crashed && !dnode.PoweredOff
+}
+
+// sendTraffic sends the datagrams of the flow due at the current virtual time and schedules the next send.
+// Datagrams due while the source node is not running are counted as lost.
+func (s *Simulation) sendTraffic(id int) {
+	cfg, ok := s.traffic.Config(id)
+	if !ok {
+		// the flow is removed
+		return
+	}
+
+	if s.nodes[cfg.Src] == nil {
+		simplelogger.Warnf("traffic flow %d stopped: node %d is deleted", id, cfg.Src)
+		_ = s.traffic.Stop(id, s.d.CurTime)
+		return
+	}
+
+	if err := s.prepareTrafficNodes(cfg); err != nil {
+		simplelogger.Warnf("traffic flow %d: prepare nodes failed: %v", id, err)
+	}
+
+	datagrams, next := s.traffic.Send(id, s.d.CurTime)
+	if s.isNodeRunning(cfg.Src) {
+		if err := s.sendDatagrams(s.nodes[cfg.Src], cfg.Dst, datagrams); err != nil {
+			simplelogger.Warnf("traffic flow %d: send failed: %v", id, err)
+		}
+	}
+
+	if next != 0 {
+		s.d.ScheduleTask(next, func() {
+			s.sendTraffic(id)
+		})
+	}
+}
+
+func (s *Simulation) sendDatagrams(src *Node, dst string, datagrams []traffic.Datagram) (err error) {
+	defer func() {
+		if rerr := recover(); rerr != nil {
+			err = errors.Errorf("%v", rerr)
+		}
+	}()
+
+	for _, dg := range datagrams {
+		src.UdpSend(dst, traffic.Port, dg.Payload)
+	}
+	return nil
+}
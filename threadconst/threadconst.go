@@ -32,4 +32,9 @@ const (
 
 	WellKnownNodeId       = 1000
 	InitialDispatcherPort = 9000
+
+	MlePort uint16 = 19788
+	TmfPort uint16 = 61631
+
+	DefaultMeshLocalPrefix = "fdde:ad00:beef:0::/64"
 )
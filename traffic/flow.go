@@ -0,0 +1,190 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package traffic
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Port is the UDP port that the receivers of the traffic flows bind.
+	Port = 50000
+	// MaxDatagramSize is the maximum payload size of the traffic datagrams, limited by the OT CLI line length.
+	MaxDatagramSize = 256
+
+	payloadPrefix = "otns"
+)
+
+// Pattern is the pattern of the send times of a traffic flow.
+type Pattern int
+
+const (
+	// PatternCbr sends one datagram at a constant interval of 1/Rate seconds.
+	PatternCbr Pattern = iota
+	// PatternPoisson sends one datagram at exponentially distributed intervals of 1/Rate seconds on average.
+	PatternPoisson
+	// PatternBurst sends Burst datagrams at once at a constant interval of 1/Rate seconds.
+	PatternBurst
+)
+
+func (p Pattern) String() string {
+	switch p {
+	case PatternCbr:
+		return "cbr"
+	case PatternPoisson:
+		return "poisson"
+	case PatternBurst:
+		return "burst"
+	default:
+		return "unknown"
+	}
+}
+
+// ParsePattern parses a traffic pattern name.
+func ParsePattern(s string) (Pattern, error) {
+	switch s {
+	case "cbr":
+		return PatternCbr, nil
+	case "poisson":
+		return PatternPoisson, nil
+	case "burst":
+		return PatternBurst, nil
+	default:
+		return PatternCbr, errors.Errorf("invalid traffic pattern: %s", s)
+	}
+}
+
+// FlowConfig is the configuration of a flow of UDP datagrams from a source node to a unicast or multicast address.
+type FlowConfig struct {
+	Src     NodeId
+	Dst     string
+	Pattern Pattern
+	// Rate is the number of datagrams (or bursts for PatternBurst) per second.
+	Rate float64
+	// Size is the payload size of the datagrams.
+	Size int
+	// Burst is the number of datagrams of each burst for PatternBurst.
+	Burst int
+	// Count is the total number of datagrams to send, or 0 to send until the flow is stopped.
+	Count int
+	// Receivers are the nodes expected to receive the datagrams, which are reported even if they receive nothing.
+	Receivers []NodeId
+}
+
+// Validate checks the flow configuration.
+func (cfg *FlowConfig) Validate() error {
+	if cfg.Rate <= 0 {
+		return errors.Errorf("invalid rate: %v", cfg.Rate)
+	}
+	if cfg.Size <= 0 || cfg.Size > MaxDatagramSize {
+		return errors.Errorf("invalid size: %d (should be 1 to %d)", cfg.Size, MaxDatagramSize)
+	}
+	if cfg.Pattern == PatternBurst && cfg.Burst <= 0 {
+		return errors.Errorf("invalid burst: %d", cfg.Burst)
+	}
+	if cfg.Count < 0 {
+		return errors.Errorf("invalid count: %d", cfg.Count)
+	}
+	return nil
+}
+
+// interval returns the virtual time (in us) until the next datagram or burst of the flow.
+func (cfg *FlowConfig) interval() uint64 {
+	seconds := 1 / cfg.Rate
+	if cfg.Pattern == PatternPoisson {
+		seconds = rand.ExpFloat64() / cfg.Rate
+	}
+
+	interval := uint64(seconds * 1000000)
+	if interval == 0 {
+		interval = 1
+	}
+	return interval
+}
+
+// datagramsPerSend returns the number of datagrams sent at once.
+func (cfg *FlowConfig) datagramsPerSend() int {
+	if cfg.Pattern == PatternBurst {
+		return cfg.Burst
+	}
+	return 1
+}
+
+// Datagram is a UDP datagram of a traffic flow.
+type Datagram struct {
+	FlowId int
+	Seq    int
+	// Payload is the text payload of the datagram, which identifies the flow and sequence number of the datagram.
+	Payload string
+}
+
+func newDatagram(flowid int, seq int, size int) Datagram {
+	payload := fmt.Sprintf("%s:%d:%d:", payloadPrefix, flowid, seq)
+	if len(payload) < size {
+		payload += strings.Repeat("x", size-len(payload))
+	}
+
+	return Datagram{
+		FlowId:  flowid,
+		Seq:     seq,
+		Payload: payload,
+	}
+}
+
+// e.x. 20 bytes from fdde:ad00:beef:0:0:ff:fe00:fc00 49153 otns:1:2:xxxxxxxxxxx
+var receivedDatagramRegexp = regexp.MustCompile(`^(\d+) bytes from \S+ \d+ ` + payloadPrefix + `:(\d+):(\d+):x*$`)
+
+// parseReceivedDatagram parses the line output by the OT CLI when a traffic datagram is received,
+// and returns the datagram and its size.
+func parseReceivedDatagram(line string) (dg Datagram, size int, ok bool) {
+	m := receivedDatagramRegexp.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+
+	var err error
+	if size, err = strconv.Atoi(m[1]); err != nil {
+		return
+	}
+	if dg.FlowId, err = strconv.Atoi(m[2]); err != nil {
+		return
+	}
+	if dg.Seq, err = strconv.Atoi(m[3]); err != nil {
+		return
+	}
+
+	dg.Payload = line[strings.Index(line, payloadPrefix+":"):]
+	ok = true
+	return
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package traffic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePattern(t *testing.T) {
+	for _, p := range []Pattern{PatternCbr, PatternPoisson, PatternBurst} {
+		parsed, err := ParsePattern(p.String())
+		assert.Nil(t, err)
+		assert.Equal(t, p, parsed)
+	}
+
+	_, err := ParsePattern("random")
+	assert.NotNil(t, err)
+}
+
+func TestFlowConfigValidate(t *testing.T) {
+	cfg := FlowConfig{Src: 1, Dst: "ff03::1", Pattern: PatternCbr, Rate: 1, Size: 20}
+	assert.Nil(t, cfg.Validate())
+
+	invalid := cfg
+	invalid.Rate = 0
+	assert.NotNil(t, invalid.Validate())
+
+	invalid = cfg
+	invalid.Size = MaxDatagramSize + 1
+	assert.NotNil(t, invalid.Validate())
+
+	invalid = cfg
+	invalid.Pattern = PatternBurst
+	assert.NotNil(t, invalid.Validate())
+	invalid.Burst = 3
+	assert.Nil(t, invalid.Validate())
+}
+
+func TestFlowConfigInterval(t *testing.T) {
+	cfg := FlowConfig{Pattern: PatternCbr, Rate: 4}
+	assert.Equal(t, uint64(250000), cfg.interval())
+
+	cfg.Pattern = PatternPoisson
+	var sum uint64
+	for i := 0; i < 1000; i++ {
+		interval := cfg.interval()
+		assert.True(t, interval > 0)
+		sum += interval
+	}
+	// the average interval is 1/Rate seconds
+	assert.InDelta(t, 250000, float64(sum)/1000, 50000)
+}
+
+func TestDatagram(t *testing.T) {
+	dg := newDatagram(3, 12, 20)
+	assert.Equal(t, "otns:3:12:xxxxxxxxxx", dg.Payload)
+	// payloads are not truncated to sizes smaller than the header
+	assert.Equal(t, "otns:3:12:", newDatagram(3, 12, 4).Payload)
+
+	parsed, size, ok := parseReceivedDatagram("20 bytes from fdde:ad00:beef:0:0:ff:fe00:fc00 49153 otns:3:12:xxxxxxxxxx")
+	assert.True(t, ok)
+	assert.Equal(t, 20, size)
+	assert.Equal(t, dg, parsed)
+
+	_, _, ok = parseReceivedDatagram("5 bytes from fdde:ad00:beef:0:0:ff:fe00:fc00 49153 hello")
+	assert.False(t, ok)
+	_, _, ok = parseReceivedDatagram("Done")
+	assert.False(t, ok)
+}
@@ -0,0 +1,405 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package traffic
+
+import (
+	"net"
+	"sort"
+	"sync"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxDeliveryCount = 10000
+)
+
+type receiver struct {
+	received      int
+	receivedBytes int
+	delaySum      uint64
+	minDelay      uint64
+	maxDelay      uint64
+	seen          map[int]struct{}
+}
+
+type flow struct {
+	FlowConfig
+	id        int
+	multicast bool
+	startTime uint64
+	stopTime  uint64
+	stopped   bool
+	// sendTimes are the virtual times when the datagrams are sent, indexed by sequence number - 1
+	sendTimes []uint64
+	sentBytes int
+	receivers map[NodeId]*receiver
+	// deliveries are the uncollected delivery records of multicast datagrams, indexed by sequence number
+	deliveries map[int]*Delivery
+}
+
+func (f *flow) receiver(nodeid NodeId) *receiver {
+	r := f.receivers[nodeid]
+	if r == nil {
+		r = &receiver{seen: map[int]struct{}{}}
+		f.receivers[nodeid] = r
+	}
+	return r
+}
+
+func (f *flow) stop(now uint64) {
+	if !f.stopped {
+		f.stopped = true
+		f.stopTime = now
+	}
+}
+
+// DeliveryReceiver is a node that received a multicast datagram.
+type DeliveryReceiver struct {
+	NodeId NodeId
+	// Timestamp is the virtual time (in us) when the datagram is received.
+	Timestamp uint64
+	// Latency is the virtual time (in us) from sending to receiving the datagram.
+	Latency uint64
+}
+
+// Delivery records the nodes that received a multicast datagram, and when.
+type Delivery struct {
+	FlowId int
+	Seq    int
+	Src    NodeId
+	Dst    string
+	// Timestamp is the virtual time (in us) when the datagram is sent.
+	Timestamp uint64
+	// Expected are the nodes expected to receive the datagram.
+	Expected  []NodeId
+	Receivers []DeliveryReceiver
+}
+
+// Ratio returns the ratio of the expected receivers that received the datagram, or 0 if no receiver is expected.
+func (d *Delivery) Ratio() float64 {
+	if len(d.Expected) == 0 {
+		return 0
+	}
+
+	received := 0
+	for _, r := range d.Receivers {
+		for _, nodeid := range d.Expected {
+			if r.NodeId == nodeid {
+				received += 1
+				break
+			}
+		}
+	}
+	return float64(received) / float64(len(d.Expected))
+}
+
+// ReceiverStats is the statistics of the datagrams of a flow received by a node.
+// The delays (in us) are 0 if no datagram is received.
+type ReceiverStats struct {
+	NodeId        NodeId
+	Received      int
+	ReceivedBytes int
+	// LossRate is the percentage of the sent datagrams not received, including those still in flight.
+	LossRate float64
+	// Throughput is the received payload bits per second of virtual time.
+	Throughput float64
+	MinDelay   uint64
+	AvgDelay   uint64
+	MaxDelay   uint64
+}
+
+// FlowStats is the statistics of a flow.
+type FlowStats struct {
+	FlowConfig
+	Id        int
+	Running   bool
+	StartTime uint64
+	// Duration is the virtual time (in us) from the start of the flow until it stopped or until now.
+	Duration  uint64
+	Sent      int
+	SentBytes int
+	// Receivers are the statistics of the expected receivers and other nodes that received datagrams of the flow,
+	// sorted by node ID.
+	Receivers []ReceiverStats
+}
+
+// Generator keeps the traffic flows and tracks the datagrams they send and receive. It is safe for concurrent use.
+type Generator struct {
+	sync.Mutex
+	flows      map[int]*flow
+	nextId     int
+	deliveries []*Delivery
+}
+
+func NewGenerator() *Generator {
+	return &Generator{
+		flows:  map[int]*flow{},
+		nextId: 1,
+	}
+}
+
+// Add adds a flow starting at the virtual time now and returns its ID.
+func (g *Generator) Add(cfg FlowConfig, now uint64) (int, error) {
+	if err := cfg.Validate(); err != nil {
+		return 0, err
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	ip := net.ParseIP(cfg.Dst)
+	f := &flow{
+		FlowConfig: cfg,
+		id:         g.nextId,
+		multicast:  ip != nil && ip.IsMulticast(),
+		startTime:  now,
+		receivers:  map[NodeId]*receiver{},
+		deliveries: map[int]*Delivery{},
+	}
+	f.Receivers = append([]NodeId(nil), cfg.Receivers...)
+	for _, nodeid := range f.Receivers {
+		f.receiver(nodeid)
+	}
+
+	g.flows[f.id] = f
+	g.nextId++
+	return f.id, nil
+}
+
+// Config returns the configuration of the flow.
+func (g *Generator) Config(id int) (FlowConfig, bool) {
+	g.Lock()
+	defer g.Unlock()
+
+	f := g.flows[id]
+	if f == nil {
+		return FlowConfig{}, false
+	}
+	return f.FlowConfig, true
+}
+
+// Send records the datagrams that the flow sends at the virtual time now and returns them, along with the virtual time
+// of the next send. No datagram is sent and next is 0 if the flow is stopped, finished or removed.
+func (g *Generator) Send(id int, now uint64) (datagrams []Datagram, next uint64) {
+	g.Lock()
+	defer g.Unlock()
+
+	f := g.flows[id]
+	if f == nil || f.stopped {
+		return nil, 0
+	}
+
+	n := f.datagramsPerSend()
+	if f.Count > 0 && len(f.sendTimes)+n > f.Count {
+		n = f.Count - len(f.sendTimes)
+	}
+
+	for i := 0; i < n; i++ {
+		dg := newDatagram(id, len(f.sendTimes)+1, f.Size)
+		f.sendTimes = append(f.sendTimes, now)
+		f.sentBytes += len(dg.Payload)
+		datagrams = append(datagrams, dg)
+
+		if f.multicast {
+			g.addDelivery(f, &Delivery{
+				FlowId:    id,
+				Seq:       dg.Seq,
+				Src:       f.Src,
+				Dst:       f.Dst,
+				Timestamp: now,
+				Expected:  f.Receivers,
+				Receivers: []DeliveryReceiver{},
+			})
+		}
+	}
+
+	if f.Count > 0 && len(f.sendTimes) >= f.Count {
+		f.stop(now)
+		return datagrams, 0
+	}
+	return datagrams, now + f.interval()
+}
+
+func (g *Generator) addDelivery(f *flow, delivery *Delivery) {
+	f.deliveries[delivery.Seq] = delivery
+	g.deliveries = append(g.deliveries, delivery)
+
+	if len(g.deliveries) > maxDeliveryCount {
+		oldest := g.deliveries[0]
+		if oldestFlow := g.flows[oldest.FlowId]; oldestFlow != nil {
+			delete(oldestFlow.deliveries, oldest.Seq)
+		}
+		g.deliveries = g.deliveries[1:]
+	}
+}
+
+// CollectDeliveries returns the delivery records of the multicast datagrams sent since the last collection,
+// in order of sending. Collected records are no longer updated by later receptions.
+func (g *Generator) CollectDeliveries() []*Delivery {
+	g.Lock()
+	defer g.Unlock()
+
+	ret := g.deliveries
+	g.deliveries = nil
+	for _, f := range g.flows {
+		f.deliveries = map[int]*Delivery{}
+	}
+	return ret
+}
+
+// Stop stops the flow at the virtual time now. Datagrams in flight are still counted when received.
+func (g *Generator) Stop(id int, now uint64) error {
+	g.Lock()
+	defer g.Unlock()
+
+	f := g.flows[id]
+	if f == nil {
+		return errors.Errorf("flow %d not found", id)
+	}
+
+	f.stop(now)
+	return nil
+}
+
+// Remove stops and removes the flow.
+func (g *Generator) Remove(id int) error {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.flows[id] == nil {
+		return errors.Errorf("flow %d not found", id)
+	}
+
+	delete(g.flows, id)
+	return nil
+}
+
+// HandleLine handles a line output by the OT CLI of the node at the virtual time now.
+// It returns true if the line reports a received datagram of any traffic flow.
+func (g *Generator) HandleLine(nodeid NodeId, line string, now uint64) bool {
+	dg, size, ok := parseReceivedDatagram(line)
+	if !ok {
+		return false
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	f := g.flows[dg.FlowId]
+	if f == nil || dg.Seq < 1 || dg.Seq > len(f.sendTimes) {
+		// datagram of a removed flow
+		return true
+	}
+
+	r := f.receiver(nodeid)
+	if _, seen := r.seen[dg.Seq]; seen {
+		return true
+	}
+	r.seen[dg.Seq] = struct{}{}
+
+	var delay uint64
+	if sendTime := f.sendTimes[dg.Seq-1]; now > sendTime {
+		delay = now - sendTime
+	}
+
+	if r.received == 0 || delay < r.minDelay {
+		r.minDelay = delay
+	}
+	if delay > r.maxDelay {
+		r.maxDelay = delay
+	}
+	r.received += 1
+	r.receivedBytes += size
+	r.delaySum += delay
+
+	if delivery := f.deliveries[dg.Seq]; delivery != nil {
+		delivery.Receivers = append(delivery.Receivers, DeliveryReceiver{
+			NodeId:    nodeid,
+			Timestamp: now,
+			Latency:   delay,
+		})
+	}
+	return true
+}
+
+// Stats returns the statistics of all flows at the virtual time now, sorted by flow ID.
+func (g *Generator) Stats(now uint64) []FlowStats {
+	g.Lock()
+	defer g.Unlock()
+
+	var allStats []FlowStats
+	for _, f := range g.flows {
+		stats := FlowStats{
+			FlowConfig: f.FlowConfig,
+			Id:         f.id,
+			Running:    !f.stopped,
+			StartTime:  f.startTime,
+			Sent:       len(f.sendTimes),
+			SentBytes:  f.sentBytes,
+			Receivers:  []ReceiverStats{},
+		}
+		endTime := now
+		if f.stopped {
+			endTime = f.stopTime
+		}
+		if endTime > f.startTime {
+			stats.Duration = endTime - f.startTime
+		}
+
+		for nodeid, r := range f.receivers {
+			rs := ReceiverStats{
+				NodeId:        nodeid,
+				Received:      r.received,
+				ReceivedBytes: r.receivedBytes,
+				MinDelay:      r.minDelay,
+				MaxDelay:      r.maxDelay,
+			}
+			if stats.Sent > 0 {
+				rs.LossRate = float64(stats.Sent-r.received) * 100 / float64(stats.Sent)
+			}
+			if stats.Duration > 0 {
+				rs.Throughput = float64(r.receivedBytes*8) * 1000000 / float64(stats.Duration)
+			}
+			if r.received > 0 {
+				rs.AvgDelay = r.delaySum / uint64(r.received)
+			}
+			stats.Receivers = append(stats.Receivers, rs)
+		}
+		sort.Slice(stats.Receivers, func(i, j int) bool {
+			return stats.Receivers[i].NodeId < stats.Receivers[j].NodeId
+		})
+
+		allStats = append(allStats, stats)
+	}
+
+	sort.Slice(allStats, func(i, j int) bool {
+		return allStats[i].Id < allStats[j].Id
+	})
+	return allStats
+}
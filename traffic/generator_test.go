@@ -0,0 +1,181 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package traffic
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/openthread/ot-ns/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func receivedLine(dg Datagram) string {
+	return fmt.Sprintf("%d bytes from fdde:ad00:beef:0:0:ff:fe00:fc00 49153 %s", len(dg.Payload), dg.Payload)
+}
+
+func TestGeneratorCbr(t *testing.T) {
+	g := NewGenerator()
+	id, err := g.Add(FlowConfig{Src: 1, Dst: "fdde:ad00:beef:0::2", Rate: 10, Size: 20, Count: 3, Receivers: []NodeId{2}}, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, id)
+
+	var sent []Datagram
+	now := uint64(1000)
+	for now != 0 {
+		dgs, next := g.Send(id, now)
+		assert.Len(t, dgs, 1)
+		sent = append(sent, dgs...)
+		if next != 0 {
+			assert.Equal(t, now+100000, next)
+		}
+		now = next
+	}
+	assert.Len(t, sent, 3)
+
+	// received with delays of 5ms and 15ms, the duplicate is ignored and the last datagram is lost
+	assert.True(t, g.HandleLine(2, receivedLine(sent[0]), 6000))
+	assert.True(t, g.HandleLine(2, receivedLine(sent[0]), 7000))
+	assert.True(t, g.HandleLine(2, receivedLine(sent[1]), 116000))
+	assert.False(t, g.HandleLine(2, "Done", 116000))
+
+	stats := g.Stats(1000000)
+	assert.Len(t, stats, 1)
+	assert.False(t, stats[0].Running)
+	assert.Equal(t, 3, stats[0].Sent)
+	assert.Equal(t, 60, stats[0].SentBytes)
+	// the flow stopped after the last datagram
+	assert.Equal(t, uint64(200000), stats[0].Duration)
+	assert.Equal(t, []ReceiverStats{{
+		NodeId: 2, Received: 2, ReceivedBytes: 40, LossRate: 100.0 / 3, Throughput: 1600,
+		MinDelay: 5000, AvgDelay: 10000, MaxDelay: 15000,
+	}}, stats[0].Receivers)
+
+	_, next := g.Send(id, 1000000)
+	assert.Equal(t, uint64(0), next)
+}
+
+func TestGeneratorBurst(t *testing.T) {
+	g := NewGenerator()
+	id, err := g.Add(FlowConfig{Src: 1, Dst: "ff03::1", Pattern: PatternBurst, Rate: 1, Size: 10, Burst: 3, Count: 5,
+		Receivers: []NodeId{2, 3}}, 0)
+	assert.Nil(t, err)
+
+	dgs, next := g.Send(id, 0)
+	assert.Len(t, dgs, 3)
+	assert.Equal(t, uint64(1000000), next)
+	// the last burst is limited by the count
+	dgs, next = g.Send(id, next)
+	assert.Len(t, dgs, 2)
+	assert.Equal(t, uint64(0), next)
+
+	assert.True(t, g.HandleLine(3, receivedLine(dgs[0]), 1001000))
+	assert.True(t, g.HandleLine(4, receivedLine(dgs[1]), 1002000))
+
+	stats := g.Stats(2000000)
+	assert.Len(t, stats, 1)
+	assert.Equal(t, 5, stats[0].Sent)
+	// expected receivers are reported even if they received nothing
+	assert.Len(t, stats[0].Receivers, 3)
+	assert.Equal(t, ReceiverStats{NodeId: 2, LossRate: 100}, stats[0].Receivers[0])
+	assert.Equal(t, NodeId(3), stats[0].Receivers[1].NodeId)
+	assert.Equal(t, 1, stats[0].Receivers[1].Received)
+	assert.Equal(t, uint64(1000), stats[0].Receivers[1].AvgDelay)
+	assert.Equal(t, NodeId(4), stats[0].Receivers[2].NodeId)
+	assert.Equal(t, float64(80), stats[0].Receivers[2].LossRate)
+}
+
+func TestGeneratorDeliveries(t *testing.T) {
+	g := NewGenerator()
+	unicast, err := g.Add(FlowConfig{Src: 1, Dst: "fdde:ad00:beef:0::2", Rate: 1, Size: 10, Receivers: []NodeId{2}}, 0)
+	assert.Nil(t, err)
+	multicast, err := g.Add(FlowConfig{Src: 1, Dst: "ff03::1", Rate: 1, Size: 10, Receivers: []NodeId{2, 3}}, 0)
+	assert.Nil(t, err)
+
+	g.Send(unicast, 0)
+	dgs, next := g.Send(multicast, 0)
+	g.HandleLine(2, receivedLine(dgs[0]), 3000)
+	g.HandleLine(4, receivedLine(dgs[0]), 5000)
+	dgs, _ = g.Send(multicast, next)
+
+	// only multicast datagrams are recorded
+	deliveries := g.CollectDeliveries()
+	assert.Equal(t, []*Delivery{
+		{
+			FlowId: multicast, Seq: 1, Src: 1, Dst: "ff03::1", Timestamp: 0, Expected: []NodeId{2, 3},
+			Receivers: []DeliveryReceiver{{NodeId: 2, Timestamp: 3000, Latency: 3000}, {NodeId: 4, Timestamp: 5000, Latency: 5000}},
+		},
+		{
+			FlowId: multicast, Seq: 2, Src: 1, Dst: "ff03::1", Timestamp: 1000000, Expected: []NodeId{2, 3},
+			Receivers: []DeliveryReceiver{},
+		},
+	}, deliveries)
+	// unexpected receivers are not counted in the delivery ratio
+	assert.Equal(t, 0.5, deliveries[0].Ratio())
+	assert.Equal(t, float64(0), deliveries[1].Ratio())
+
+	// collected records are no longer updated
+	g.HandleLine(3, receivedLine(dgs[0]), 1004000)
+	assert.Empty(t, deliveries[1].Receivers)
+	assert.Empty(t, g.CollectDeliveries())
+	assert.Equal(t, 1, g.Stats(2000000)[1].Receivers[1].Received)
+}
+
+func TestGeneratorStopAndRemove(t *testing.T) {
+	g := NewGenerator()
+	_, err := g.Add(FlowConfig{Src: 1, Dst: "ff03::1", Rate: 0, Size: 10}, 0)
+	assert.NotNil(t, err)
+
+	id, err := g.Add(FlowConfig{Src: 1, Dst: "ff03::1", Pattern: PatternPoisson, Rate: 1, Size: 10}, 0)
+	assert.Nil(t, err)
+	dgs, next := g.Send(id, 0)
+	assert.Len(t, dgs, 1)
+	assert.True(t, next > 0)
+
+	assert.Nil(t, g.Stop(id, 500000))
+	dgs, next = g.Send(id, next)
+	assert.Empty(t, dgs)
+	assert.Equal(t, uint64(0), next)
+	// datagrams in flight are still counted after the flow is stopped
+	assert.True(t, g.HandleLine(2, receivedLine(newDatagram(id, 1, 10)), 600000))
+	stats := g.Stats(1000000)
+	assert.Equal(t, uint64(500000), stats[0].Duration)
+	assert.Equal(t, 1, stats[0].Receivers[0].Received)
+
+	cfg, ok := g.Config(id)
+	assert.True(t, ok)
+	assert.Equal(t, PatternPoisson, cfg.Pattern)
+
+	assert.Nil(t, g.Remove(id))
+	assert.NotNil(t, g.Remove(id))
+	assert.NotNil(t, g.Stop(id, 0))
+	_, ok = g.Config(id)
+	assert.False(t, ok)
+	// datagrams of removed flows are still recognized
+	assert.True(t, g.HandleLine(2, receivedLine(newDatagram(id, 1, 10)), 700000))
+	assert.Empty(t, g.Stats(1000000))
+}
@@ -0,0 +1,339 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// NetDataPrefix is an on-mesh prefix in `netdata show`.
+type NetDataPrefix struct {
+	Prefix     net.IPNet
+	Flags      string
+	Preference string
+	Rloc16     uint16
+}
+
+// NetDataRoute is an external route in `netdata show`.
+type NetDataRoute struct {
+	Prefix     net.IPNet
+	Flags      string
+	Preference string
+	Rloc16     uint16
+}
+
+// NetDataService is a service in `netdata show`.
+type NetDataService struct {
+	EnterpriseNumber uint32
+	ServiceData      []byte
+	ServerData       []byte
+	Stable           bool
+	Rloc16           uint16
+}
+
+// NetData is the network data printed by `netdata show`.
+type NetData struct {
+	Prefixes []NetDataPrefix
+	Routes   []NetDataRoute
+	Services []NetDataService
+}
+
+func (p NetDataPrefix) String() string {
+	return formatNetDataPrefix(p.Prefix, p.Flags, p.Preference, p.Rloc16)
+}
+
+func (r NetDataRoute) String() string {
+	return formatNetDataPrefix(r.Prefix, r.Flags, r.Preference, r.Rloc16)
+}
+
+func formatNetDataPrefix(prefix net.IPNet, flags string, preference string, rloc16 uint16) string {
+	if flags == "" {
+		return fmt.Sprintf("%s %s %04x", prefix.String(), preference, rloc16)
+	}
+	return fmt.Sprintf("%s %s %s %04x", prefix.String(), flags, preference, rloc16)
+}
+
+func (s NetDataService) String() string {
+	stable := ""
+	if s.Stable {
+		stable = " s"
+	}
+	return fmt.Sprintf("%d %x %x%s %04x", s.EnterpriseNumber, s.ServiceData, s.ServerData, stable, s.Rloc16)
+}
+
+// SrpHost is a host registered on the SRP server of a node.
+type SrpHost struct {
+	Name      string
+	Addresses []string
+}
+
+// SrpService is a service instance registered on the SRP server of a node.
+type SrpService struct {
+	Name string
+	Host string
+	Port int
+}
+
+// NodeNetworkState is the network data, leader, key sequence, channel and SRP registrations reported by a node.
+type NodeNetworkState struct {
+	// LeaderRouterId is the router ID of the leader, or InvalidRouterId if unknown.
+	LeaderRouterId int
+	KeySequence    uint32
+	// Channel is the radio channel, or 0 if unknown.
+	Channel     int
+	NetData     NetData
+	SrpHosts    []SrpHost
+	SrpServices []SrpService
+}
+
+// InvalidRouterId is the router ID of no router.
+const InvalidRouterId = 63
+
+func NewNodeNetworkState() NodeNetworkState {
+	return NodeNetworkState{
+		LeaderRouterId: InvalidRouterId,
+		NetData: NetData{
+			Prefixes: []NetDataPrefix{},
+			Routes:   []NetDataRoute{},
+			Services: []NetDataService{},
+		},
+		SrpHosts:    []SrpHost{},
+		SrpServices: []SrpService{},
+	}
+}
+
+// ParseRloc16 parses a hexadecimal RLOC16, e.g. `0x4000` or `4000`.
+func ParseRloc16(s string) (uint16, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	v, err := strconv.ParseUint(s, 16, 16)
+	return uint16(v), err
+}
+
+// ParseNetData parses the output of `netdata show`. Sections not listed in NetData are ignored.
+func ParseNetData(output []string) (*NetData, error) {
+	netdata := &NetData{
+		Prefixes: []NetDataPrefix{},
+		Routes:   []NetDataRoute{},
+		Services: []NetDataService{},
+	}
+
+	section := ""
+	for _, line := range output {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasSuffix(line, ":") {
+			section = strings.TrimSuffix(line, ":")
+			continue
+		}
+
+		var err error
+		switch section {
+		case "Prefixes":
+			var prefix NetDataPrefix
+			prefix, err = ParseNetDataPrefix(line)
+			netdata.Prefixes = append(netdata.Prefixes, prefix)
+		case "Routes":
+			var route NetDataRoute
+			route, err = ParseNetDataRoute(line)
+			netdata.Routes = append(netdata.Routes, route)
+		case "Services":
+			var service NetDataService
+			service, err = ParseNetDataService(line)
+			netdata.Services = append(netdata.Services, service)
+		case "":
+			err = errors.Errorf("expected section")
+		}
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "netdata %#v", line)
+		}
+	}
+
+	return netdata, nil
+}
+
+// ParseNetDataPrefix parses an on-mesh prefix line of `netdata show`, e.g. `fd00:dead:beef:cafe::/64 paros med 4000`.
+func ParseNetDataPrefix(line string) (prefix NetDataPrefix, err error) {
+	prefix.Prefix, prefix.Flags, prefix.Preference, prefix.Rloc16, err = parseNetDataPrefix(line)
+	return
+}
+
+// ParseNetDataRoute parses an external route line of `netdata show`, e.g. `fd00:1234::/64 s med 4000`.
+func ParseNetDataRoute(line string) (route NetDataRoute, err error) {
+	route.Prefix, route.Flags, route.Preference, route.Rloc16, err = parseNetDataPrefix(line)
+	return
+}
+
+// parseNetDataPrefix parses a prefix or route, e.g. `fd00:dead:beef:cafe::/64 paros med 4000`.
+// The flags are not printed by some OpenThread versions if there is none.
+func parseNetDataPrefix(line string) (prefix net.IPNet, flags string, preference string, rloc16 uint16, err error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 && len(fields) != 4 {
+		err = errors.Errorf("expected 3 or 4 fields")
+		return
+	}
+
+	var ipnet *net.IPNet
+	if _, ipnet, err = net.ParseCIDR(fields[0]); err != nil {
+		return
+	}
+
+	prefix = *ipnet
+	if len(fields) == 4 {
+		flags = fields[1]
+	}
+	preference = fields[len(fields)-2]
+	rloc16, err = ParseRloc16(fields[len(fields)-1])
+	return
+}
+
+// ParseNetDataService parses a service line of `netdata show`, e.g. `44970 5d c000 s 4000`.
+func ParseNetDataService(line string) (service NetDataService, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 {
+		err = errors.Errorf("expected at least 4 fields")
+		return
+	}
+
+	var enterprise uint64
+	if enterprise, err = strconv.ParseUint(fields[0], 10, 32); err != nil {
+		return
+	}
+	service.EnterpriseNumber = uint32(enterprise)
+
+	if service.ServiceData, err = hex.DecodeString(fields[1]); err != nil {
+		return
+	}
+
+	if service.ServerData, err = hex.DecodeString(fields[2]); err != nil {
+		return
+	}
+
+	rest := fields[3:]
+	if rest[0] == "s" {
+		service.Stable = true
+		rest = rest[1:]
+	}
+
+	if len(rest) == 0 {
+		err = errors.Errorf("missing RLOC16")
+		return
+	}
+
+	service.Rloc16, err = ParseRloc16(rest[0])
+	return
+}
+
+// srpServerEntry is a host or service instance in the output of `srp server host` or `srp server service`.
+type srpServerEntry struct {
+	name   string
+	fields map[string]string
+}
+
+// parseSrpServerEntries parses the output of `srp server host` or `srp server service`, where each entry is the
+// full name followed by indented `<field>: <value>` lines. Deleted entries are ignored.
+func parseSrpServerEntries(output []string) ([]srpServerEntry, error) {
+	var entries []srpServerEntry
+	for _, line := range output {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if i := strings.Index(line, ": "); i >= 0 {
+			if len(entries) == 0 {
+				return nil, errors.Errorf("srp server %#v: expected name", line)
+			}
+			entries[len(entries)-1].fields[line[:i]] = strings.TrimSpace(line[i+2:])
+		} else {
+			entries = append(entries, srpServerEntry{name: line, fields: map[string]string{}})
+		}
+	}
+
+	registered := entries[:0]
+	for _, entry := range entries {
+		if entry.fields["deleted"] != "true" {
+			registered = append(registered, entry)
+		}
+	}
+	return registered, nil
+}
+
+// ParseSrpServerHosts parses the output of `srp server host`, where the addresses of a host are listed as
+// `addresses: [fd00:0:0:0:0:0:0:1, fd00:0:0:0:0:0:0:2]`.
+func ParseSrpServerHosts(output []string) ([]SrpHost, error) {
+	entries, err := parseSrpServerEntries(output)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []SrpHost{}
+	for _, entry := range entries {
+		host := SrpHost{Name: entry.name, Addresses: []string{}}
+		addrs := strings.TrimSuffix(strings.TrimPrefix(entry.fields["addresses"], "["), "]")
+		for _, addr := range strings.Split(addrs, ",") {
+			addr = strings.TrimSpace(addr)
+			if addr == "" {
+				continue
+			}
+			if net.ParseIP(addr) == nil {
+				return nil, errors.Errorf("srp server host %s: invalid address: %s", entry.name, addr)
+			}
+			host.Addresses = append(host.Addresses, addr)
+		}
+		hosts = append(hosts, host)
+	}
+	return hosts, nil
+}
+
+// ParseSrpServerServices parses the output of `srp server service`, where the port and host of a service instance
+// are listed as `port: 631` and `host: host1.default.service.arpa.`.
+func ParseSrpServerServices(output []string) ([]SrpService, error) {
+	entries, err := parseSrpServerEntries(output)
+	if err != nil {
+		return nil, err
+	}
+
+	services := []SrpService{}
+	for _, entry := range entries {
+		port, err := strconv.ParseUint(entry.fields["port"], 10, 16)
+		if err != nil {
+			return nil, errors.Wrapf(err, "srp server service %s", entry.name)
+		}
+		services = append(services, SrpService{Name: entry.name, Host: entry.fields["host"], Port: int(port)})
+	}
+	return services, nil
+}
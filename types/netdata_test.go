@@ -0,0 +1,117 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNetData(t *testing.T) {
+	netdata, err := ParseNetData([]string{
+		"Prefixes:",
+		"fd00:dead:beef:cafe::/64 paros med 4000",
+		"Routes:",
+		"fd00:1234::/64 s med 4000",
+		"fd00:5678::/64 high c000",
+		"Services:",
+		"44970 5d c000 s 4000",
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(netdata.Prefixes))
+	assert.Equal(t, "fd00:dead:beef:cafe::/64", netdata.Prefixes[0].Prefix.String())
+	assert.Equal(t, "paros", netdata.Prefixes[0].Flags)
+	assert.Equal(t, "med", netdata.Prefixes[0].Preference)
+	assert.Equal(t, uint16(0x4000), netdata.Prefixes[0].Rloc16)
+
+	assert.Equal(t, 2, len(netdata.Routes))
+	assert.Equal(t, "s", netdata.Routes[0].Flags)
+	assert.Equal(t, "", netdata.Routes[1].Flags)
+	assert.Equal(t, "high", netdata.Routes[1].Preference)
+	assert.Equal(t, uint16(0xc000), netdata.Routes[1].Rloc16)
+
+	assert.Equal(t, []NetDataService{
+		{EnterpriseNumber: 44970, ServiceData: []byte{0x5d}, ServerData: []byte{0xc0, 0x00}, Stable: true, Rloc16: 0x4000},
+	}, netdata.Services)
+
+	_, err = ParseNetData([]string{"Prefixes:", "fd00::/64 med"})
+	assert.NotNil(t, err)
+}
+
+func TestNetDataString(t *testing.T) {
+	for _, line := range []string{"fd00:dead:beef:cafe::/64 paros med 4000", "fd00:5678::/64 high c000"} {
+		prefix, err := ParseNetDataPrefix(line)
+		assert.Nil(t, err)
+		assert.Equal(t, line, prefix.String())
+
+		route, err := ParseNetDataRoute(line)
+		assert.Nil(t, err)
+		assert.Equal(t, line, route.String())
+	}
+
+	for _, line := range []string{"44970 5d c000 s 4000", "44970 01 0a0b 6c00"} {
+		service, err := ParseNetDataService(line)
+		assert.Nil(t, err)
+		assert.Equal(t, line, service.String())
+	}
+}
+
+func TestParseSrpServer(t *testing.T) {
+	hosts, err := ParseSrpServerHosts([]string{
+		"host1.default.service.arpa.",
+		"    deleted: false",
+		"    addresses: [fd00:0:0:0:0:0:0:1, fd00:0:0:0:0:0:0:2]",
+		"host2.default.service.arpa.",
+		"    deleted: true",
+		"    addresses: []",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []SrpHost{{Name: "host1.default.service.arpa.", Addresses: []string{"fd00:0:0:0:0:0:0:1", "fd00:0:0:0:0:0:0:2"}}}, hosts)
+
+	services, err := ParseSrpServerServices([]string{
+		"ins1._ipps._tcp.default.service.arpa.",
+		"    deleted: false",
+		"    port: 631",
+		"    priority: 0",
+		"    weight: 0",
+		"    host: host1.default.service.arpa.",
+		"    addresses: [fd00:0:0:0:0:0:0:1]",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []SrpService{{Name: "ins1._ipps._tcp.default.service.arpa.", Host: "host1.default.service.arpa.", Port: 631}}, services)
+
+	hosts, err = ParseSrpServerHosts(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, hosts)
+
+	_, err = ParseSrpServerHosts([]string{"    deleted: false"})
+	assert.NotNil(t, err)
+	_, err = ParseSrpServerServices([]string{"ins1._ipps._tcp.default.service.arpa.", "    port: x"})
+	assert.NotNil(t, err)
+}
@@ -29,6 +29,7 @@ package types
 import (
 	"math"
 
+	"github.com/pkg/errors"
 	"github.com/simonlingoogle/go-simplelogger"
 )
 
@@ -80,6 +81,9 @@ const (
 	AddrTypeMleid     AddrType = "mleid"
 	AddrTypeRloc      AddrType = "rloc"
 	AddrTypeLinkLocal AddrType = "linklocal"
+	AddrTypeAloc      AddrType = "aloc"
+	AddrTypeGlobal    AddrType = "global"
+	AddrTypeMulticast AddrType = "multicast"
 )
 
 type OtDeviceRole int
@@ -109,3 +113,13 @@ func (r OtDeviceRole) String() string {
 		return "invalid"
 	}
 }
+
+// ParseOtDeviceRole parses a device role name.
+func ParseOtDeviceRole(s string) (OtDeviceRole, error) {
+	for role := OtDeviceRoleDisabled; role <= OtDeviceRoleLeader; role++ {
+		if role.String() == s {
+			return role, nil
+		}
+	}
+	return OtDeviceRoleDisabled, errors.Errorf("invalid device role: %s", s)
+}
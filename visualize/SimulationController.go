@@ -26,6 +26,11 @@
 
 package visualize
 
+import "github.com/openthread/ot-ns/nodelog"
+
 type SimulationController interface {
 	Command(cmd string) ([]string, error)
+	NodeLogs() *nodelog.Capture
+	// CurTime returns the current virtual time of the simulation. It is safe to call from any goroutine.
+	CurTime() uint64
 }
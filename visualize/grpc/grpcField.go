@@ -77,6 +77,18 @@ func (f *grpcField) onNodeRecover(id NodeId) {
 	f.nodes[id].failed = false
 }
 
+func (f *grpcField) onNodeCrash(id NodeId, reason string) {
+	node := f.nodes[id]
+	node.crashed = true
+	node.crashReason = reason
+}
+
+func (f *grpcField) onNodeRestart(id NodeId) {
+	node := f.nodes[id]
+	node.crashed = false
+	node.crashReason = ""
+}
+
 func (f *grpcField) setNodePos(id NodeId, x int, y int) {
 	node := f.nodes[id]
 	node.x = x
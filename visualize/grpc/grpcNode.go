@@ -29,6 +29,7 @@ package visualize_grpc
 import (
 	"github.com/openthread/ot-ns/threadconst"
 	. "github.com/openthread/ot-ns/types"
+	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
 )
 
 type grpcNode struct {
@@ -42,9 +43,13 @@ type grpcNode struct {
 	role        OtDeviceRole
 	partitionId uint32
 	failed      bool
+	crashed     bool
+	crashReason string
 	parent      uint64
 	routerTable map[uint64]struct{}
 	childTable  map[uint64]struct{}
+	// networkState is the latest network state event of the node, or nil if the node has not reported any
+	networkState *pb.SetNodeNetworkStateEvent
 }
 
 func newGprcNode(id NodeId, x int, y int, radioRange int) *grpcNode {
@@ -29,8 +29,11 @@ package visualize_grpc
 import (
 	"context"
 	"net"
+	"regexp"
 	"time"
 
+	"github.com/openthread/ot-ns/nodelog"
+	. "github.com/openthread/ot-ns/types"
 	"github.com/simonlingoogle/go-simplelogger"
 
 	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
@@ -96,6 +99,49 @@ func (gs *grpcServer) Command(ctx context.Context, req *pb.CommandRequest) (*pb.
 	}, err
 }
 
+// NodeLogs sends the captured log lines of the requested node, and then streams new log lines until the client leaves.
+func (gs *grpcServer) NodeLogs(req *pb.NodeLogsRequest, stream pb.VisualizeGrpcService_NodeLogsServer) error {
+	var pattern *regexp.Regexp
+	if req.Grep != "" {
+		var err error
+		if pattern, err = regexp.Compile(req.Grep); err != nil {
+			return err
+		}
+	}
+
+	entries, sub := gs.vis.simctrl.NodeLogs().Follow(NodeId(req.NodeId), pattern, int(req.Last))
+	defer sub.Close()
+
+	for i := range entries {
+		if err := stream.Send(newNodeLogEntry(&entries[i])); err != nil {
+			return err
+		}
+	}
+
+	contextDone := stream.Context().Done()
+	for {
+		select {
+		case entry, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(newNodeLogEntry(&entry)); err != nil {
+				return err
+			}
+		case <-contextDone:
+			return stream.Context().Err()
+		}
+	}
+}
+
+func newNodeLogEntry(entry *nodelog.Entry) *pb.NodeLogEntry {
+	return &pb.NodeLogEntry{
+		NodeId:      int32(entry.NodeId),
+		VirtualTime: entry.Time,
+		Line:        entry.Line,
+	}
+}
+
 func (gs *grpcServer) Run() error {
 	lis, err := net.Listen("tcp", gs.address)
 	simplelogger.PanicIfError(err)
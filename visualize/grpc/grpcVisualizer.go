@@ -151,6 +151,7 @@ func (gv *grpcVisualizer) Send(srcid NodeId, dstid NodeId, mvinfo *visualize.Msg
 			Seq:             uint32(mvinfo.Seq),
 			DstAddrShort:    uint32(mvinfo.DstAddrShort),
 			DstAddrExtended: mvinfo.DstAddrExtended,
+			Label:           mvinfo.Label(),
 		},
 	}}}, false)
 }
@@ -166,6 +167,41 @@ func (gv *grpcVisualizer) SetNodePartitionId(nodeid NodeId, parid uint32) {
 	}}}, false)
 }
 
+func (gv *grpcVisualizer) SetNodeNetworkState(nodeid NodeId, state NodeNetworkState) {
+	gv.Lock()
+	defer gv.Unlock()
+
+	event := newNetworkStateEvent(nodeid, &state)
+	gv.f.nodes[nodeid].networkState = event
+	gv.AddVisualizationEvent(&pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNodeNetworkState{SetNodeNetworkState: event}}, false)
+}
+
+func newNetworkStateEvent(nodeid NodeId, state *NodeNetworkState) *pb.SetNodeNetworkStateEvent {
+	event := &pb.SetNodeNetworkStateEvent{
+		NodeId:         int32(nodeid),
+		LeaderRouterId: uint32(state.LeaderRouterId),
+		KeySequence:    state.KeySequence,
+		Channel:        uint32(state.Channel),
+	}
+
+	for _, prefix := range state.NetData.Prefixes {
+		event.Prefixes = append(event.Prefixes, prefix.String())
+	}
+	for _, route := range state.NetData.Routes {
+		event.Routes = append(event.Routes, route.String())
+	}
+	for _, service := range state.NetData.Services {
+		event.Services = append(event.Services, service.String())
+	}
+	for _, host := range state.SrpHosts {
+		event.SrpHosts = append(event.SrpHosts, &pb.SrpHost{Name: host.Name, Addresses: host.Addresses})
+	}
+	for _, service := range state.SrpServices {
+		event.SrpServices = append(event.SrpServices, &pb.SrpService{Name: service.Name, Host: service.Host, Port: uint32(service.Port)})
+	}
+	return event
+}
+
 func (gv *grpcVisualizer) SetSpeed(speed float64) {
 	gv.Lock()
 	defer gv.Unlock()
@@ -207,6 +243,39 @@ func (gv *grpcVisualizer) OnNodeRecover(nodeid NodeId) {
 	}}}, false)
 }
 
+// OnNodeCrash marks the node as crashed. The node is also shown as failed unless its radio is already turned off.
+func (gv *grpcVisualizer) OnNodeCrash(nodeid NodeId, reason string) {
+	gv.Lock()
+	defer gv.Unlock()
+
+	gv.f.onNodeCrash(nodeid, reason)
+	gv.AddVisualizationEvent(&pb.VisualizeEvent{Type: &pb.VisualizeEvent_OnNodeCrash{OnNodeCrash: &pb.OnNodeCrashEvent{
+		NodeId: int32(nodeid),
+		Reason: reason,
+	}}}, false)
+	if !gv.f.nodes[nodeid].failed {
+		gv.AddVisualizationEvent(&pb.VisualizeEvent{Type: &pb.VisualizeEvent_OnNodeFail{OnNodeFail: &pb.OnNodeFailEvent{
+			NodeId: int32(nodeid),
+		}}}, false)
+	}
+}
+
+// OnNodeRestart clears the crashed state of the node after its process was restarted.
+func (gv *grpcVisualizer) OnNodeRestart(nodeid NodeId) {
+	gv.Lock()
+	defer gv.Unlock()
+
+	gv.f.onNodeRestart(nodeid)
+	gv.AddVisualizationEvent(&pb.VisualizeEvent{Type: &pb.VisualizeEvent_OnNodeRestart{OnNodeRestart: &pb.OnNodeRestartEvent{
+		NodeId: int32(nodeid),
+	}}}, false)
+	if !gv.f.nodes[nodeid].failed {
+		gv.AddVisualizationEvent(&pb.VisualizeEvent{Type: &pb.VisualizeEvent_OnNodeRecover{OnNodeRecover: &pb.OnNodeRecoverEvent{
+			NodeId: int32(nodeid),
+		}}}, false)
+	}
+}
+
 func (gv *grpcVisualizer) SetController(ctrl visualize.SimulationController) {
 	gv.simctrl = ctrl
 }
@@ -325,97 +394,90 @@ func (gv *grpcVisualizer) SetTitle(titleInfo visualize.TitleInfo) {
 }
 
 func (gv *grpcVisualizer) prepareStream(stream *grpcStream) error {
+	for _, event := range gv.stateEvents() {
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stateEvents returns the events which draw the current state of the visualization from scratch.
+func (gv *grpcVisualizer) stateEvents() []*pb.VisualizeEvent {
+	var events []*pb.VisualizeEvent
+
 	// set network info
-	if err := stream.Send(&pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNetworkInfo{SetNetworkInfo: &pb.SetNetworkInfoEvent{
+	events = append(events, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNetworkInfo{SetNetworkInfo: &pb.SetNetworkInfoEvent{
 		Real:    gv.f.networkInfo.Real,
 		Version: gv.f.networkInfo.Version,
 		Commit:  gv.f.networkInfo.Commit,
-	}}}); err != nil {
-		return err
-	}
+	}}})
 	// show demo legend if necessary
 	if gv.showDemoLegendEvent != nil {
-		if err := stream.Send(gv.showDemoLegendEvent); err != nil {
-			return err
-		}
+		events = append(events, gv.showDemoLegendEvent)
 	}
 
 	// set speed
-	if err := stream.Send(&pb.VisualizeEvent{
+	events = append(events, &pb.VisualizeEvent{
 		Type: &pb.VisualizeEvent_SetSpeed{SetSpeed: &pb.SetSpeedEvent{
 			Speed: gv.f.speed,
 		}},
-	}); err != nil {
-		return err
-	}
+	})
 	// set title
 	if gv.f.titleInfo.Title != "" {
-		if err := stream.Send(&pb.VisualizeEvent{
+		events = append(events, &pb.VisualizeEvent{
 			Type: &pb.VisualizeEvent_SetTitle{SetTitle: &pb.SetTitleEvent{
 				Title:    gv.f.titleInfo.Title,
 				X:        int32(gv.f.titleInfo.X),
 				Y:        int32(gv.f.titleInfo.Y),
 				FontSize: int32(gv.f.titleInfo.FontSize),
 			}},
-		}); err != nil {
-			return err
-		}
+		})
 	}
 	// advance time
-	if err := stream.Send(&pb.VisualizeEvent{
+	events = append(events, &pb.VisualizeEvent{
 		Type: &pb.VisualizeEvent_AdvanceTime{AdvanceTime: &pb.AdvanceTimeEvent{
 			Ts:    gv.f.curTime,
 			Speed: gv.f.curSpeed,
 		}},
-	}); err != nil {
-		return err
-	}
+	})
 
 	// draw all nodes
 	for nodeid, node := range gv.f.nodes {
-		addNodeEvent := &pb.VisualizeEvent{Type: &pb.VisualizeEvent_AddNode{AddNode: &pb.AddNodeEvent{
+		events = append(events, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_AddNode{AddNode: &pb.AddNodeEvent{
 			NodeId:     int32(nodeid),
 			X:          int32(node.x),
 			Y:          int32(node.y),
 			RadioRange: int32(node.radioRange),
-		}}}
-
-		if err := stream.Send(addNodeEvent); err != nil {
-			return err
-		}
+		}}})
 	}
 
 	// draw node attributes
 	for nodeid, node := range gv.f.nodes {
 		// extaddr
-		if err := stream.Send(&pb.VisualizeEvent{
+		events = append(events, &pb.VisualizeEvent{
 			Type: &pb.VisualizeEvent_OnExtAddrChange{OnExtAddrChange: &pb.OnExtAddrChangeEvent{
 				NodeId:  int32(nodeid),
 				ExtAddr: node.extaddr,
 			}},
-		}); err != nil {
-			return err
-		}
+		})
 		// rloc16
-		if err := stream.Send(&pb.VisualizeEvent{
+		events = append(events, &pb.VisualizeEvent{
 			Type: &pb.VisualizeEvent_SetNodeRloc16{SetNodeRloc16: &pb.SetNodeRloc16Event{
 				NodeId: int32(nodeid),
 				Rloc16: uint32(node.rloc16),
 			}},
-		}); err != nil {
-			return err
-		}
+		})
 		// role
-		if err := stream.Send(&pb.VisualizeEvent{
+		events = append(events, &pb.VisualizeEvent{
 			Type: &pb.VisualizeEvent_SetNodeRole{SetNodeRole: &pb.SetNodeRoleEvent{
 				NodeId: int32(nodeid),
 				Role:   pb.OtDeviceRole(node.role),
 			}},
-		}); err != nil {
-			return err
-		}
+		})
 		// mode
-		if err := stream.Send(&pb.VisualizeEvent{
+		events = append(events, &pb.VisualizeEvent{
 			Type: &pb.VisualizeEvent_SetNodeMode{SetNodeMode: &pb.SetNodeModeEvent{
 				NodeId: int32(nodeid),
 				NodeMode: &pb.NodeMode{
@@ -424,80 +486,91 @@ func (gv *grpcVisualizer) prepareStream(stream *grpcStream) error {
 					FullNetworkData:  node.mode.FullNetworkData,
 				},
 			}},
-		}); err != nil {
-			return err
-		}
+		})
 		// partition id
-		if err := stream.Send(&pb.VisualizeEvent{
+		events = append(events, &pb.VisualizeEvent{
 			Type: &pb.VisualizeEvent_SetNodePartitionId{SetNodePartitionId: &pb.SetNodePartitionIdEvent{
 				NodeId:      int32(nodeid),
 				PartitionId: node.partitionId,
 			}},
-		}); err != nil {
-			return err
-		}
+		})
 		// parent
-		if err := stream.Send(&pb.VisualizeEvent{
+		events = append(events, &pb.VisualizeEvent{
 			Type: &pb.VisualizeEvent_SetParent{SetParent: &pb.SetParentEvent{
 				NodeId:  int32(nodeid),
 				ExtAddr: node.parent,
 			}},
-		}); err != nil {
-			return err
-		}
+		})
 
 		// child table
 		for extaddr := range node.childTable {
-			if err := stream.Send(&pb.VisualizeEvent{
+			events = append(events, &pb.VisualizeEvent{
 				Type: &pb.VisualizeEvent_AddChildTable{AddChildTable: &pb.AddChildTableEvent{
 					NodeId:  int32(nodeid),
 					ExtAddr: extaddr,
 				}},
-			}); err != nil {
-				return err
-			}
+			})
 		}
 		// router table
 		for extaddr := range node.routerTable {
-			if err := stream.Send(&pb.VisualizeEvent{
+			events = append(events, &pb.VisualizeEvent{
 				Type: &pb.VisualizeEvent_AddRouterTable{AddRouterTable: &pb.AddRouterTableEvent{
 					NodeId:  int32(nodeid),
 					ExtAddr: extaddr,
 				}},
-			}); err != nil {
-				return err
-			}
+			})
+		}
+		// network state
+		if node.networkState != nil {
+			events = append(events, &pb.VisualizeEvent{
+				Type: &pb.VisualizeEvent_SetNodeNetworkState{SetNodeNetworkState: node.networkState},
+			})
 		}
 		// node fail
-		if node.failed {
-			if err := stream.Send(&pb.VisualizeEvent{
+		if node.failed || node.crashed {
+			events = append(events, &pb.VisualizeEvent{
 				Type: &pb.VisualizeEvent_OnNodeFail{OnNodeFail: &pb.OnNodeFailEvent{
 					NodeId: int32(nodeid),
 				}},
-			}); err != nil {
-				return err
-			}
+			})
+		}
+		// node crash
+		if node.crashed {
+			events = append(events, &pb.VisualizeEvent{
+				Type: &pb.VisualizeEvent_OnNodeCrash{OnNodeCrash: &pb.OnNodeCrashEvent{
+					NodeId: int32(nodeid),
+					Reason: node.crashReason,
+				}},
+			})
 		}
 	}
 
-	return nil
+	return events
 }
 
 func (gv *grpcVisualizer) AddVisualizationEvent(event *pb.VisualizeEvent, trivial bool) {
 	if gv.replay != nil {
-		gv.replay.Append(event, trivial)
+		gv.replay.Append(event, gv.curTime(), trivial)
 	}
 	gv.server.SendEvent(event, trivial)
 }
 
-func NewGrpcVisualizer(address string, replayFn string) visualize.Visualizer {
+// curTime returns the current virtual time of the simulation, or 0 before the controller is set.
+func (gv *grpcVisualizer) curTime() uint64 {
+	if gv.simctrl == nil {
+		return 0
+	}
+	return gv.simctrl.CurTime()
+}
+
+func NewGrpcVisualizer(address string, replayFn string, replayOptions replay.Options) visualize.Visualizer {
 	gsv := &grpcVisualizer{
 		simctrl: nil,
 		f:       newGrpcField(),
 	}
 
 	if replayFn != "" {
-		gsv.replay = replay.NewReplay(replayFn)
+		gsv.replay = replay.NewReplay(replayFn, replayOptions, gsv.stateEvents)
 	}
 
 	gsv.server = newGrpcServer(gsv, address)
@@ -177,6 +177,9 @@ type VisualizeEvent struct {
 	//	*VisualizeEvent_SetTitle
 	//	*VisualizeEvent_SetNodeMode
 	//	*VisualizeEvent_SetNetworkInfo
+	//	*VisualizeEvent_OnNodeCrash
+	//	*VisualizeEvent_OnNodeRestart
+	//	*VisualizeEvent_SetNodeNetworkState
 	Type isVisualizeEvent_Type `protobuf_oneof:"type"`
 }
 
@@ -380,6 +383,27 @@ func (x *VisualizeEvent) GetSetNetworkInfo() *SetNetworkInfoEvent {
 	return nil
 }
 
+func (x *VisualizeEvent) GetOnNodeCrash() *OnNodeCrashEvent {
+	if x, ok := x.GetType().(*VisualizeEvent_OnNodeCrash); ok {
+		return x.OnNodeCrash
+	}
+	return nil
+}
+
+func (x *VisualizeEvent) GetOnNodeRestart() *OnNodeRestartEvent {
+	if x, ok := x.GetType().(*VisualizeEvent_OnNodeRestart); ok {
+		return x.OnNodeRestart
+	}
+	return nil
+}
+
+func (x *VisualizeEvent) GetSetNodeNetworkState() *SetNodeNetworkStateEvent {
+	if x, ok := x.GetType().(*VisualizeEvent_SetNodeNetworkState); ok {
+		return x.SetNodeNetworkState
+	}
+	return nil
+}
+
 type isVisualizeEvent_Type interface {
 	isVisualizeEvent_Type()
 }
@@ -476,6 +500,18 @@ type VisualizeEvent_SetNetworkInfo struct {
 	SetNetworkInfo *SetNetworkInfoEvent `protobuf:"bytes,23,opt,name=set_network_info,json=setNetworkInfo,proto3,oneof"`
 }
 
+type VisualizeEvent_OnNodeCrash struct {
+	OnNodeCrash *OnNodeCrashEvent `protobuf:"bytes,24,opt,name=on_node_crash,json=onNodeCrash,proto3,oneof"`
+}
+
+type VisualizeEvent_OnNodeRestart struct {
+	OnNodeRestart *OnNodeRestartEvent `protobuf:"bytes,25,opt,name=on_node_restart,json=onNodeRestart,proto3,oneof"`
+}
+
+type VisualizeEvent_SetNodeNetworkState struct {
+	SetNodeNetworkState *SetNodeNetworkStateEvent `protobuf:"bytes,26,opt,name=set_node_network_state,json=setNodeNetworkState,proto3,oneof"`
+}
+
 func (*VisualizeEvent_AddNode) isVisualizeEvent_Type() {}
 
 func (*VisualizeEvent_DeleteNode) isVisualizeEvent_Type() {}
@@ -522,6 +558,12 @@ func (*VisualizeEvent_SetNodeMode) isVisualizeEvent_Type() {}
 
 func (*VisualizeEvent_SetNetworkInfo) isVisualizeEvent_Type() {}
 
+func (*VisualizeEvent_OnNodeCrash) isVisualizeEvent_Type() {}
+
+func (*VisualizeEvent_OnNodeRestart) isVisualizeEvent_Type() {}
+
+func (*VisualizeEvent_SetNodeNetworkState) isVisualizeEvent_Type() {}
+
 type SendEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -595,6 +637,7 @@ type MsgVisualizeInfo struct {
 	Seq             uint32 `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
 	DstAddrShort    uint32 `protobuf:"varint,4,opt,name=dst_addr_short,json=dstAddrShort,proto3" json:"dst_addr_short,omitempty"`
 	DstAddrExtended uint64 `protobuf:"varint,5,opt,name=dst_addr_extended,json=dstAddrExtended,proto3" json:"dst_addr_extended,omitempty"`
+	Label           string `protobuf:"bytes,6,opt,name=label,proto3" json:"label,omitempty"`
 }
 
 func (x *MsgVisualizeInfo) Reset() {
@@ -664,6 +707,13 @@ func (x *MsgVisualizeInfo) GetDstAddrExtended() uint64 {
 	return 0
 }
 
+func (x *MsgVisualizeInfo) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
 type AddRouterTableEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1464,16 +1514,17 @@ func (x *OnNodeRecoverEvent) GetNodeId() int32 {
 	return 0
 }
 
-type DeleteNodeEvent struct {
+type OnNodeCrashEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	NodeId int32 `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	NodeId int32  `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 }
 
-func (x *DeleteNodeEvent) Reset() {
-	*x = DeleteNodeEvent{}
+func (x *OnNodeCrashEvent) Reset() {
+	*x = OnNodeCrashEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_visualize_grpc_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1481,13 +1532,13 @@ func (x *DeleteNodeEvent) Reset() {
 	}
 }
 
-func (x *DeleteNodeEvent) String() string {
+func (x *OnNodeCrashEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteNodeEvent) ProtoMessage() {}
+func (*OnNodeCrashEvent) ProtoMessage() {}
 
-func (x *DeleteNodeEvent) ProtoReflect() protoreflect.Message {
+func (x *OnNodeCrashEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_visualize_grpc_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1499,31 +1550,35 @@ func (x *DeleteNodeEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteNodeEvent.ProtoReflect.Descriptor instead.
-func (*DeleteNodeEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use OnNodeCrashEvent.ProtoReflect.Descriptor instead.
+func (*OnNodeCrashEvent) Descriptor() ([]byte, []int) {
 	return file_visualize_grpc_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *DeleteNodeEvent) GetNodeId() int32 {
+func (x *OnNodeCrashEvent) GetNodeId() int32 {
 	if x != nil {
 		return x.NodeId
 	}
 	return 0
 }
 
-type AddNodeEvent struct {
+func (x *OnNodeCrashEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type OnNodeRestartEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	NodeId     int32 `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	X          int32 `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y          int32 `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
-	RadioRange int32 `protobuf:"varint,4,opt,name=radio_range,json=radioRange,proto3" json:"radio_range,omitempty"`
+	NodeId int32 `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 }
 
-func (x *AddNodeEvent) Reset() {
-	*x = AddNodeEvent{}
+func (x *OnNodeRestartEvent) Reset() {
+	*x = OnNodeRestartEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_visualize_grpc_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1531,13 +1586,13 @@ func (x *AddNodeEvent) Reset() {
 	}
 }
 
-func (x *AddNodeEvent) String() string {
+func (x *OnNodeRestartEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddNodeEvent) ProtoMessage() {}
+func (*OnNodeRestartEvent) ProtoMessage() {}
 
-func (x *AddNodeEvent) ProtoReflect() protoreflect.Message {
+func (x *OnNodeRestartEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_visualize_grpc_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1549,52 +1604,37 @@ func (x *AddNodeEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddNodeEvent.ProtoReflect.Descriptor instead.
-func (*AddNodeEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use OnNodeRestartEvent.ProtoReflect.Descriptor instead.
+func (*OnNodeRestartEvent) Descriptor() ([]byte, []int) {
 	return file_visualize_grpc_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *AddNodeEvent) GetNodeId() int32 {
+func (x *OnNodeRestartEvent) GetNodeId() int32 {
 	if x != nil {
 		return x.NodeId
 	}
 	return 0
 }
 
-func (x *AddNodeEvent) GetX() int32 {
-	if x != nil {
-		return x.X
-	}
-	return 0
-}
-
-func (x *AddNodeEvent) GetY() int32 {
-	if x != nil {
-		return x.Y
-	}
-	return 0
-}
-
-func (x *AddNodeEvent) GetRadioRange() int32 {
-	if x != nil {
-		return x.RadioRange
-	}
-	return 0
-}
-
-type NodeMode struct {
+type SetNodeNetworkStateEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RxOnWhenIdle       bool `protobuf:"varint,1,opt,name=rx_on_when_idle,json=rxOnWhenIdle,proto3" json:"rx_on_when_idle,omitempty"`
-	SecureDataRequests bool `protobuf:"varint,2,opt,name=secure_data_requests,json=secureDataRequests,proto3" json:"secure_data_requests,omitempty"`
-	FullThreadDevice   bool `protobuf:"varint,3,opt,name=full_thread_device,json=fullThreadDevice,proto3" json:"full_thread_device,omitempty"`
-	FullNetworkData    bool `protobuf:"varint,4,opt,name=full_network_data,json=fullNetworkData,proto3" json:"full_network_data,omitempty"`
+	NodeId         int32  `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	LeaderRouterId uint32 `protobuf:"varint,2,opt,name=leader_router_id,json=leaderRouterId,proto3" json:"leader_router_id,omitempty"`
+	KeySequence    uint32 `protobuf:"varint,3,opt,name=key_sequence,json=keySequence,proto3" json:"key_sequence,omitempty"`
+	Channel        uint32 `protobuf:"varint,4,opt,name=channel,proto3" json:"channel,omitempty"`
+	// prefixes, routes and services are formatted as in `netdata show`
+	Prefixes    []string      `protobuf:"bytes,5,rep,name=prefixes,proto3" json:"prefixes,omitempty"`
+	Routes      []string      `protobuf:"bytes,6,rep,name=routes,proto3" json:"routes,omitempty"`
+	Services    []string      `protobuf:"bytes,7,rep,name=services,proto3" json:"services,omitempty"`
+	SrpHosts    []*SrpHost    `protobuf:"bytes,8,rep,name=srp_hosts,json=srpHosts,proto3" json:"srp_hosts,omitempty"`
+	SrpServices []*SrpService `protobuf:"bytes,9,rep,name=srp_services,json=srpServices,proto3" json:"srp_services,omitempty"`
 }
 
-func (x *NodeMode) Reset() {
-	*x = NodeMode{}
+func (x *SetNodeNetworkStateEvent) Reset() {
+	*x = SetNodeNetworkStateEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_visualize_grpc_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1602,13 +1642,13 @@ func (x *NodeMode) Reset() {
 	}
 }
 
-func (x *NodeMode) String() string {
+func (x *SetNodeNetworkStateEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NodeMode) ProtoMessage() {}
+func (*SetNodeNetworkStateEvent) ProtoMessage() {}
 
-func (x *NodeMode) ProtoReflect() protoreflect.Message {
+func (x *SetNodeNetworkStateEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_visualize_grpc_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1620,50 +1660,85 @@ func (x *NodeMode) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NodeMode.ProtoReflect.Descriptor instead.
-func (*NodeMode) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetNodeNetworkStateEvent.ProtoReflect.Descriptor instead.
+func (*SetNodeNetworkStateEvent) Descriptor() ([]byte, []int) {
 	return file_visualize_grpc_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *NodeMode) GetRxOnWhenIdle() bool {
+func (x *SetNodeNetworkStateEvent) GetNodeId() int32 {
 	if x != nil {
-		return x.RxOnWhenIdle
+		return x.NodeId
 	}
-	return false
+	return 0
 }
 
-func (x *NodeMode) GetSecureDataRequests() bool {
+func (x *SetNodeNetworkStateEvent) GetLeaderRouterId() uint32 {
 	if x != nil {
-		return x.SecureDataRequests
+		return x.LeaderRouterId
 	}
-	return false
+	return 0
 }
 
-func (x *NodeMode) GetFullThreadDevice() bool {
+func (x *SetNodeNetworkStateEvent) GetKeySequence() uint32 {
 	if x != nil {
-		return x.FullThreadDevice
+		return x.KeySequence
 	}
-	return false
+	return 0
 }
 
-func (x *NodeMode) GetFullNetworkData() bool {
+func (x *SetNodeNetworkStateEvent) GetChannel() uint32 {
 	if x != nil {
-		return x.FullNetworkData
+		return x.Channel
 	}
-	return false
+	return 0
 }
 
-type SetNodeRloc16Event struct {
+func (x *SetNodeNetworkStateEvent) GetPrefixes() []string {
+	if x != nil {
+		return x.Prefixes
+	}
+	return nil
+}
+
+func (x *SetNodeNetworkStateEvent) GetRoutes() []string {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *SetNodeNetworkStateEvent) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *SetNodeNetworkStateEvent) GetSrpHosts() []*SrpHost {
+	if x != nil {
+		return x.SrpHosts
+	}
+	return nil
+}
+
+func (x *SetNodeNetworkStateEvent) GetSrpServices() []*SrpService {
+	if x != nil {
+		return x.SrpServices
+	}
+	return nil
+}
+
+type SrpHost struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	NodeId int32  `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	Rloc16 uint32 `protobuf:"varint,2,opt,name=rloc16,proto3" json:"rloc16,omitempty"`
+	Name      string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Addresses []string `protobuf:"bytes,2,rep,name=addresses,proto3" json:"addresses,omitempty"`
 }
 
-func (x *SetNodeRloc16Event) Reset() {
-	*x = SetNodeRloc16Event{}
+func (x *SrpHost) Reset() {
+	*x = SrpHost{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_visualize_grpc_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1671,13 +1746,13 @@ func (x *SetNodeRloc16Event) Reset() {
 	}
 }
 
-func (x *SetNodeRloc16Event) String() string {
+func (x *SrpHost) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetNodeRloc16Event) ProtoMessage() {}
+func (*SrpHost) ProtoMessage() {}
 
-func (x *SetNodeRloc16Event) ProtoReflect() protoreflect.Message {
+func (x *SrpHost) ProtoReflect() protoreflect.Message {
 	mi := &file_visualize_grpc_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1689,36 +1764,37 @@ func (x *SetNodeRloc16Event) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetNodeRloc16Event.ProtoReflect.Descriptor instead.
-func (*SetNodeRloc16Event) Descriptor() ([]byte, []int) {
+// Deprecated: Use SrpHost.ProtoReflect.Descriptor instead.
+func (*SrpHost) Descriptor() ([]byte, []int) {
 	return file_visualize_grpc_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *SetNodeRloc16Event) GetNodeId() int32 {
+func (x *SrpHost) GetName() string {
 	if x != nil {
-		return x.NodeId
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *SetNodeRloc16Event) GetRloc16() uint32 {
+func (x *SrpHost) GetAddresses() []string {
 	if x != nil {
-		return x.Rloc16
+		return x.Addresses
 	}
-	return 0
+	return nil
 }
 
-type OnExtAddrChangeEvent struct {
+type SrpService struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	NodeId  int32  `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	ExtAddr uint64 `protobuf:"varint,2,opt,name=ext_addr,json=extAddr,proto3" json:"ext_addr,omitempty"`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Host string `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Port uint32 `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
 }
 
-func (x *OnExtAddrChangeEvent) Reset() {
-	*x = OnExtAddrChangeEvent{}
+func (x *SrpService) Reset() {
+	*x = SrpService{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_visualize_grpc_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1726,13 +1802,13 @@ func (x *OnExtAddrChangeEvent) Reset() {
 	}
 }
 
-func (x *OnExtAddrChangeEvent) String() string {
+func (x *SrpService) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OnExtAddrChangeEvent) ProtoMessage() {}
+func (*SrpService) ProtoMessage() {}
 
-func (x *OnExtAddrChangeEvent) ProtoReflect() protoreflect.Message {
+func (x *SrpService) ProtoReflect() protoreflect.Message {
 	mi := &file_visualize_grpc_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1744,38 +1820,42 @@ func (x *OnExtAddrChangeEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OnExtAddrChangeEvent.ProtoReflect.Descriptor instead.
-func (*OnExtAddrChangeEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use SrpService.ProtoReflect.Descriptor instead.
+func (*SrpService) Descriptor() ([]byte, []int) {
 	return file_visualize_grpc_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *OnExtAddrChangeEvent) GetNodeId() int32 {
+func (x *SrpService) GetName() string {
 	if x != nil {
-		return x.NodeId
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *OnExtAddrChangeEvent) GetExtAddr() uint64 {
+func (x *SrpService) GetHost() string {
 	if x != nil {
-		return x.ExtAddr
+		return x.Host
+	}
+	return ""
+}
+
+func (x *SrpService) GetPort() uint32 {
+	if x != nil {
+		return x.Port
 	}
 	return 0
 }
 
-type SetTitleEvent struct {
+type DeleteNodeEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Title    string `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	X        int32  `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
-	Y        int32  `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
-	FontSize int32  `protobuf:"varint,4,opt,name=font_size,json=fontSize,proto3" json:"font_size,omitempty"`
+	NodeId int32 `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
 }
 
-func (x *SetTitleEvent) Reset() {
-	*x = SetTitleEvent{}
+func (x *DeleteNodeEvent) Reset() {
+	*x = DeleteNodeEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_visualize_grpc_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1783,13 +1863,13 @@ func (x *SetTitleEvent) Reset() {
 	}
 }
 
-func (x *SetTitleEvent) String() string {
+func (x *DeleteNodeEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetTitleEvent) ProtoMessage() {}
+func (*DeleteNodeEvent) ProtoMessage() {}
 
-func (x *SetTitleEvent) ProtoReflect() protoreflect.Message {
+func (x *DeleteNodeEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_visualize_grpc_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1801,50 +1881,31 @@ func (x *SetTitleEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetTitleEvent.ProtoReflect.Descriptor instead.
-func (*SetTitleEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteNodeEvent.ProtoReflect.Descriptor instead.
+func (*DeleteNodeEvent) Descriptor() ([]byte, []int) {
 	return file_visualize_grpc_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *SetTitleEvent) GetTitle() string {
-	if x != nil {
-		return x.Title
-	}
-	return ""
-}
-
-func (x *SetTitleEvent) GetX() int32 {
-	if x != nil {
-		return x.X
-	}
-	return 0
-}
-
-func (x *SetTitleEvent) GetY() int32 {
-	if x != nil {
-		return x.Y
-	}
-	return 0
-}
-
-func (x *SetTitleEvent) GetFontSize() int32 {
+func (x *DeleteNodeEvent) GetNodeId() int32 {
 	if x != nil {
-		return x.FontSize
+		return x.NodeId
 	}
 	return 0
 }
 
-type SetNodeModeEvent struct {
+type AddNodeEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	NodeId   int32     `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
-	NodeMode *NodeMode `protobuf:"bytes,2,opt,name=node_mode,json=nodeMode,proto3" json:"node_mode,omitempty"`
+	NodeId     int32 `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	X          int32 `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y          int32 `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	RadioRange int32 `protobuf:"varint,4,opt,name=radio_range,json=radioRange,proto3" json:"radio_range,omitempty"`
 }
 
-func (x *SetNodeModeEvent) Reset() {
-	*x = SetNodeModeEvent{}
+func (x *AddNodeEvent) Reset() {
+	*x = AddNodeEvent{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_visualize_grpc_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1852,13 +1913,13 @@ func (x *SetNodeModeEvent) Reset() {
 	}
 }
 
-func (x *SetNodeModeEvent) String() string {
+func (x *AddNodeEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetNodeModeEvent) ProtoMessage() {}
+func (*AddNodeEvent) ProtoMessage() {}
 
-func (x *SetNodeModeEvent) ProtoReflect() protoreflect.Message {
+func (x *AddNodeEvent) ProtoReflect() protoreflect.Message {
 	mi := &file_visualize_grpc_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1870,37 +1931,52 @@ func (x *SetNodeModeEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetNodeModeEvent.ProtoReflect.Descriptor instead.
-func (*SetNodeModeEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddNodeEvent.ProtoReflect.Descriptor instead.
+func (*AddNodeEvent) Descriptor() ([]byte, []int) {
 	return file_visualize_grpc_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *SetNodeModeEvent) GetNodeId() int32 {
+func (x *AddNodeEvent) GetNodeId() int32 {
 	if x != nil {
 		return x.NodeId
 	}
 	return 0
 }
 
-func (x *SetNodeModeEvent) GetNodeMode() *NodeMode {
+func (x *AddNodeEvent) GetX() int32 {
 	if x != nil {
-		return x.NodeMode
+		return x.X
 	}
-	return nil
+	return 0
 }
 
-type SetNetworkInfoEvent struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Real    bool   `protobuf:"varint,1,opt,name=real,proto3" json:"real,omitempty"`
-	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
-	Commit  string `protobuf:"bytes,3,opt,name=commit,proto3" json:"commit,omitempty"`
-}
+func (x *AddNodeEvent) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
 
-func (x *SetNetworkInfoEvent) Reset() {
-	*x = SetNetworkInfoEvent{}
+func (x *AddNodeEvent) GetRadioRange() int32 {
+	if x != nil {
+		return x.RadioRange
+	}
+	return 0
+}
+
+type NodeMode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RxOnWhenIdle       bool `protobuf:"varint,1,opt,name=rx_on_when_idle,json=rxOnWhenIdle,proto3" json:"rx_on_when_idle,omitempty"`
+	SecureDataRequests bool `protobuf:"varint,2,opt,name=secure_data_requests,json=secureDataRequests,proto3" json:"secure_data_requests,omitempty"`
+	FullThreadDevice   bool `protobuf:"varint,3,opt,name=full_thread_device,json=fullThreadDevice,proto3" json:"full_thread_device,omitempty"`
+	FullNetworkData    bool `protobuf:"varint,4,opt,name=full_network_data,json=fullNetworkData,proto3" json:"full_network_data,omitempty"`
+}
+
+func (x *NodeMode) Reset() {
+	*x = NodeMode{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_visualize_grpc_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1908,13 +1984,13 @@ func (x *SetNetworkInfoEvent) Reset() {
 	}
 }
 
-func (x *SetNetworkInfoEvent) String() string {
+func (x *NodeMode) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetNetworkInfoEvent) ProtoMessage() {}
+func (*NodeMode) ProtoMessage() {}
 
-func (x *SetNetworkInfoEvent) ProtoReflect() protoreflect.Message {
+func (x *NodeMode) ProtoReflect() protoreflect.Message {
 	mi := &file_visualize_grpc_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1926,57 +2002,726 @@ func (x *SetNetworkInfoEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetNetworkInfoEvent.ProtoReflect.Descriptor instead.
-func (*SetNetworkInfoEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use NodeMode.ProtoReflect.Descriptor instead.
+func (*NodeMode) Descriptor() ([]byte, []int) {
 	return file_visualize_grpc_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *SetNetworkInfoEvent) GetReal() bool {
+func (x *NodeMode) GetRxOnWhenIdle() bool {
 	if x != nil {
-		return x.Real
+		return x.RxOnWhenIdle
 	}
 	return false
 }
 
-func (x *SetNetworkInfoEvent) GetVersion() string {
+func (x *NodeMode) GetSecureDataRequests() bool {
 	if x != nil {
-		return x.Version
+		return x.SecureDataRequests
 	}
-	return ""
+	return false
 }
 
-func (x *SetNetworkInfoEvent) GetCommit() string {
+func (x *NodeMode) GetFullThreadDevice() bool {
 	if x != nil {
-		return x.Commit
+		return x.FullThreadDevice
+	}
+	return false
+}
+
+func (x *NodeMode) GetFullNetworkData() bool {
+	if x != nil {
+		return x.FullNetworkData
+	}
+	return false
+}
+
+type SetNodeRloc16Event struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeId int32  `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Rloc16 uint32 `protobuf:"varint,2,opt,name=rloc16,proto3" json:"rloc16,omitempty"`
+}
+
+func (x *SetNodeRloc16Event) Reset() {
+	*x = SetNodeRloc16Event{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetNodeRloc16Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNodeRloc16Event) ProtoMessage() {}
+
+func (x *SetNodeRloc16Event) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNodeRloc16Event.ProtoReflect.Descriptor instead.
+func (*SetNodeRloc16Event) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SetNodeRloc16Event) GetNodeId() int32 {
+	if x != nil {
+		return x.NodeId
+	}
+	return 0
+}
+
+func (x *SetNodeRloc16Event) GetRloc16() uint32 {
+	if x != nil {
+		return x.Rloc16
+	}
+	return 0
+}
+
+type OnExtAddrChangeEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeId  int32  `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	ExtAddr uint64 `protobuf:"varint,2,opt,name=ext_addr,json=extAddr,proto3" json:"ext_addr,omitempty"`
+}
+
+func (x *OnExtAddrChangeEvent) Reset() {
+	*x = OnExtAddrChangeEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OnExtAddrChangeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnExtAddrChangeEvent) ProtoMessage() {}
+
+func (x *OnExtAddrChangeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnExtAddrChangeEvent.ProtoReflect.Descriptor instead.
+func (*OnExtAddrChangeEvent) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *OnExtAddrChangeEvent) GetNodeId() int32 {
+	if x != nil {
+		return x.NodeId
+	}
+	return 0
+}
+
+func (x *OnExtAddrChangeEvent) GetExtAddr() uint64 {
+	if x != nil {
+		return x.ExtAddr
+	}
+	return 0
+}
+
+type SetTitleEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Title    string `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	X        int32  `protobuf:"varint,2,opt,name=x,proto3" json:"x,omitempty"`
+	Y        int32  `protobuf:"varint,3,opt,name=y,proto3" json:"y,omitempty"`
+	FontSize int32  `protobuf:"varint,4,opt,name=font_size,json=fontSize,proto3" json:"font_size,omitempty"`
+}
+
+func (x *SetTitleEvent) Reset() {
+	*x = SetTitleEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetTitleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTitleEvent) ProtoMessage() {}
+
+func (x *SetTitleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTitleEvent.ProtoReflect.Descriptor instead.
+func (*SetTitleEvent) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SetTitleEvent) GetTitle() string {
+	if x != nil {
+		return x.Title
 	}
 	return ""
 }
 
-type CommandRequest struct {
+func (x *SetTitleEvent) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *SetTitleEvent) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *SetTitleEvent) GetFontSize() int32 {
+	if x != nil {
+		return x.FontSize
+	}
+	return 0
+}
+
+type SetNodeModeEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Command string `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	NodeId   int32     `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	NodeMode *NodeMode `protobuf:"bytes,2,opt,name=node_mode,json=nodeMode,proto3" json:"node_mode,omitempty"`
+}
+
+func (x *SetNodeModeEvent) Reset() {
+	*x = SetNodeModeEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetNodeModeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNodeModeEvent) ProtoMessage() {}
+
+func (x *SetNodeModeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNodeModeEvent.ProtoReflect.Descriptor instead.
+func (*SetNodeModeEvent) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SetNodeModeEvent) GetNodeId() int32 {
+	if x != nil {
+		return x.NodeId
+	}
+	return 0
+}
+
+func (x *SetNodeModeEvent) GetNodeMode() *NodeMode {
+	if x != nil {
+		return x.NodeMode
+	}
+	return nil
+}
+
+type SetNetworkInfoEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Real    bool   `protobuf:"varint,1,opt,name=real,proto3" json:"real,omitempty"`
+	Version string `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Commit  string `protobuf:"bytes,3,opt,name=commit,proto3" json:"commit,omitempty"`
+}
+
+func (x *SetNetworkInfoEvent) Reset() {
+	*x = SetNetworkInfoEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetNetworkInfoEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNetworkInfoEvent) ProtoMessage() {}
+
+func (x *SetNetworkInfoEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNetworkInfoEvent.ProtoReflect.Descriptor instead.
+func (*SetNetworkInfoEvent) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SetNetworkInfoEvent) GetReal() bool {
+	if x != nil {
+		return x.Real
+	}
+	return false
+}
+
+func (x *SetNetworkInfoEvent) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *SetNetworkInfoEvent) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+type CommandRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Command string `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+}
+
+func (x *CommandRequest) Reset() {
+	*x = CommandRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandRequest) ProtoMessage() {}
+
+func (x *CommandRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandRequest.ProtoReflect.Descriptor instead.
+func (*CommandRequest) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *CommandRequest) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+type CommandResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Output []string `protobuf:"bytes,1,rep,name=output,proto3" json:"output,omitempty"`
+}
+
+func (x *CommandResponse) Reset() {
+	*x = CommandResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResponse) ProtoMessage() {}
+
+func (x *CommandResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
+func (*CommandResponse) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CommandResponse) GetOutput() []string {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+type NodeLogsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// node_id 0 selects all nodes
+	NodeId int32  `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Grep   string `protobuf:"bytes,2,opt,name=grep,proto3" json:"grep,omitempty"`
+	Last   int32  `protobuf:"varint,3,opt,name=last,proto3" json:"last,omitempty"`
+}
+
+func (x *NodeLogsRequest) Reset() {
+	*x = NodeLogsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeLogsRequest) ProtoMessage() {}
+
+func (x *NodeLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeLogsRequest.ProtoReflect.Descriptor instead.
+func (*NodeLogsRequest) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *NodeLogsRequest) GetNodeId() int32 {
+	if x != nil {
+		return x.NodeId
+	}
+	return 0
+}
+
+func (x *NodeLogsRequest) GetGrep() string {
+	if x != nil {
+		return x.Grep
+	}
+	return ""
+}
+
+func (x *NodeLogsRequest) GetLast() int32 {
+	if x != nil {
+		return x.Last
+	}
+	return 0
+}
+
+type NodeLogEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeId      int32  `protobuf:"varint,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	VirtualTime uint64 `protobuf:"varint,2,opt,name=virtual_time,json=virtualTime,proto3" json:"virtual_time,omitempty"`
+	Line        string `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`
+}
+
+func (x *NodeLogEntry) Reset() {
+	*x = NodeLogEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeLogEntry) ProtoMessage() {}
+
+func (x *NodeLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeLogEntry.ProtoReflect.Descriptor instead.
+func (*NodeLogEntry) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *NodeLogEntry) GetNodeId() int32 {
+	if x != nil {
+		return x.NodeId
+	}
+	return 0
+}
+
+func (x *NodeLogEntry) GetVirtualTime() uint64 {
+	if x != nil {
+		return x.VirtualTime
+	}
+	return 0
+}
+
+func (x *NodeLogEntry) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+type ReplayHeader struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FormatVersion uint32               `protobuf:"varint,1,opt,name=format_version,json=formatVersion,proto3" json:"format_version,omitempty"`
+	OtnsVersion   string               `protobuf:"bytes,2,opt,name=otns_version,json=otnsVersion,proto3" json:"otns_version,omitempty"`
+	Seed          int64                `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"`
+	NetworkInfo   *SetNetworkInfoEvent `protobuf:"bytes,4,opt,name=network_info,json=networkInfo,proto3" json:"network_info,omitempty"`
+}
+
+func (x *ReplayHeader) Reset() {
+	*x = ReplayHeader{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayHeader) ProtoMessage() {}
+
+func (x *ReplayHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayHeader.ProtoReflect.Descriptor instead.
+func (*ReplayHeader) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ReplayHeader) GetFormatVersion() uint32 {
+	if x != nil {
+		return x.FormatVersion
+	}
+	return 0
+}
+
+func (x *ReplayHeader) GetOtnsVersion() string {
+	if x != nil {
+		return x.OtnsVersion
+	}
+	return ""
+}
+
+func (x *ReplayHeader) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *ReplayHeader) GetNetworkInfo() *SetNetworkInfoEvent {
+	if x != nil {
+		return x.NetworkInfo
+	}
+	return nil
+}
+
+type ReplayEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timestamp   uint64          `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Event       *VisualizeEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	Keyframe    *ReplayKeyframe `protobuf:"bytes,3,opt,name=keyframe,proto3" json:"keyframe,omitempty"`
+	VirtualTime uint64          `protobuf:"varint,4,opt,name=virtual_time,json=virtualTime,proto3" json:"virtual_time,omitempty"`
+}
+
+func (x *ReplayEntry) Reset() {
+	*x = ReplayEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_visualize_grpc_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReplayEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayEntry) ProtoMessage() {}
+
+func (x *ReplayEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayEntry.ProtoReflect.Descriptor instead.
+func (*ReplayEntry) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ReplayEntry) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ReplayEntry) GetEvent() *VisualizeEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *ReplayEntry) GetKeyframe() *ReplayKeyframe {
+	if x != nil {
+		return x.Keyframe
+	}
+	return nil
+}
+
+func (x *ReplayEntry) GetVirtualTime() uint64 {
+	if x != nil {
+		return x.VirtualTime
+	}
+	return 0
+}
+
+type ReplayKeyframe struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*VisualizeEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
 }
 
-func (x *CommandRequest) Reset() {
-	*x = CommandRequest{}
+func (x *ReplayKeyframe) Reset() {
+	*x = ReplayKeyframe{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_visualize_grpc_proto_msgTypes[27]
+		mi := &file_visualize_grpc_proto_msgTypes[38]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CommandRequest) String() string {
+func (x *ReplayKeyframe) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CommandRequest) ProtoMessage() {}
+func (*ReplayKeyframe) ProtoMessage() {}
 
-func (x *CommandRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_visualize_grpc_proto_msgTypes[27]
+func (x *ReplayKeyframe) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[38]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1987,43 +2732,44 @@ func (x *CommandRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CommandRequest.ProtoReflect.Descriptor instead.
-func (*CommandRequest) Descriptor() ([]byte, []int) {
-	return file_visualize_grpc_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use ReplayKeyframe.ProtoReflect.Descriptor instead.
+func (*ReplayKeyframe) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *CommandRequest) GetCommand() string {
+func (x *ReplayKeyframe) GetEvents() []*VisualizeEvent {
 	if x != nil {
-		return x.Command
+		return x.Events
 	}
-	return ""
+	return nil
 }
 
-type CommandResponse struct {
+type ReplayIndex struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Output []string `protobuf:"bytes,1,rep,name=output,proto3" json:"output,omitempty"`
+	ReplaySize uint64              `protobuf:"varint,1,opt,name=replay_size,json=replaySize,proto3" json:"replay_size,omitempty"`
+	Keyframes  []*ReplayIndexEntry `protobuf:"bytes,2,rep,name=keyframes,proto3" json:"keyframes,omitempty"`
 }
 
-func (x *CommandResponse) Reset() {
-	*x = CommandResponse{}
+func (x *ReplayIndex) Reset() {
+	*x = ReplayIndex{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_visualize_grpc_proto_msgTypes[28]
+		mi := &file_visualize_grpc_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *CommandResponse) String() string {
+func (x *ReplayIndex) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CommandResponse) ProtoMessage() {}
+func (*ReplayIndex) ProtoMessage() {}
 
-func (x *CommandResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_visualize_grpc_proto_msgTypes[28]
+func (x *ReplayIndex) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2034,44 +2780,52 @@ func (x *CommandResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CommandResponse.ProtoReflect.Descriptor instead.
-func (*CommandResponse) Descriptor() ([]byte, []int) {
-	return file_visualize_grpc_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use ReplayIndex.ProtoReflect.Descriptor instead.
+func (*ReplayIndex) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *CommandResponse) GetOutput() []string {
+func (x *ReplayIndex) GetReplaySize() uint64 {
 	if x != nil {
-		return x.Output
+		return x.ReplaySize
+	}
+	return 0
+}
+
+func (x *ReplayIndex) GetKeyframes() []*ReplayIndexEntry {
+	if x != nil {
+		return x.Keyframes
 	}
 	return nil
 }
 
-type ReplayEntry struct {
+type ReplayIndexEntry struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Timestamp uint64          `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Event     *VisualizeEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	Timestamp   uint64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Offset      uint64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	VirtualTime uint64 `protobuf:"varint,3,opt,name=virtual_time,json=virtualTime,proto3" json:"virtual_time,omitempty"`
 }
 
-func (x *ReplayEntry) Reset() {
-	*x = ReplayEntry{}
+func (x *ReplayIndexEntry) Reset() {
+	*x = ReplayIndexEntry{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_visualize_grpc_proto_msgTypes[29]
+		mi := &file_visualize_grpc_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ReplayEntry) String() string {
+func (x *ReplayIndexEntry) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReplayEntry) ProtoMessage() {}
+func (*ReplayIndexEntry) ProtoMessage() {}
 
-func (x *ReplayEntry) ProtoReflect() protoreflect.Message {
-	mi := &file_visualize_grpc_proto_msgTypes[29]
+func (x *ReplayIndexEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_visualize_grpc_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2082,23 +2836,30 @@ func (x *ReplayEntry) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReplayEntry.ProtoReflect.Descriptor instead.
-func (*ReplayEntry) Descriptor() ([]byte, []int) {
-	return file_visualize_grpc_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use ReplayIndexEntry.ProtoReflect.Descriptor instead.
+func (*ReplayIndexEntry) Descriptor() ([]byte, []int) {
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *ReplayEntry) GetTimestamp() uint64 {
+func (x *ReplayIndexEntry) GetTimestamp() uint64 {
 	if x != nil {
 		return x.Timestamp
 	}
 	return 0
 }
 
-func (x *ReplayEntry) GetEvent() *VisualizeEvent {
+func (x *ReplayIndexEntry) GetOffset() uint64 {
 	if x != nil {
-		return x.Event
+		return x.Offset
 	}
-	return nil
+	return 0
+}
+
+func (x *ReplayIndexEntry) GetVirtualTime() uint64 {
+	if x != nil {
+		return x.VirtualTime
+	}
+	return 0
 }
 
 type Empty struct {
@@ -2110,7 +2871,7 @@ type Empty struct {
 func (x *Empty) Reset() {
 	*x = Empty{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_visualize_grpc_proto_msgTypes[30]
+		mi := &file_visualize_grpc_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -2123,7 +2884,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_visualize_grpc_proto_msgTypes[30]
+	mi := &file_visualize_grpc_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2136,7 +2897,7 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_visualize_grpc_proto_rawDescGZIP(), []int{30}
+	return file_visualize_grpc_proto_rawDescGZIP(), []int{41}
 }
 
 var File_visualize_grpc_proto protoreflect.FileDescriptor
@@ -2145,7 +2906,7 @@ var file_visualize_grpc_proto_rawDesc = []byte{
 	0x0a, 0x14, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a,
 	0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x22, 0x12, 0x0a, 0x10, 0x56, 0x69, 0x73,
-	0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xed, 0x0d,
+	0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xed, 0x0f,
 	0x0a, 0x0e, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74,
 	0x12, 0x3c, 0x0a, 0x08, 0x61, 0x64, 0x64, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67,
@@ -2256,7 +3017,23 @@ var file_visualize_grpc_proto_rawDesc = []byte{
 	0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e,
 	0x53, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x76,
 	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x73, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
-	0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x77, 0x0a,
+	0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x49, 0x0a, 0x0d, 0x6f, 0x6e, 0x5f, 0x6e, 0x6f, 0x64, 0x65,
+	0x5f, 0x63, 0x72, 0x61, 0x73, 0x68, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x76,
+	0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62,
+	0x2e, 0x4f, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x72, 0x61, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x48, 0x00, 0x52, 0x0b, 0x6f, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x72, 0x61, 0x73, 0x68,
+	0x12, 0x4f, 0x0a, 0x0f, 0x6f, 0x6e, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x72, 0x65, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x76, 0x69, 0x73, 0x75,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x4f, 0x6e,
+	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x48, 0x00, 0x52, 0x0d, 0x6f, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x12, 0x62, 0x0a, 0x16, 0x73, 0x65, 0x74, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x2b, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72,
+	0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x13, 0x73, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x77, 0x0a,
 	0x09, 0x53, 0x65, 0x6e, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x73, 0x72,
 	0x63, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x72, 0x63, 0x49,
 	0x64, 0x12, 0x15, 0x0a, 0x06, 0x64, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
@@ -2264,7 +3041,7 @@ var file_visualize_grpc_proto_rawDesc = []byte{
 	0x6e, 0x66, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x76, 0x69, 0x73, 0x75,
 	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x4d, 0x73,
 	0x67, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06,
-	0x6d, 0x76, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0xb5, 0x01, 0x0a, 0x10, 0x4d, 0x73, 0x67, 0x56, 0x69,
+	0x6d, 0x76, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0xcb, 0x01, 0x0a, 0x10, 0x4d, 0x73, 0x67, 0x56, 0x69,
 	0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x18, 0x0a, 0x07, 0x63,
 	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x63, 0x68,
 	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x5f, 0x63,
@@ -2275,152 +3052,245 @@ var file_visualize_grpc_proto_rawDesc = []byte{
 	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x64, 0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x53, 0x68, 0x6f,
 	0x72, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x64, 0x73, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x5f, 0x65,
 	0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x64,
-	0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x22, 0x49,
-	0x0a, 0x13, 0x41, 0x64, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x54, 0x61, 0x62, 0x6c, 0x65,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x19,
-	0x0a, 0x08, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x07, 0x65, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22, 0x4c, 0x0a, 0x16, 0x52, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x76,
+	0x73, 0x74, 0x41, 0x64, 0x64, 0x72, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x64, 0x65, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c,
+	0x61, 0x62, 0x65, 0x6c, 0x22, 0x49, 0x0a, 0x13, 0x41, 0x64, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e,
+	0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f,
+	0x64, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x65, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22,
+	0x4c, 0x0a, 0x16, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x54,
+	0x61, 0x62, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65,
+	0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x65, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22, 0x48, 0x0a,
+	0x12, 0x41, 0x64, 0x64, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x76,
 	0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08,
 	0x65, 0x78, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
-	0x65, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22, 0x48, 0x0a, 0x12, 0x41, 0x64, 0x64, 0x43, 0x68,
-	0x69, 0x6c, 0x64, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a,
+	0x65, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22, 0x4b, 0x0a, 0x15, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x78, 0x74,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x65, 0x78, 0x74,
+	0x41, 0x64, 0x64, 0x72, 0x22, 0x25, 0x0a, 0x0d, 0x53, 0x65, 0x74, 0x53, 0x70, 0x65, 0x65, 0x64,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x22, 0x10, 0x0a, 0x0e, 0x48,
+	0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x38, 0x0a,
+	0x10, 0x41, 0x64, 0x76, 0x61, 0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x74,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x22, 0x44, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x50, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65,
+	0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x65, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22, 0x45, 0x0a,
+	0x0e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x44, 0x6f, 0x77, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x1f, 0x0a, 0x0b, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x65, 0x78, 0x74, 0x22, 0x47, 0x0a, 0x13, 0x53, 0x68, 0x6f, 0x77, 0x44, 0x65, 0x6d, 0x6f,
+	0x4c, 0x65, 0x67, 0x65, 0x6e, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x22, 0x46, 0x0a,
+	0x0f, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x01, 0x79, 0x22, 0x60, 0x0a, 0x10, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65,
+	0x52, 0x6f, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65,
+	0x49, 0x64, 0x12, 0x33, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1f, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70,
+	0x63, 0x5f, 0x70, 0x62, 0x2e, 0x4f, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x6f, 0x6c,
+	0x65, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22, 0x55, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x4e, 0x6f,
+	0x64, 0x65, 0x50, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x70,
+	0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x2a,
+	0x0a, 0x0f, 0x4f, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x2d, 0x0a, 0x12, 0x4f, 0x6e,
+	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x43, 0x0a, 0x10, 0x4f, 0x6e, 0x4e,
+	0x6f, 0x64, 0x65, 0x43, 0x72, 0x61, 0x73, 0x68, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a,
 	0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
-	0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x64,
-	0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x65, 0x78, 0x74, 0x41, 0x64, 0x64,
-	0x72, 0x22, 0x4b, 0x0a, 0x15, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x43, 0x68, 0x69, 0x6c, 0x64,
-	0x54, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f,
-	0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64,
-	0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x65, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22, 0x25,
-	0x0a, 0x0d, 0x53, 0x65, 0x74, 0x53, 0x70, 0x65, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
-	0x14, 0x0a, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05,
-	0x73, 0x70, 0x65, 0x65, 0x64, 0x22, 0x10, 0x0a, 0x0e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65,
-	0x61, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x38, 0x0a, 0x10, 0x41, 0x64, 0x76, 0x61, 0x6e,
-	0x63, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x74,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73,
-	0x70, 0x65, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x70, 0x65, 0x65,
-	0x64, 0x22, 0x44, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08,
-	0x65, 0x78, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07,
-	0x65, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22, 0x45, 0x0a, 0x0e, 0x43, 0x6f, 0x75, 0x6e, 0x74,
-	0x44, 0x6f, 0x77, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x64, 0x75, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
-	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
-	0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x47,
-	0x0a, 0x13, 0x53, 0x68, 0x6f, 0x77, 0x44, 0x65, 0x6d, 0x6f, 0x4c, 0x65, 0x67, 0x65, 0x6e, 0x64,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01,
-	0x79, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x22, 0x46, 0x0a, 0x0f, 0x53, 0x65, 0x74, 0x4e, 0x6f,
-	0x64, 0x65, 0x50, 0x6f, 0x73, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f,
-	0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64,
-	0x65, 0x49, 0x64, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01,
-	0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x79, 0x22,
-	0x60, 0x0a, 0x10, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x33, 0x0a, 0x04,
-	0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x76, 0x69, 0x73,
-	0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x4f,
-	0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x52, 0x04, 0x72, 0x6f, 0x6c,
-	0x65, 0x22, 0x55, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x61, 0x72, 0x74,
-	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07,
-	0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e,
-	0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69,
-	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x61, 0x72,
-	0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x2a, 0x0a, 0x0f, 0x4f, 0x6e, 0x4e, 0x6f,
-	0x64, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e,
-	0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f,
-	0x64, 0x65, 0x49, 0x64, 0x22, 0x2d, 0x0a, 0x12, 0x4f, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65,
-	0x63, 0x6f, 0x76, 0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f,
+	0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x2d,
+	0x0a, 0x12, 0x4f, 0x6e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73, 0x74, 0x61, 0x72, 0x74, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0xe5, 0x02,
+	0x0a, 0x18, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f,
 	0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64,
-	0x65, 0x49, 0x64, 0x22, 0x2a, 0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4e, 0x6f, 0x64,
-	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22,
-	0x64, 0x0a, 0x0c, 0x41, 0x64, 0x64, 0x4e, 0x6f, 0x64, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x65, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x6c, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6c,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x49, 0x64, 0x12, 0x21, 0x0a,
+	0x0c, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6b, 0x65, 0x79, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x37, 0x0a, 0x09, 0x73, 0x72,
+	0x70, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70,
+	0x62, 0x2e, 0x53, 0x72, 0x70, 0x48, 0x6f, 0x73, 0x74, 0x52, 0x08, 0x73, 0x72, 0x70, 0x48, 0x6f,
+	0x73, 0x74, 0x73, 0x12, 0x40, 0x0a, 0x0c, 0x73, 0x72, 0x70, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x69, 0x73, 0x75,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x53, 0x72,
+	0x70, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x0b, 0x73, 0x72, 0x70, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x22, 0x3b, 0x0a, 0x07, 0x53, 0x72, 0x70, 0x48, 0x6f, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x22, 0x48, 0x0a, 0x0a, 0x53, 0x72, 0x70, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x2a, 0x0a, 0x0f,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
 	0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x01, 0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x61, 0x64, 0x69, 0x6f, 0x5f, 0x72, 0x61,
-	0x6e, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x72, 0x61, 0x64, 0x69, 0x6f,
-	0x52, 0x61, 0x6e, 0x67, 0x65, 0x22, 0xbd, 0x01, 0x0a, 0x08, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x6f,
-	0x64, 0x65, 0x12, 0x25, 0x0a, 0x0f, 0x72, 0x78, 0x5f, 0x6f, 0x6e, 0x5f, 0x77, 0x68, 0x65, 0x6e,
-	0x5f, 0x69, 0x64, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x72, 0x78, 0x4f,
-	0x6e, 0x57, 0x68, 0x65, 0x6e, 0x49, 0x64, 0x6c, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x65, 0x63,
-	0x75, 0x72, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x44,
-	0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x66,
-	0x75, 0x6c, 0x6c, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63,
-	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x66, 0x75, 0x6c, 0x6c, 0x54, 0x68, 0x72,
-	0x65, 0x61, 0x64, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x66, 0x75, 0x6c,
-	0x6c, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x66, 0x75, 0x6c, 0x6c, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
-	0x6b, 0x44, 0x61, 0x74, 0x61, 0x22, 0x45, 0x0a, 0x12, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65,
-	0x52, 0x6c, 0x6f, 0x63, 0x31, 0x36, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e,
-	0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f,
-	0x64, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6c, 0x6f, 0x63, 0x31, 0x36, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x72, 0x6c, 0x6f, 0x63, 0x31, 0x36, 0x22, 0x4a, 0x0a, 0x14,
-	0x4f, 0x6e, 0x45, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x45,
+	0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x22, 0x64, 0x0a, 0x0c, 0x41, 0x64, 0x64, 0x4e,
+	0x6f, 0x64, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49,
+	0x64, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12,
+	0x0c, 0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x79, 0x12, 0x1f, 0x0a,
+	0x0b, 0x72, 0x61, 0x64, 0x69, 0x6f, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x72, 0x61, 0x64, 0x69, 0x6f, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x22, 0xbd,
+	0x01, 0x0a, 0x08, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x25, 0x0a, 0x0f, 0x72,
+	0x78, 0x5f, 0x6f, 0x6e, 0x5f, 0x77, 0x68, 0x65, 0x6e, 0x5f, 0x69, 0x64, 0x6c, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x72, 0x78, 0x4f, 0x6e, 0x57, 0x68, 0x65, 0x6e, 0x49, 0x64,
+	0x6c, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x64, 0x61, 0x74,
+	0x61, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x12, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x44, 0x61, 0x74, 0x61, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x66, 0x75, 0x6c, 0x6c, 0x5f, 0x74, 0x68, 0x72,
+	0x65, 0x61, 0x64, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x10, 0x66, 0x75, 0x6c, 0x6c, 0x54, 0x68, 0x72, 0x65, 0x61, 0x64, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x66, 0x75, 0x6c, 0x6c, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x66,
+	0x75, 0x6c, 0x6c, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x44, 0x61, 0x74, 0x61, 0x22, 0x45,
+	0x0a, 0x12, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x6c, 0x6f, 0x63, 0x31, 0x36, 0x45,
 	0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a,
-	0x08, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x07, 0x65, 0x78, 0x74, 0x41, 0x64, 0x64, 0x72, 0x22, 0x5e, 0x0a, 0x0d, 0x53, 0x65, 0x74, 0x54,
-	0x69, 0x74, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74,
-	0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12,
-	0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a,
-	0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x66,
-	0x6f, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
-	0x66, 0x6f, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x65, 0x0a, 0x10, 0x53, 0x65, 0x74, 0x4e,
-	0x6f, 0x64, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07,
-	0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e,
-	0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6d, 0x6f,
-	0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61,
-	0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x4e, 0x6f, 0x64,
-	0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x22,
-	0x5b, 0x0a, 0x13, 0x53, 0x65, 0x74, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x66,
-	0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x65, 0x61, 0x6c, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x72, 0x65, 0x61, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22, 0x2a, 0x0a, 0x0e,
-	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18,
-	0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x22, 0x29, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x6d,
-	0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f,
-	0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x75, 0x74,
-	0x70, 0x75, 0x74, 0x22, 0x64, 0x0a, 0x0b, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x12, 0x37, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x6c, 0x6f, 0x63, 0x31, 0x36, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x72,
+	0x6c, 0x6f, 0x63, 0x31, 0x36, 0x22, 0x4a, 0x0a, 0x14, 0x4f, 0x6e, 0x45, 0x78, 0x74, 0x41, 0x64,
+	0x64, 0x72, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x78, 0x74, 0x5f, 0x61, 0x64,
+	0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x65, 0x78, 0x74, 0x41, 0x64, 0x64,
+	0x72, 0x22, 0x5e, 0x0a, 0x0d, 0x53, 0x65, 0x74, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x01, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x6f, 0x6e, 0x74, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x66, 0x6f, 0x6e, 0x74, 0x53, 0x69, 0x7a,
+	0x65, 0x22, 0x65, 0x0a, 0x10, 0x53, 0x65, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x6f, 0x64, 0x65,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x38,
+	0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1b, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72,
+	0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x08,
+	0x6e, 0x6f, 0x64, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x22, 0x5b, 0x0a, 0x13, 0x53, 0x65, 0x74, 0x4e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x72, 0x65, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x72,
+	0x65, 0x61, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x22, 0x2a, 0x0a, 0x0e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x22, 0x29, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0x52, 0x0a, 0x0f,
+	0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x6f, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x72, 0x65, 0x70,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x72, 0x65, 0x70, 0x12, 0x12, 0x0a, 0x04,
+	0x6c, 0x61, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x61, 0x73, 0x74,
+	0x22, 0x5e, 0x0a, 0x0c, 0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x76, 0x69, 0x72,
+	0x74, 0x75, 0x61, 0x6c, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x0b, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65,
+	0x22, 0xb7, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x12, 0x25, 0x0a, 0x0e, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x66, 0x6f, 0x72, 0x6d, 0x61,
+	0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x74, 0x6e, 0x73,
+	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x6f, 0x74, 0x6e, 0x73, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x73,
+	0x65, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12,
+	0x49, 0x0a, 0x0c, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x53, 0x65, 0x74, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x0b, 0x6e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0xc6, 0x01, 0x0a, 0x0b, 0x52,
+	0x65, 0x70, 0x6c, 0x61, 0x79, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x37, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x56, 0x69, 0x73, 0x75,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x3d, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x4b, 0x65,
+	0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65,
+	0x12, 0x21, 0x0a, 0x0c, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x54,
+	0x69, 0x6d, 0x65, 0x22, 0x4b, 0x0a, 0x0e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x4b, 0x65, 0x79,
+	0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x39, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a,
+	0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73,
+	0x22, 0x71, 0x0a, 0x0b, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12,
+	0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x41, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x66, 0x72, 0x61,
+	0x6d, 0x65, 0x73, 0x22, 0x6b, 0x0a, 0x10, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x21, 0x0a,
+	0x0c, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0b, 0x76, 0x69, 0x72, 0x74, 0x75, 0x61, 0x6c, 0x54, 0x69, 0x6d, 0x65,
+	0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x2a, 0x98, 0x01, 0x0a, 0x0c, 0x4f, 0x74,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x4f, 0x54,
+	0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x44, 0x49, 0x53,
+	0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1b, 0x0a, 0x17, 0x4f, 0x54, 0x5f, 0x44, 0x45,
+	0x56, 0x49, 0x43, 0x45, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x44, 0x45, 0x54, 0x41, 0x43, 0x48,
+	0x45, 0x44, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x4f, 0x54, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43,
+	0x45, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x43, 0x48, 0x49, 0x4c, 0x44, 0x10, 0x02, 0x12, 0x19,
+	0x0a, 0x15, 0x4f, 0x54, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x52, 0x4f, 0x4c, 0x45,
+	0x5f, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x52, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x4f, 0x54, 0x5f,
+	0x44, 0x45, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x4c, 0x45, 0x41, 0x44,
+	0x45, 0x52, 0x10, 0x04, 0x32, 0x92, 0x02, 0x0a, 0x14, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x47, 0x72, 0x70, 0x63, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x55, 0x0a,
+	0x09, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x12, 0x23, 0x2e, 0x76, 0x69, 0x73,
+	0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x56,
+	0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
 	0x21, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63,
 	0x5f, 0x70, 0x62, 0x2e, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x45, 0x76, 0x65,
-	0x6e, 0x74, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x2a, 0x98, 0x01, 0x0a, 0x0c, 0x4f, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52,
-	0x6f, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x4f, 0x54, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45,
-	0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x00,
-	0x12, 0x1b, 0x0a, 0x17, 0x4f, 0x54, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x52, 0x4f,
-	0x4c, 0x45, 0x5f, 0x44, 0x45, 0x54, 0x41, 0x43, 0x48, 0x45, 0x44, 0x10, 0x01, 0x12, 0x18, 0x0a,
-	0x14, 0x4f, 0x54, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f,
-	0x43, 0x48, 0x49, 0x4c, 0x44, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x4f, 0x54, 0x5f, 0x44, 0x45,
-	0x56, 0x49, 0x43, 0x45, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x52,
-	0x10, 0x03, 0x12, 0x19, 0x0a, 0x15, 0x4f, 0x54, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45, 0x5f,
-	0x52, 0x4f, 0x4c, 0x45, 0x5f, 0x4c, 0x45, 0x41, 0x44, 0x45, 0x52, 0x10, 0x04, 0x32, 0xbf, 0x01,
-	0x0a, 0x14, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x47, 0x72, 0x70, 0x63, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x55, 0x0a, 0x09, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c,
-	0x69, 0x7a, 0x65, 0x12, 0x23, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f,
-	0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x56, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61,
-	0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x56, 0x69, 0x73,
-	0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x50, 0x0a,
-	0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x21, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61,
-	0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x76, 0x69,
-	0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e,
-	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x74, 0x30, 0x01, 0x12, 0x50, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
+	0x21, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63,
+	0x5f, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67,
+	0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x08, 0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x6f,
+	0x67, 0x73, 0x12, 0x22, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x5f, 0x67,
+	0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x6f, 0x67, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x76, 0x69, 0x73, 0x75, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x5f, 0x67, 0x72, 0x70, 0x63, 0x5f, 0x70, 0x62, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x4c,
+	0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x30, 0x01, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
 }
 
 var (
@@ -2436,45 +3306,56 @@ func file_visualize_grpc_proto_rawDescGZIP() []byte {
 }
 
 var file_visualize_grpc_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_visualize_grpc_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_visualize_grpc_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
 var file_visualize_grpc_proto_goTypes = []interface{}{
-	(OtDeviceRole)(0),               // 0: visualize_grpc_pb.OtDeviceRole
-	(*VisualizeRequest)(nil),        // 1: visualize_grpc_pb.VisualizeRequest
-	(*VisualizeEvent)(nil),          // 2: visualize_grpc_pb.VisualizeEvent
-	(*SendEvent)(nil),               // 3: visualize_grpc_pb.SendEvent
-	(*MsgVisualizeInfo)(nil),        // 4: visualize_grpc_pb.MsgVisualizeInfo
-	(*AddRouterTableEvent)(nil),     // 5: visualize_grpc_pb.AddRouterTableEvent
-	(*RemoveRouterTableEvent)(nil),  // 6: visualize_grpc_pb.RemoveRouterTableEvent
-	(*AddChildTableEvent)(nil),      // 7: visualize_grpc_pb.AddChildTableEvent
-	(*RemoveChildTableEvent)(nil),   // 8: visualize_grpc_pb.RemoveChildTableEvent
-	(*SetSpeedEvent)(nil),           // 9: visualize_grpc_pb.SetSpeedEvent
-	(*HeartbeatEvent)(nil),          // 10: visualize_grpc_pb.HeartbeatEvent
-	(*AdvanceTimeEvent)(nil),        // 11: visualize_grpc_pb.AdvanceTimeEvent
-	(*SetParentEvent)(nil),          // 12: visualize_grpc_pb.SetParentEvent
-	(*CountDownEvent)(nil),          // 13: visualize_grpc_pb.CountDownEvent
-	(*ShowDemoLegendEvent)(nil),     // 14: visualize_grpc_pb.ShowDemoLegendEvent
-	(*SetNodePosEvent)(nil),         // 15: visualize_grpc_pb.SetNodePosEvent
-	(*SetNodeRoleEvent)(nil),        // 16: visualize_grpc_pb.SetNodeRoleEvent
-	(*SetNodePartitionIdEvent)(nil), // 17: visualize_grpc_pb.SetNodePartitionIdEvent
-	(*OnNodeFailEvent)(nil),         // 18: visualize_grpc_pb.OnNodeFailEvent
-	(*OnNodeRecoverEvent)(nil),      // 19: visualize_grpc_pb.OnNodeRecoverEvent
-	(*DeleteNodeEvent)(nil),         // 20: visualize_grpc_pb.DeleteNodeEvent
-	(*AddNodeEvent)(nil),            // 21: visualize_grpc_pb.AddNodeEvent
-	(*NodeMode)(nil),                // 22: visualize_grpc_pb.NodeMode
-	(*SetNodeRloc16Event)(nil),      // 23: visualize_grpc_pb.SetNodeRloc16Event
-	(*OnExtAddrChangeEvent)(nil),    // 24: visualize_grpc_pb.OnExtAddrChangeEvent
-	(*SetTitleEvent)(nil),           // 25: visualize_grpc_pb.SetTitleEvent
-	(*SetNodeModeEvent)(nil),        // 26: visualize_grpc_pb.SetNodeModeEvent
-	(*SetNetworkInfoEvent)(nil),     // 27: visualize_grpc_pb.SetNetworkInfoEvent
-	(*CommandRequest)(nil),          // 28: visualize_grpc_pb.CommandRequest
-	(*CommandResponse)(nil),         // 29: visualize_grpc_pb.CommandResponse
-	(*ReplayEntry)(nil),             // 30: visualize_grpc_pb.ReplayEntry
-	(*Empty)(nil),                   // 31: visualize_grpc_pb.Empty
+	(OtDeviceRole)(0),                // 0: visualize_grpc_pb.OtDeviceRole
+	(*VisualizeRequest)(nil),         // 1: visualize_grpc_pb.VisualizeRequest
+	(*VisualizeEvent)(nil),           // 2: visualize_grpc_pb.VisualizeEvent
+	(*SendEvent)(nil),                // 3: visualize_grpc_pb.SendEvent
+	(*MsgVisualizeInfo)(nil),         // 4: visualize_grpc_pb.MsgVisualizeInfo
+	(*AddRouterTableEvent)(nil),      // 5: visualize_grpc_pb.AddRouterTableEvent
+	(*RemoveRouterTableEvent)(nil),   // 6: visualize_grpc_pb.RemoveRouterTableEvent
+	(*AddChildTableEvent)(nil),       // 7: visualize_grpc_pb.AddChildTableEvent
+	(*RemoveChildTableEvent)(nil),    // 8: visualize_grpc_pb.RemoveChildTableEvent
+	(*SetSpeedEvent)(nil),            // 9: visualize_grpc_pb.SetSpeedEvent
+	(*HeartbeatEvent)(nil),           // 10: visualize_grpc_pb.HeartbeatEvent
+	(*AdvanceTimeEvent)(nil),         // 11: visualize_grpc_pb.AdvanceTimeEvent
+	(*SetParentEvent)(nil),           // 12: visualize_grpc_pb.SetParentEvent
+	(*CountDownEvent)(nil),           // 13: visualize_grpc_pb.CountDownEvent
+	(*ShowDemoLegendEvent)(nil),      // 14: visualize_grpc_pb.ShowDemoLegendEvent
+	(*SetNodePosEvent)(nil),          // 15: visualize_grpc_pb.SetNodePosEvent
+	(*SetNodeRoleEvent)(nil),         // 16: visualize_grpc_pb.SetNodeRoleEvent
+	(*SetNodePartitionIdEvent)(nil),  // 17: visualize_grpc_pb.SetNodePartitionIdEvent
+	(*OnNodeFailEvent)(nil),          // 18: visualize_grpc_pb.OnNodeFailEvent
+	(*OnNodeRecoverEvent)(nil),       // 19: visualize_grpc_pb.OnNodeRecoverEvent
+	(*OnNodeCrashEvent)(nil),         // 20: visualize_grpc_pb.OnNodeCrashEvent
+	(*OnNodeRestartEvent)(nil),       // 21: visualize_grpc_pb.OnNodeRestartEvent
+	(*SetNodeNetworkStateEvent)(nil), // 22: visualize_grpc_pb.SetNodeNetworkStateEvent
+	(*SrpHost)(nil),                  // 23: visualize_grpc_pb.SrpHost
+	(*SrpService)(nil),               // 24: visualize_grpc_pb.SrpService
+	(*DeleteNodeEvent)(nil),          // 25: visualize_grpc_pb.DeleteNodeEvent
+	(*AddNodeEvent)(nil),             // 26: visualize_grpc_pb.AddNodeEvent
+	(*NodeMode)(nil),                 // 27: visualize_grpc_pb.NodeMode
+	(*SetNodeRloc16Event)(nil),       // 28: visualize_grpc_pb.SetNodeRloc16Event
+	(*OnExtAddrChangeEvent)(nil),     // 29: visualize_grpc_pb.OnExtAddrChangeEvent
+	(*SetTitleEvent)(nil),            // 30: visualize_grpc_pb.SetTitleEvent
+	(*SetNodeModeEvent)(nil),         // 31: visualize_grpc_pb.SetNodeModeEvent
+	(*SetNetworkInfoEvent)(nil),      // 32: visualize_grpc_pb.SetNetworkInfoEvent
+	(*CommandRequest)(nil),           // 33: visualize_grpc_pb.CommandRequest
+	(*CommandResponse)(nil),          // 34: visualize_grpc_pb.CommandResponse
+	(*NodeLogsRequest)(nil),          // 35: visualize_grpc_pb.NodeLogsRequest
+	(*NodeLogEntry)(nil),             // 36: visualize_grpc_pb.NodeLogEntry
+	(*ReplayHeader)(nil),             // 37: visualize_grpc_pb.ReplayHeader
+	(*ReplayEntry)(nil),              // 38: visualize_grpc_pb.ReplayEntry
+	(*ReplayKeyframe)(nil),           // 39: visualize_grpc_pb.ReplayKeyframe
+	(*ReplayIndex)(nil),              // 40: visualize_grpc_pb.ReplayIndex
+	(*ReplayIndexEntry)(nil),         // 41: visualize_grpc_pb.ReplayIndexEntry
+	(*Empty)(nil),                    // 42: visualize_grpc_pb.Empty
 }
 var file_visualize_grpc_proto_depIdxs = []int32{
-	21, // 0: visualize_grpc_pb.VisualizeEvent.add_node:type_name -> visualize_grpc_pb.AddNodeEvent
-	20, // 1: visualize_grpc_pb.VisualizeEvent.delete_node:type_name -> visualize_grpc_pb.DeleteNodeEvent
-	23, // 2: visualize_grpc_pb.VisualizeEvent.set_node_rloc16:type_name -> visualize_grpc_pb.SetNodeRloc16Event
+	26, // 0: visualize_grpc_pb.VisualizeEvent.add_node:type_name -> visualize_grpc_pb.AddNodeEvent
+	25, // 1: visualize_grpc_pb.VisualizeEvent.delete_node:type_name -> visualize_grpc_pb.DeleteNodeEvent
+	28, // 2: visualize_grpc_pb.VisualizeEvent.set_node_rloc16:type_name -> visualize_grpc_pb.SetNodeRloc16Event
 	16, // 3: visualize_grpc_pb.VisualizeEvent.set_node_role:type_name -> visualize_grpc_pb.SetNodeRoleEvent
 	15, // 4: visualize_grpc_pb.VisualizeEvent.set_node_pos:type_name -> visualize_grpc_pb.SetNodePosEvent
 	17, // 5: visualize_grpc_pb.VisualizeEvent.set_node_partition_id:type_name -> visualize_grpc_pb.SetNodePartitionIdEvent
@@ -2491,23 +3372,34 @@ var file_visualize_grpc_proto_depIdxs = []int32{
 	3,  // 16: visualize_grpc_pb.VisualizeEvent.send:type_name -> visualize_grpc_pb.SendEvent
 	9,  // 17: visualize_grpc_pb.VisualizeEvent.set_speed:type_name -> visualize_grpc_pb.SetSpeedEvent
 	10, // 18: visualize_grpc_pb.VisualizeEvent.heartbeat:type_name -> visualize_grpc_pb.HeartbeatEvent
-	24, // 19: visualize_grpc_pb.VisualizeEvent.on_ext_addr_change:type_name -> visualize_grpc_pb.OnExtAddrChangeEvent
-	25, // 20: visualize_grpc_pb.VisualizeEvent.set_title:type_name -> visualize_grpc_pb.SetTitleEvent
-	26, // 21: visualize_grpc_pb.VisualizeEvent.set_node_mode:type_name -> visualize_grpc_pb.SetNodeModeEvent
-	27, // 22: visualize_grpc_pb.VisualizeEvent.set_network_info:type_name -> visualize_grpc_pb.SetNetworkInfoEvent
-	4,  // 23: visualize_grpc_pb.SendEvent.mv_info:type_name -> visualize_grpc_pb.MsgVisualizeInfo
-	0,  // 24: visualize_grpc_pb.SetNodeRoleEvent.role:type_name -> visualize_grpc_pb.OtDeviceRole
-	22, // 25: visualize_grpc_pb.SetNodeModeEvent.node_mode:type_name -> visualize_grpc_pb.NodeMode
-	2,  // 26: visualize_grpc_pb.ReplayEntry.event:type_name -> visualize_grpc_pb.VisualizeEvent
-	1,  // 27: visualize_grpc_pb.VisualizeGrpcService.Visualize:input_type -> visualize_grpc_pb.VisualizeRequest
-	28, // 28: visualize_grpc_pb.VisualizeGrpcService.Command:input_type -> visualize_grpc_pb.CommandRequest
-	2,  // 29: visualize_grpc_pb.VisualizeGrpcService.Visualize:output_type -> visualize_grpc_pb.VisualizeEvent
-	29, // 30: visualize_grpc_pb.VisualizeGrpcService.Command:output_type -> visualize_grpc_pb.CommandResponse
-	29, // [29:31] is the sub-list for method output_type
-	27, // [27:29] is the sub-list for method input_type
-	27, // [27:27] is the sub-list for extension type_name
-	27, // [27:27] is the sub-list for extension extendee
-	0,  // [0:27] is the sub-list for field type_name
+	29, // 19: visualize_grpc_pb.VisualizeEvent.on_ext_addr_change:type_name -> visualize_grpc_pb.OnExtAddrChangeEvent
+	30, // 20: visualize_grpc_pb.VisualizeEvent.set_title:type_name -> visualize_grpc_pb.SetTitleEvent
+	31, // 21: visualize_grpc_pb.VisualizeEvent.set_node_mode:type_name -> visualize_grpc_pb.SetNodeModeEvent
+	32, // 22: visualize_grpc_pb.VisualizeEvent.set_network_info:type_name -> visualize_grpc_pb.SetNetworkInfoEvent
+	20, // 23: visualize_grpc_pb.VisualizeEvent.on_node_crash:type_name -> visualize_grpc_pb.OnNodeCrashEvent
+	21, // 24: visualize_grpc_pb.VisualizeEvent.on_node_restart:type_name -> visualize_grpc_pb.OnNodeRestartEvent
+	22, // 25: visualize_grpc_pb.VisualizeEvent.set_node_network_state:type_name -> visualize_grpc_pb.SetNodeNetworkStateEvent
+	4,  // 26: visualize_grpc_pb.SendEvent.mv_info:type_name -> visualize_grpc_pb.MsgVisualizeInfo
+	0,  // 27: visualize_grpc_pb.SetNodeRoleEvent.role:type_name -> visualize_grpc_pb.OtDeviceRole
+	23, // 28: visualize_grpc_pb.SetNodeNetworkStateEvent.srp_hosts:type_name -> visualize_grpc_pb.SrpHost
+	24, // 29: visualize_grpc_pb.SetNodeNetworkStateEvent.srp_services:type_name -> visualize_grpc_pb.SrpService
+	27, // 30: visualize_grpc_pb.SetNodeModeEvent.node_mode:type_name -> visualize_grpc_pb.NodeMode
+	32, // 31: visualize_grpc_pb.ReplayHeader.network_info:type_name -> visualize_grpc_pb.SetNetworkInfoEvent
+	2,  // 32: visualize_grpc_pb.ReplayEntry.event:type_name -> visualize_grpc_pb.VisualizeEvent
+	39, // 33: visualize_grpc_pb.ReplayEntry.keyframe:type_name -> visualize_grpc_pb.ReplayKeyframe
+	2,  // 34: visualize_grpc_pb.ReplayKeyframe.events:type_name -> visualize_grpc_pb.VisualizeEvent
+	41, // 35: visualize_grpc_pb.ReplayIndex.keyframes:type_name -> visualize_grpc_pb.ReplayIndexEntry
+	1,  // 36: visualize_grpc_pb.VisualizeGrpcService.Visualize:input_type -> visualize_grpc_pb.VisualizeRequest
+	33, // 37: visualize_grpc_pb.VisualizeGrpcService.Command:input_type -> visualize_grpc_pb.CommandRequest
+	35, // 38: visualize_grpc_pb.VisualizeGrpcService.NodeLogs:input_type -> visualize_grpc_pb.NodeLogsRequest
+	2,  // 39: visualize_grpc_pb.VisualizeGrpcService.Visualize:output_type -> visualize_grpc_pb.VisualizeEvent
+	34, // 40: visualize_grpc_pb.VisualizeGrpcService.Command:output_type -> visualize_grpc_pb.CommandResponse
+	36, // 41: visualize_grpc_pb.VisualizeGrpcService.NodeLogs:output_type -> visualize_grpc_pb.NodeLogEntry
+	39, // [39:42] is the sub-list for method output_type
+	36, // [36:39] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_visualize_grpc_proto_init() }
@@ -2745,7 +3637,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteNodeEvent); i {
+			switch v := v.(*OnNodeCrashEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2757,7 +3649,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddNodeEvent); i {
+			switch v := v.(*OnNodeRestartEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2769,7 +3661,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NodeMode); i {
+			switch v := v.(*SetNodeNetworkStateEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2781,7 +3673,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetNodeRloc16Event); i {
+			switch v := v.(*SrpHost); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2793,7 +3685,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*OnExtAddrChangeEvent); i {
+			switch v := v.(*SrpService); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2805,7 +3697,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetTitleEvent); i {
+			switch v := v.(*DeleteNodeEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2817,7 +3709,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetNodeModeEvent); i {
+			switch v := v.(*AddNodeEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2829,7 +3721,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetNetworkInfoEvent); i {
+			switch v := v.(*NodeMode); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2841,7 +3733,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CommandRequest); i {
+			switch v := v.(*SetNodeRloc16Event); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2853,7 +3745,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CommandResponse); i {
+			switch v := v.(*OnExtAddrChangeEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2865,7 +3757,7 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReplayEntry); i {
+			switch v := v.(*SetTitleEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2877,6 +3769,138 @@ func file_visualize_grpc_proto_init() {
 			}
 		}
 		file_visualize_grpc_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetNodeModeEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetNetworkInfoEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeLogsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeLogEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayHeader); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayKeyframe); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayIndex); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReplayIndexEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_visualize_grpc_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Empty); i {
 			case 0:
 				return &v.state
@@ -2913,6 +3937,9 @@ func file_visualize_grpc_proto_init() {
 		(*VisualizeEvent_SetTitle)(nil),
 		(*VisualizeEvent_SetNodeMode)(nil),
 		(*VisualizeEvent_SetNetworkInfo)(nil),
+		(*VisualizeEvent_OnNodeCrash)(nil),
+		(*VisualizeEvent_OnNodeRestart)(nil),
+		(*VisualizeEvent_SetNodeNetworkState)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -2920,7 +3947,7 @@ func file_visualize_grpc_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_visualize_grpc_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   31,
+			NumMessages:   42,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -2950,6 +3977,7 @@ type VisualizeGrpcServiceClient interface {
 	//    rpc Echo (EchoRequest) returns (EchoResponse);
 	Visualize(ctx context.Context, in *VisualizeRequest, opts ...grpc.CallOption) (VisualizeGrpcService_VisualizeClient, error)
 	Command(ctx context.Context, in *CommandRequest, opts ...grpc.CallOption) (*CommandResponse, error)
+	NodeLogs(ctx context.Context, in *NodeLogsRequest, opts ...grpc.CallOption) (VisualizeGrpcService_NodeLogsClient, error)
 }
 
 type visualizeGrpcServiceClient struct {
@@ -3001,11 +4029,44 @@ func (c *visualizeGrpcServiceClient) Command(ctx context.Context, in *CommandReq
 	return out, nil
 }
 
+func (c *visualizeGrpcServiceClient) NodeLogs(ctx context.Context, in *NodeLogsRequest, opts ...grpc.CallOption) (VisualizeGrpcService_NodeLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_VisualizeGrpcService_serviceDesc.Streams[1], "/visualize_grpc_pb.VisualizeGrpcService/NodeLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &visualizeGrpcServiceNodeLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type VisualizeGrpcService_NodeLogsClient interface {
+	Recv() (*NodeLogEntry, error)
+	grpc.ClientStream
+}
+
+type visualizeGrpcServiceNodeLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *visualizeGrpcServiceNodeLogsClient) Recv() (*NodeLogEntry, error) {
+	m := new(NodeLogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // VisualizeGrpcServiceServer is the server API for VisualizeGrpcService service.
 type VisualizeGrpcServiceServer interface {
 	//    rpc Echo (EchoRequest) returns (EchoResponse);
 	Visualize(*VisualizeRequest, VisualizeGrpcService_VisualizeServer) error
 	Command(context.Context, *CommandRequest) (*CommandResponse, error)
+	NodeLogs(*NodeLogsRequest, VisualizeGrpcService_NodeLogsServer) error
 }
 
 // UnimplementedVisualizeGrpcServiceServer can be embedded to have forward compatible implementations.
@@ -3018,6 +4079,9 @@ func (*UnimplementedVisualizeGrpcServiceServer) Visualize(*VisualizeRequest, Vis
 func (*UnimplementedVisualizeGrpcServiceServer) Command(context.Context, *CommandRequest) (*CommandResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Command not implemented")
 }
+func (*UnimplementedVisualizeGrpcServiceServer) NodeLogs(*NodeLogsRequest, VisualizeGrpcService_NodeLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method NodeLogs not implemented")
+}
 
 func RegisterVisualizeGrpcServiceServer(s *grpc.Server, srv VisualizeGrpcServiceServer) {
 	s.RegisterService(&_VisualizeGrpcService_serviceDesc, srv)
@@ -3062,6 +4126,27 @@ func _VisualizeGrpcService_Command_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VisualizeGrpcService_NodeLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NodeLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VisualizeGrpcServiceServer).NodeLogs(m, &visualizeGrpcServiceNodeLogsServer{stream})
+}
+
+type VisualizeGrpcService_NodeLogsServer interface {
+	Send(*NodeLogEntry) error
+	grpc.ServerStream
+}
+
+type visualizeGrpcServiceNodeLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *visualizeGrpcServiceNodeLogsServer) Send(m *NodeLogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _VisualizeGrpcService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "visualize_grpc_pb.VisualizeGrpcService",
 	HandlerType: (*VisualizeGrpcServiceServer)(nil),
@@ -3077,6 +4162,11 @@ var _VisualizeGrpcService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _VisualizeGrpcService_Visualize_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "NodeLogs",
+			Handler:       _VisualizeGrpcService_NodeLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "visualize_grpc.proto",
 }
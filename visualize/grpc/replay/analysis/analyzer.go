@@ -0,0 +1,452 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+// Package analysis answers questions about a replay offline, e.g. node role and partition timelines, network
+// convergence, node failures, frame counts and the topology at a given time.
+package analysis
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	visualize_grpc_pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/openthread/ot-ns/visualize/grpc/replay"
+)
+
+// labelSeqPat matches the MAC sequence number following the packet type in visualization labels, e.g. `MLE042`.
+var labelSeqPat = regexp.MustCompile(`^([A-Z]+)\d{3}\b`)
+
+// Time is the time of an event in the replay.
+type Time struct {
+	// Time is the time (in us) on the timeline of the replay reader
+	Time uint64 `json:"time_us"`
+	// VirtualTime is the virtual time (in us) of the simulation
+	VirtualTime uint64 `json:"virtual_time_us"`
+}
+
+func entryTime(entry *visualize_grpc_pb.ReplayEntry) Time {
+	return Time{Time: entry.Timestamp, VirtualTime: entry.VirtualTime}
+}
+
+type nodeState struct {
+	id          int32
+	x, y        int32
+	radioRange  int32
+	extAddr     uint64
+	rloc16      uint32
+	role        visualize_grpc_pb.OtDeviceRole
+	partitionId uint32
+	mode        *visualize_grpc_pb.NodeMode
+	parent      uint64
+	failed      bool
+	routers     map[uint64]struct{}
+	children    map[uint64]struct{}
+}
+
+// Analyzer builds the analysis results from the entries of a replay.
+type Analyzer struct {
+	time   Time
+	nodes  map[int32]*nodeState
+	frames map[int32]*FrameCount
+
+	roles        RoleTimeline
+	partitions   PartitionTimeline
+	failures     Failures
+	convergences Convergences
+}
+
+// NewAnalyzer creates an analyzer without nodes.
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{
+		nodes:        map[int32]*nodeState{},
+		frames:       map[int32]*FrameCount{},
+		roles:        RoleTimeline{},
+		partitions:   PartitionTimeline{},
+		failures:     Failures{},
+		convergences: Convergences{},
+	}
+}
+
+// Analyze reads all entries of the replay into a new analyzer.
+func Analyze(reader *replay.Reader) (*Analyzer, error) {
+	a := NewAnalyzer()
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF {
+			return a, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		a.Add(entry)
+	}
+}
+
+// AnalyzeUntil builds the state of the network at the time `at` on the timeline of the reader.
+// The reader jumps to the last keyframe before the time, so only the state (i.e. Topology) of the analyzer is valid.
+func AnalyzeUntil(reader *replay.Reader, at uint64) (*Analyzer, error) {
+	a := NewAnalyzer()
+
+	keyframe, err := reader.FindKeyframe(at)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyframe != nil {
+		entry, err := reader.SeekKeyframe(keyframe)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range entry.Keyframe.Events {
+			a.Add(&visualize_grpc_pb.ReplayEntry{Timestamp: entry.Timestamp, VirtualTime: entry.VirtualTime, Event: event})
+		}
+	}
+
+	for {
+		entry, err := reader.Next()
+		if err == io.EOF || (err == nil && entry.Timestamp > at) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		a.Add(entry)
+	}
+
+	a.time.Time = at
+	return a, nil
+}
+
+// Add applies the event of the entry.
+func (a *Analyzer) Add(entry *visualize_grpc_pb.ReplayEntry) {
+	if entry.Keyframe != nil {
+		// the state in the keyframe is already built by the previous events
+		return
+	}
+
+	a.time = entryTime(entry)
+
+	switch event := entry.Event.GetType().(type) {
+	case *visualize_grpc_pb.VisualizeEvent_AddNode:
+		e := event.AddNode
+		a.nodes[e.NodeId] = &nodeState{
+			id:         e.NodeId,
+			x:          e.X,
+			y:          e.Y,
+			radioRange: e.RadioRange,
+			routers:    map[uint64]struct{}{},
+			children:   map[uint64]struct{}{},
+		}
+	case *visualize_grpc_pb.VisualizeEvent_DeleteNode:
+		delete(a.nodes, event.DeleteNode.NodeId)
+	case *visualize_grpc_pb.VisualizeEvent_SetNodePos:
+		if node := a.nodes[event.SetNodePos.NodeId]; node != nil {
+			node.x, node.y = event.SetNodePos.X, event.SetNodePos.Y
+		}
+	case *visualize_grpc_pb.VisualizeEvent_OnExtAddrChange:
+		if node := a.nodes[event.OnExtAddrChange.NodeId]; node != nil {
+			node.extAddr = event.OnExtAddrChange.ExtAddr
+		}
+	case *visualize_grpc_pb.VisualizeEvent_SetNodeRloc16:
+		if node := a.nodes[event.SetNodeRloc16.NodeId]; node != nil {
+			node.rloc16 = event.SetNodeRloc16.Rloc16
+		}
+	case *visualize_grpc_pb.VisualizeEvent_SetNodeMode:
+		if node := a.nodes[event.SetNodeMode.NodeId]; node != nil {
+			node.mode = event.SetNodeMode.NodeMode
+		}
+	case *visualize_grpc_pb.VisualizeEvent_SetParent:
+		if node := a.nodes[event.SetParent.NodeId]; node != nil {
+			node.parent = event.SetParent.ExtAddr
+		}
+	case *visualize_grpc_pb.VisualizeEvent_SetNodeRole:
+		if node := a.nodes[event.SetNodeRole.NodeId]; node != nil && node.role != event.SetNodeRole.Role {
+			node.role = event.SetNodeRole.Role
+			a.roles = append(a.roles, RoleChange{Time: a.time, Node: node.id, Role: roleName(node.role)})
+		}
+	case *visualize_grpc_pb.VisualizeEvent_SetNodePartitionId:
+		if node := a.nodes[event.SetNodePartitionId.NodeId]; node != nil && node.partitionId != event.SetNodePartitionId.PartitionId {
+			node.partitionId = event.SetNodePartitionId.PartitionId
+			a.partitions = append(a.partitions, PartitionChange{Time: a.time, Node: node.id, PartitionId: node.partitionId})
+		}
+	case *visualize_grpc_pb.VisualizeEvent_OnNodeFail:
+		if node := a.nodes[event.OnNodeFail.NodeId]; node != nil && !node.failed {
+			node.failed = true
+			a.failures = append(a.failures, Failure{Node: node.id, Failed: a.time})
+		}
+	case *visualize_grpc_pb.VisualizeEvent_OnNodeRecover:
+		if node := a.nodes[event.OnNodeRecover.NodeId]; node != nil && node.failed {
+			node.failed = false
+			a.recover(node.id)
+		}
+	case *visualize_grpc_pb.VisualizeEvent_AddRouterTable:
+		if node := a.nodes[event.AddRouterTable.NodeId]; node != nil {
+			node.routers[event.AddRouterTable.ExtAddr] = struct{}{}
+		}
+	case *visualize_grpc_pb.VisualizeEvent_RemoveRouterTable:
+		if node := a.nodes[event.RemoveRouterTable.NodeId]; node != nil {
+			delete(node.routers, event.RemoveRouterTable.ExtAddr)
+		}
+	case *visualize_grpc_pb.VisualizeEvent_AddChildTable:
+		if node := a.nodes[event.AddChildTable.NodeId]; node != nil {
+			node.children[event.AddChildTable.ExtAddr] = struct{}{}
+		}
+	case *visualize_grpc_pb.VisualizeEvent_RemoveChildTable:
+		if node := a.nodes[event.RemoveChildTable.NodeId]; node != nil {
+			delete(node.children, event.RemoveChildTable.ExtAddr)
+		}
+	case *visualize_grpc_pb.VisualizeEvent_Send:
+		a.countFrame(event.Send)
+		return
+	default:
+		return
+	}
+
+	a.updateConvergence()
+}
+
+func (a *Analyzer) recover(nodeid int32) {
+	for i := len(a.failures) - 1; i >= 0; i-- {
+		if a.failures[i].Node == nodeid && a.failures[i].Recovered == nil {
+			recovered := a.time
+			a.failures[i].Recovered = &recovered
+			return
+		}
+	}
+}
+
+func (a *Analyzer) countFrame(send *visualize_grpc_pb.SendEvent) {
+	src := a.frameCount(send.SrcId)
+	src.Tx++
+	if send.DstId < 0 {
+		src.TxBroadcast++
+	} else if send.DstId == 0 {
+		// the dispatcher found no node with the destination address of the unicast frame
+		src.TxUndelivered++
+	} else {
+		src.TxUnicast++
+		a.frameCount(send.DstId).RxUnicast++
+	}
+
+	if label := send.MvInfo.GetLabel(); label != "" {
+		src.Labels[frameType(label)]++
+	}
+}
+
+// frameType returns the type of the frame from its visualization label by removing the MAC sequence number,
+// e.g. `MLE Advertisement` for `MLE042 Advertisement`.
+func frameType(label string) string {
+	return labelSeqPat.ReplaceAllString(label, "$1")
+}
+
+func (a *Analyzer) frameCount(nodeid int32) *FrameCount {
+	fc := a.frames[nodeid]
+	if fc == nil {
+		fc = &FrameCount{Node: nodeid, Labels: map[string]uint64{}}
+		a.frames[nodeid] = fc
+	}
+	return fc
+}
+
+// isConverged returns if all nodes that did not fail are attached to the same partition.
+func (a *Analyzer) isConverged() bool {
+	var partitionId uint32
+	attached := 0
+
+	for _, node := range a.nodes {
+		if node.failed {
+			continue
+		}
+
+		if node.role < visualize_grpc_pb.OtDeviceRole_OT_DEVICE_ROLE_CHILD {
+			return false
+		}
+
+		if attached > 0 && node.partitionId != partitionId {
+			return false
+		}
+
+		partitionId = node.partitionId
+		attached++
+	}
+
+	return attached > 0
+}
+
+func (a *Analyzer) updateConvergence() {
+	converged := a.isConverged()
+
+	n := len(a.convergences)
+	wasConverged := n > 0 && a.convergences[n-1].End == nil
+	if converged == wasConverged {
+		return
+	}
+
+	if converged {
+		a.convergences = append(a.convergences, Convergence{Start: a.time})
+	} else if a.convergences[n-1].Start.Time == a.time.Time {
+		// the network was in a single partition only transiently while the events at the same time are applied
+		a.convergences = a.convergences[:n-1]
+	} else {
+		end := a.time
+		a.convergences[n-1].End = &end
+	}
+}
+
+// Roles returns the role changes of all nodes.
+func (a *Analyzer) Roles() RoleTimeline {
+	return a.roles
+}
+
+// Partitions returns the partition ID changes of all nodes.
+func (a *Analyzer) Partitions() PartitionTimeline {
+	return a.partitions
+}
+
+// Failures returns the node failures and their recoveries.
+func (a *Analyzer) Failures() Failures {
+	return a.failures
+}
+
+// Convergences returns the periods when the network was in a single partition.
+func (a *Analyzer) Convergences() Convergences {
+	return a.convergences
+}
+
+// Converged returns the time since when the network is in a single partition, or nil if it is not at the end.
+func (a *Analyzer) Converged() *Time {
+	n := len(a.convergences)
+	if n == 0 || a.convergences[n-1].End != nil {
+		return nil
+	}
+
+	return &a.convergences[n-1].Start
+}
+
+// Frames returns the frame counts of all nodes sorted by node ID.
+func (a *Analyzer) Frames() FrameCounts {
+	frames := FrameCounts{}
+	for _, fc := range a.frames {
+		frames = append(frames, *fc)
+	}
+
+	sort.Slice(frames, func(i, j int) bool {
+		return frames[i].Node < frames[j].Node
+	})
+	return frames
+}
+
+// Topology returns the current state of all nodes sorted by node ID.
+func (a *Analyzer) Topology() *Topology {
+	topology := &Topology{Time: a.time, Nodes: []NodeInfo{}}
+
+	for _, node := range a.nodes {
+		info := NodeInfo{
+			Node:        node.id,
+			ExtAddr:     extAddrString(node.extAddr),
+			Rloc16:      fmt.Sprintf("0x%04x", node.rloc16),
+			Role:        roleName(node.role),
+			PartitionId: node.partitionId,
+			X:           node.x,
+			Y:           node.y,
+			RadioRange:  node.radioRange,
+			Mode:        modeString(node.mode),
+			Parent:      extAddrString(node.parent),
+			Failed:      node.failed,
+			Routers:     extAddrList(node.routers),
+			Children:    extAddrList(node.children),
+		}
+		topology.Nodes = append(topology.Nodes, info)
+	}
+
+	sort.Slice(topology.Nodes, func(i, j int) bool {
+		return topology.Nodes[i].Node < topology.Nodes[j].Node
+	})
+	return topology
+}
+
+func roleName(role visualize_grpc_pb.OtDeviceRole) string {
+	switch role {
+	case visualize_grpc_pb.OtDeviceRole_OT_DEVICE_ROLE_DISABLED:
+		return "disabled"
+	case visualize_grpc_pb.OtDeviceRole_OT_DEVICE_ROLE_DETACHED:
+		return "detached"
+	case visualize_grpc_pb.OtDeviceRole_OT_DEVICE_ROLE_CHILD:
+		return "child"
+	case visualize_grpc_pb.OtDeviceRole_OT_DEVICE_ROLE_ROUTER:
+		return "router"
+	case visualize_grpc_pb.OtDeviceRole_OT_DEVICE_ROLE_LEADER:
+		return "leader"
+	default:
+		return fmt.Sprintf("invalid(%d)", role)
+	}
+}
+
+// modeString formats the mode as in the OpenThread CLI, e.g. `rsdn`.
+func modeString(mode *visualize_grpc_pb.NodeMode) string {
+	if mode == nil {
+		return ""
+	}
+
+	s := ""
+	if mode.RxOnWhenIdle {
+		s += "r"
+	}
+	if mode.SecureDataRequests {
+		s += "s"
+	}
+	if mode.FullThreadDevice {
+		s += "d"
+	}
+	if mode.FullNetworkData {
+		s += "n"
+	}
+	if s == "" {
+		s = "-"
+	}
+	return s
+}
+
+func extAddrString(extaddr uint64) string {
+	if extaddr == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%016x", extaddr)
+}
+
+func extAddrList(extaddrs map[uint64]struct{}) []string {
+	list := []string{}
+	for extaddr := range extaddrs {
+		list = append(list, extAddrString(extaddr))
+	}
+
+	sort.Strings(list)
+	return list
+}
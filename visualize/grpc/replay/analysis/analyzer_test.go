@@ -0,0 +1,170 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package analysis
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/openthread/ot-ns/visualize/grpc/replay"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	roleLeader = pb.OtDeviceRole_OT_DEVICE_ROLE_LEADER
+	roleRouter = pb.OtDeviceRole_OT_DEVICE_ROLE_ROUTER
+)
+
+type testEntry struct {
+	ts    uint64
+	event *pb.VisualizeEvent
+}
+
+func testEntries() []testEntry {
+	return []testEntry{
+		{0, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_AddNode{AddNode: &pb.AddNodeEvent{NodeId: 1, X: 10, Y: 20, RadioRange: 100}}}},
+		{0, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_AddNode{AddNode: &pb.AddNodeEvent{NodeId: 2}}}},
+		{0, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_OnExtAddrChange{OnExtAddrChange: &pb.OnExtAddrChangeEvent{NodeId: 2, ExtAddr: 0x22}}}},
+		{100, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNodeRole{SetNodeRole: &pb.SetNodeRoleEvent{NodeId: 1, Role: roleLeader}}}},
+		{100, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNodePartitionId{SetNodePartitionId: &pb.SetNodePartitionIdEvent{NodeId: 1, PartitionId: 1}}}},
+		{200, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNodeRole{SetNodeRole: &pb.SetNodeRoleEvent{NodeId: 2, Role: roleLeader}}}},
+		{200, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNodePartitionId{SetNodePartitionId: &pb.SetNodePartitionIdEvent{NodeId: 2, PartitionId: 2}}}},
+		{300, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_Send{Send: &pb.SendEvent{SrcId: 1, DstId: -1, MvInfo: &pb.MsgVisualizeInfo{Label: "MLE041 Advertisement"}}}}},
+		{300, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_Send{Send: &pb.SendEvent{SrcId: 1, DstId: -1, MvInfo: &pb.MsgVisualizeInfo{Label: "MLE042 Advertisement"}}}}},
+		{300, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_Send{Send: &pb.SendEvent{SrcId: 2, DstId: 1, MvInfo: &pb.MsgVisualizeInfo{Label: "MLE007 Link Request"}}}}},
+		{300, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_Send{Send: &pb.SendEvent{SrcId: 1, DstId: 2, MvInfo: &pb.MsgVisualizeInfo{Label: "ACK007"}}}}},
+		// a unicast frame to an address of no node
+		{300, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_Send{Send: &pb.SendEvent{SrcId: 2, DstId: 0, MvInfo: &pb.MsgVisualizeInfo{Label: "MAC008"}}}}},
+		{300, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_Send{Send: &pb.SendEvent{SrcId: 2, DstId: 1, MvInfo: &pb.MsgVisualizeInfo{}}}}},
+		// node 2 merges into the partition of node 1
+		{400, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNodePartitionId{SetNodePartitionId: &pb.SetNodePartitionIdEvent{NodeId: 2, PartitionId: 1}}}},
+		{400, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNodeRole{SetNodeRole: &pb.SetNodeRoleEvent{NodeId: 2, Role: roleRouter}}}},
+		{400, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_AddRouterTable{AddRouterTable: &pb.AddRouterTableEvent{NodeId: 1, ExtAddr: 0x22}}}},
+		// node 1 fails and recovers
+		{500, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_OnNodeFail{OnNodeFail: &pb.OnNodeFailEvent{NodeId: 1}}}},
+		{600, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_OnNodeRecover{OnNodeRecover: &pb.OnNodeRecoverEvent{NodeId: 1}}}},
+		{600, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNodeRole{SetNodeRole: &pb.SetNodeRoleEvent{NodeId: 1, Role: pb.OtDeviceRole_OT_DEVICE_ROLE_DETACHED}}}},
+		{700, &pb.VisualizeEvent{Type: &pb.VisualizeEvent_SetNodeRole{SetNodeRole: &pb.SetNodeRoleEvent{NodeId: 1, Role: roleRouter}}}},
+	}
+}
+
+func TestAnalyzer(t *testing.T) {
+	a := NewAnalyzer()
+	for _, e := range testEntries() {
+		a.Add(&pb.ReplayEntry{Timestamp: e.ts, VirtualTime: e.ts * 10, Event: e.event})
+	}
+
+	assert.Equal(t, RoleTimeline{
+		{Time{100, 1000}, 1, "leader"},
+		{Time{200, 2000}, 2, "leader"},
+		{Time{400, 4000}, 2, "router"},
+		{Time{600, 6000}, 1, "detached"},
+		{Time{700, 7000}, 1, "router"},
+	}, a.Roles())
+	assert.Equal(t, 3, len(a.Partitions()))
+
+	assert.Equal(t, Failures{{Node: 1, Failed: Time{500, 5000}, Recovered: &Time{600, 6000}}}, a.Failures())
+
+	// the network converges when node 2 merges, is still converged while node 1 is down, and converges again
+	// when node 1 reattaches
+	assert.Equal(t, Convergences{
+		{Start: Time{400, 4000}, End: &Time{600, 6000}},
+		{Start: Time{700, 7000}},
+	}, a.Convergences())
+	assert.Equal(t, &Time{700, 7000}, a.Converged())
+
+	frames := a.Frames()
+	assert.Equal(t, 2, len(frames))
+	assert.Equal(t, FrameCount{Node: 1, Tx: 3, TxUnicast: 1, TxBroadcast: 2, RxUnicast: 2,
+		Labels: map[string]uint64{"MLE Advertisement": 2, "ACK": 1}}, frames[0])
+	assert.Equal(t, FrameCount{Node: 2, Tx: 3, TxUnicast: 2, TxUndelivered: 1, RxUnicast: 1,
+		Labels: map[string]uint64{"MLE Link Request": 1, "MAC": 1}}, frames[1])
+
+	topology := a.Topology()
+	assert.Equal(t, 2, len(topology.Nodes))
+	assert.Equal(t, "router", topology.Nodes[0].Role)
+	assert.Equal(t, []string{"0000000000000022"}, topology.Nodes[0].Routers)
+	assert.Equal(t, int32(100), topology.Nodes[0].RadioRange)
+	assert.Equal(t, "0000000000000022", topology.Nodes[1].ExtAddr)
+
+	var buf bytes.Buffer
+	assert.Nil(t, Write(&buf, a.Failures(), OutputCSV))
+	assert.Equal(t, "node,failed_time_us,failed_virtual_time_us,recovered_time_us,recovered_virtual_time_us\n1,500,5000,600,6000\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, Write(&buf, a.Convergences()[1:], OutputJSON))
+	assert.Contains(t, buf.String(), `"end": null`)
+}
+
+func TestAnalyzeUntil(t *testing.T) {
+	dir, err := ioutil.TempDir("", "analysis")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "test.replay")
+	w, err := replay.NewWriter(filename, replay.DefaultOptions())
+	assert.Nil(t, err)
+
+	a := NewAnalyzer()
+	for _, e := range testEntries() {
+		assert.Nil(t, w.WriteEntry(&pb.ReplayEntry{Timestamp: e.ts, Event: e.event}))
+		a.Add(&pb.ReplayEntry{Timestamp: e.ts, Event: e.event})
+
+		if e.ts == 300 {
+			// a keyframe with the state of node 1 only, which is enough to find the topology after it
+			assert.Nil(t, w.WriteEntry(&pb.ReplayEntry{Timestamp: e.ts, Keyframe: &pb.ReplayKeyframe{Events: []*pb.VisualizeEvent{
+				{Type: &pb.VisualizeEvent_AddNode{AddNode: &pb.AddNodeEvent{NodeId: 1}}},
+			}}}))
+		}
+	}
+	assert.Nil(t, w.Close())
+
+	for _, at := range []uint64{150, 450} {
+		reader, err := replay.OpenReader(filename)
+		assert.Nil(t, err)
+
+		a, err := AnalyzeUntil(reader, at)
+		assert.Nil(t, err)
+		_ = reader.Close()
+
+		topology := a.Topology()
+		assert.Equal(t, at, topology.Time.Time)
+		if at == 150 {
+			assert.Equal(t, 2, len(topology.Nodes))
+			assert.Equal(t, "leader", topology.Nodes[0].Role)
+			assert.Equal(t, "disabled", topology.Nodes[1].Role)
+		} else {
+			assert.Equal(t, 1, len(topology.Nodes))
+			assert.Equal(t, "disabled", topology.Nodes[0].Role)
+			assert.Equal(t, []string{"0000000000000022"}, topology.Nodes[0].Routers)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package analysis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Result is an analysis result which can be written as a CSV table or as JSON.
+type Result interface {
+	// Header returns the CSV column names.
+	Header() []string
+	// Rows returns the CSV rows.
+	Rows() [][]string
+}
+
+// OutputFormat is the format of written results.
+type OutputFormat int
+
+const (
+	OutputCSV OutputFormat = iota
+	OutputJSON
+)
+
+func (f OutputFormat) String() string {
+	switch f {
+	case OutputCSV:
+		return "csv"
+	case OutputJSON:
+		return "json"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseOutputFormat parses the output format name.
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	switch s {
+	case "csv":
+		return OutputCSV, nil
+	case "json":
+		return OutputJSON, nil
+	default:
+		return OutputCSV, errors.Errorf("invalid output format: %s", s)
+	}
+}
+
+// Write writes the result in the format.
+func Write(w io.Writer, result Result, format OutputFormat) error {
+	if format == OutputJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(result.Header()); err != nil {
+		return err
+	}
+
+	if err := cw.WriteAll(result.Rows()); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+func timeColumns(t *Time) []string {
+	if t == nil {
+		return []string{"", ""}
+	}
+
+	return []string{fmt.Sprint(t.Time), fmt.Sprint(t.VirtualTime)}
+}
+
+func timeHeader(prefix string) []string {
+	return []string{prefix + "time_us", prefix + "virtual_time_us"}
+}
+
+// RoleChange is a role change of a node.
+type RoleChange struct {
+	Time
+	Node int32  `json:"node"`
+	Role string `json:"role"`
+}
+
+// RoleTimeline is the role changes of all nodes in time order.
+type RoleTimeline []RoleChange
+
+func (t RoleTimeline) Header() []string {
+	return append(timeHeader(""), "node", "role")
+}
+
+func (t RoleTimeline) Rows() [][]string {
+	rows := [][]string{}
+	for i := range t {
+		rows = append(rows, append(timeColumns(&t[i].Time), fmt.Sprint(t[i].Node), t[i].Role))
+	}
+	return rows
+}
+
+// PartitionChange is a partition ID change of a node.
+type PartitionChange struct {
+	Time
+	Node        int32  `json:"node"`
+	PartitionId uint32 `json:"partition_id"`
+}
+
+// PartitionTimeline is the partition ID changes of all nodes in time order.
+type PartitionTimeline []PartitionChange
+
+func (t PartitionTimeline) Header() []string {
+	return append(timeHeader(""), "node", "partition_id")
+}
+
+func (t PartitionTimeline) Rows() [][]string {
+	rows := [][]string{}
+	for i := range t {
+		rows = append(rows, append(timeColumns(&t[i].Time), fmt.Sprint(t[i].Node), fmt.Sprintf("%08x", t[i].PartitionId)))
+	}
+	return rows
+}
+
+// Failure is a failure of a node, and its recovery if the node recovered.
+type Failure struct {
+	Node      int32 `json:"node"`
+	Failed    Time  `json:"failed"`
+	Recovered *Time `json:"recovered"`
+}
+
+// Failures is the node failures in time order.
+type Failures []Failure
+
+func (f Failures) Header() []string {
+	header := append([]string{"node"}, timeHeader("failed_")...)
+	return append(header, timeHeader("recovered_")...)
+}
+
+func (f Failures) Rows() [][]string {
+	rows := [][]string{}
+	for i := range f {
+		row := append([]string{fmt.Sprint(f[i].Node)}, timeColumns(&f[i].Failed)...)
+		rows = append(rows, append(row, timeColumns(f[i].Recovered)...))
+	}
+	return rows
+}
+
+// Convergence is a period when all nodes that did not fail were attached to the same partition.
+// End is nil if the network was still converged at the end of the replay.
+type Convergence struct {
+	Start Time  `json:"start"`
+	End   *Time `json:"end"`
+}
+
+// Convergences is the convergence periods in time order.
+type Convergences []Convergence
+
+func (c Convergences) Header() []string {
+	return append(timeHeader("start_"), timeHeader("end_")...)
+}
+
+func (c Convergences) Rows() [][]string {
+	rows := [][]string{}
+	for i := range c {
+		rows = append(rows, append(timeColumns(&c[i].Start), timeColumns(c[i].End)...))
+	}
+	return rows
+}
+
+// FrameCount is the number of frames sent and received by a node.
+type FrameCount struct {
+	Node        int32  `json:"node"`
+	Tx          uint64 `json:"tx"`
+	TxUnicast   uint64 `json:"tx_unicast"`
+	TxBroadcast uint64 `json:"tx_broadcast"`
+	// TxUndelivered counts the unicast frames sent to an address of no node
+	TxUndelivered uint64 `json:"tx_undelivered"`
+	RxUnicast     uint64 `json:"rx_unicast"`
+	// Labels counts the frames sent by the node per frame type (e.g. `MLE Advertisement` or `ACK`)
+	Labels map[string]uint64 `json:"labels"`
+}
+
+// FrameCounts is the frame counts of all nodes.
+type FrameCounts []FrameCount
+
+func (f FrameCounts) Header() []string {
+	return []string{"node", "tx", "tx_unicast", "tx_broadcast", "tx_undelivered", "rx_unicast", "labels"}
+}
+
+func (f FrameCounts) Rows() [][]string {
+	rows := [][]string{}
+	for _, fc := range f {
+		var labels []string
+		for label, count := range fc.Labels {
+			labels = append(labels, fmt.Sprintf("%s=%d", label, count))
+		}
+		sort.Strings(labels)
+
+		rows = append(rows, []string{fmt.Sprint(fc.Node), fmt.Sprint(fc.Tx), fmt.Sprint(fc.TxUnicast),
+			fmt.Sprint(fc.TxBroadcast), fmt.Sprint(fc.TxUndelivered), fmt.Sprint(fc.RxUnicast), strings.Join(labels, ";")})
+	}
+	return rows
+}
+
+// NodeInfo is the state of a node.
+type NodeInfo struct {
+	Node        int32    `json:"node"`
+	ExtAddr     string   `json:"ext_addr"`
+	Rloc16      string   `json:"rloc16"`
+	Role        string   `json:"role"`
+	PartitionId uint32   `json:"partition_id"`
+	X           int32    `json:"x"`
+	Y           int32    `json:"y"`
+	RadioRange  int32    `json:"radio_range"`
+	Mode        string   `json:"mode"`
+	Parent      string   `json:"parent"`
+	Failed      bool     `json:"failed"`
+	Routers     []string `json:"routers"`
+	Children    []string `json:"children"`
+}
+
+// Topology is the state of all nodes at a time.
+type Topology struct {
+	Time
+	Nodes []NodeInfo `json:"nodes"`
+}
+
+func (t *Topology) Header() []string {
+	return []string{"node", "ext_addr", "rloc16", "role", "partition_id", "x", "y", "radio_range", "mode", "parent",
+		"failed", "routers", "children"}
+}
+
+func (t *Topology) Rows() [][]string {
+	rows := [][]string{}
+	for _, n := range t.Nodes {
+		rows = append(rows, []string{fmt.Sprint(n.Node), n.ExtAddr, n.Rloc16, n.Role, fmt.Sprintf("%08x", n.PartitionId),
+			fmt.Sprint(n.X), fmt.Sprint(n.Y), fmt.Sprint(n.RadioRange), n.Mode, n.Parent, fmt.Sprint(n.Failed),
+			strings.Join(n.Routers, ";"), strings.Join(n.Children, ";")})
+	}
+	return rows
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"io"
+
+	visualize_grpc_pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+)
+
+// Convert converts the replay `src` to `dst` in the format and compression of the options.
+// The header of a binary source replay is kept, and the keyframe index of `dst` is rebuilt.
+func Convert(src string, dst string, options Options) (err error) {
+	r, err := OpenReader(src)
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	w, err := NewWriter(dst, options)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cerr := w.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	header := r.Header()
+	if header == nil {
+		header = &visualize_grpc_pb.ReplayHeader{
+			OtnsVersion: options.OtnsVersion,
+			Seed:        options.Seed,
+		}
+	}
+
+	if err = w.WriteHeader(header); err != nil {
+		return err
+	}
+
+	for {
+		var entry *visualize_grpc_pb.ReplayEntry
+		if entry, err = r.Next(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err = w.WriteEntry(entry); err != nil {
+			return err
+		}
+	}
+}
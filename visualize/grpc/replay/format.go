@@ -0,0 +1,211 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Format is the encoding of the entries of a replay file.
+type Format int
+
+const (
+	// FormatText writes one text protobuf entry per line.
+	FormatText Format = iota
+	// FormatBinary writes a header followed by length-delimited binary protobuf entries.
+	FormatBinary
+)
+
+// BinaryFormatVersion is the version of the binary replay format recorded in the header.
+const BinaryFormatVersion = 1
+
+var (
+	// binaryMagic starts a binary replay (after decompression).
+	binaryMagic = []byte("OTNSRPLY")
+
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatText:
+		return "text"
+	case FormatBinary:
+		return "binary"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseFormat parses the format name.
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "text":
+		return FormatText, nil
+	case "binary":
+		return FormatBinary, nil
+	default:
+		return FormatText, errors.Errorf("invalid replay format: %s", s)
+	}
+}
+
+// Compression is the streaming compression of a replay file.
+type Compression int
+
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+	CompressionZstd
+)
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseCompression parses the compression name.
+func ParseCompression(s string) (Compression, error) {
+	switch s {
+	case "none":
+		return CompressionNone, nil
+	case "gzip":
+		return CompressionGzip, nil
+	case "zstd":
+		return CompressionZstd, nil
+	default:
+		return CompressionNone, errors.Errorf("invalid replay compression: %s", s)
+	}
+}
+
+// Options configures the format of a replay file.
+type Options struct {
+	Format      Format
+	Compression Compression
+	// OtnsVersion and Seed are recorded in the header of binary replays.
+	OtnsVersion string
+	Seed        int64
+}
+
+// DefaultOptions returns the default options for writing replays.
+func DefaultOptions() Options {
+	return Options{
+		Format:      FormatText,
+		Compression: CompressionNone,
+	}
+}
+
+// detectCompression detects the compression of the replay by the magic number.
+func detectCompression(r *bufio.Reader) (Compression, error) {
+	head, err := r.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return CompressionNone, err
+	}
+
+	if bytes.HasPrefix(head, gzipMagic) {
+		return CompressionGzip, nil
+	} else if bytes.HasPrefix(head, zstdMagic) {
+		return CompressionZstd, nil
+	}
+
+	return CompressionNone, nil
+}
+
+// detectFormat detects the format of the (decompressed) replay by the magic number.
+func detectFormat(r *bufio.Reader) (Format, error) {
+	head, err := r.Peek(len(binaryMagic))
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return FormatText, err
+	}
+
+	if bytes.Equal(head, binaryMagic) {
+		return FormatBinary, nil
+	}
+
+	return FormatText, nil
+}
+
+// decompressor wraps the replay file for reading according to the compression.
+type decompressor struct {
+	io.Reader
+	close func()
+}
+
+func newDecompressor(r io.Reader, compression Compression) (*decompressor, error) {
+	switch compression {
+	case CompressionGzip:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return &decompressor{Reader: zr, close: func() { _ = zr.Close() }}, nil
+	case CompressionZstd:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return &decompressor{Reader: zr, close: zr.Close}, nil
+	default:
+		return &decompressor{Reader: r, close: func() {}}, nil
+	}
+}
+
+// compressor wraps the replay file for writing according to the compression.
+type compressor struct {
+	io.Writer
+	close func() error
+}
+
+func newCompressor(w io.Writer, compression Compression) (*compressor, error) {
+	switch compression {
+	case CompressionGzip:
+		zw := gzip.NewWriter(w)
+		return &compressor{Writer: zw, close: zw.Close}, nil
+	case CompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		return &compressor{Writer: zw, close: zw.Close}, nil
+	default:
+		return &compressor{Writer: w, close: func() error { return nil }}, nil
+	}
+}
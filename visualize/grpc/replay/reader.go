@@ -0,0 +1,403 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	visualize_grpc_pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	unmarshalOptions = prototext.UnmarshalOptions{}
+)
+
+// maxMessageSize limits the size of a binary entry, so that a corrupted length does not exhaust the memory.
+const maxMessageSize = 64 * 1024 * 1024
+
+// Reader reads the entries of a replay file one by one.
+// The format and compression of the replay are detected automatically.
+type Reader struct {
+	filename    string
+	f           *os.File
+	compression Compression
+	format      Format
+	header      *visualize_grpc_pb.ReplayHeader
+	decomp      *decompressor
+	br          *bufio.Reader
+	// offset is the offset of the next entry in the decompressed replay
+	offset     uint64
+	dataOffset uint64
+	index      *visualize_grpc_pb.ReplayIndex
+
+	timeline Timeline
+	maxGap   uint64
+	state    timelineState
+	// keyframes are the keyframes of the index on the timeline
+	keyframes []*visualize_grpc_pb.ReplayIndexEntry
+}
+
+// OpenReader opens the replay file for reading from the beginning.
+func OpenReader(filename string) (*Reader, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reader{
+		filename: filename,
+		f:        f,
+	}
+
+	if err = r.open(); err != nil {
+		_ = f.Close()
+		return nil, errors.Wrapf(err, "open replay %s", filename)
+	}
+
+	return r, nil
+}
+
+func (r *Reader) open() error {
+	var err error
+
+	fr := bufio.NewReader(r.f)
+	if r.compression, err = detectCompression(fr); err != nil {
+		return err
+	}
+
+	if r.decomp, err = newDecompressor(fr, r.compression); err != nil {
+		return err
+	}
+
+	r.br = bufio.NewReaderSize(r.decomp, 64*1024)
+	if r.format, err = detectFormat(r.br); err != nil {
+		return err
+	}
+
+	if r.format == FormatBinary {
+		if _, err = r.br.Discard(len(binaryMagic)); err != nil {
+			return err
+		}
+		r.offset = uint64(len(binaryMagic))
+
+		r.header = &visualize_grpc_pb.ReplayHeader{}
+		if err = r.readMessage(r.header); err != nil {
+			return errors.Wrapf(err, "read header")
+		}
+
+		if r.header.FormatVersion > BinaryFormatVersion {
+			return errors.Errorf("unsupported replay format version: %d", r.header.FormatVersion)
+		}
+	}
+
+	r.dataOffset = r.offset
+	return nil
+}
+
+// Format returns the format of the replay.
+func (r *Reader) Format() Format {
+	return r.format
+}
+
+// Compression returns the compression of the replay.
+func (r *Reader) Compression() Compression {
+	return r.compression
+}
+
+// Header returns the header of a binary replay, or nil for a text replay.
+func (r *Reader) Header() *visualize_grpc_pb.ReplayHeader {
+	return r.header
+}
+
+// Next returns the next entry of the replay, or io.EOF if there is no more entry.
+// A truncated entry at the end (e.g. when the simulation crashed) is also treated as the end of the replay.
+// The timestamp of the entry is the time on the timeline of the reader (see SetTimeline).
+func (r *Reader) Next() (*visualize_grpc_pb.ReplayEntry, error) {
+	entry, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+
+	r.retime(entry)
+	return entry, nil
+}
+
+// next reads the next entry as recorded in the replay.
+func (r *Reader) next() (*visualize_grpc_pb.ReplayEntry, error) {
+	entry := &visualize_grpc_pb.ReplayEntry{}
+
+	if r.format == FormatBinary {
+		offset := r.offset
+		if err := r.readMessage(entry); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, io.EOF
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "offset %d", offset)
+		}
+
+		return entry, nil
+	}
+
+	for {
+		offset := r.offset
+		line, err := r.br.ReadBytes('\n')
+		r.offset += uint64(len(line))
+		if err != nil && err != io.EOF {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			return nil, err
+		}
+
+		eof := err == io.EOF
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			if eof {
+				return nil, io.EOF
+			}
+			continue
+		}
+
+		if err := unmarshalOptions.Unmarshal(line, entry); err != nil {
+			if eof {
+				// the last line is incomplete
+				return nil, io.EOF
+			}
+			return nil, errors.Wrapf(err, "offset %d", offset)
+		}
+
+		return entry, nil
+	}
+}
+
+// readMessage reads a length-delimited binary protobuf message.
+func (r *Reader) readMessage(m proto.Message) error {
+	size, err := binary.ReadUvarint(r.br)
+	if err != nil {
+		return err
+	}
+
+	if size > maxMessageSize {
+		return errors.Errorf("message too large: %d bytes", size)
+	}
+
+	data := make([]byte, size)
+	if _, err = io.ReadFull(r.br, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	var lenbuf [binary.MaxVarintLen64]byte
+	r.offset += uint64(binary.PutUvarint(lenbuf[:], size)) + size
+	return proto.Unmarshal(data, m)
+}
+
+// Rewind moves the reader to the first entry of the replay.
+func (r *Reader) Rewind() error {
+	if err := r.seek(r.dataOffset); err != nil {
+		return err
+	}
+
+	r.state = timelineState{}
+	return nil
+}
+
+// seek moves the reader to the offset in the decompressed replay.
+// Compressed replays are decompressed again from the beginning if seeking backward.
+func (r *Reader) seek(offset uint64) error {
+	if r.compression == CompressionNone {
+		if _, err := r.f.Seek(int64(offset), io.SeekStart); err != nil {
+			return err
+		}
+
+		r.br.Reset(r.f)
+		r.offset = offset
+		return nil
+	}
+
+	if offset < r.offset {
+		if _, err := r.f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		r.decomp.close()
+
+		decomp, err := newDecompressor(bufio.NewReader(r.f), r.compression)
+		if err != nil {
+			return err
+		}
+
+		r.decomp = decomp
+		r.br.Reset(decomp)
+		r.offset = 0
+	}
+
+	if _, err := io.CopyN(ioutil.Discard, r.br, int64(offset-r.offset)); err != nil {
+		return err
+	}
+
+	r.offset = offset
+	return nil
+}
+
+// FindKeyframe returns the index entry of the last keyframe at or before the timestamp on the timeline of the reader,
+// or nil if there is none.
+func (r *Reader) FindKeyframe(timestamp uint64) (*visualize_grpc_pb.ReplayIndexEntry, error) {
+	keyframes, err := r.timelineKeyframes()
+	if err != nil {
+		return nil, err
+	}
+	i := sort.Search(len(keyframes), func(i int) bool {
+		return keyframes[i].Timestamp > timestamp
+	})
+
+	if i == 0 {
+		return nil, nil
+	}
+
+	return keyframes[i-1], nil
+}
+
+// SeekKeyframe moves the reader to the keyframe found by FindKeyframe and returns the keyframe entry.
+// The following entries are read from right after the keyframe.
+func (r *Reader) SeekKeyframe(keyframe *visualize_grpc_pb.ReplayIndexEntry) (*visualize_grpc_pb.ReplayEntry, error) {
+	if err := r.seek(keyframe.Offset); err != nil {
+		return nil, err
+	}
+
+	entry, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Keyframe == nil {
+		return nil, errors.Errorf("no keyframe at offset %d", keyframe.Offset)
+	}
+
+	if entry.VirtualTime == 0 {
+		entry.VirtualTime = entryVirtualTime(entry, keyframe.VirtualTime)
+	}
+
+	r.state = timelineState{
+		wallTime:    entry.Timestamp,
+		virtualTime: entry.VirtualTime,
+		time:        keyframe.Timestamp,
+	}
+	entry.Timestamp = keyframe.Timestamp
+	return entry, nil
+}
+
+// Index returns the keyframe index of the replay.
+// The index is loaded from the index file, or built by scanning the replay if the index file is missing or outdated.
+func (r *Reader) Index() (*visualize_grpc_pb.ReplayIndex, error) {
+	if r.index != nil {
+		return r.index, nil
+	}
+
+	fi, err := r.f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	index := &visualize_grpc_pb.ReplayIndex{}
+	data, err := ioutil.ReadFile(IndexFilename(r.filename))
+	if err != nil || unmarshalOptions.Unmarshal(data, index) != nil || index.ReplaySize != uint64(fi.Size()) {
+		if index, err = buildIndex(r.filename); err != nil {
+			return nil, err
+		}
+	}
+
+	r.index = index
+	return index, nil
+}
+
+// buildIndex scans the replay for keyframes.
+func buildIndex(filename string) (*visualize_grpc_pb.ReplayIndex, error) {
+	keyframes, err := scanKeyframes(filename, TimelineWall, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &visualize_grpc_pb.ReplayIndex{
+		ReplaySize: uint64(fi.Size()),
+		Keyframes:  keyframes,
+	}, nil
+}
+
+// scanKeyframes reads the whole replay on the timeline to find the time of each keyframe.
+func scanKeyframes(filename string, timeline Timeline, maxGap uint64) ([]*visualize_grpc_pb.ReplayIndexEntry, error) {
+	r, err := OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+
+	if err = r.SetTimeline(timeline, maxGap); err != nil {
+		return nil, err
+	}
+
+	var keyframes []*visualize_grpc_pb.ReplayIndexEntry
+	for {
+		offset := r.offset
+		entry, err := r.Next()
+		if err == io.EOF {
+			return keyframes, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if entry.Keyframe != nil {
+			keyframes = append(keyframes, &visualize_grpc_pb.ReplayIndexEntry{
+				Timestamp:   entry.Timestamp,
+				Offset:      offset,
+				VirtualTime: entry.VirtualTime,
+			})
+		}
+	}
+}
+
+// Close closes the replay file.
+func (r *Reader) Close() error {
+	r.decomp.close()
+	return r.f.Close()
+}
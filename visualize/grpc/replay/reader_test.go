@@ -0,0 +1,233 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	visualize_grpc_pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+var allOptions = []Options{
+	{Format: FormatText, Compression: CompressionNone},
+	{Format: FormatText, Compression: CompressionGzip},
+	{Format: FormatBinary, Compression: CompressionNone},
+	{Format: FormatBinary, Compression: CompressionGzip},
+	{Format: FormatBinary, Compression: CompressionZstd},
+}
+
+func addNodeEvent(nodeid int) *visualize_grpc_pb.VisualizeEvent {
+	return &visualize_grpc_pb.VisualizeEvent{
+		Type: &visualize_grpc_pb.VisualizeEvent_AddNode{AddNode: &visualize_grpc_pb.AddNodeEvent{NodeId: int32(nodeid)}},
+	}
+}
+
+func checkEntries(t *testing.T, filename string, options Options, count int) {
+	reader, err := OpenReader(filename)
+	assert.Nil(t, err)
+	defer reader.Close()
+
+	assert.Equal(t, options.Format, reader.Format())
+	assert.Equal(t, options.Compression, reader.Compression())
+
+	var lastTimestamp uint64
+	for i := 1; i <= count; i++ {
+		entry, err := reader.Next()
+		assert.Nil(t, err)
+		assert.Equal(t, int32(i), entry.Event.GetAddNode().NodeId)
+		assert.Equal(t, uint64(i)*1000, entry.VirtualTime)
+		assert.True(t, entry.Timestamp >= lastTimestamp)
+		lastTimestamp = entry.Timestamp
+	}
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, options := range allOptions {
+		options.OtnsVersion = "v1"
+		options.Seed = 123
+
+		filename := filepath.Join(dir, "test.replay")
+		rep := NewReplay(filename, options, nil)
+		for i := 1; i <= 3; i++ {
+			rep.Append(addNodeEvent(i), uint64(i)*1000, false)
+		}
+		rep.Close()
+
+		checkEntries(t, filename, options, 3)
+
+		reader, err := OpenReader(filename)
+		assert.Nil(t, err)
+		if options.Format == FormatBinary {
+			assert.Equal(t, uint32(BinaryFormatVersion), reader.Header().FormatVersion)
+			assert.Equal(t, "v1", reader.Header().OtnsVersion)
+			assert.Equal(t, int64(123), reader.Header().Seed)
+		} else {
+			assert.Nil(t, reader.Header())
+		}
+		_ = reader.Close()
+	}
+}
+
+func TestReader_Truncated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, options := range allOptions {
+		filename := filepath.Join(dir, "test.replay")
+		w, err := NewWriter(filename, options)
+		assert.Nil(t, err)
+		// write enough entries to span several compressed blocks, so that a truncated zstd stream still decodes some
+		for i := 1; i <= 100000; i++ {
+			assert.Nil(t, w.WriteEntry(&visualize_grpc_pb.ReplayEntry{Timestamp: uint64(i * i), Event: addNodeEvent(i)}))
+		}
+		assert.Nil(t, w.Close())
+
+		// the replay is cut in an entry, as if the simulation crashed
+		fi, err := os.Stat(filename)
+		assert.Nil(t, err)
+		assert.Nil(t, os.Truncate(filename, fi.Size()/2))
+
+		reader, err := OpenReader(filename)
+		assert.Nil(t, err)
+
+		count := 0
+		for {
+			_, err = reader.Next()
+			if err != nil {
+				break
+			}
+			count++
+		}
+
+		assert.Equal(t, io.EOF, err, "options %+v", options)
+		assert.True(t, count > 0 && count < 100000, "options %+v, count %d", options, count)
+		_ = reader.Close()
+	}
+}
+
+func TestReader_Keyframes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, options := range allOptions {
+		nodeCount := 0
+		filename := filepath.Join(dir, "test.replay")
+		rep := NewReplay(filename, options, func() []*visualize_grpc_pb.VisualizeEvent {
+			return []*visualize_grpc_pb.VisualizeEvent{{
+				Type: &visualize_grpc_pb.VisualizeEvent_SetTitle{SetTitle: &visualize_grpc_pb.SetTitleEvent{
+					FontSize: int32(nodeCount),
+				}},
+			}}
+		})
+		rep.keyframeInterval = 0
+		for i := 1; i <= 3; i++ {
+			time.Sleep(time.Millisecond)
+			nodeCount = i
+			rep.Append(addNodeEvent(i), uint64(i)*1000, false)
+		}
+		rep.Close()
+
+		check := func() {
+			reader, err := OpenReader(filename)
+			assert.Nil(t, err)
+			defer reader.Close()
+
+			index, err := reader.Index()
+			assert.Nil(t, err)
+			assert.Equal(t, 3, len(index.Keyframes))
+
+			keyframe, err := reader.FindKeyframe(index.Keyframes[1].Timestamp)
+			assert.Nil(t, err)
+			assert.Equal(t, index.Keyframes[1], keyframe)
+
+			keyframe, err = reader.FindKeyframe(index.Keyframes[0].Timestamp - 1)
+			assert.Nil(t, err)
+			assert.Nil(t, keyframe)
+
+			for _, i := range []int{1, 0, 2} {
+				entry, err := reader.SeekKeyframe(index.Keyframes[i])
+				assert.Nil(t, err)
+				assert.Equal(t, int32(i+1), entry.Keyframe.Events[0].GetSetTitle().FontSize)
+
+				if i < 2 {
+					entry, err = reader.Next()
+					assert.Nil(t, err)
+					assert.Equal(t, int32(i+2), entry.Event.GetAddNode().NodeId)
+				}
+			}
+
+			assert.Nil(t, reader.Rewind())
+			entry, err := reader.Next()
+			assert.Nil(t, err)
+			assert.Equal(t, int32(1), entry.Event.GetAddNode().NodeId)
+		}
+
+		check()
+
+		// the index is rebuilt by scanning the replay if the index file is missing
+		assert.Nil(t, os.Remove(IndexFilename(filename)))
+		check()
+	}
+}
+
+func TestConvert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src.replay")
+	rep := NewReplay(src, Options{Format: FormatText}, nil)
+	for i := 1; i <= 10; i++ {
+		rep.Append(addNodeEvent(i), uint64(i)*1000, false)
+	}
+	rep.Close()
+
+	for _, options := range allOptions {
+		dst := filepath.Join(dir, "dst.replay")
+		assert.Nil(t, Convert(src, dst, options))
+		checkEntries(t, dst, options, 10)
+
+		back := filepath.Join(dir, "back.replay")
+		assert.Nil(t, Convert(dst, back, Options{Format: FormatText}))
+		checkEntries(t, back, Options{Format: FormatText}, 10)
+	}
+}
@@ -1,8 +1,6 @@
 package replay
 
 import (
-	"bufio"
-	"os"
 	"time"
 
 	visualize_grpc_pb "github.com/openthread/ot-ns/visualize/grpc/pb"
@@ -16,21 +14,78 @@ var (
 	}
 )
 
+const (
+	// KeyframeInterval is the minimal interval (in us) between two keyframes of a replay.
+	KeyframeInterval = 10000000
+)
+
+// KeyframeSource returns the events which draw the current state of the visualization from scratch.
+type KeyframeSource func() []*visualize_grpc_pb.VisualizeEvent
+
 type Replay struct {
-	f              *os.File
-	fileWriter     *bufio.Writer
-	pendingChan    chan *visualize_grpc_pb.ReplayEntry
-	fileWriterDone chan struct{}
-	beginTime      time.Time
+	writer           *Writer
+	options          Options
+	header           *visualize_grpc_pb.ReplayHeader
+	pendingChan      chan *visualize_grpc_pb.ReplayEntry
+	fileWriterDone   chan struct{}
+	beginTime        time.Time
+	keyframeSource   KeyframeSource
+	keyframeInterval uint64
+	lastKeyframe     uint64
 }
 
-func (rep *Replay) Append(event *visualize_grpc_pb.VisualizeEvent, trivial bool) {
-	timestamp := time.Since(rep.beginTime) / time.Microsecond
+// IndexFilename returns the filename of the keyframe index of the replay.
+func IndexFilename(filename string) string {
+	return filename + ".idx"
+}
+
+// Append appends the event to the replay, stamped with the wall-clock time since the replay began and with the
+// current virtual time of the simulation.
+func (rep *Replay) Append(event *visualize_grpc_pb.VisualizeEvent, virtualTime uint64, trivial bool) {
+	if rep.header == nil {
+		rep.header = rep.newHeader()
+	}
+
+	timestamp := uint64(time.Since(rep.beginTime) / time.Microsecond)
 	entry := &visualize_grpc_pb.ReplayEntry{
-		Event:     event,
-		Timestamp: uint64(timestamp),
+		Event:       event,
+		Timestamp:   timestamp,
+		VirtualTime: virtualTime,
+	}
+
+	rep.appendEntry(entry, trivial)
+
+	if rep.keyframeSource != nil && timestamp >= rep.lastKeyframe+rep.keyframeInterval {
+		// the keyframe is written after the event, because the state already includes the event
+		rep.lastKeyframe = timestamp
+		rep.appendEntry(&visualize_grpc_pb.ReplayEntry{
+			Timestamp:   timestamp,
+			VirtualTime: virtualTime,
+			Keyframe:    &visualize_grpc_pb.ReplayKeyframe{Events: rep.keyframeSource()},
+		}, false)
+	}
+}
+
+// newHeader creates the replay header when the first event is appended, so that it records the network info
+// set up by the simulation.
+func (rep *Replay) newHeader() *visualize_grpc_pb.ReplayHeader {
+	header := &visualize_grpc_pb.ReplayHeader{
+		OtnsVersion: rep.options.OtnsVersion,
+		Seed:        rep.options.Seed,
+	}
+
+	if rep.keyframeSource != nil {
+		for _, event := range rep.keyframeSource() {
+			if networkInfo := event.GetSetNetworkInfo(); networkInfo != nil {
+				header.NetworkInfo = networkInfo
+			}
+		}
 	}
 
+	return header
+}
+
+func (rep *Replay) appendEntry(entry *visualize_grpc_pb.ReplayEntry, trivial bool) {
 	if !trivial {
 		rep.pendingChan <- entry
 	} else {
@@ -60,37 +115,36 @@ func (rep *Replay) fileWriterRoutine() {
 		}
 	}()
 
-	defer rep.f.Close()
-
 	for e := range rep.pendingChan {
-		var data []byte
-
-		if data, err = marshalOptions.Marshal(e); err != nil {
-			break
-		}
-
-		if _, err = rep.fileWriter.Write(data); err != nil {
+		// the header is set before the first entry is sent to the channel
+		if err = rep.writer.WriteHeader(rep.header); err != nil {
 			break
 		}
 
-		if _, err = rep.fileWriter.Write([]byte{'\n'}); err != nil {
+		if err = rep.writer.WriteEntry(e); err != nil {
 			break
 		}
 	}
 
-	err = rep.fileWriter.Flush()
+	if cerr := rep.writer.Close(); err == nil {
+		err = cerr
+	}
 }
 
-func NewReplay(filename string) *Replay {
-	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+// NewReplay creates a replay file. If keyframeSource is not nil, keyframes are written to the replay periodically,
+// and their offsets are written to the index file when the replay is closed.
+func NewReplay(filename string, options Options, keyframeSource KeyframeSource) *Replay {
+	writer, err := NewWriter(filename, options)
 	simplelogger.PanicIfError(err)
 
 	rep := &Replay{
-		f:              f,
-		fileWriter:     bufio.NewWriterSize(f, 8192),
-		pendingChan:    make(chan *visualize_grpc_pb.ReplayEntry, 10000),
-		fileWriterDone: make(chan struct{}),
-		beginTime:      time.Now(),
+		writer:           writer,
+		options:          options,
+		pendingChan:      make(chan *visualize_grpc_pb.ReplayEntry, 10000),
+		fileWriterDone:   make(chan struct{}),
+		beginTime:        time.Now(),
+		keyframeSource:   keyframeSource,
+		keyframeInterval: KeyframeInterval,
 	}
 
 	go rep.fileWriterRoutine()
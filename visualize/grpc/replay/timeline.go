@@ -0,0 +1,188 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	visualize_grpc_pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/pkg/errors"
+)
+
+// Timeline selects the time of the replay entries returned by a Reader.
+type Timeline int
+
+const (
+	// TimelineWall uses the wall-clock time since the replay began.
+	TimelineWall Timeline = iota
+	// TimelineVirtual uses the virtual time of the simulation.
+	TimelineVirtual
+)
+
+func (t Timeline) String() string {
+	switch t {
+	case TimelineWall:
+		return "wall"
+	case TimelineVirtual:
+		return "virtual"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTimeline parses the timeline name.
+func ParseTimeline(s string) (Timeline, error) {
+	switch s {
+	case "wall":
+		return TimelineWall, nil
+	case "virtual":
+		return TimelineVirtual, nil
+	default:
+		return TimelineWall, errors.Errorf("invalid replay timeline: %s", s)
+	}
+}
+
+// timelineState is the time of the last entry read.
+type timelineState struct {
+	// wallTime is the wall-clock timestamp recorded in the entry
+	wallTime uint64
+	// virtualTime is the virtual time recorded in the entry
+	virtualTime uint64
+	// time is the time on the timeline of the reader
+	time uint64
+}
+
+// SetTimeline sets the timeline of the entries and keyframes returned by the reader.
+// If the timeline is TimelineWall and maxGap is not 0, wall-clock gaps longer than maxGap (in us) between
+// entries, e.g. when the simulation was paused, are compressed to maxGap.
+// It must be called before reading the first entry.
+func (r *Reader) SetTimeline(timeline Timeline, maxGap uint64) error {
+	if r.offset != r.dataOffset {
+		return errors.Errorf("replay timeline must be set before reading")
+	}
+
+	if timeline != TimelineWall {
+		maxGap = 0
+	}
+
+	r.timeline = timeline
+	r.maxGap = maxGap
+	r.keyframes = nil
+	return nil
+}
+
+// retime sets the virtual time of the entry if it is missing, and replaces its timestamp by the time on the timeline.
+func (r *Reader) retime(entry *visualize_grpc_pb.ReplayEntry) {
+	if entry.VirtualTime == 0 {
+		// replays recorded without virtual time follow the advance time events
+		entry.VirtualTime = entryVirtualTime(entry, r.state.virtualTime)
+	}
+
+	switch {
+	case r.timeline == TimelineVirtual:
+		r.state.time = entry.VirtualTime
+	case r.maxGap > 0:
+		var gap uint64
+		if entry.Timestamp > r.state.wallTime {
+			gap = entry.Timestamp - r.state.wallTime
+		}
+
+		if gap > r.maxGap {
+			gap = r.maxGap
+		}
+		r.state.time += gap
+	default:
+		r.state.time = entry.Timestamp
+	}
+
+	r.state.wallTime = entry.Timestamp
+	r.state.virtualTime = entry.VirtualTime
+	entry.Timestamp = r.state.time
+}
+
+// entryVirtualTime returns the time of the advance time event in the entry or in its keyframe, or `last` otherwise.
+func entryVirtualTime(entry *visualize_grpc_pb.ReplayEntry, last uint64) uint64 {
+	if advanceTime := entry.Event.GetAdvanceTime(); advanceTime != nil {
+		return advanceTime.Ts
+	}
+
+	for _, event := range entry.Keyframe.GetEvents() {
+		if advanceTime := event.GetAdvanceTime(); advanceTime != nil {
+			return advanceTime.Ts
+		}
+	}
+
+	return last
+}
+
+// timelineKeyframes returns the keyframes of the index on the timeline of the reader.
+// The times of keyframes with compressed wall-clock gaps depend on all previous entries, so they are computed by
+// scanning the replay.
+func (r *Reader) timelineKeyframes() ([]*visualize_grpc_pb.ReplayIndexEntry, error) {
+	if r.keyframes != nil {
+		return r.keyframes, nil
+	}
+
+	index, err := r.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	var keyframes []*visualize_grpc_pb.ReplayIndexEntry
+	switch {
+	case r.timeline == TimelineVirtual && !indexMissesVirtualTime(index):
+		for _, keyframe := range index.Keyframes {
+			keyframes = append(keyframes, &visualize_grpc_pb.ReplayIndexEntry{
+				Timestamp:   keyframe.VirtualTime,
+				Offset:      keyframe.Offset,
+				VirtualTime: keyframe.VirtualTime,
+			})
+		}
+	case r.timeline == TimelineVirtual || r.maxGap > 0:
+		if keyframes, err = scanKeyframes(r.filename, r.timeline, r.maxGap); err != nil {
+			return nil, err
+		}
+	default:
+		keyframes = index.Keyframes
+	}
+
+	if keyframes == nil {
+		keyframes = []*visualize_grpc_pb.ReplayIndexEntry{}
+	}
+
+	r.keyframes = keyframes
+	return keyframes, nil
+}
+
+// indexMissesVirtualTime returns if the index was written before virtual time was recorded in replays.
+func indexMissesVirtualTime(index *visualize_grpc_pb.ReplayIndex) bool {
+	for _, keyframe := range index.Keyframes {
+		if keyframe.VirtualTime == 0 && keyframe.Timestamp > 0 {
+			return true
+		}
+	}
+
+	return false
+}
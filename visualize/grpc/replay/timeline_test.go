@@ -0,0 +1,123 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	visualize_grpc_pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"github.com/stretchr/testify/assert"
+)
+
+func advanceTimeEvent(ts uint64) *visualize_grpc_pb.VisualizeEvent {
+	return &visualize_grpc_pb.VisualizeEvent{
+		Type: &visualize_grpc_pb.VisualizeEvent_AdvanceTime{AdvanceTime: &visualize_grpc_pb.AdvanceTimeEvent{Ts: ts}},
+	}
+}
+
+func TestReader_Timeline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replay")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// the simulation is paused for 5s after advancing to 1ms
+	entries := []*visualize_grpc_pb.ReplayEntry{
+		{Timestamp: 100, VirtualTime: 0, Event: addNodeEvent(1)},
+		{Timestamp: 200, VirtualTime: 1000, Event: advanceTimeEvent(1000)},
+		{Timestamp: 5000000, VirtualTime: 1000, Event: addNodeEvent(2)},
+		{Timestamp: 5000000, VirtualTime: 1000, Keyframe: &visualize_grpc_pb.ReplayKeyframe{}},
+		{Timestamp: 5000100, VirtualTime: 3000, Event: advanceTimeEvent(3000)},
+	}
+
+	tests := []struct {
+		timeline Timeline
+		maxGap   uint64
+		times    []uint64
+	}{
+		{TimelineWall, 0, []uint64{100, 200, 5000000, 5000000, 5000100}},
+		{TimelineWall, 1000, []uint64{100, 200, 1200, 1200, 1300}},
+		{TimelineVirtual, 1000, []uint64{0, 1000, 1000, 1000, 3000}},
+	}
+
+	// replays recorded without virtual time follow the advance time events
+	for _, withVirtualTime := range []bool{true, false} {
+		filename := filepath.Join(dir, "test.replay")
+		w, err := NewWriter(filename, DefaultOptions())
+		assert.Nil(t, err)
+		for _, entry := range entries {
+			entry = &visualize_grpc_pb.ReplayEntry{Timestamp: entry.Timestamp, VirtualTime: entry.VirtualTime, Event: entry.Event, Keyframe: entry.Keyframe}
+			if !withVirtualTime {
+				entry.VirtualTime = 0
+			}
+			assert.Nil(t, w.WriteEntry(entry))
+		}
+		assert.Nil(t, w.Close())
+
+		for _, test := range tests {
+			reader, err := OpenReader(filename)
+			assert.Nil(t, err)
+			assert.Nil(t, reader.SetTimeline(test.timeline, test.maxGap))
+
+			for i, ts := range test.times {
+				entry, err := reader.Next()
+				assert.Nil(t, err)
+				assert.Equal(t, ts, entry.Timestamp, "%+v entry %d", test, i)
+				assert.Equal(t, entries[i].VirtualTime, entry.VirtualTime, "%+v entry %d", test, i)
+			}
+
+			_, err = reader.Next()
+			assert.Equal(t, io.EOF, err)
+
+			// seek to the keyframe on the timeline
+			keyframe, err := reader.FindKeyframe(test.times[3])
+			assert.Nil(t, err)
+			assert.Equal(t, test.times[3], keyframe.Timestamp)
+			keyframe, err = reader.FindKeyframe(test.times[3] - 1)
+			assert.Nil(t, err)
+			assert.Nil(t, keyframe)
+
+			keyframe, _ = reader.FindKeyframe(test.times[3])
+			entry, err := reader.SeekKeyframe(keyframe)
+			assert.Nil(t, err)
+			assert.Equal(t, test.times[3], entry.Timestamp)
+			entry, err = reader.Next()
+			assert.Nil(t, err)
+			assert.Equal(t, test.times[4], entry.Timestamp)
+
+			assert.Nil(t, reader.Rewind())
+			entry, err = reader.Next()
+			assert.Nil(t, err)
+			assert.Equal(t, test.times[0], entry.Timestamp)
+
+			_ = reader.Close()
+		}
+	}
+}
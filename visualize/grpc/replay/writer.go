@@ -0,0 +1,198 @@
+// Copyright (c) 2020, The OTNS Authors.
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+// 3. Neither the name of the copyright holder nor the
+//    names of its contributors may be used to endorse or promote products
+//    derived from this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE
+// LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR
+// CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF
+// SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN
+// CONTRACT, STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE)
+// ARISING IN ANY WAY OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE
+// POSSIBILITY OF SUCH DAMAGE.
+
+package replay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+
+	visualize_grpc_pb "github.com/openthread/ot-ns/visualize/grpc/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// countingWriter counts the bytes written to the replay file.
+type countingWriter struct {
+	f     *os.File
+	count uint64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.f.Write(p)
+	w.count += uint64(n)
+	return n, err
+}
+
+// Writer writes entries to a replay file in the configured format, and writes the keyframe index when closed.
+type Writer struct {
+	filename      string
+	options       Options
+	f             *os.File
+	fileCounter   *countingWriter
+	compressor    *compressor
+	bufWriter     *bufio.Writer
+	headerWritten bool
+	// offset is the offset of the next entry in the decompressed replay
+	offset uint64
+	index  visualize_grpc_pb.ReplayIndex
+}
+
+// NewWriter creates the replay file for writing.
+func NewWriter(filename string, options Options) (*Writer, error) {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	fileCounter := &countingWriter{f: f}
+	comp, err := newCompressor(fileCounter, options.Compression)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &Writer{
+		filename:    filename,
+		options:     options,
+		f:           f,
+		fileCounter: fileCounter,
+		compressor:  comp,
+		bufWriter:   bufio.NewWriterSize(comp, 8192),
+	}, nil
+}
+
+// WriteHeader writes the header of a binary replay. It must be called before writing any entry.
+// Text replays have no header.
+func (w *Writer) WriteHeader(header *visualize_grpc_pb.ReplayHeader) error {
+	if w.options.Format != FormatBinary || w.headerWritten {
+		return nil
+	}
+
+	w.headerWritten = true
+
+	header = proto.Clone(header).(*visualize_grpc_pb.ReplayHeader)
+	header.FormatVersion = BinaryFormatVersion
+
+	if _, err := w.bufWriter.Write(binaryMagic); err != nil {
+		return err
+	}
+	w.offset += uint64(len(binaryMagic))
+
+	return w.writeMessage(header)
+}
+
+// WriteEntry writes an entry to the replay.
+func (w *Writer) WriteEntry(entry *visualize_grpc_pb.ReplayEntry) error {
+	if w.options.Format == FormatBinary && !w.headerWritten {
+		if err := w.WriteHeader(&visualize_grpc_pb.ReplayHeader{
+			OtnsVersion: w.options.OtnsVersion,
+			Seed:        w.options.Seed,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if entry.Keyframe != nil {
+		w.index.Keyframes = append(w.index.Keyframes, &visualize_grpc_pb.ReplayIndexEntry{
+			Timestamp:   entry.Timestamp,
+			Offset:      w.offset,
+			VirtualTime: entry.VirtualTime,
+		})
+	}
+
+	if w.options.Format == FormatBinary {
+		return w.writeMessage(entry)
+	}
+
+	data, err := marshalOptions.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.bufWriter.Write(data); err != nil {
+		return err
+	}
+
+	if err = w.bufWriter.WriteByte('\n'); err != nil {
+		return err
+	}
+
+	w.offset += uint64(len(data)) + 1
+	return nil
+}
+
+// writeMessage writes a length-delimited binary protobuf message.
+func (w *Writer) writeMessage(m proto.Message) error {
+	data, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	var lenbuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenbuf[:], uint64(len(data)))
+
+	if _, err = w.bufWriter.Write(lenbuf[:n]); err != nil {
+		return err
+	}
+
+	if _, err = w.bufWriter.Write(data); err != nil {
+		return err
+	}
+
+	w.offset += uint64(n + len(data))
+	return nil
+}
+
+// Close flushes and closes the replay file, and writes the keyframe index file.
+func (w *Writer) Close() error {
+	err := w.bufWriter.Flush()
+
+	if cerr := w.compressor.close(); err == nil {
+		err = cerr
+	}
+
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.index.ReplaySize = w.fileCounter.count
+	return w.writeIndex()
+}
+
+func (w *Writer) writeIndex() error {
+	data, err := marshalOptions.Marshal(&w.index)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(IndexFilename(w.filename), data, 0644)
+}
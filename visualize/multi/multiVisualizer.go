@@ -123,6 +123,24 @@ func (mv *multiVisualizer) OnNodeRecover(nodeid NodeId) {
 	}
 }
 
+func (mv *multiVisualizer) OnNodeCrash(nodeid NodeId, reason string) {
+	for _, v := range mv.vs {
+		v.OnNodeCrash(nodeid, reason)
+	}
+}
+
+func (mv *multiVisualizer) OnNodeRestart(nodeid NodeId) {
+	for _, v := range mv.vs {
+		v.OnNodeRestart(nodeid)
+	}
+}
+
+func (mv *multiVisualizer) SetNodeNetworkState(nodeid NodeId, state NodeNetworkState) {
+	for _, v := range mv.vs {
+		v.SetNodeNetworkState(nodeid, state)
+	}
+}
+
 func (mv *multiVisualizer) SetController(ctrl visualize.SimulationController) {
 	for _, v := range mv.vs {
 		v.SetController(ctrl)
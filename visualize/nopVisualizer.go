@@ -119,6 +119,18 @@ func (nv nopVisualizer) OnNodeRecover(NodeId) {
 
 }
 
+func (nv nopVisualizer) OnNodeCrash(NodeId, string) {
+
+}
+
+func (nv nopVisualizer) OnNodeRestart(NodeId) {
+
+}
+
+func (nv nopVisualizer) SetNodeNetworkState(nodeid NodeId, state NodeNetworkState) {
+
+}
+
 func (nv nopVisualizer) SetTitle(titleInfo TitleInfo) {
 
 }
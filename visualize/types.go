@@ -30,6 +30,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/openthread/ot-ns/dissectpkt"
+	"github.com/openthread/ot-ns/dissectpkt/mle"
 	"github.com/openthread/ot-ns/dissectpkt/wpan"
 	. "github.com/openthread/ot-ns/types"
 )
@@ -44,11 +46,14 @@ type Visualizer interface {
 	SetNodeMode(nodeid NodeId, mode NodeMode)
 	Send(srcid NodeId, dstid NodeId, mvinfo *MsgVisualizeInfo)
 	SetNodePartitionId(nodeid NodeId, parid uint32)
+	SetNodeNetworkState(nodeid NodeId, state NodeNetworkState)
 	SetSpeed(speed float64)
 	AdvanceTime(ts uint64, speed float64)
 
 	OnNodeFail(nodeId NodeId)
 	OnNodeRecover(nodeId NodeId)
+	OnNodeCrash(nodeId NodeId, reason string)
+	OnNodeRestart(nodeId NodeId)
 	SetController(ctrl SimulationController)
 	SetNodePos(nodeid NodeId, x, y int)
 	DeleteNode(id NodeId)
@@ -70,16 +75,26 @@ type MsgVisualizeInfo struct {
 	Seq             uint8
 	DstAddrShort    uint16
 	DstAddrExtended uint64
+	PktType         dissectpkt.PktType
+	// MleCommand is the MLE command type if the frame carries a complete MLE message.
+	MleCommand *mle.CommandType
 }
 
 func (info *MsgVisualizeInfo) Label() string {
-	frameType := info.FrameControl.FrameType()
-	if frameType == wpan.FrameTypeAck {
+	switch info.PktType {
+	case dissectpkt.PktTypeAck:
 		return fmt.Sprintf("ACK%03d", info.Seq)
-	} else if info.FrameControl.SecurityEnabled() {
-		return fmt.Sprintf("MAC%03d", info.Seq)
-	} else {
+	case dissectpkt.PktTypeMle:
+		if info.MleCommand != nil {
+			return fmt.Sprintf("MLE%03d %s", info.Seq, *info.MleCommand)
+		}
 		return fmt.Sprintf("MLE%03d", info.Seq)
+	case dissectpkt.PktTypeTmf:
+		return fmt.Sprintf("TMF%03d", info.Seq)
+	case dissectpkt.PktTypeIcmp6:
+		return fmt.Sprintf("ICMP%03d", info.Seq)
+	default:
+		return fmt.Sprintf("MAC%03d", info.Seq)
 	}
 }
 
@@ -49,6 +49,8 @@ export default class Node extends VObject {
         this.rloc16 = 0xfffe;
         this.role = OtDeviceRole.OT_DEVICE_ROLE_DISABLED;
         this._failed = false;
+        this.crashed = false;
+        this.networkState = null;
         this._parent = 0;
         this._partition = 0;
         this._children = {};
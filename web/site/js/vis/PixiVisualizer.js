@@ -51,6 +51,7 @@ export default class PixiVisualizer extends VObject {
 
         this.app = app;
         this.grpcServiceClient = grpcServiceClient;
+        this.commandMetadata = {};
         this.speed = 1;
         this.curTime = 0;
         this.curSpeed = 1;
@@ -403,6 +404,44 @@ export default class PixiVisualizer extends VObject {
         this.logNode(nodeId, "Radio is ON")
     }
 
+    visOnNodeCrash(nodeId, reason) {
+        // the node is also marked as failed by a separate OnNodeFail event
+        this.nodes[nodeId].crashed = true;
+        this.logNode(nodeId, `Crashed: ${reason}`)
+    }
+
+    visOnNodeRestart(nodeId) {
+        this.nodes[nodeId].crashed = false;
+        this.logNode(nodeId, "Restarted")
+    }
+
+    visSetNodeNetworkState(nodeId, state) {
+        let node = this.nodes[nodeId];
+        let old = node.networkState;
+        node.networkState = state;
+
+        if (old === null || old.getLeaderRouterId() !== state.getLeaderRouterId()) {
+            this.logNode(nodeId, `Leader router ID set to ${state.getLeaderRouterId()}`)
+        }
+        if (old === null || old.getKeySequence() !== state.getKeySequence()) {
+            this.logNode(nodeId, `Key sequence set to ${state.getKeySequence()}`)
+        }
+        if (old === null || old.getChannel() !== state.getChannel()) {
+            this.logNode(nodeId, `Channel set to ${state.getChannel()}`)
+        }
+
+        let netData = (s) => s.getPrefixesList().concat(s.getRoutesList(), s.getServicesList());
+        if (old === null || netData(old).join() !== netData(state).join()) {
+            let entries = netData(state);
+            this.logNode(nodeId, `Network data: ${entries.length > 0 ? entries.join(', ') : 'empty'}`)
+        }
+
+        let srp = (s) => `${s.getSrpHostsList().length} SRP hosts, ${s.getSrpServicesList().length} SRP services`;
+        if (old === null || srp(old) !== srp(state)) {
+            this.logNode(nodeId, srp(state))
+        }
+    }
+
     visSetParent(nodeId, extAddr) {
         this.nodes[nodeId].parent = extAddr;
         this.logNode(nodeId, `Parent set to ${this.formatExtAddrPretty(extAddr)}`)
@@ -479,13 +518,17 @@ export default class PixiVisualizer extends VObject {
         this.runCommand("speed " + speed)
     }
 
+    setCommandMetadata(metadata) {
+        this.commandMetadata = metadata;
+    }
+
     runCommand(cmd, callback) {
         let req = new CommandRequest();
         req.setCommand(cmd);
         this.log(`> ${cmd}`);
         console.log(`> ${cmd}`);
 
-        this.grpcServiceClient.command(req, {}, (err, resp) => {
+        this.grpcServiceClient.command(req, this.commandMetadata, (err, resp) => {
                 if (err !== null) {
                     this.log("Error: " + err.toLocaleString());
                     console.error("Error: " + err.toLocaleString());
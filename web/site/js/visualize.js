@@ -89,6 +89,13 @@ function loadOk() {
     let visualizeRequest = new VisualizeRequest();
     let metadata = {'custom-header-1': 'value1'};
     let stream = grpcServiceClient.visualize(visualizeRequest, metadata);
+    stream.on('metadata', function (md) {
+        // otns-replay identifies the replay cursor of this stream, commands must carry it
+        let cursor = md['otns-replay-cursor'];
+        if (cursor !== undefined) {
+            vis.setCommandMetadata({'otns-replay-cursor': cursor});
+        }
+    });
     stream.on('data', function (resp) {
         let e = null;
         switch (resp.getTypeCase()) {
@@ -184,6 +191,18 @@ function loadOk() {
                 e = resp.getSetNetworkInfo();
                 vis.visSetNetworkInfo(e.getVersion(), e.getCommit(), e.getReal());
                 break;
+            case VisualizeEvent.TypeCase.ON_NODE_CRASH:
+                e = resp.getOnNodeCrash();
+                vis.visOnNodeCrash(e.getNodeId(), e.getReason());
+                break;
+            case VisualizeEvent.TypeCase.ON_NODE_RESTART:
+                e = resp.getOnNodeRestart();
+                vis.visOnNodeRestart(e.getNodeId());
+                break;
+            case VisualizeEvent.TypeCase.SET_NODE_NETWORK_STATE:
+                e = resp.getSetNodeNetworkState();
+                vis.visSetNodeNetworkState(e.getNodeId(), e);
+                break;
             default:
                 console.log('unknown event!!! ' + resp.getTypeCase());
                 break